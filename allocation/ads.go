@@ -0,0 +1,147 @@
+package allocation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+// A Reference is an identifier an ad is known by in the ADS, such as its
+// campaign, orderline, advertiser or category; Type is matched against the
+// referenceType of AdsReferenceIds.
+type Reference struct {
+	Type string `json:"type,omitempty"`
+	Id   string `json:"id"`
+}
+
+// An Ad is a candidate for the slots of an allocation.
+type Ad struct {
+	Id         string        `json:"id"`
+	Duration   time.Duration `json:"duration"`
+	References []Reference   `json:"references,omitempty"`
+}
+
+func (ad *Ad) String() string {
+	return ad.Id
+}
+
+// Matches reports whether ad is the one an AdsReferenceId refers to: an
+// AdsReferenceId without a referenceType matches the id of the ad and its
+// references of every type; one with a referenceType only matches the
+// references of that type.
+func (ad *Ad) Matches(ref *scte224.AdsReferenceId) bool {
+	id := strings.TrimSpace(ref.ID)
+	if ref.ReferenceType == "" && ad.Id == id {
+		return true
+	}
+	for _, r := range ad.References {
+		if r.Id == id && (ref.ReferenceType == "" || r.Type == ref.ReferenceType) {
+			return true
+		}
+	}
+	return false
+}
+
+// Eligible returns why ad may not play in slot, or "" when it may: an ad must
+// have a duration no longer than the slot, match one of the AdsReferenceIds of
+// the slot that are not exclusions, if there are any, and none of those that are.
+func Eligible(slot *PlacedSlot, ad *Ad) string {
+	if ad.Duration <= 0 {
+		return "no duration"
+	}
+	if ad.Duration > slot.Duration() {
+		return fmt.Sprintf("%s is longer than the slot of %s", ad.Duration, slot.Duration())
+	}
+	included, includes := false, 0
+	for _, ref := range slot.Slot.AdsReferenceId {
+		if ref == nil {
+			continue
+		}
+		if ref.Exclude {
+			if ad.Matches(ref) {
+				return "excluded by " + describe(ref)
+			}
+			continue
+		}
+		includes++
+		included = included || ad.Matches(ref)
+	}
+	if includes > 0 && !included {
+		return fmt.Sprintf("not one of the %d AdsReferenceId(s) of the slot", includes)
+	}
+	return ""
+}
+
+func describe(ref *scte224.AdsReferenceId) string {
+	if ref.ReferenceType == "" {
+		return strings.TrimSpace(ref.ID)
+	}
+	return ref.ReferenceType + " " + strings.TrimSpace(ref.ID)
+}
+
+// A Rejection is a candidate ad that may not play in a slot, and why.
+type Rejection struct {
+	Ad     *Ad    `json:"ad"`
+	Reason string `json:"reason"`
+}
+
+// An Assignment is the ads of a slot.
+type Assignment struct {
+	Slot *PlacedSlot `json:"slot"`
+	// Eligible are the candidates that may play in the slot, in candidate order.
+	Eligible []*Ad `json:"eligible"`
+	// Rejected are the other candidates.
+	Rejected []Rejection `json:"rejected,omitempty"`
+	// Picked are the ads filling the slot, in play out order.
+	Picked []*Ad `json:"picked"`
+	// Remaining is the time of the slot no picked ad fills.
+	Remaining time.Duration `json:"remaining"`
+}
+
+// Assign picks the ads of each slot of the plan, in the order of the slots: the
+// eligible candidates, in the order given, that pass the SlotRules of the slot
+// and fit in the time of the slot left by the ads picked before them. An ad is
+// picked for one slot of the break at most. The SlotRules of a slot are checked
+// against the ads picked for the break so far; when they cannot be bound, no ad
+// is eligible for the slot.
+func (p *Plan) Assign(candidates []*Ad) []*Assignment {
+	rules := p.Rules
+	if rules == nil {
+		rules = Default
+	}
+	var placed []Placement
+	played := make(map[*Ad]bool)
+	assignments := make([]*Assignment, 0, len(p.Slots))
+	for _, slot := range p.Slots {
+		a := &Assignment{Slot: slot, Remaining: slot.Duration()}
+		check, err := rules.Bind(slot.Slot)
+		for _, ad := range candidates {
+			if ad == nil {
+				continue
+			}
+			reason := Eligible(slot, ad)
+			if reason == "" && err != nil {
+				reason = err.Error()
+			}
+			start := slot.End - a.Remaining
+			if reason == "" && check != nil {
+				reason = check(&Candidate{Ad: ad, Slot: slot, Start: start, Placed: placed})
+			}
+			if reason != "" {
+				a.Rejected = append(a.Rejected, Rejection{Ad: ad, Reason: reason})
+				continue
+			}
+			a.Eligible = append(a.Eligible, ad)
+			if !played[ad] && ad.Duration <= a.Remaining {
+				played[ad] = true
+				a.Picked = append(a.Picked, ad)
+				a.Remaining -= ad.Duration
+				placed = append(placed, Placement{Ad: ad, Slot: slot.Index, Start: start, End: start + ad.Duration})
+			}
+		}
+		assignments = append(assignments, a)
+	}
+	return assignments
+}
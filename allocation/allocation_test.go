@@ -0,0 +1,145 @@
+package allocation
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+const allocation = `<Allocation xmlns="urn:scte:224:action" ownerType="PROVIDER" ownerName="Stu" duration="PT60S" ads="FreeWheel/MRM">
+	<Slots>
+		<Slot duration="PT15S" offset="PT0S">
+			<AdsReferenceId referenceType="campaign">c1</AdsReferenceId>
+			<AdsReferenceId>a3</AdsReferenceId>
+		</Slot>
+		<Slot duration="PT30S" offset="PT15S">
+			<AdsReferenceId referenceType="advertiser" exclude="true">Joes Cola</AdsReferenceId>
+		</Slot>
+		<Slot duration="PT15S" offset="PT45S"/>
+	</Slots>
+</Allocation>`
+
+func unmarshal(t *testing.T, raw string) *scte224.Allocation {
+	alloc := &scte224.Allocation{}
+	if err := xml.Unmarshal([]byte(raw), alloc); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	return alloc
+}
+
+func TestPlan(t *testing.T) {
+	plan := NewPlan(unmarshal(t, allocation))
+	if !plan.Valid() || plan.Duration != time.Minute || len(plan.Slots) != 3 {
+		t.Log("Unexpected plan", plan.Duration, plan.Slots, plan.Problems)
+		t.FailNow()
+	}
+	if s := plan.Slots[1]; s.Start != 15*time.Second || s.End != 45*time.Second || s.Duration() != 30*time.Second {
+		t.Logf("Unexpected slot %+v", s)
+		t.Fail()
+	}
+
+	// the slots of the fixture of the types package overflow their allocation of
+	// PT30S, and a slot out of document order overlaps the first
+	plan = NewPlan(unmarshal(t, `<Allocation xmlns="urn:scte:224:action" duration="PT30S">
+	<Slots>
+		<Slot duration="PT15S" offset="PT0S"/>
+		<Slot duration="PT30S" offset="PT15S"/>
+		<Slot duration="PT15S" offset="PT45S"/>
+	</Slots>
+	<Slots>
+		<Slot duration="PT10S" offset="PT10S"/>
+		<Slot offset="PT20S"/>
+	</Slots>
+</Allocation>`))
+	expected := []Problem{
+		{Kind: MissingDuration, Slot: 4, Message: "slot 4 at 20s has no duration"},
+		{Kind: Overlap, Slot: 0, Other: 3, Message: "slot 0 from 0s to 15s overlaps slot 3 from 10s to 20s"},
+		{Kind: Overlap, Slot: 1, Other: 3, Message: "slot 1 from 15s to 45s overlaps slot 3 from 10s to 20s"},
+		{Kind: Overflow, Slot: 1, Message: "slot 1 from 15s to 45s ends after the allocation of 30s"},
+		{Kind: Overflow, Slot: 2, Message: "slot 2 from 45s to 1m0s ends after the allocation of 30s"},
+	}
+	if len(plan.Problems) != len(expected) {
+		t.Log("Unexpected problems", plan.Problems)
+		t.FailNow()
+	}
+	for i, problem := range plan.Problems {
+		if problem != expected[i] {
+			t.Logf("Problem %d: expected %+v, got %+v", i, expected[i], problem)
+			t.Fail()
+		}
+	}
+
+	if plan := NewPlan(unmarshal(t, `<Allocation xmlns="urn:scte:224:action"><Slots><Slot duration="PT2H"/></Slots></Allocation>`)); !plan.Valid() {
+		t.Log("Slots of an allocation without a duration are unbounded", plan.Problems)
+		t.Fail()
+	}
+}
+
+func TestAssign(t *testing.T) {
+	plan := NewPlan(unmarshal(t, allocation))
+	ads := []*Ad{
+		{Id: "a1", Duration: 15 * time.Second, References: []Reference{{Type: "campaign", Id: "c1"}, {Type: "advertiser", Id: "Joes Cola"}}},
+		{Id: "a2", Duration: 15 * time.Second, References: []Reference{{Type: "advertiser", Id: "c1"}}},
+		{Id: "a3", Duration: 10 * time.Second},
+		{Id: "a4", Duration: 30 * time.Second},
+		{Id: "a5"},
+	}
+	assignments := plan.Assign(ads)
+	if len(assignments) != 3 {
+		t.Log("Unexpected assignments", len(assignments))
+		t.FailNow()
+	}
+
+	expected := []struct {
+		eligible, picked []string
+		remaining        time.Duration
+	}{
+		{[]string{"a1", "a3"}, []string{"a1"}, 0},
+		{[]string{"a2", "a3", "a4"}, []string{"a2", "a3"}, 5 * time.Second},
+		{[]string{"a1", "a2", "a3"}, nil, 15 * time.Second},
+	}
+	ids := func(ads []*Ad) []string {
+		var ids []string
+		for _, ad := range ads {
+			ids = append(ids, ad.Id)
+		}
+		return ids
+	}
+	equal := func(a, b []string) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+	for i, a := range assignments {
+		if !equal(ids(a.Eligible), expected[i].eligible) || !equal(ids(a.Picked), expected[i].picked) || a.Remaining != expected[i].remaining {
+			t.Logf("Slot %d: eligible %v, picked %v, remaining %s", i, ids(a.Eligible), ids(a.Picked), a.Remaining)
+			t.Fail()
+		}
+	}
+
+	reasons := make(map[string]string)
+	for _, r := range assignments[1].Rejected {
+		reasons[r.Ad.Id] = r.Reason
+	}
+	if reasons["a1"] != "excluded by advertiser Joes Cola" || reasons["a5"] != "no duration" {
+		t.Log("Unexpected rejections", reasons)
+		t.Fail()
+	}
+	reasons = make(map[string]string)
+	for _, r := range assignments[0].Rejected {
+		reasons[r.Ad.Id] = r.Reason
+	}
+	if reasons["a2"] != "not one of the 2 AdsReferenceId(s) of the slot" || reasons["a4"] != "30s is longer than the slot of 15s" {
+		t.Log("Unexpected rejections", reasons)
+		t.Fail()
+	}
+}
@@ -0,0 +1,131 @@
+// Package allocation lays out the Slots of an Allocation action on the timeline
+// of its break, checks that they fit, and picks the ads eligible for each slot:
+//
+//	plan := allocation.NewPlan(vp.Allocation)
+//	for _, problem := range plan.Problems {
+//		fmt.Println(problem)
+//	}
+//	for _, a := range plan.Assign(candidates) {
+//		fmt.Println(a.Slot.Start, a.Slot.End, a.Picked)
+//	}
+//
+// Times are offsets from the start of the allocation, as the offsets of slots are.
+// The SlotRules of a slot are honored by the rules of the same name in a
+// Registry: maxDuration, categorySeparation and advertiserExclusion are built in,
+// and applications Register their own.
+package allocation
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+// A ProblemKind classifies the problems of a Plan.
+type ProblemKind string
+
+const (
+	// MissingDuration is a slot without a positive duration.
+	MissingDuration ProblemKind = "missing-duration"
+	// Overflow is a slot ending after the duration of the allocation.
+	Overflow ProblemKind = "overflow"
+	// Overlap is a slot starting before the end of another.
+	Overlap ProblemKind = "overlap"
+)
+
+// A Problem is a slot that does not fit the allocation.
+type Problem struct {
+	Kind ProblemKind `json:"kind"`
+	// Slot is the index of the slot in document order, and Other that of the
+	// slot it overlaps, if any.
+	Slot    int    `json:"slot"`
+	Other   int    `json:"other,omitempty"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	return p.Message
+}
+
+// A PlacedSlot is a slot laid out on the timeline of its break.
+type PlacedSlot struct {
+	// Index is the position of the slot in document order, across Slots elements.
+	Index int           `json:"index"`
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+	Slot  *scte224.Slot `json:"slot"`
+}
+
+// Duration returns the length of the slot.
+func (s *PlacedSlot) Duration() time.Duration {
+	return s.End - s.Start
+}
+
+// A Plan is the layout of the slots of an Allocation.
+type Plan struct {
+	// Duration is that of the allocation; zero when it has none, and its slots
+	// are then not checked against it.
+	Duration time.Duration `json:"duration"`
+	// Slots are ordered by start, then document order.
+	Slots    []*PlacedSlot `json:"slots"`
+	Problems []Problem     `json:"problems,omitempty"`
+	// Rules implement the SlotRules of the slots; the Default registry when nil.
+	Rules *Registry `json:"-"`
+}
+
+// NewPlan lays out the slots of alloc, and records the slots without a duration,
+// those ending after the allocation and those overlapping another.
+func NewPlan(alloc *scte224.Allocation) *Plan {
+	plan := &Plan{Duration: alloc.Duration.GoDuration()}
+	for _, group := range alloc.Slots {
+		if group == nil {
+			continue
+		}
+		for _, slot := range group.AdSlots {
+			if slot == nil {
+				continue
+			}
+			start := slot.Offset.GoDuration()
+			placed := &PlacedSlot{Index: len(plan.Slots), Start: start, End: start + slot.Duration.GoDuration(), Slot: slot}
+			plan.Slots = append(plan.Slots, placed)
+			if placed.End <= placed.Start {
+				plan.problem(Problem{Kind: MissingDuration, Slot: placed.Index, Message: fmt.Sprintf("slot %d at %s has no duration", placed.Index, start)})
+				placed.End = placed.Start
+			}
+		}
+	}
+	sort.SliceStable(plan.Slots, func(i, j int) bool {
+		return plan.Slots[i].Start < plan.Slots[j].Start
+	})
+
+	for i, s := range plan.Slots {
+		if plan.Duration > 0 && s.End > plan.Duration {
+			plan.problem(Problem{Kind: Overflow, Slot: s.Index, Message: fmt.Sprintf("slot %d from %s to %s ends after the allocation of %s", s.Index, s.Start, s.End, plan.Duration)})
+		}
+		for _, next := range plan.Slots[i+1:] {
+			if next.Start >= s.End {
+				break
+			}
+			if next.End == next.Start {
+				continue
+			}
+			first, second := s, next
+			if second.Index < first.Index {
+				first, second = second, first
+			}
+			plan.problem(Problem{Kind: Overlap, Slot: first.Index, Other: second.Index, Message: fmt.Sprintf("slot %d from %s to %s overlaps slot %d from %s to %s", first.Index, first.Start, first.End, second.Index, second.Start, second.End)})
+		}
+	}
+	return plan
+}
+
+func (p *Plan) problem(problem Problem) {
+	p.Problems = append(p.Problems, problem)
+}
+
+// Valid reports whether the slots fit the allocation without overlapping.
+func (p *Plan) Valid() bool {
+	return len(p.Problems) == 0
+}
@@ -0,0 +1,245 @@
+package allocation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Comcast/scte224structs/internal/duration"
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+// The reference types the built-in rules read the advertiser and the categories
+// of an ad from.
+const (
+	AdvertiserReference = "advertiser"
+	CategoryReference   = "category"
+)
+
+// ReferenceIds returns the ids of the references of ad of the given type.
+func (ad *Ad) ReferenceIds(referenceType string) []string {
+	var ids []string
+	for _, r := range ad.References {
+		if r.Type == referenceType {
+			ids = append(ids, r.Id)
+		}
+	}
+	return ids
+}
+
+// Parameters are the values of the Parameters of a SlotRule, by parameterName;
+// a parameter may be repeated.
+type Parameters map[string][]string
+
+// NewParameters returns the parameters of rule, with surrounding whitespace
+// removed from their values.
+func NewParameters(rule *scte224.SlotRule) Parameters {
+	params := make(Parameters)
+	for _, p := range rule.Parameters {
+		if p != nil {
+			params[p.ParameterName] = append(params[p.ParameterName], strings.TrimSpace(p.Value))
+		}
+	}
+	return params
+}
+
+// Get returns the first value of the named parameter, or "".
+func (p Parameters) Get(name string) string {
+	if values := p[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// A Placement is an ad placed in a break.
+type Placement struct {
+	Ad    *Ad           `json:"ad"`
+	Slot  int           `json:"slot"`
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+}
+
+// A Candidate is an ad considered for a slot.
+type Candidate struct {
+	Ad   *Ad
+	Slot *PlacedSlot
+	// Start is the time the ad would start at, after the ads picked for the slot
+	// before it.
+	Start time.Duration
+	// Placed are the ads picked for the break so far, in the order they were
+	// picked.
+	Placed []Placement
+}
+
+// A Check returns why a candidate breaks a rule, or "" when it does not.
+type Check func(c *Candidate) string
+
+// A Rule binds the parameters of a SlotRule, and returns the check of the
+// candidates of its slot. It fails for parameters it cannot use.
+type Rule func(params Parameters) (Check, error)
+
+// Registry maps rule names to their implementations. It is safe for concurrent
+// use.
+type Registry struct {
+	mu    sync.RWMutex
+	rules map[string]Rule
+}
+
+// NewRegistry returns a Registry of the built-in rules: maxDuration,
+// categorySeparation and advertiserExclusion.
+func NewRegistry() *Registry {
+	return &Registry{rules: map[string]Rule{
+		"maxDuration":         MaxDuration,
+		"categorySeparation":  CategorySeparation,
+		"advertiserExclusion": AdvertiserExclusion,
+	}}
+}
+
+// Register sets the implementation of the named rule, replacing any registered
+// before; a nil rule unregisters it.
+func (r *Registry) Register(name string, rule Rule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rule == nil {
+		delete(r.rules, name)
+		return
+	}
+	r.rules[name] = rule
+}
+
+// Bind returns the check of the SlotRules of slot, all of which a candidate must
+// pass. A rule without an implementation cannot be honored, and fails.
+func (r *Registry) Bind(slot *scte224.Slot) (Check, error) {
+	if slot.SlotRules == nil {
+		return nil, nil
+	}
+	var checks []Check
+	var names []string
+	for _, rule := range slot.SlotRules.SlotRule {
+		if rule == nil {
+			continue
+		}
+		r.mu.RLock()
+		implementation, ok := r.rules[rule.Rule]
+		r.mu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("allocation: no implementation of SlotRule %q", rule.Rule)
+		}
+		check, err := implementation(NewParameters(rule))
+		if err != nil {
+			return nil, fmt.Errorf("allocation: SlotRule %s: %v", rule.Rule, err)
+		}
+		checks = append(checks, check)
+		names = append(names, rule.Rule)
+	}
+	if len(checks) == 0 {
+		return nil, nil
+	}
+	return func(c *Candidate) string {
+		for i, check := range checks {
+			if reason := check(c); reason != "" {
+				return names[i] + ": " + reason
+			}
+		}
+		return ""
+	}, nil
+}
+
+// Default is the registry Plans use when they have none of their own.
+var Default = NewRegistry()
+
+// Register sets the implementation of a rule in the Default registry.
+func Register(name string, rule Rule) {
+	Default.Register(name, rule)
+}
+
+// durationParameter returns the named duration parameter, and whether there is one.
+func durationParameter(params Parameters, name string) (time.Duration, bool, error) {
+	value := params.Get(name)
+	if value == "" {
+		return 0, false, nil
+	}
+	d, err := duration.Parse(value)
+	if err != nil {
+		return 0, false, fmt.Errorf("parameter %s: %v", name, err)
+	}
+	return d, true, nil
+}
+
+// MaxDuration rejects ads longer than its duration parameter.
+func MaxDuration(params Parameters) (Check, error) {
+	max, ok, err := durationParameter(params, "duration")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("parameter duration is required")
+	}
+	return func(c *Candidate) string {
+		if c.Ad.Duration > max {
+			return fmt.Sprintf("%s is longer than %s", c.Ad.Duration, max)
+		}
+		return ""
+	}, nil
+}
+
+// CategorySeparation keeps ads of the same category apart: an ad may not start
+// within the separation parameter of the end of an ad of a category it shares,
+// nor end within it of its start, and may not share the break with it when
+// there is no separation parameter. Repeated category parameters restrict the
+// rule to these categories.
+func CategorySeparation(params Parameters) (Check, error) {
+	separation, separated, err := durationParameter(params, "separation")
+	if err != nil {
+		return nil, err
+	}
+	only := make(map[string]bool)
+	for _, category := range params["category"] {
+		only[category] = true
+	}
+	return func(c *Candidate) string {
+		for _, category := range c.Ad.ReferenceIds(CategoryReference) {
+			if len(only) > 0 && !only[category] {
+				continue
+			}
+			for _, placed := range c.Placed {
+				if !contains(placed.Ad.ReferenceIds(CategoryReference), category) {
+					continue
+				}
+				end := c.Start + c.Ad.Duration
+				if !separated || (c.Start < placed.End+separation && placed.Start < end+separation) {
+					return fmt.Sprintf("%s shares the category %s", placed.Ad.Id, category)
+				}
+			}
+		}
+		return ""
+	}, nil
+}
+
+// AdvertiserExclusion rejects the ads of the advertisers of its repeated
+// advertiser parameter.
+func AdvertiserExclusion(params Parameters) (Check, error) {
+	excluded := params["advertiser"]
+	if len(excluded) == 0 {
+		return nil, errors.New("parameter advertiser is required")
+	}
+	return func(c *Candidate) string {
+		for _, advertiser := range c.Ad.ReferenceIds(AdvertiserReference) {
+			if contains(excluded, advertiser) {
+				return "advertiser " + advertiser + " is excluded"
+			}
+		}
+		return ""
+	}, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package allocation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+const ruled = `<Allocation xmlns="urn:scte:224:action" duration="PT80S">
+	<Slots>
+		<Slot duration="PT20S" offset="PT0S">
+			<SlotRules>
+				<SlotRule rule="maxDuration"><Parameter parameterName="duration">PT15S</Parameter></SlotRule>
+			</SlotRules>
+		</Slot>
+		<Slot duration="PT30S" offset="PT20S">
+			<SlotRules>
+				<SlotRule rule="categorySeparation">
+					<Parameter parameterName="category">cars</Parameter>
+					<Parameter parameterName="separation">PT30S</Parameter>
+				</SlotRule>
+				<SlotRule rule="advertiserExclusion">
+					<Parameter parameterName="advertiser">Joes Cola</Parameter>
+					<Parameter parameterName="advertiser">Acme</Parameter>
+				</SlotRule>
+			</SlotRules>
+		</Slot>
+		<Slot duration="PT30S" offset="PT50S">
+			<SlotRules>
+				<SlotRule rule="exclusionWithInference">
+					<Parameter parameterName="advertiser">advertiser_external_id</Parameter>
+				</SlotRule>
+			</SlotRules>
+		</Slot>
+	</Slots>
+</Allocation>`
+
+func ad(id string, seconds int, references ...string) *Ad {
+	a := &Ad{Id: id, Duration: time.Duration(seconds) * time.Second}
+	for _, r := range references {
+		i := strings.Index(r, ":")
+		a.References = append(a.References, Reference{Type: r[:i], Id: r[i+1:]})
+	}
+	return a
+}
+
+func TestSlotRules(t *testing.T) {
+	plan := NewPlan(unmarshal(t, ruled))
+	ads := []*Ad{
+		ad("sedan", 15, "category:cars"),
+		ad("long", 20),
+		ad("cola", 10, "advertiser:Joes Cola"),
+		ad("truck", 10, "category:cars"),
+		ad("soap", 15, "category:household"),
+	}
+	assignments := plan.Assign(ads)
+
+	reasons := make([]map[string]string, len(assignments))
+	for i, a := range assignments {
+		reasons[i] = make(map[string]string)
+		for _, r := range a.Rejected {
+			reasons[i][r.Ad.Id] = r.Reason
+		}
+	}
+	if r := reasons[0]["long"]; r != "maxDuration: 20s is longer than 15s" {
+		t.Log("Unexpected rejection", r)
+		t.Fail()
+	}
+	// the sedan plays from 0s to 15s, and the truck would start at 40s, after
+	// the long ad
+	if r := reasons[1]["truck"]; r != "categorySeparation: sedan shares the category cars" {
+		t.Log("Unexpected rejection", r)
+		t.Fail()
+	}
+	if r := reasons[1]["cola"]; r != "advertiserExclusion: advertiser Joes Cola is excluded" {
+		t.Log("Unexpected rejection", r)
+		t.Fail()
+	}
+	if len(assignments[2].Picked) != 0 || !strings.Contains(reasons[2]["soap"], `no implementation of SlotRule "exclusionWithInference"`) {
+		t.Log("Expected no ad to be picked for an unknown rule", reasons[2])
+		t.Fail()
+	}
+
+	registry := NewRegistry()
+	registry.Register("exclusionWithInference", func(params Parameters) (Check, error) {
+		return func(c *Candidate) string { return "" }, nil
+	})
+	plan.Rules = registry
+	assignments = plan.Assign(ads)
+	var picked []string
+	for _, a := range assignments {
+		for _, ad := range a.Picked {
+			picked = append(picked, ad.Id)
+		}
+	}
+	if strings.Join(picked, " ") != "sedan long cola truck" {
+		t.Log("Unexpected picks", picked)
+		t.Fail()
+	}
+}
+
+func TestCategorySeparation(t *testing.T) {
+	sedan := Placement{Ad: ad("sedan", 15, "category:cars"), Start: 0, End: 15 * time.Second}
+	truck := ad("truck", 10, "category:cars", "category:trucks")
+	for _, test := range []struct {
+		params Parameters
+		start  time.Duration
+		pass   bool
+	}{
+		{Parameters{}, time.Hour, false},
+		{Parameters{"separation": {"PT20S"}}, 30 * time.Second, false},
+		{Parameters{"separation": {"PT20S"}}, 35 * time.Second, true},
+		{Parameters{"separation": {"PT20S"}, "category": {"trucks"}}, 15 * time.Second, true},
+		// a separation without a time part is a whole day, not nothing
+		{Parameters{"separation": {"P1D"}}, time.Hour, false},
+	} {
+		check, err := CategorySeparation(test.params)
+		if nil != err {
+			t.Log(err)
+			t.FailNow()
+		}
+		reason := check(&Candidate{Ad: truck, Start: test.start, Placed: []Placement{sedan}})
+		if (reason == "") != test.pass {
+			t.Logf("%v at %s: expected pass %v, got %q", test.params, test.start, test.pass, reason)
+			t.Fail()
+		}
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	for _, rule := range []*scte224.SlotRule{
+		{Rule: "maxDuration"},
+		{Rule: "maxDuration", Parameters: []*scte224.Parameter{{ParameterName: "duration", Value: "15 seconds"}}},
+		{Rule: "categorySeparation", Parameters: []*scte224.Parameter{{ParameterName: "separation", Value: "PT"}}},
+		{Rule: "advertiserExclusion"},
+		{Rule: "unknown"},
+	} {
+		slot := &scte224.Slot{SlotRules: &scte224.SlotRules{SlotRule: []*scte224.SlotRule{rule}}}
+		if _, err := Default.Bind(slot); nil == err {
+			t.Logf("Expected an error binding %+v", rule)
+			t.Fail()
+		}
+	}
+}
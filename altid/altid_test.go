@@ -0,0 +1,145 @@
+package altid
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+func TestValidate(t *testing.T) {
+	for _, test := range []struct {
+		altIDType, value string
+		valid            bool
+	}{
+		{"EIDR", "10.5240/7791-8534-2C23-9030-8610-5", true},
+		{"EIDR", " 10.5240/7791-8534-2c23-9030-8610-5\n", true},
+		{"EIDR", "10.5240/7791-8534-2C23-9030-8610-6", false},
+		{"EIDR", "10.5240/7791-8534-2C23-9030-8610", false},
+		{"EIDR", "10.5240/7791-8534-2C23-9030-861G-5", false},
+		{"EIDR", "10.5237/9F3A-2B5C", true},
+		{"EIDR", "10.5237/9F3A-2B5C-1", false},
+		{"EIDR", "10.1000/7791-8534-2C23-9030-8610-5", false},
+		{"EIDR", "7791-8534-2C23-9030-8610-5", false},
+		{"Ad-ID", "ABCD1234000", true},
+		{"Ad-ID", "ABCD1234000H", true},
+		{"Ad-ID", "1BCD1234000", false},
+		{"Ad-ID", "ABCD1234000X", false},
+		{ADIAssetIdType, "ABCD0123456789012345", true},
+		{ADIAssetIdType, "ABC00123456789012345", false},
+		{TMSIdType, "EP012345670003", true},
+		{TMSIdType, "SH012345670000", true},
+		{TMSIdType, "SH012345670003", false},
+		{TMSIdType, "XX012345670003", false},
+		{"CallSign", "anything at all", true},
+		{"private:unknown", "", true},
+	} {
+		err := Validate(test.altIDType, test.value)
+		if (err == nil) != test.valid {
+			t.Logf("%s %q: expected valid %v, got %v", test.altIDType, test.value, test.valid, err)
+			t.Fail()
+		}
+	}
+
+	err := Validate("EIDR", "10.5240/7791-8534-2C23-9030-8610-6")
+	if nil == err || !strings.Contains(err.Error(), "check character 6 should be 5") {
+		t.Log("Expected the check character to be reported, got", err)
+		t.Fail()
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("private:house", ValidateADIAssetId)
+	defer Register("private:house", nil)
+	if nil == ValidateAltID(&scte224.AltID{Type: "private:house", Value: "1234"}) {
+		t.Log("Expected the registered validator to be used")
+		t.Fail()
+	}
+	Register("private:house", nil)
+	if err := Validate("private:house", "1234"); nil != err {
+		t.Log("Expected no validator after unregistering, got", err)
+		t.Fail()
+	}
+}
+
+const lineup = `<Media xmlns="http://www.scte.org/schemas/224" id="m1">
+	<AltID type="EIDR">10.5240/7791-8534-2C23-9030-8610-5</AltID>
+	<MediaPoint id="mp1">
+		<AltID type="private:TMS">EP012345670003</AltID>
+		<Apply>
+			<Policy id="p1">
+				<ViewingPolicy id="vp1">
+					<Audience id="a1" match="ANY">
+						<AltID type="CallSign">WXYZ</AltID>
+						<Audience id="a2"><AltID type="CallSign">WABC</AltID></Audience>
+					</Audience>
+				</ViewingPolicy>
+			</Policy>
+		</Apply>
+	</MediaPoint>
+</Media>`
+
+const results = `<Results xmlns="http://www.scte.org/schemas/224">
+	<Media id="m2"><AltID type="EIDR">10.5240/7791-8534-2c23-9030-8610-5</AltID></Media>
+	<Policy id="p1"><AltID type="Ad-ID">ABCD123400</AltID></Policy>
+</Results>`
+
+func TestIndex(t *testing.T) {
+	var m scte224.Media
+	var r scte224.Results
+	if err := xml.Unmarshal([]byte(lineup), &m); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if err := xml.Unmarshal([]byte(results), &r); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	index := NewIndex()
+	for source, v := range map[string]interface{}{"lineup.xml": &m, "results.xml": &r} {
+		if err := index.Add(source, v); nil != err {
+			t.Log(err)
+			t.FailNow()
+		}
+	}
+	if nil == index.Add("bad", m) {
+		t.Log("Expected a Media value to be refused")
+		t.Fail()
+	}
+
+	entries := index.Lookup("EIDR", "10.5240/7791-8534-2C23-9030-8610-5")
+	if len(entries) != 2 {
+		t.Log("Expected the EIDR of both Media, got", len(entries))
+		t.FailNow()
+	}
+	if entries[0].Element != "Media" || entries[0].Object.(*scte224.Media).Id != entries[0].Id {
+		t.Logf("Unexpected entry %+v", entries[0])
+		t.Fail()
+	}
+	if entries := index.Lookup("CallSign", "WABC"); len(entries) != 1 || entries[0].Id != "a2" || entries[0].Source != "lineup.xml" {
+		t.Log("Expected the nested audience, got", entries)
+		t.Fail()
+	}
+	if entries := index.Lookup("private:TMS", "EP012345670003"); len(entries) != 1 || entries[0].Element != "MediaPoint" {
+		t.Log("Expected the MediaPoint, got", entries)
+		t.Fail()
+	}
+	if entries := index.Lookup("CallSign", "wabc"); len(entries) != 0 {
+		t.Log("Call signs are case sensitive, got", entries)
+		t.Fail()
+	}
+
+	if keys := index.Keys(); len(keys) != 5 || keys[0] != (Key{"Ad-ID", "ABCD123400"}) {
+		t.Log("Unexpected keys", keys)
+		t.Fail()
+	}
+	if conflicts := index.Conflicts(); len(conflicts) != 1 || conflicts[0].Type != "EIDR" {
+		t.Log("Expected the EIDR claimed by both Media to conflict, got", conflicts)
+		t.Fail()
+	}
+	if errs := index.Validate(); len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "results.xml: Policy p1: Ad-ID") {
+		t.Log("Expected the short Ad-ID to be invalid, got", errs)
+		t.Fail()
+	}
+}
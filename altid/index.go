@@ -0,0 +1,214 @@
+package altid
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+// A Key is an AltID type and value, normalized.
+type Key struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// NewKey returns the key of an AltID of the given type and value: the value is
+// stripped of surrounding whitespace, and upper-cased for the EIDR and Ad-ID
+// types, whose identifiers are case insensitive.
+func NewKey(altIDType, value string) Key {
+	value = strings.TrimSpace(value)
+	if altIDType == scte224.AltIDEIDR || altIDType == scte224.AltIDAdID {
+		value = strings.ToUpper(value)
+	}
+	return Key{Type: altIDType, Value: value}
+}
+
+// An Entry is an object holding an AltID.
+type Entry struct {
+	// Source names the document of the object, as given to Index.Add.
+	Source string `json:"source"`
+	// Element is the local name of the object: Media, MediaPoint, Policy,
+	// ViewingPolicy or Audience.
+	Element string `json:"element"`
+	// Id is the id of the object, or else its href.
+	Id string `json:"id,omitempty"`
+	// Object is the *scte224.Media, *scte224.MediaPoint and so on.
+	Object interface{}    `json:"-"`
+	AltID  *scte224.AltID `json:"altID"`
+}
+
+// An Index finds the objects of several documents by their AltIDs. It is not
+// safe for concurrent use.
+type Index struct {
+	entries map[Key][]*Entry
+}
+
+// NewIndex returns an empty Index.
+func NewIndex() *Index {
+	return &Index{entries: make(map[Key][]*Entry)}
+}
+
+// Add indexes the AltIDs of v and of the objects it contains, recording source
+// as their document. v is a *scte224.Media, *scte224.MediaPoint, *scte224.Policy,
+// *scte224.ViewingPolicy, *scte224.Audience or *scte224.Results.
+func (ix *Index) Add(source string, v interface{}) error {
+	switch v := v.(type) {
+	case *scte224.Media:
+		ix.addMedia(source, v)
+	case *scte224.MediaPoint:
+		ix.addMediaPoint(source, v)
+	case *scte224.Policy:
+		ix.addPolicy(source, v)
+	case *scte224.ViewingPolicy:
+		ix.addViewingPolicy(source, v)
+	case *scte224.Audience:
+		ix.addAudience(source, v)
+	case *scte224.Results:
+		if v == nil {
+			return nil
+		}
+		for _, m := range v.Medias {
+			ix.addMedia(source, m)
+		}
+		for _, mp := range v.MediaPoints {
+			ix.addMediaPoint(source, mp)
+		}
+		for _, p := range v.Policys {
+			ix.addPolicy(source, p)
+		}
+		for _, vp := range v.ViewingPolicys {
+			ix.addViewingPolicy(source, vp)
+		}
+		for _, aud := range v.Audiences {
+			ix.addAudience(source, aud)
+		}
+	default:
+		return fmt.Errorf("altid: cannot index a %T", v)
+	}
+	return nil
+}
+
+// add indexes the AltIDs of idType, those of object, identified by its id or else
+// its href.
+func (ix *Index) add(source, element string, idType *scte224.IdentifiableType, href string, object interface{}) {
+	id := idType.Id
+	if id == "" {
+		id = href
+	}
+	for _, altID := range idType.AltIDs {
+		if altID == nil {
+			continue
+		}
+		key := NewKey(altID.Type, altID.Value)
+		ix.entries[key] = append(ix.entries[key], &Entry{Source: source, Element: element, Id: id, Object: object, AltID: altID})
+	}
+}
+
+func (ix *Index) addMedia(source string, m *scte224.Media) {
+	if m == nil {
+		return
+	}
+	ix.add(source, "Media", &m.IdentifiableType, m.XLinkHRef, m)
+	for _, mp := range m.MediaPoints {
+		ix.addMediaPoint(source, mp)
+	}
+}
+
+func (ix *Index) addMediaPoint(source string, mp *scte224.MediaPoint) {
+	if mp == nil {
+		return
+	}
+	ix.add(source, "MediaPoint", &mp.IdentifiableType, "", mp)
+	for _, remove := range mp.Removes {
+		if remove != nil {
+			ix.addPolicy(source, remove.Policy)
+		}
+	}
+	for _, apply := range mp.Applys {
+		if apply != nil {
+			ix.addPolicy(source, apply.Policy)
+		}
+	}
+}
+
+func (ix *Index) addPolicy(source string, p *scte224.Policy) {
+	if p == nil {
+		return
+	}
+	ix.add(source, "Policy", &p.IdentifiableType, p.XLinkHRef, p)
+	for _, vp := range p.ViewingPolicys {
+		ix.addViewingPolicy(source, vp)
+	}
+}
+
+func (ix *Index) addViewingPolicy(source string, vp *scte224.ViewingPolicy) {
+	if vp == nil {
+		return
+	}
+	ix.add(source, "ViewingPolicy", &vp.IdentifiableType, vp.XLinkHRef, vp)
+	ix.addAudience(source, vp.Audience)
+}
+
+func (ix *Index) addAudience(source string, aud *scte224.Audience) {
+	if aud == nil {
+		return
+	}
+	ix.add(source, "Audience", &aud.IdentifiableType, aud.XLinkHRef, aud)
+	for _, child := range aud.Audiences {
+		ix.addAudience(source, child)
+	}
+}
+
+// Lookup returns the objects holding an AltID of the given type and value, in
+// the order they were added; values are compared as NewKey normalizes them.
+func (ix *Index) Lookup(altIDType, value string) []*Entry {
+	return ix.entries[NewKey(altIDType, value)]
+}
+
+// Keys returns the keys of the index, sorted by type and value.
+func (ix *Index) Keys() []Key {
+	keys := make([]Key, 0, len(ix.entries))
+	for key := range ix.entries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Type != keys[j].Type {
+			return keys[i].Type < keys[j].Type
+		}
+		return keys[i].Value < keys[j].Value
+	})
+	return keys
+}
+
+// Conflicts returns the keys held by objects of the same element with different
+// ids, such as two Media of different documents claiming the same EIDR.
+func (ix *Index) Conflicts() []Key {
+	var conflicts []Key
+	for _, key := range ix.Keys() {
+		ids := make(map[string]string)
+		for _, entry := range ix.entries[key] {
+			if id, ok := ids[entry.Element]; ok && id != entry.Id {
+				conflicts = append(conflicts, key)
+				break
+			}
+			ids[entry.Element] = entry.Id
+		}
+	}
+	return conflicts
+}
+
+// Validate returns the errors of the AltIDs of the index that are not well-formed,
+// ordered by key, naming the object of each.
+func (ix *Index) Validate() []error {
+	var errs []error
+	for _, key := range ix.Keys() {
+		for _, entry := range ix.entries[key] {
+			if err := ValidateAltID(entry.AltID); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s %s: %v", entry.Source, entry.Element, entry.Id, err))
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,165 @@
+// Package altid validates the alternate identifiers of SCTE 224 objects, and
+// indexes the objects of several documents by them:
+//
+//	index := altid.NewIndex()
+//	index.Add("lineup.xml", &media)
+//	index.Add("policies.xml", &results)
+//	for _, entry := range index.Lookup(scte224.AltIDEIDR, "10.5240/7791-8534-2C23-9030-8610-5") {
+//		fmt.Println(entry.Source, entry.Element, entry.Id)
+//	}
+//
+// The EIDR and Ad-ID types of the 2020 schema are validated as such. Identifiers
+// of other schemes are carried in private types whose names are agreed between
+// partners; validators for ADI Asset_IDs and TMS ids are registered under
+// private:ADI and private:TMS, and applications may Register their own.
+package altid
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Comcast/scte224structs/iso7064"
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+// Private AltID types whose values are validated by default.
+const (
+	ADIAssetIdType = scte224.AltIDPrivatePrefix + "ADI"
+	TMSIdType      = scte224.AltIDPrivatePrefix + "TMS"
+)
+
+// A Validator returns why value is not a well-formed identifier, or nil.
+type Validator func(value string) error
+
+var (
+	validatorsMu sync.RWMutex
+	validators   = map[string]Validator{
+		scte224.AltIDEIDR: ValidateEIDR,
+		scte224.AltIDAdID: ValidateAdId,
+		ADIAssetIdType:    ValidateADIAssetId,
+		TMSIdType:         ValidateTMSId,
+	}
+)
+
+// Register sets the validator of an AltID type, replacing any registered before;
+// a nil validator leaves values of the type unchecked.
+func Register(altIDType string, v Validator) {
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	if v == nil {
+		delete(validators, altIDType)
+		return
+	}
+	validators[altIDType] = v
+}
+
+// Validate checks value with the validator registered for its type, ignoring
+// surrounding whitespace. Values of types without a validator are valid.
+func Validate(altIDType, value string) error {
+	validatorsMu.RLock()
+	v, ok := validators[altIDType]
+	validatorsMu.RUnlock()
+	if !ok {
+		return nil
+	}
+	if err := v(strings.TrimSpace(value)); err != nil {
+		return fmt.Errorf("%s %q: %v", altIDType, strings.TrimSpace(value), err)
+	}
+	return nil
+}
+
+// ValidateAltID validates an AltID by its type.
+func ValidateAltID(a *scte224.AltID) error {
+	return Validate(a.Type, a.Value)
+}
+
+// eidrPrefixes are the DOI prefixes of the EIDR registries, and whether their
+// identifiers end with a check character.
+var eidrPrefixes = map[string]bool{
+	"10.5240": true,  // content
+	"10.5237": false, // party
+	"10.5238": false, // user
+	"10.5239": false, // video service
+}
+
+var hexGroupRegex = regexp.MustCompile(`^[0-9A-Fa-f]{4}$`)
+
+// ValidateEIDR checks an EIDR DOI. Content identifiers, of the prefix 10.5240,
+// have a suffix of five groups of four hexadecimal digits and the ISO 7064 MOD
+// 37,36 check character of these digits; party, user and video service
+// identifiers have two groups and no check character.
+func ValidateEIDR(value string) error {
+	slash := strings.IndexByte(value, '/')
+	if slash < 0 {
+		return errors.New("not a DOI")
+	}
+	checked, ok := eidrPrefixes[value[:slash]]
+	if !ok {
+		return fmt.Errorf("%s is not the prefix of an EIDR registry", value[:slash])
+	}
+	groups := strings.Split(value[slash+1:], "-")
+	expected := 2
+	if checked {
+		expected = 6
+	}
+	if len(groups) != expected {
+		return fmt.Errorf("suffix has %d groups instead of %d", len(groups), expected)
+	}
+	var check string
+	if checked {
+		check, groups = groups[5], groups[:5]
+		if len(check) != 1 {
+			return fmt.Errorf("check character %q is not one character", check)
+		}
+	}
+	for _, group := range groups {
+		if !hexGroupRegex.MatchString(group) {
+			return fmt.Errorf("%q is not four hexadecimal digits", group)
+		}
+	}
+	if checked && !iso7064.ValidMod3736(strings.Join(groups, "")+check) {
+		expected, _ := iso7064.Mod3736(strings.Join(groups, ""))
+		return fmt.Errorf("check character %s should be %c", check, expected)
+	}
+	return nil
+}
+
+var (
+	adIdRegex       = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]{3}[A-Za-z0-9]{7}[HDhd]?$`)
+	adiAssetIdRegex = regexp.MustCompile(`^[A-Za-z]{4}[0-9]{16}$`)
+	tmsIdRegex      = regexp.MustCompile(`^(MV|EP|SH|SP)[0-9]{12}$`)
+)
+
+// ValidateAdId checks an Ad-ID: a four character company prefix starting with
+// a letter and seven alphanumeric characters, followed by H for HD and D for 3D
+// spots.
+func ValidateAdId(value string) error {
+	if !adIdRegex.MatchString(value) {
+		return errors.New("not a 4 character prefix and 7 alphanumeric characters, optionally followed by H or D")
+	}
+	return nil
+}
+
+// ValidateADIAssetId checks an ADI 1.1 Asset_ID: four letters identifying the
+// provider followed by 16 digits.
+func ValidateADIAssetId(value string) error {
+	if !adiAssetIdRegex.MatchString(value) {
+		return errors.New("not 4 letters followed by 16 digits")
+	}
+	return nil
+}
+
+// ValidateTMSId checks a TMS id: MV, EP, SH or SP, eight digits identifying the
+// program and four the episode, which are 0000 for series (SH).
+func ValidateTMSId(value string) error {
+	if !tmsIdRegex.MatchString(value) {
+		return errors.New("not MV, EP, SH or SP followed by 12 digits")
+	}
+	if strings.HasPrefix(value, "SH") && !strings.HasSuffix(value, "0000") {
+		return errors.New("series ids end with 0000")
+	}
+	return nil
+}
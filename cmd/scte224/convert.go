@@ -0,0 +1,384 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/Comcast/scte224structs/convert"
+	scte224_2015 "github.com/Comcast/scte224structs/types/scte224v20151115"
+	scte224_2018 "github.com/Comcast/scte224structs/types/scte224v20180501"
+	scte224_2020 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+func runConvert(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlagSet("convert", "-to VERSION [FILE]")
+	to := fs.String("to", "", "target schema version: 2015, 2018 or 2020")
+	from := fs.String("from", "", "source schema version, detected from the document when empty")
+	asJSON := fs.Bool("json", false, "write the converted document as JSON instead of XML")
+	if err := parseFlags(fs, args, 0, 1); err != nil {
+		return err
+	}
+	if *to == "" {
+		fs.Usage()
+		return errUsage
+	}
+
+	in, err := openInput(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	doc, _, err := readDocument(in, *from)
+	if err != nil {
+		return err
+	}
+	converted, err := convertDocument(doc, *to)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return writeJSON(stdout, converted.value)
+	}
+	return writeXML(stdout, converted.value)
+}
+
+func runJSON(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlagSet("json", "[FILE]")
+	version := fs.String("version", "", "schema version to decode as, detected from the document when empty")
+	if err := parseFlags(fs, args, 0, 1); err != nil {
+		return err
+	}
+
+	in, err := openInput(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	doc, _, err := readDocument(in, *version)
+	if err != nil {
+		return err
+	}
+	return writeJSON(stdout, doc.value)
+}
+
+func runXML(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlagSet("xml", "-version VERSION -root ELEMENT [FILE]")
+	version := fs.String("version", v2020, "schema version of the JSON document")
+	root := fs.String("root", "", "root element of the JSON document, e.g. Media or ViewingPolicy")
+	if err := parseFlags(fs, args, 0, 1); err != nil {
+		return err
+	}
+	if *root == "" {
+		fs.Usage()
+		return errUsage
+	}
+
+	in, err := openInput(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	raw, err := ioutil.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	doc, err := decodeJSON(raw, *version, *root)
+	if err != nil {
+		return err
+	}
+	return writeXML(stdout, doc.value)
+}
+
+// convertDocument walks doc one schema version at a time until it reaches target.
+// Upgrades and downgrades between 2015 and 2018 use the convert package, downgrades
+// from 2020 use the Get2018 and Get2015 methods, and because 2018 and 2020 share a
+// namespace an upgrade from 2018 simply reads the 2018 XML with the 2020 structs.
+func convertDocument(doc *document, target string) (*document, error) {
+	if _, ok := roots[target]; !ok {
+		_, err := newRoot(target, doc.root)
+		return nil, err
+	}
+
+	for doc.version != target {
+		var next interface{}
+		var err error
+		nextVersion := target
+
+		switch {
+		case doc.version == v2015:
+			nextVersion = v2018
+			next, err = upgrade2015(doc.value)
+		case doc.version == v2018 && target == v2015:
+			next, err = downgrade2018(doc.value)
+		case doc.version == v2018:
+			next, err = reinterpret(doc.value, v2020, doc.root)
+		case doc.version == v2020 && target == v2015:
+			next, err = downgrade2020To2015(doc.value)
+		default:
+			next, err = downgrade2020To2018(doc.value, doc.root)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("converting %s from %s to %s: %v", doc.root, doc.version, nextVersion, err)
+		}
+
+		doc = &document{version: nextVersion, root: doc.root, value: next}
+	}
+	return doc, nil
+}
+
+// reinterpret marshals value and decodes the XML into the structs of another version
+// that shares its namespace.
+func reinterpret(value interface{}, version, root string) (interface{}, error) {
+	raw, err := xml.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeXML(raw, version, root)
+	if err != nil {
+		return nil, err
+	}
+	return doc.value, nil
+}
+
+func upgrade2015(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case *scte224_2015.Media:
+		dst := convert.UpgradeMedia(*v)
+		return &dst, nil
+	case *scte224_2015.MediaPoint:
+		dst := convert.UpgradeMediaPoint(*v)
+		return &dst, nil
+	case *scte224_2015.Policy:
+		dst := convert.UpgradePolicy(*v)
+		return &dst, nil
+	case *scte224_2015.ViewingPolicy:
+		dst := convert.UpgradeViewingPolicy(*v)
+		return &dst, nil
+	case *scte224_2015.Audience:
+		dst := convert.UpgradeAudience(*v)
+		return &dst, nil
+	case *scte224_2015.Results:
+		if len(v.Audits) > 0 {
+			return nil, fmt.Errorf("Audit entries cannot be converted")
+		}
+		dst := &scte224_2018.Results{Size: v.Size}
+		for _, media := range v.Medias {
+			if media != nil {
+				upgraded := convert.UpgradeMedia(*media)
+				dst.Medias = append(dst.Medias, &upgraded)
+			}
+		}
+		for _, mp := range v.MediaPoints {
+			if mp != nil {
+				upgraded := convert.UpgradeMediaPoint(*mp)
+				dst.MediaPoints = append(dst.MediaPoints, &upgraded)
+			}
+		}
+		for _, policy := range v.Policys {
+			if policy != nil {
+				upgraded := convert.UpgradePolicy(*policy)
+				dst.Policys = append(dst.Policys, &upgraded)
+			}
+		}
+		for _, vp := range v.ViewingPolicys {
+			if vp != nil {
+				upgraded := convert.UpgradeViewingPolicy(*vp)
+				dst.ViewingPolicys = append(dst.ViewingPolicys, &upgraded)
+			}
+		}
+		for _, aud := range v.Audiences {
+			if aud != nil {
+				upgraded := convert.UpgradeAudience(*aud)
+				dst.Audiences = append(dst.Audiences, &upgraded)
+			}
+		}
+		return dst, nil
+	}
+	return nil, fmt.Errorf("no converter for %T", value)
+}
+
+func downgrade2018(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case *scte224_2018.Media:
+		dst := convert.DowngradeMedia(*v)
+		return &dst, nil
+	case *scte224_2018.MediaPoint:
+		dst := convert.DowngradeMediaPoint(*v)
+		return &dst, nil
+	case *scte224_2018.Policy:
+		dst := convert.DowngradePolicy(*v)
+		return &dst, nil
+	case *scte224_2018.ViewingPolicy:
+		dst := convert.DowngradeViewingPolicy(*v)
+		return &dst, nil
+	case *scte224_2018.Audience:
+		dst := convert.DowngradeAudience(*v)
+		return &dst, nil
+	case *scte224_2018.Results:
+		if len(v.Audits) > 0 {
+			return nil, fmt.Errorf("Audit entries cannot be converted")
+		}
+		dst := &scte224_2015.Results{Size: v.Size}
+		for _, media := range v.Medias {
+			if media != nil {
+				downgraded := convert.DowngradeMedia(*media)
+				dst.Medias = append(dst.Medias, &downgraded)
+			}
+		}
+		for _, mp := range v.MediaPoints {
+			if mp != nil {
+				downgraded := convert.DowngradeMediaPoint(*mp)
+				dst.MediaPoints = append(dst.MediaPoints, &downgraded)
+			}
+		}
+		for _, policy := range v.Policys {
+			if policy != nil {
+				downgraded := convert.DowngradePolicy(*policy)
+				dst.Policys = append(dst.Policys, &downgraded)
+			}
+		}
+		for _, vp := range v.ViewingPolicys {
+			if vp != nil {
+				downgraded := convert.DowngradeViewingPolicy(*vp)
+				dst.ViewingPolicys = append(dst.ViewingPolicys, &downgraded)
+			}
+		}
+		for _, aud := range v.Audiences {
+			if aud != nil {
+				downgraded := convert.DowngradeAudience(*aud)
+				dst.Audiences = append(dst.Audiences, &downgraded)
+			}
+		}
+		return dst, nil
+	}
+	return nil, fmt.Errorf("no converter for %T", value)
+}
+
+func downgrade2020To2018(value interface{}, root string) (interface{}, error) {
+	switch v := value.(type) {
+	case *scte224_2020.Media:
+		dst := v.Get2018()
+		return &dst, nil
+	case *scte224_2020.MediaPoint:
+		dst := v.Get2018()
+		return &dst, nil
+	case *scte224_2020.Policy:
+		dst := v.Get2018()
+		return &dst, nil
+	case *scte224_2020.ViewingPolicy:
+		dst := v.Get2018()
+		return &dst, nil
+	case *scte224_2020.Audience:
+		dst := v.Get2018()
+		return &dst, nil
+	case *scte224_2020.Results:
+		dst := &scte224_2018.Results{Size: v.Size}
+		for _, media := range v.Medias {
+			if media != nil {
+				downgraded := media.Get2018()
+				dst.Medias = append(dst.Medias, &downgraded)
+			}
+		}
+		for _, mp := range v.MediaPoints {
+			if mp != nil {
+				downgraded := mp.Get2018()
+				dst.MediaPoints = append(dst.MediaPoints, &downgraded)
+			}
+		}
+		for _, policy := range v.Policys {
+			if policy != nil {
+				downgraded := policy.Get2018()
+				dst.Policys = append(dst.Policys, &downgraded)
+			}
+		}
+		for _, vp := range v.ViewingPolicys {
+			if vp != nil {
+				downgraded := vp.Get2018()
+				dst.ViewingPolicys = append(dst.ViewingPolicys, &downgraded)
+			}
+		}
+		for _, aud := range v.Audiences {
+			if aud != nil {
+				downgraded := aud.Get2018()
+				dst.Audiences = append(dst.Audiences, &downgraded)
+			}
+		}
+		for _, audit := range v.Audits {
+			if audit != nil {
+				downgraded, err := reinterpret(audit, v2018, "Audit")
+				if err != nil {
+					return nil, err
+				}
+				dst.Audits = append(dst.Audits, downgraded.(*scte224_2018.Audit))
+			}
+		}
+		return dst, nil
+	case *scte224_2020.Audit:
+		// Audit is unchanged between 2018 and 2020 and has no Get2018
+		return reinterpret(v, v2018, root)
+	}
+	return nil, fmt.Errorf("no converter for %T", value)
+}
+
+func downgrade2020To2015(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case *scte224_2020.Media:
+		dst := v.Get2015()
+		return &dst, nil
+	case *scte224_2020.MediaPoint:
+		dst := v.Get2015()
+		return &dst, nil
+	case *scte224_2020.Policy:
+		dst := v.Get2015()
+		return &dst, nil
+	case *scte224_2020.ViewingPolicy:
+		dst := v.Get2015()
+		return &dst, nil
+	case *scte224_2020.Audience:
+		dst := v.Get2015()
+		return &dst, nil
+	case *scte224_2020.Results:
+		if len(v.Audits) > 0 {
+			return nil, fmt.Errorf("Audit entries cannot be converted")
+		}
+		dst := &scte224_2015.Results{Size: v.Size}
+		for _, media := range v.Medias {
+			if media != nil {
+				downgraded := media.Get2015()
+				dst.Medias = append(dst.Medias, &downgraded)
+			}
+		}
+		for _, mp := range v.MediaPoints {
+			if mp != nil {
+				downgraded := mp.Get2015()
+				dst.MediaPoints = append(dst.MediaPoints, &downgraded)
+			}
+		}
+		for _, policy := range v.Policys {
+			if policy != nil {
+				downgraded := policy.Get2015()
+				dst.Policys = append(dst.Policys, &downgraded)
+			}
+		}
+		for _, vp := range v.ViewingPolicys {
+			if vp != nil {
+				downgraded := vp.Get2015()
+				dst.ViewingPolicys = append(dst.ViewingPolicys, &downgraded)
+			}
+		}
+		for _, aud := range v.Audiences {
+			if aud != nil {
+				downgraded := aud.Get2015()
+				dst.Audiences = append(dst.Audiences, &downgraded)
+			}
+		}
+		return dst, nil
+	}
+	return nil, fmt.Errorf("no converter for %T", value)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+func runDiff(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlagSet("diff", "OLD NEW")
+	if err := parseFlags(fs, args, 2, 2); err != nil {
+		return err
+	}
+
+	trees := make([]*node, 2)
+	for i := range trees {
+		in, err := openInput(fs.Arg(i), stdin)
+		if err != nil {
+			return err
+		}
+		raw, err := ioutil.ReadAll(in)
+		in.Close()
+		if err != nil {
+			return err
+		}
+		if trees[i], err = parseTree(raw); err != nil {
+			return fmt.Errorf("%s: %v", fs.Arg(i), err)
+		}
+	}
+
+	changes := diffTrees(trees[0], trees[1])
+	for _, change := range changes {
+		fmt.Fprintln(stdout, change)
+	}
+	if len(changes) > 0 {
+		return fmt.Errorf("documents differ in %d place(s)", len(changes))
+	}
+	return nil
+}
+
+// diffTrees compares two element trees and returns one line per difference.
+// Namespace prefixes, attribute order and surrounding whitespace are ignored, and
+// elements with an id or xlink:href are matched by it rather than by position, so
+// reordered MediaPoints are not reported as rewritten ones.
+func diffTrees(old, new *node) []string {
+	var changes []string
+	if old.name != new.name {
+		return []string{fmt.Sprintf("~ /: root %s -> %s", qualifiedName(old.name), qualifiedName(new.name))}
+	}
+	diffNode("/"+old.label(1), old, new, &changes)
+	return changes
+}
+
+func diffNode(path string, old, new *node, changes *[]string) {
+	for _, attr := range old.attrs {
+		name := qualifiedName(attr.Name)
+		if value, ok := findAttr(new, attr); !ok {
+			*changes = append(*changes, fmt.Sprintf("- %s/@%s: %q", path, name, attr.Value))
+		} else if value != attr.Value {
+			*changes = append(*changes, fmt.Sprintf("~ %s/@%s: %q -> %q", path, name, attr.Value, value))
+		}
+	}
+	for _, attr := range new.attrs {
+		if _, ok := findAttr(old, attr); !ok {
+			*changes = append(*changes, fmt.Sprintf("+ %s/@%s: %q", path, qualifiedName(attr.Name), attr.Value))
+		}
+	}
+
+	if old.text != new.text {
+		*changes = append(*changes, fmt.Sprintf("~ %s/text(): %q -> %q", path, old.text, new.text))
+	}
+
+	oldLabels, oldChildren := old.labeledChildren()
+	newLabels, newChildren := new.labeledChildren()
+	for _, label := range oldLabels {
+		if newChild, ok := newChildren[label]; ok {
+			diffNode(path+"/"+label, oldChildren[label], newChild, changes)
+		} else {
+			*changes = append(*changes, fmt.Sprintf("- %s/%s", path, label))
+		}
+	}
+	for _, label := range newLabels {
+		if _, ok := oldChildren[label]; !ok {
+			*changes = append(*changes, fmt.Sprintf("+ %s/%s", path, label))
+		}
+	}
+}
+
+func findAttr(n *node, attr xml.Attr) (string, bool) {
+	for _, candidate := range n.attrs {
+		if candidate.Name == attr.Name {
+			return candidate.Value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	scte224_2015 "github.com/Comcast/scte224structs/types/scte224v20151115"
+	scte224_2018 "github.com/Comcast/scte224structs/types/scte224v20180501"
+	scte224_2020 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+const (
+	v2015 = "2015"
+	v2018 = "2018"
+	v2020 = "2020"
+
+	esni2015Namespace = "http://www.scte.org/schemas/224/2015"
+	esniNamespace     = "http://www.scte.org/schemas/224"
+	actionNamespace   = "urn:scte:224:action"
+	xlinkNamespace    = "http://www.w3.org/1999/xlink"
+)
+
+var versions = []string{v2015, v2018, v2020}
+
+// roots maps a schema version and a root element name to a constructor for the
+// matching struct from the version's types package.
+var roots = map[string]map[string]func() interface{}{
+	v2015: {
+		"Media":         func() interface{} { return &scte224_2015.Media{} },
+		"MediaPoint":    func() interface{} { return &scte224_2015.MediaPoint{} },
+		"Policy":        func() interface{} { return &scte224_2015.Policy{} },
+		"ViewingPolicy": func() interface{} { return &scte224_2015.ViewingPolicy{} },
+		"Audience":      func() interface{} { return &scte224_2015.Audience{} },
+		"Results":       func() interface{} { return &scte224_2015.Results{} },
+		"Audit":         func() interface{} { return &scte224_2015.Audit{} },
+	},
+	v2018: {
+		"Media":         func() interface{} { return &scte224_2018.Media{} },
+		"MediaPoint":    func() interface{} { return &scte224_2018.MediaPoint{} },
+		"Policy":        func() interface{} { return &scte224_2018.Policy{} },
+		"ViewingPolicy": func() interface{} { return &scte224_2018.ViewingPolicy{} },
+		"Audience":      func() interface{} { return &scte224_2018.Audience{} },
+		"Results":       func() interface{} { return &scte224_2018.Results{} },
+		"Audit":         func() interface{} { return &scte224_2018.Audit{} },
+	},
+	v2020: {
+		"Media":         func() interface{} { return &scte224_2020.Media{} },
+		"MediaPoint":    func() interface{} { return &scte224_2020.MediaPoint{} },
+		"Policy":        func() interface{} { return &scte224_2020.Policy{} },
+		"ViewingPolicy": func() interface{} { return &scte224_2020.ViewingPolicy{} },
+		"Audience":      func() interface{} { return &scte224_2020.Audience{} },
+		"Results":       func() interface{} { return &scte224_2020.Results{} },
+		"Audit":         func() interface{} { return &scte224_2020.Audit{} },
+	},
+}
+
+// document is an SCTE 224 document decoded into the structs of its schema version.
+type document struct {
+	version string
+	root    string
+	value   interface{}
+}
+
+func newRoot(version, root string) (interface{}, error) {
+	byRoot, ok := roots[version]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema version %q (expected one of %s)", version, strings.Join(versions, ", "))
+	}
+	constructor, ok := byRoot[root]
+	if !ok {
+		names := make([]string, 0, len(byRoot))
+		for name := range byRoot {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown root element %q (expected one of %s)", root, strings.Join(names, ", "))
+	}
+	return constructor(), nil
+}
+
+// detectVersion inspects the namespaces used in raw to decide which schema it was
+// written against. 2018 and 2020 share a namespace, so a document is only reported
+// as 2020 when it uses a construct the 2018 schema lacks; anything else is reported
+// as 2018, which the 2020 structs can read as well.
+func detectVersion(raw []byte) (version, root string, err error) {
+	decoder := xml.NewDecoder(bytes.NewReader(raw))
+	for {
+		token, tokenErr := decoder.Token()
+		if tokenErr == io.EOF {
+			break
+		}
+		if tokenErr != nil {
+			return "", "", tokenErr
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		if root == "" {
+			root = start.Name.Local
+			switch start.Name.Space {
+			case esni2015Namespace:
+				return v2015, root, nil
+			case esniNamespace:
+				version = v2018
+			default:
+				return "", "", fmt.Errorf("root element %s is not in an SCTE 224 namespace", qualifiedName(start.Name))
+			}
+		}
+
+		if uses2020Construct(start) {
+			return v2020, root, nil
+		}
+	}
+
+	if root == "" {
+		return "", "", fmt.Errorf("no root element found")
+	}
+	return version, root, nil
+}
+
+// uses2020Construct reports whether start is an element or attribute added in the 2020 schema.
+func uses2020Construct(start xml.StartElement) bool {
+	switch start.Name {
+	case xml.Name{Space: actionNamespace, Local: "Allocation"}:
+		return true
+	case xml.Name{Space: esniNamespace, Local: "AltID"}:
+		return hasAttr(start, "type")
+	case xml.Name{Space: esniNamespace, Local: "MatchSignal"}:
+		return hasAttr(start, "schema")
+	}
+	return false
+}
+
+func hasAttr(start xml.StartElement, local string) bool {
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "" && attr.Name.Local == local {
+			return true
+		}
+	}
+	return false
+}
+
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return "{" + name.Space + "}" + name.Local
+}
+
+// readDocument reads an XML document from r, detecting its version unless one is forced.
+func readDocument(r io.Reader, forceVersion string) (*document, []byte, error) {
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	version, root, err := detectVersion(raw)
+	if err != nil {
+		return nil, raw, err
+	}
+	if forceVersion != "" {
+		if forceVersion == v2015 && version != v2015 || forceVersion != v2015 && version == v2015 {
+			return nil, raw, fmt.Errorf("document uses the %s namespace and cannot be read as %s", version, forceVersion)
+		}
+		version = forceVersion
+	}
+
+	doc, err := decodeXML(raw, version, root)
+	return doc, raw, err
+}
+
+func decodeXML(raw []byte, version, root string) (*document, error) {
+	value, err := newRoot(version, root)
+	if err != nil {
+		return nil, err
+	}
+	if err := xml.Unmarshal(raw, value); err != nil {
+		return nil, err
+	}
+	return &document{version: version, root: root, value: value}, nil
+}
+
+func decodeJSON(raw []byte, version, root string) (*document, error) {
+	value, err := newRoot(version, root)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, value); err != nil {
+		return nil, err
+	}
+	return &document{version: version, root: root, value: value}, nil
+}
+
+func writeXML(w io.Writer, value interface{}) error {
+	out, err := xml.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(out); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func writeJSON(w io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
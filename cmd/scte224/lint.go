@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Comcast/scte224structs/lint"
+)
+
+func runLint(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlagSet("lint", "[FILE]")
+	version := fs.String("version", "", "schema version to decode as, detected from the document when empty")
+	asJSON := fs.Bool("json", false, "write the findings as JSON")
+	disable := fs.String("disable", "", "comma-separated names of rules not to run")
+	if err := parseFlags(fs, args, 0, 1); err != nil {
+		return err
+	}
+
+	linter := lint.New(lint.DefaultRules...)
+	if *disable != "" {
+		for _, name := range strings.Split(*disable, ",") {
+			if !linter.Disable(strings.TrimSpace(name)) {
+				return fmt.Errorf("unknown lint rule %q", name)
+			}
+		}
+	}
+
+	in, err := openInput(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	doc, _, err := readDocument(in, *version)
+	if err != nil {
+		return err
+	}
+	findings, err := linter.Lint(doc.value)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		if err := writeJSON(stdout, findings); err != nil {
+			return err
+		}
+	} else {
+		for _, finding := range findings {
+			fmt.Fprintln(stdout, finding)
+		}
+	}
+	if len(findings) > 0 {
+		return fmt.Errorf("found %d lint finding(s)", len(findings))
+	}
+	return nil
+}
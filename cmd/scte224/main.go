@@ -0,0 +1,118 @@
+// Command scte224 is a small toolbox for working with SCTE 224 (ESNI) documents.
+//
+// It can detect which schema version a document was written against, validate it,
+// convert it between the 2015, 2018 and 2020 schemas, re-serialize it between XML
+// and JSON, print a human-readable schedule summary and diff two documents.
+//
+// Every command that takes a file also accepts "-" (or no argument at all) to read
+// from standard input.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+type command struct {
+	synopsis string
+	run      func(args []string, stdin io.Reader, stdout io.Writer) error
+}
+
+var commands = map[string]command{
+	"version":  {"detect the SCTE 224 schema version and root element of a document", runVersion},
+	"validate": {"check a document against the rules of its schema version", runValidate},
+	"convert":  {"convert a document between the 2015, 2018 and 2020 schemas", runConvert},
+	"json":     {"re-serialize an XML document as JSON", runJSON},
+	"xml":      {"re-serialize a JSON document as XML", runXML},
+	"print":    {"print a human-readable schedule summary of a document", runPrint},
+	"diff":     {"show the structural differences between two documents", runDiff},
+	"lint":     {"report semantic problems such as policies that are never removed", runLint},
+	"timeline": {"list the intervals each audience of a Media is subject to policies", runTimeline},
+	"simulate": {"replay recorded SCTE 35 cues against a Media and list the resulting decisions", runSimulate},
+	"pois":     {"answer ESAM signal processing requests with the viewing policies of a Media", runPOIS},
+	"query":    {"list the objects of documents that match a query such as \"source:EAST zip:80202\"", runQuery},
+}
+
+// errUsage is returned by commands that were invoked with bad arguments; the flag
+// package has already told the user what went wrong by the time it is returned.
+var errUsage = errors.New("usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "-help" {
+		usage(stderr)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "scte224: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	if err := cmd.run(args[1:], stdin, stdout); err != nil {
+		if err == errUsage || err == flag.ErrHelp {
+			return 2
+		}
+		fmt.Fprintf(stderr, "scte224 %s: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: scte224 <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-9s %s\n", name, commands[name].synopsis)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "run \"scte224 <command> -h\" for the flags of a command")
+}
+
+// newFlagSet returns a flag set that reports errors instead of exiting, so commands
+// can be exercised from tests.
+func newFlagSet(name, arguments string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: scte224 %s [flags] %s\n", name, arguments)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args and checks that between min and max positional arguments remain.
+func parseFlags(fs *flag.FlagSet, args []string, min, max int) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() < min || fs.NArg() > max {
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// openInput opens the named file, or returns stdin for "" and "-".
+func openInput(name string, stdin io.Reader) (io.ReadCloser, error) {
+	if name == "" || name == "-" {
+		return ioutil.NopCloser(stdin), nil
+	}
+	return os.Open(name)
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const media2015 = `<Media xmlns="http://www.scte.org/schemas/224/2015" id="test/media/" description="test" lastUpdated="2021-07-27T01:13:25.849Z" source="TEST">
+  <MediaPoint xmlns="http://www.scte.org/schemas/224/2015" id="test/media/program/start" description="new show" effective="2021-07-26T08:58:00Z" expires="2021-07-26T09:02:00Z" matchTime="2021-07-26T09:00:00Z" source="TEST">
+    <AltID xmlns="http://www.scte.org/schemas/224/2015">12345</AltID>
+    <Apply xmlns="http://www.scte.org/schemas/224/2015" duration="PT1H">
+      <Policy xmlns="http://www.scte.org/schemas/224/2015" xmlns:xlink="http://www.w3.org/1999/xlink" xlink:href="test/policy/blackout"></Policy>
+    </Apply>
+    <MatchSignal xmlns="http://www.scte.org/schemas/224/2015" match="ANY">
+      <Assert xmlns="http://www.scte.org/schemas/224/2015">/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=16]</Assert>
+    </MatchSignal>
+  </MediaPoint>
+</Media>`
+
+const media2018 = `<Media xmlns="http://www.scte.org/schemas/224" id="test/media/" description="test" lastUpdated="2021-07-27T01:13:25.849Z" source="TEST">
+  <MediaPoint xmlns="http://www.scte.org/schemas/224" id="test/media/program/start" description="new show" effective="2021-07-26T08:58:00Z" expires="2021-07-26T09:02:00Z" matchTime="2021-07-26T09:00:00Z" source="TEST">
+    <AltID xmlns="http://www.scte.org/schemas/224">12345</AltID>
+    <Apply xmlns="http://www.scte.org/schemas/224" duration="PT1H">
+      <Policy xmlns="http://www.scte.org/schemas/224" xmlns:xlink="http://www.w3.org/1999/xlink" xlink:href="test/policy/blackout"></Policy>
+    </Apply>
+    <MatchSignal xmlns="http://www.scte.org/schemas/224" match="ANY">
+      <Assert xmlns="http://www.scte.org/schemas/224">/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=16]</Assert>
+    </MatchSignal>
+  </MediaPoint>
+</Media>`
+
+// Same as media2018 with a typed AltID, which only the 2020 schema allows
+const media2020 = `<Media xmlns="http://www.scte.org/schemas/224" id="test/media/" description="test" lastUpdated="2021-07-27T01:13:25.849Z" source="TEST">
+  <MediaPoint xmlns="http://www.scte.org/schemas/224" id="test/media/program/start" description="new show" effective="2021-07-26T08:58:00Z" expires="2021-07-26T09:02:00Z" matchTime="2021-07-26T09:00:00Z" source="TEST">
+    <AltID xmlns="http://www.scte.org/schemas/224" type="CallSign">12345</AltID>
+    <Apply xmlns="http://www.scte.org/schemas/224" duration="PT1H">
+      <Policy xmlns="http://www.scte.org/schemas/224" xmlns:xlink="http://www.w3.org/1999/xlink" xlink:href="test/policy/blackout"></Policy>
+    </Apply>
+    <MatchSignal xmlns="http://www.scte.org/schemas/224" match="ANY" schema="http://www.scte.org/schemas/35">
+      <Assert xmlns="http://www.scte.org/schemas/224">/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=16]</Assert>
+    </MatchSignal>
+  </MediaPoint>
+</Media>`
+
+func runCommand(t *testing.T, stdin string, args ...string) (string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	if code != 0 {
+		t.Log(stderr.String())
+	}
+	return stdout.String(), code
+}
+
+func TestVersion(t *testing.T) {
+	for expected, raw := range map[string]string{"2015 Media": media2015, "2018 Media": media2018, "2020 Media": media2020} {
+		out, code := runCommand(t, raw, "version")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, expected+"\n", out)
+	}
+
+	_, code := runCommand(t, `<Media xmlns="urn:not:esni"/>`, "version")
+	assert.Equal(t, 1, code, "foreign namespaces should be rejected")
+}
+
+func TestConvert(t *testing.T) {
+	out, code := runCommand(t, media2015, "convert", "-to", "2018")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, media2018+"\n", out, "Upgrade failed")
+
+	out, code = runCommand(t, media2018, "convert", "-to", "2015")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, media2015+"\n", out, "Downgrade failed")
+
+	out, code = runCommand(t, media2020, "convert", "-to", "2015")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, media2015+"\n", out, "Downgrade from 2020 failed")
+
+	out, code = runCommand(t, media2015, "convert", "-to", "2020")
+	assert.Equal(t, 0, code)
+	version, _ := runCommand(t, out, "version", "-")
+	assert.Equal(t, "2020 Media\n", version, "2015 documents should upgrade to 2020")
+
+	// the 2018 schema has no Allocation, which is carried through it verbatim
+	vp := `<ViewingPolicy xmlns="http://www.scte.org/schemas/224" id="vp"><Allocation xmlns="urn:scte:224:action" duration="PT30S"><Slots><Slot duration="PT15S" offset="PT0S"><AdsReferenceId>1</AdsReferenceId></Slot></Slots></Allocation></ViewingPolicy>`
+	expected, code := runCommand(t, vp, "convert", "-to", "2020")
+	assert.Equal(t, 0, code)
+	out, code = runCommand(t, vp, "convert", "-to", "2018")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, `<Allocation xmlns="urn:scte:224:action" duration="PT30S">`)
+	out, code = runCommand(t, out, "convert", "-to", "2020")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, expected, out, "Allocation lost in the round trip through 2018")
+
+	_, code = runCommand(t, media2020, "convert", "-to", "2017")
+	assert.Equal(t, 1, code)
+}
+
+func TestJSONRoundtrip(t *testing.T) {
+	asJSON, code := runCommand(t, media2020, "json")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, asJSON, `"type": "CallSign"`)
+
+	asXML, code := runCommand(t, asJSON, "xml", "-version", "2020", "-root", "Media")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, media2020+"\n", asXML)
+
+	_, code = runCommand(t, asJSON, "xml", "-version", "2020")
+	assert.Equal(t, 2, code, "-root is required")
+}
+
+func TestValidate(t *testing.T) {
+	out, code := runCommand(t, media2020, "validate")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "valid 2020 Media\n", out)
+
+	invalid := strings.NewReplacer(`match="ANY"`, `match="SOME"`, `duration="PT1H"`, `duration="1 hour"`).Replace(media2020)
+	out, code = runCommand(t, invalid, "validate")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, out, `@match "SOME" is not one of ALL, ANY, NONE`)
+	assert.Contains(t, out, `@duration "1 hour" is not an xs:duration`)
+
+	invalid = strings.Replace(media2020, `type="CallSign">12345`, `type="EIDR">10.5240/7791-8534-2C23-9030-8610-6`, 1)
+	out, code = runCommand(t, invalid, "validate")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, out, `AltID[1]: EIDR "10.5240/7791-8534-2C23-9030-8610-6": check character 6 should be 5`)
+
+	out, code = runCommand(t, media2018, "validate", "-version", "2015")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, out, "cannot be read as 2015")
+}
+
+func TestPrint(t *testing.T) {
+	out, code := runCommand(t, media2015, "print")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "SCTE 224 2015 Media")
+	assert.Contains(t, out, "MediaPoint test/media/program/start \"new show\"")
+	assert.Contains(t, out, "match     at 2021-07-26T09:00:00Z")
+	assert.Contains(t, out, "apply     -> test/policy/blackout (for PT1H)")
+}
+
+func TestDiff(t *testing.T) {
+	trees := make([]*node, 2)
+	for i, raw := range []string{media2018, media2020} {
+		tree, err := parseTree([]byte(raw))
+		assert.Nil(t, err)
+		trees[i] = tree
+	}
+
+	changes := diffTrees(trees[0], trees[1])
+	assert.Equal(t, []string{
+		`+ /Media[@id='test/media/']/MediaPoint[@id='test/media/program/start']/AltID[1]/@type: "CallSign"`,
+		`+ /Media[@id='test/media/']/MediaPoint[@id='test/media/program/start']/MatchSignal[1]/@schema: "http://www.scte.org/schemas/35"`,
+	}, changes)
+
+	// namespace prefixes are not a difference
+	prefixed := `<esni:Media xmlns:esni="http://www.scte.org/schemas/224" id="test/media/"><esni:MediaPoint id="a"/></esni:Media>`
+	inline := `<Media xmlns="http://www.scte.org/schemas/224" id="test/media/"><MediaPoint id="a"></MediaPoint></Media>`
+	a, _ := parseTree([]byte(prefixed))
+	b, _ := parseTree([]byte(inline))
+	assert.Empty(t, diffTrees(a, b))
+}
+
+func TestLint(t *testing.T) {
+	for _, raw := range []string{media2015, media2018, media2020} {
+		out, code := runCommand(t, raw, "lint")
+		assert.Equal(t, 0, code)
+		assert.Empty(t, out)
+	}
+
+	unbounded := strings.Replace(media2020, ` duration="PT1H"`, "", 1)
+	out, code := runCommand(t, unbounded, "lint")
+	assert.Equal(t, 1, code)
+	assert.Equal(t, `warning apply-never-removed /Media[@id='test/media/']/MediaPoint[@id='test/media/program/start']/Apply[1]: policy "test/policy/blackout" is applied without a duration and never removed`+"\n", out)
+
+	out, code = runCommand(t, unbounded, "lint", "-json")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, out, `"severity": "warning"`)
+
+	out, code = runCommand(t, unbounded, "lint", "-disable", "apply-never-removed,duplicate-order")
+	assert.Equal(t, 0, code)
+	assert.Empty(t, out)
+
+	_, code = runCommand(t, unbounded, "lint", "-disable", "no-such-rule")
+	assert.Equal(t, 1, code)
+}
+
+func TestTimeline(t *testing.T) {
+	out, code := runCommand(t, media2015, "timeline")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "unresolved Policy test/policy/blackout\n", out)
+
+	inline := strings.Replace(media2020,
+		`xmlns:xlink="http://www.w3.org/1999/xlink" xlink:href="test/policy/blackout"></Policy>`,
+		`id="test/policy/blackout"><ViewingPolicy id="vp"><Audience id="zip-a"></Audience><Content xmlns="urn:scte:224:action">slate</Content></ViewingPolicy></Policy>`, 1)
+	out, code = runCommand(t, inline, "timeline")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "Audience zip-a\n  2021-07-26T09:00:00Z 2021-07-26T10:00:00Z  content slate  test/policy/blackout/vp\n", out)
+
+	out, code = runCommand(t, inline, "timeline", "-json", "-to", "2021-07-26T09:30:00Z")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, `"end": "2021-07-26T09:30:00Z"`)
+
+	_, code = runCommand(t, inline, "timeline", "-from", "yesterday")
+	assert.Equal(t, 1, code)
+}
+
+func TestSimulate(t *testing.T) {
+	recording, err := ioutil.TempFile("", "cues")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.Remove(recording.Name())
+	_, err = recording.WriteString("2021-07-26T08:59:00Z /DA0AAAAAAAA///wBQb+cr0AUAAeAhxDVUVJSAAAjn/PAAGlmbAICAAAAAAsoKGKNAIAmsnRfg==\n" +
+		"PT1M /DBIAAAAAAAA///wBQb+ek2ItgAyAhdDVUVJSAAAGH+fCAgAAAAALMvDRBEAAAIXQ1VFSUgAABl/nwgIAAAAACyk26AQAACZcuND\n")
+	recording.Close()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	out, code := runCommand(t, media2015, "simulate", "-cues", recording.Name(), "-start", "2021-07-26T08:59:00Z")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, `2021-07-26T08:59:00Z cue line 1
+2021-07-26T09:00:00Z cue line 2
+2021-07-26T09:00:00Z match MediaPoint test/media/program/start by cue on line 2
+2021-07-26T09:00:00Z apply Policy test/policy/blackout by MediaPoint test/media/program/start
+2021-07-26T10:00:00Z expire Policy test/policy/blackout by MediaPoint test/media/program/start
+`, out)
+
+	out, code = runCommand(t, media2015, "simulate", "-json", "-cues", recording.Name(), "-start", "2021-07-26T08:59:00Z", "-stop", "2021-07-26T08:59:30Z")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, `"kind": "cue"`)
+	assert.NotContains(t, out, `"kind": "match"`)
+
+	_, code = runCommand(t, media2015, "simulate")
+	assert.Equal(t, 1, code)
+	_, code = runCommand(t, media2015, "simulate", "-cues", recording.Name())
+	assert.Equal(t, 1, code)
+}
+
+func TestPOIS(t *testing.T) {
+	media, err := ioutil.TempFile("", "media")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.Remove(media.Name())
+	_, err = media.WriteString(`<Media xmlns="http://www.scte.org/schemas/224" xmlns:action="urn:scte:224:action" id="m">
+  <MediaPoint id="start" matchTime="2021-07-26T09:00:00Z">
+    <MatchSignal match="ANY">
+      <Assert>/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=52]</Assert>
+    </MatchSignal>
+    <Apply>
+      <Policy id="p">
+        <ViewingPolicy id="strip">
+          <Audience id="zone-a"/>
+          <action:SignalPointDeletion>true</action:SignalPointDeletion>
+        </ViewingPolicy>
+      </Policy>
+    </Apply>
+  </MediaPoint>
+</Media>`)
+	media.Close()
+	if !assert.NoError(t, err) {
+		return
+	}
+	request := `<SignalProcessingEvent xmlns="urn:cablelabs:iptvservices:esam:xsd:signal:1" xmlns:sig="urn:cablelabs:md:xsd:signaling:3.0">
+  <AcquiredSignal acquisitionPointIdentity="encoder-a" acquisitionSignalID="po-1">
+    <sig:UTCPoint utcPoint="2021-07-26T09:05:00Z"/>
+    <sig:BinaryData signalType="SCTE35">/DA0AAAAAAAA///wBQb+cr0AUAAeAhxDVUVJSAAAjn/PAAGlmbAICAAAAAAsoKGKNAIAmsnRfg==</sig:BinaryData>
+  </AcquiredSignal>
+</SignalProcessingEvent>`
+
+	out, code := runCommand(t, request, "pois", "-media", media.Name(), "-audience", "encoder-a=zone-a")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, `<SignalProcessingNotification xmlns="urn:cablelabs:iptvservices:esam:xsd:signal:1" xmlns:common="urn:cablelabs:iptvservices:esam:xsd:common:1" xmlns:sig="urn:cablelabs:md:xsd:signaling:3.0" acquisitionPointIdentity="encoder-a">
+  <common:StatusCode classCode="0"/>
+  <ResponseSignal action="delete" acquisitionPointIdentity="encoder-a" acquisitionSignalID="po-1">
+    <sig:UTCPoint utcPoint="2021-07-26T09:05:00Z"/>
+  </ResponseSignal>
+</SignalProcessingNotification>
+`, out)
+
+	out, code = runCommand(t, request, "pois", "-media", media.Name())
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, `action="noop"`, "the acquisition point serves no audience")
+
+	_, code = runCommand(t, request, "pois")
+	assert.Equal(t, 1, code)
+	_, code = runCommand(t, request, "pois", "-media", media.Name(), "-audience", "zone-a")
+	assert.Equal(t, 2, code)
+}
+
+func TestUnknownCommand(t *testing.T) {
+	_, code := runCommand(t, "", "frobnicate")
+	assert.Equal(t, 2, code)
+}
+
+func TestQuery(t *testing.T) {
+	out, code := runCommand(t, media2020, "query", "altid:CallSign=123* effective:2021-07-26T09:00:00Z")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, `<Results xmlns="http://www.scte.org/schemas/224">`)
+	assert.Contains(t, out, `id="test/media/"`)
+
+	out, code = runCommand(t, media2018, "query", "-json", "source:OTHER")
+	assert.Equal(t, 0, code)
+	assert.NotContains(t, out, "test/media/")
+
+	_, code = runCommand(t, media2020, "query", "color:red")
+	assert.Equal(t, 1, code)
+	_, code = runCommand(t, media2020, "query")
+	assert.Equal(t, 2, code)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Comcast/scte224structs/esam"
+	"github.com/Comcast/scte224structs/namespace"
+	scte224_2020 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+// audienceFlag collects repeated -audience AP=KEY flags.
+type audienceFlag map[string]string
+
+func (a audienceFlag) String() string {
+	pairs := make([]string, 0, len(a))
+	for ap, key := range a {
+		pairs = append(pairs, ap+"="+key)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (a audienceFlag) Set(value string) error {
+	i := strings.Index(value, "=")
+	if i <= 0 {
+		return fmt.Errorf("%q is not ACQUISITION-POINT=AUDIENCE", value)
+	}
+	a[value[:i]] = value[i+1:]
+	return nil
+}
+
+func runPOIS(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlagSet("pois", "-media FILE [REQUEST...]")
+	mediaFlag := fs.String("media", "", "Media whose viewing policies decide the signals")
+	audiences := audienceFlag{}
+	fs.Var(audiences, "audience", "`ACQUISITION-POINT=AUDIENCE` serves the audience with that id or href to the acquisition point; repeatable")
+	listen := fs.String("listen", "", "serve ESAM requests over HTTP on this address instead of answering recorded ones")
+	verbose := fs.Bool("v", false, "log the decision on each signal to standard error")
+	if err := parseFlags(fs, args, 0, math.MaxInt32); err != nil {
+		return err
+	}
+	if *mediaFlag == "" {
+		return errors.New("-media is required")
+	}
+	if *listen != "" && fs.NArg() > 0 {
+		return errors.New("recorded requests cannot be answered with -listen")
+	}
+	if *mediaFlag == "-" && *listen == "" && (fs.NArg() == 0 || fs.Arg(0) == "-") {
+		return errors.New("the Media and the requests cannot both be read from standard input")
+	}
+
+	in, err := openInput(*mediaFlag, stdin)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	doc, _, err := readDocument(in, "")
+	if err != nil {
+		return err
+	}
+	latest, err := convertDocument(doc, v2020)
+	if err != nil {
+		return err
+	}
+	media, ok := latest.value.(*scte224_2020.Media)
+	if !ok {
+		return fmt.Errorf("pois requires a Media document, not %s", doc.root)
+	}
+
+	decide, err := esam.MediaDecider(media, audiences)
+	if err != nil {
+		return err
+	}
+	pois := &esam.Processor{Decide: decide}
+	if *verbose {
+		pois.Logger = log.New(os.Stderr, "", log.LstdFlags)
+	}
+	if *listen != "" {
+		return http.ListenAndServe(*listen, pois)
+	}
+
+	requests := fs.Args()
+	if len(requests) == 0 {
+		requests = []string{"-"}
+	}
+	enc := esam.NewEncoder(stdout)
+	enc.Indent("", "  ")
+	for _, name := range requests {
+		if err := answer(enc, pois, name, stdin); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout)
+	}
+	return nil
+}
+
+// answer writes the notification answering the SignalProcessingEvent recorded in
+// the named file.
+func answer(enc *namespace.Encoder, pois *esam.Processor, name string, stdin io.Reader) error {
+	in, err := openInput(name, stdin)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	var event esam.SignalProcessingEvent
+	if err := xml.NewDecoder(in).Decode(&event); err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	return enc.Encode(pois.Process(&event))
+}
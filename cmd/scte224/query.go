@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/Comcast/scte224structs/query"
+	scte224_2020 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+func runQuery(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlagSet("query", "QUERY [FILE...]")
+	asJSON := fs.Bool("json", false, "write the matching objects as JSON")
+	if err := parseFlags(fs, args, 1, math.MaxInt32); err != nil {
+		return err
+	}
+	q, err := query.Parse(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	names := fs.Args()[1:]
+	if len(names) == 0 {
+		names = []string{"-"}
+	}
+	// the objects of every document are queried together, as the Results of a
+	// single search
+	results := &scte224_2020.Results{}
+	for _, name := range names {
+		if err := collect(results, name, stdin); err != nil {
+			return err
+		}
+	}
+
+	matching := q.Filter(results)
+	if *asJSON {
+		return writeJSON(stdout, matching)
+	}
+	return writeXML(stdout, matching)
+}
+
+// collect adds the objects of the named document to results.
+func collect(results *scte224_2020.Results, name string, stdin io.Reader) error {
+	in, err := openInput(name, stdin)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	doc, _, err := readDocument(in, "")
+	if err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	latest, err := convertDocument(doc, v2020)
+	if err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	switch v := latest.value.(type) {
+	case *scte224_2020.Media:
+		results.Medias = append(results.Medias, v)
+	case *scte224_2020.MediaPoint:
+		results.MediaPoints = append(results.MediaPoints, v)
+	case *scte224_2020.Policy:
+		results.Policys = append(results.Policys, v)
+	case *scte224_2020.ViewingPolicy:
+		results.ViewingPolicys = append(results.ViewingPolicys, v)
+	case *scte224_2020.Audience:
+		results.Audiences = append(results.Audiences, v)
+	case *scte224_2020.Audit:
+		results.Audits = append(results.Audits, v)
+	case *scte224_2020.Results:
+		results.Medias = append(results.Medias, v.Medias...)
+		results.MediaPoints = append(results.MediaPoints, v.MediaPoints...)
+		results.Policys = append(results.Policys, v.Policys...)
+		results.ViewingPolicys = append(results.ViewingPolicys, v.ViewingPolicys...)
+		results.Audiences = append(results.Audiences, v.Audiences...)
+		results.Audits = append(results.Audits, v.Audits...)
+	}
+	return nil
+}
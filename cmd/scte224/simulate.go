@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Comcast/scte224structs/simulate"
+	scte224_2020 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+func runSimulate(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlagSet("simulate", "-cues RECORDING [FILE]")
+	cuesFlag := fs.String("cues", "", "recording of timestamped SCTE 35 cues, one per line")
+	startFlag := fs.String("start", "", "start of the virtual clock as an xs:dateTime, which cue offsets are from; the first cue when empty")
+	stopFlag := fs.String("stop", "", "end of the virtual clock as an xs:dateTime, unbounded when empty")
+	asJSON := fs.Bool("json", false, "write the events as JSON")
+	if err := parseFlags(fs, args, 0, 1); err != nil {
+		return err
+	}
+	if *cuesFlag == "" {
+		return errors.New("-cues is required")
+	}
+	if *cuesFlag == "-" && (fs.Arg(0) == "" || fs.Arg(0) == "-") {
+		return errors.New("the recording and the Media cannot both be read from standard input")
+	}
+	start, err := parseTimeFlag("start", *startFlag)
+	if err != nil {
+		return err
+	}
+	stop, err := parseTimeFlag("stop", *stopFlag)
+	if err != nil {
+		return err
+	}
+
+	recording, err := openInput(*cuesFlag, stdin)
+	if err != nil {
+		return err
+	}
+	defer recording.Close()
+	cues, err := simulate.ReadCues(recording, start)
+	if err != nil {
+		return err
+	}
+
+	in, err := openInput(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	doc, _, err := readDocument(in, "")
+	if err != nil {
+		return err
+	}
+	latest, err := convertDocument(doc, v2020)
+	if err != nil {
+		return err
+	}
+	media, ok := latest.value.(*scte224_2020.Media)
+	if !ok {
+		return fmt.Errorf("simulate requires a Media document, not %s", doc.root)
+	}
+
+	events, err := simulate.Run(media, cues, simulate.Clock{Start: start, Stop: stop})
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return writeJSON(stdout, events)
+	}
+	s := &summary{w: stdout}
+	for _, e := range events {
+		s.printf(0, "%s", e)
+	}
+	return s.err
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	scte224_2020 "github.com/Comcast/scte224structs/types/scte224v20200407"
+	"github.com/Comcast/scte224structs/types/xsd"
+)
+
+func runPrint(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlagSet("print", "[FILE]")
+	if err := parseFlags(fs, args, 0, 1); err != nil {
+		return err
+	}
+
+	in, err := openInput(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	doc, _, err := readDocument(in, "")
+	if err != nil {
+		return err
+	}
+	// the summary is written against the 2020 structs, which can represent every version
+	latest, err := convertDocument(doc, v2020)
+	if err != nil {
+		return err
+	}
+
+	s := &summary{w: stdout}
+	s.printf(0, "SCTE 224 %s %s", doc.version, doc.root)
+	switch v := latest.value.(type) {
+	case *scte224_2020.Media:
+		s.media(1, v)
+	case *scte224_2020.MediaPoint:
+		s.mediaPoint(1, v)
+	case *scte224_2020.Policy:
+		s.policy(1, v)
+	case *scte224_2020.ViewingPolicy:
+		s.viewingPolicy(1, v)
+	case *scte224_2020.Audience:
+		s.audience(1, v)
+	case *scte224_2020.Audit:
+		s.audit(1, v)
+	case *scte224_2020.Results:
+		s.results(1, v)
+	}
+	return s.err
+}
+
+// summary writes an indented, human-readable outline of SCTE 224 objects.
+type summary struct {
+	w   io.Writer
+	err error
+}
+
+func (s *summary) printf(depth int, format string, args ...interface{}) {
+	if s.err != nil {
+		return
+	}
+	_, s.err = fmt.Fprintf(s.w, "%s%s\n", strings.Repeat("  ", depth), fmt.Sprintf(format, args...))
+}
+
+func (s *summary) results(depth int, results *scte224_2020.Results) {
+	s.printf(depth, "%d Media, %d MediaPoint, %d Policy, %d ViewingPolicy, %d Audience, %d Audit",
+		len(results.Medias), len(results.MediaPoints), len(results.Policys),
+		len(results.ViewingPolicys), len(results.Audiences), len(results.Audits))
+	for _, media := range results.Medias {
+		s.media(depth, media)
+	}
+	for _, mp := range sortedMediaPoints(results.MediaPoints) {
+		s.mediaPoint(depth, mp)
+	}
+	for _, policy := range results.Policys {
+		s.policy(depth, policy)
+	}
+	for _, vp := range results.ViewingPolicys {
+		s.viewingPolicy(depth, vp)
+	}
+	for _, aud := range results.Audiences {
+		s.audience(depth, aud)
+	}
+	for _, audit := range results.Audits {
+		s.audit(depth, audit)
+	}
+}
+
+func (s *summary) media(depth int, media *scte224_2020.Media) {
+	if media == nil {
+		return
+	}
+	s.printf(depth, "Media %s%s", reference(media.Id, media.XLinkHRef), describe(media.Description))
+	if media.Source != "" {
+		s.printf(depth+1, "source    %s", media.Source)
+	}
+	if window := eligibility(media.Effective, media.Expires); window != "" {
+		s.printf(depth+1, "eligible  %s", window)
+	}
+	for _, mp := range sortedMediaPoints(media.MediaPoints) {
+		s.mediaPoint(depth+1, mp)
+	}
+}
+
+func (s *summary) mediaPoint(depth int, mp *scte224_2020.MediaPoint) {
+	if mp == nil {
+		return
+	}
+	order := ""
+	if mp.HasExplicitOrder() {
+		order = fmt.Sprintf(" [order %d]", mp.GetOrder())
+	}
+	s.printf(depth, "MediaPoint %s%s%s", mp.Id, describe(mp.Description), order)
+	if window := eligibility(mp.Effective, mp.Expires); window != "" {
+		s.printf(depth+1, "eligible  %s", window)
+	}
+	if mp.MatchTime != nil {
+		match := formatTime(mp.MatchTime)
+		if mp.MatchOffset != "" {
+			match += " offset " + string(mp.MatchOffset)
+		}
+		s.printf(depth+1, "match     at %s", match)
+	}
+	if ms := mp.MatchSignal; ms != nil {
+		match := string(ms.Match)
+		if match == "" {
+			match = "ALL"
+		}
+		s.printf(depth+1, "match     signal, %s of %d assert(s)", match, len(ms.Assertions))
+		for _, assertion := range ms.Assertions {
+			if assertion != nil {
+				s.printf(depth+2, "%s", strings.TrimSpace(assertion.Declaration))
+			}
+		}
+	}
+	if mp.ExpectedDuration != "" {
+		s.printf(depth+1, "expected  %s", mp.ExpectedDuration)
+	}
+	for _, remove := range mp.Removes {
+		if remove != nil && remove.Policy != nil {
+			s.printf(depth+1, "remove    %s", reference(remove.Policy.Id, remove.Policy.XLinkHRef))
+		}
+	}
+	for _, apply := range mp.Applys {
+		if apply == nil || apply.Policy == nil {
+			continue
+		}
+		var details []string
+		if apply.Duration != "" {
+			details = append(details, "for "+string(apply.Duration))
+		}
+		if apply.HasExplicitPriority() {
+			details = append(details, fmt.Sprintf("priority %d", apply.GetPriority()))
+		}
+		extra := ""
+		if len(details) > 0 {
+			extra = " (" + strings.Join(details, ", ") + ")"
+		}
+		s.printf(depth+1, "apply     %s%s", reference(apply.Policy.Id, apply.Policy.XLinkHRef), extra)
+		for _, vp := range apply.Policy.ViewingPolicys {
+			s.viewingPolicy(depth+2, vp)
+		}
+	}
+}
+
+func (s *summary) policy(depth int, policy *scte224_2020.Policy) {
+	if policy == nil {
+		return
+	}
+	s.printf(depth, "Policy %s%s", reference(policy.Id, policy.XLinkHRef), describe(policy.Description))
+	for _, vp := range policy.ViewingPolicys {
+		s.viewingPolicy(depth+1, vp)
+	}
+}
+
+func (s *summary) viewingPolicy(depth int, vp *scte224_2020.ViewingPolicy) {
+	if vp == nil {
+		return
+	}
+	audience := "(no audience)"
+	if vp.Audience != nil {
+		audience = reference(vp.Audience.Id, vp.Audience.XLinkHRef)
+	}
+	s.printf(depth, "ViewingPolicy %s%s -> %s", reference(vp.Id, vp.XLinkHRef), describe(vp.Description), audience)
+	for _, action := range actions(vp) {
+		s.printf(depth+1, "%s", action)
+	}
+}
+
+func (s *summary) audience(depth int, aud *scte224_2020.Audience) {
+	if aud == nil {
+		return
+	}
+	match := ""
+	if aud.Match != "" {
+		match = " match " + string(aud.Match)
+	}
+	s.printf(depth, "Audience %s%s%s", reference(aud.Id, aud.XLinkHRef), describe(aud.Description), match)
+	for _, property := range aud.AudienceProperty {
+		s.printf(depth+1, "%s = %s", property.XMLName.Local, strings.TrimSpace(property.Value))
+	}
+	for _, nested := range aud.Audiences {
+		s.audience(depth+1, nested)
+	}
+}
+
+func (s *summary) audit(depth int, audit *scte224_2020.Audit) {
+	if audit == nil {
+		return
+	}
+	s.printf(depth, "Audit %s%s trigger=%s result=%s", reference(audit.Id, audit.XLinkHRef), describe(audit.Description), audit.Trigger, audit.Result)
+	for _, nested := range audit.Audits {
+		s.audit(depth+1, nested)
+	}
+}
+
+// actions describes each action carried by a viewing policy on a line of its own.
+func actions(vp *scte224_2020.ViewingPolicy) []string {
+	var lines []string
+	if vp.Content != nil {
+		lines = append(lines, "Content "+strings.TrimSpace(vp.Content.Content))
+	}
+	if vp.SignalPointDeletion != nil {
+		lines = append(lines, "SignalPointDeletion "+strings.TrimSpace(vp.SignalPointDeletion.SignalPointDeletion))
+	}
+	if spi := vp.SignalPointInsertion; spi != nil {
+		lines = append(lines, fmt.Sprintf("SignalPointInsertion %d signal point(s)", len(spi.SignalPoints)))
+	}
+	if alloc := vp.Allocation; alloc != nil {
+		slots := 0
+		for _, group := range alloc.Slots {
+			if group != nil {
+				slots += len(group.AdSlots)
+			}
+		}
+		lines = append(lines, fmt.Sprintf("Allocation %s %s %s, %d slot(s)", alloc.OwnerType, alloc.OwnerName, alloc.Duration, slots))
+	}
+	for _, property := range vp.ActionProperty {
+		lines = append(lines, property.XMLName.Local+" "+strings.TrimSpace(property.Value))
+	}
+	return lines
+}
+
+// sortedMediaPoints orders points the way they will play out: by match time, then
+// eligibility, then explicit order.
+func sortedMediaPoints(points []*scte224_2020.MediaPoint) []*scte224_2020.MediaPoint {
+	sorted := make([]*scte224_2020.MediaPoint, 0, len(points))
+	for _, mp := range points {
+		if mp != nil {
+			sorted = append(sorted, mp)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := scheduledAt(sorted[i]), scheduledAt(sorted[j])
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return sorted[i].GetOrder() < sorted[j].GetOrder()
+	})
+	return sorted
+}
+
+func scheduledAt(mp *scte224_2020.MediaPoint) time.Time {
+	if mp.MatchTime != nil {
+		return mp.MatchTime.Time().Add(mp.MatchOffset.GoDuration())
+	}
+	if mp.Effective != nil {
+		return mp.Effective.Time()
+	}
+	return time.Time{}
+}
+
+func reference(id, href string) string {
+	switch {
+	case id != "":
+		return id
+	case href != "":
+		return "-> " + href
+	}
+	return "(anonymous)"
+}
+
+func describe(description string) string {
+	if description == "" {
+		return ""
+	}
+	return fmt.Sprintf(" %q", description)
+}
+
+func eligibility(effective, expires *xsd.DateTime) string {
+	if effective == nil && expires == nil {
+		return ""
+	}
+	return formatTime(effective) + " .. " + formatTime(expires)
+}
+
+// formatTime prints a time as the document has it.
+func formatTime(t *xsd.DateTime) string {
+	if t == nil {
+		return "(open)"
+	}
+	return t.String()
+}
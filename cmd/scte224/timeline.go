@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Comcast/scte224structs/timeline"
+	scte224_2020 "github.com/Comcast/scte224structs/types/scte224v20200407"
+	"github.com/Comcast/scte224structs/types/xsd"
+)
+
+func runTimeline(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlagSet("timeline", "[FILE]")
+	fromFlag := fs.String("from", "", "start of the range as an xs:dateTime, unbounded when empty")
+	toFlag := fs.String("to", "", "end of the range as an xs:dateTime, unbounded when empty")
+	signals := fs.Bool("signals", false, "place MediaPoints matched by signal only at their effective time")
+	asJSON := fs.Bool("json", false, "write the timelines as JSON")
+	if err := parseFlags(fs, args, 0, 1); err != nil {
+		return err
+	}
+	from, err := parseTimeFlag("from", *fromFlag)
+	if err != nil {
+		return err
+	}
+	to, err := parseTimeFlag("to", *toFlag)
+	if err != nil {
+		return err
+	}
+
+	in, err := openInput(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	doc, _, err := readDocument(in, "")
+	if err != nil {
+		return err
+	}
+	latest, err := convertDocument(doc, v2020)
+	if err != nil {
+		return err
+	}
+	media, ok := latest.value.(*scte224_2020.Media)
+	if !ok {
+		return fmt.Errorf("timeline requires a Media document, not %s", doc.root)
+	}
+
+	var opts []timeline.Option
+	if *signals {
+		opts = append(opts, timeline.WithScheduler(timeline.MatchTimeOrEffective))
+	}
+	report, err := timeline.Calculate(media, from, to, opts...)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return writeJSON(stdout, report)
+	}
+
+	s := &summary{w: stdout}
+	for _, tl := range report.Timelines {
+		if tl.Key == "" {
+			s.printf(0, "Audience (every viewer)")
+		} else {
+			s.printf(0, "Audience %s", tl.Key)
+		}
+		for _, interval := range tl.Intervals {
+			end := "..."
+			if interval.End != nil {
+				end = interval.End.Format(time.RFC3339)
+			}
+			var actions []string
+			for _, action := range interval.Actions {
+				actions = append(actions, action.Policy+"/"+reference(action.ViewingPolicy.Id, action.ViewingPolicy.XLinkHRef))
+			}
+			content := "-"
+			if c := interval.Content(); c != nil {
+				content = c.Content
+			}
+			s.printf(1, "%s %s  content %s  %s", interval.Start.Format(time.RFC3339), end, content, strings.Join(actions, ", "))
+		}
+	}
+	for _, mp := range report.Unscheduled {
+		s.printf(0, "unscheduled MediaPoint %s", mp.Id)
+	}
+	for _, href := range report.Unresolved {
+		s.printf(0, "unresolved Policy %s", href)
+	}
+	return s.err
+}
+
+func parseTimeFlag(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	dt, err := xsd.ParseDateTime(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("-%s: %v", name, err)
+	}
+	return dt.Time(), nil
+}
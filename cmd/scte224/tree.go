@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// node is a namespace-resolved, version-independent view of an XML element. It is used
+// where the typed structs get in the way: validation and diffing.
+type node struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*node
+	text     string
+	line     int
+}
+
+func parseTree(raw []byte) (*node, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(raw))
+	var stack []*node
+	var root *node
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			line, _ := decoder.InputPos()
+			n := &node{name: t.Name, line: line}
+			for _, attr := range t.Attr {
+				// namespace declarations are syntax, not content
+				if attr.Name.Space == "xmlns" || attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+					continue
+				}
+				n.attrs = append(n.attrs, attr)
+			}
+			sort.Slice(n.attrs, func(i, j int) bool {
+				return qualifiedName(n.attrs[i].Name) < qualifiedName(n.attrs[j].Name)
+			})
+
+			if len(stack) == 0 {
+				root = n
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			}
+			stack = append(stack, n)
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		case xml.EndElement:
+			n := stack[len(stack)-1]
+			n.text = strings.TrimSpace(n.text)
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("no root element found")
+	}
+	return root, nil
+}
+
+func (n *node) attr(local string) (string, bool) {
+	for _, attr := range n.attrs {
+		if attr.Name.Space == "" && attr.Name.Local == local {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+// label identifies n among its siblings, preferring its id or xlink:href over its position.
+func (n *node) label(position int) string {
+	if id, ok := n.attr("id"); ok {
+		return fmt.Sprintf("%s[@id='%s']", n.name.Local, id)
+	}
+	for _, attr := range n.attrs {
+		if attr.Name.Space == xlinkNamespace && attr.Name.Local == "href" {
+			return fmt.Sprintf("%s[@href='%s']", n.name.Local, attr.Value)
+		}
+	}
+	return fmt.Sprintf("%s[%d]", n.name.Local, position)
+}
+
+// labeledChildren returns the children of n keyed by their label, in document order.
+func (n *node) labeledChildren() (labels []string, byLabel map[string]*node) {
+	byLabel = make(map[string]*node, len(n.children))
+	positions := make(map[xml.Name]int)
+	for _, child := range n.children {
+		positions[child.name]++
+		label := child.label(positions[child.name])
+		if _, duplicate := byLabel[label]; duplicate {
+			label = fmt.Sprintf("%s[%d]", child.name.Local, positions[child.name])
+		}
+		labels = append(labels, label)
+		byLabel[label] = child
+	}
+	return labels, byLabel
+}
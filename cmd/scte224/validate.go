@@ -0,0 +1,239 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Comcast/scte224structs/altid"
+)
+
+func runVersion(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlagSet("version", "[FILE]")
+	if err := parseFlags(fs, args, 0, 1); err != nil {
+		return err
+	}
+
+	in, err := openInput(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	raw, err := ioutil.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	version, root, err := detectVersion(raw)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%s %s\n", version, root)
+	return nil
+}
+
+func runValidate(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlagSet("validate", "[FILE]")
+	version := fs.String("version", "", "schema version to validate against, detected from the document when empty")
+	if err := parseFlags(fs, args, 0, 1); err != nil {
+		return err
+	}
+
+	in, err := openInput(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	doc, raw, err := readDocument(in, *version)
+	if err != nil && raw == nil {
+		return err
+	}
+
+	var problems []string
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	if tree, treeErr := parseTree(raw); treeErr == nil {
+		docVersion := *version
+		if doc != nil {
+			docVersion = doc.version
+		} else if docVersion == "" {
+			docVersion, _, _ = detectVersion(raw)
+		}
+		problems = append(problems, validateTree(tree, docVersion)...)
+	}
+
+	for _, problem := range problems {
+		fmt.Fprintln(stdout, problem)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("found %d problem(s)", len(problems))
+	}
+	fmt.Fprintf(stdout, "valid %s %s\n", doc.version, doc.root)
+	return nil
+}
+
+var (
+	dateTimeRegex    = regexp.MustCompile(`^-?\d{4,}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})?$`)
+	durationLexRegex = regexp.MustCompile(`^-?P(\d+Y)?(\d+M)?(\d+D)?(T(\d+H)?(\d+M)?(\d+(\.\d+)?S)?)?$`)
+	altIDTypeRegex   = regexp.MustCompile(`^(CallSign|EIDR|Ad-ID|private:.+)$`)
+)
+
+// attribute value checks shared by every element of the ESNI namespaces
+var attrChecks = map[string]func(string) string{
+	"effective":        checkDateTime,
+	"expires":          checkDateTime,
+	"matchTime":        checkDateTime,
+	"lastUpdated":      checkDateTime,
+	"matchOffset":      checkDuration,
+	"expectedDuration": checkDuration,
+	"signalTolerance":  checkDuration,
+	"duration":         checkDuration,
+	"order":            checkNonNegativeInteger,
+	"priority":         checkNonNegativeInteger,
+	"size":             checkNonNegativeInteger,
+	"reusable":         checkBoolean,
+	"match":            checkEnumeration("ALL", "ANY", "NONE"),
+	"policyMode":       checkEnumeration("APPLY", "REMOVE"),
+	"trigger":          checkEnumeration("NONE", "TIME", "SIGNAL", "DURATION", "GET", "PUT", "DELETE", "STATUS", "MANUAL"),
+	"result":           checkEnumeration("SUCCESS", "FAIL"),
+}
+
+// attributes the 2015 schema does not define, by element
+var not2015Attrs = map[string][]string{
+	"MediaPoint": {"expectedDuration", "order", "reusable"},
+	"Apply":      {"priority"},
+	"AltID":      {"description"},
+}
+
+// validateTree checks the rules of the SCTE 224 schemas that decoding into the
+// structs does not enforce: lexical forms, enumerations and required children.
+func validateTree(root *node, version string) []string {
+	var problems []string
+	report := func(n *node, path, format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf("line %d: %s: %s", n.line, path, fmt.Sprintf(format, args...)))
+	}
+
+	var walk func(n *node, path string)
+	walk = func(n *node, path string) {
+		if n.name.Space != esniNamespace && n.name.Space != esni2015Namespace {
+			// extension content is only checked for well-formedness
+			return
+		}
+
+		for _, attr := range n.attrs {
+			if attr.Name.Space != "" {
+				continue
+			}
+			if check, ok := attrChecks[attr.Name.Local]; ok {
+				if message := check(attr.Value); message != "" {
+					report(n, path, "@%s %s", attr.Name.Local, message)
+				}
+			}
+		}
+
+		if version == v2015 {
+			for _, local := range not2015Attrs[n.name.Local] {
+				if _, ok := n.attr(local); ok {
+					report(n, path, "@%s is not defined by the 2015 schema", local)
+				}
+			}
+		}
+
+		switch n.name.Local {
+		case "AltID":
+			if altIDType, ok := n.attr("type"); ok {
+				if version != v2020 {
+					report(n, path, "@type is only defined by the 2020 schema")
+				} else if !altIDTypeRegex.MatchString(altIDType) {
+					report(n, path, "@type %q is not CallSign, EIDR, Ad-ID or private:*", altIDType)
+				} else if err := altid.Validate(altIDType, n.text); err != nil {
+					report(n, path, "%v", err)
+				}
+			}
+		case "MatchSignal":
+			if countChildren(n, "Assert") == 0 {
+				report(n, path, "MatchSignal requires at least one Assert")
+			}
+		case "Apply", "Remove":
+			if count := countChildren(n, "Policy"); count != 1 {
+				report(n, path, "%s requires exactly one Policy, found %d", n.name.Local, count)
+			}
+		case "ViewingPolicy":
+			if len(n.children) > 0 && countChildren(n, "Audience") == 0 && hasActions(n) {
+				report(n, path, "ViewingPolicy actions require an Audience")
+			}
+		}
+
+		labels, children := n.labeledChildren()
+		for _, label := range labels {
+			walk(children[label], path+"/"+label)
+		}
+	}
+
+	walk(root, "/"+root.label(1))
+	return problems
+}
+
+func countChildren(n *node, local string) int {
+	count := 0
+	for _, child := range n.children {
+		if child.name.Local == local && child.name.Space == n.name.Space {
+			count++
+		}
+	}
+	return count
+}
+
+func hasActions(n *node) bool {
+	for _, child := range n.children {
+		if child.name.Space != n.name.Space {
+			return true
+		}
+	}
+	return false
+}
+
+func checkDateTime(value string) string {
+	if !dateTimeRegex.MatchString(value) {
+		return fmt.Sprintf("%q is not an xs:dateTime", value)
+	}
+	return ""
+}
+
+func checkDuration(value string) string {
+	if !durationLexRegex.MatchString(value) || strings.HasSuffix(value, "P") || strings.HasSuffix(value, "T") {
+		return fmt.Sprintf("%q is not an xs:duration", value)
+	}
+	return ""
+}
+
+func checkNonNegativeInteger(value string) string {
+	if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+		return fmt.Sprintf("%q is not a non-negative integer", value)
+	}
+	return ""
+}
+
+func checkBoolean(value string) string {
+	switch value {
+	case "true", "false", "1", "0":
+		return ""
+	}
+	return fmt.Sprintf("%q is not an xs:boolean", value)
+}
+
+func checkEnumeration(allowed ...string) func(string) string {
+	return func(value string) string {
+		for _, candidate := range allowed {
+			if value == candidate {
+				return ""
+			}
+		}
+		return fmt.Sprintf("%q is not one of %s", value, strings.Join(allowed, ", "))
+	}
+}
@@ -21,6 +21,7 @@ func UpgradeIdentifiableType(identifiableType scte224_2015.IdentifiableType) sct
 				XMLName:    node.XMLName,
 				Attributes: node.Attributes,
 				Value:      node.Value,
+				Typed:      node.Typed,
 			}
 		}
 		dst.Ext = &scte224.Ext{
@@ -35,6 +36,7 @@ func UpgradeIdentifiableType(identifiableType scte224_2015.IdentifiableType) sct
 				XMLName:    node.XMLName,
 				Attributes: node.Attributes,
 				Value:      node.Value,
+				Typed:      node.Typed,
 			}
 		}
 		dst.Metadata = &scte224.Metadata{
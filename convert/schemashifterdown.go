@@ -21,6 +21,7 @@ func DowngradeIdentifiableType(identifiableType scte224.IdentifiableType) scte22
 				XMLName:    node.XMLName,
 				Attributes: node.Attributes,
 				Value:      node.Value,
+				Typed:      node.Typed,
 			}
 		}
 		dst.Ext = &scte224_2015.Ext{
@@ -35,6 +36,7 @@ func DowngradeIdentifiableType(identifiableType scte224.IdentifiableType) scte22
 				XMLName:    node.XMLName,
 				Attributes: node.Attributes,
 				Value:      node.Value,
+				Typed:      node.Typed,
 			}
 		}
 
@@ -0,0 +1,197 @@
+// Package cuefilter strips SCTE 35 cues from a stream as the SignalPointDeletion
+// actions of the viewing policies in force for an audience require:
+//
+//	f, err := cuefilter.New(cuefilter.FromActions(&media, interval.Actions))
+//	for _, cue := range cues {
+//		if f.Filter(cue).Pass {
+//			forward(cue)
+//		}
+//	}
+//
+// A SignalPointDeletion is an xs:boolean. True deletes the cues matched by the
+// MatchSignal of the MediaPoint that applied the policy, as the schema describes
+// it. WithExpressions extends this to XPath expressions, which delete the cues
+// they match. Every decision carries its reason, and is logged to the Logger of
+// the Filter when it has one.
+package cuefilter
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Comcast/scte224structs/scte35"
+	"github.com/Comcast/scte224structs/timeline"
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+	"github.com/Comcast/scte224structs/xpath"
+)
+
+// An Active is a ViewingPolicy in force, with the MatchSignal of the MediaPoint
+// that applied it, if any.
+type Active struct {
+	ViewingPolicy *scte224.ViewingPolicy
+	MatchSignal   *scte224.MatchSignal
+}
+
+// FromActions returns the viewing policies of actions, such as those of a
+// timeline.Interval, with the MatchSignals of the MediaPoints of media that
+// applied them.
+func FromActions(media *scte224.Media, actions []*timeline.Action) []Active {
+	signals := make(map[string]*scte224.MatchSignal)
+	for _, mp := range media.MediaPoints {
+		if mp != nil && mp.MatchSignal != nil {
+			signals[mp.Id] = mp.MatchSignal
+		}
+	}
+	active := make([]Active, 0, len(actions))
+	for _, action := range actions {
+		active = append(active, Active{ViewingPolicy: action.ViewingPolicy, MatchSignal: signals[action.MediaPoint]})
+	}
+	return active
+}
+
+// A Decision is what a Filter did with a cue, and why.
+type Decision struct {
+	Pass bool `json:"pass"`
+	// ViewingPolicy is the id, or else the href, of the viewing policy that
+	// deleted the cue; empty for cues passed.
+	ViewingPolicy string `json:"viewingPolicy,omitempty"`
+	Reason        string `json:"reason"`
+}
+
+func (d Decision) String() string {
+	if d.Pass {
+		return "pass: " + d.Reason
+	}
+	return "drop: " + d.Reason
+}
+
+// deletion is a SignalPointDeletion in force: an expression, or a MatchSignal.
+type deletion struct {
+	viewingPolicy string
+	expression    string
+	expr          *xpath.Expr
+	signal        *scte224.MatchSignal
+	matcher       *scte35.Matcher
+}
+
+// A Filter passes or drops cues. It is not safe for concurrent use.
+type Filter struct {
+	deletions []*deletion
+	// Logger receives a line for each decision; nothing is logged when nil.
+	Logger *log.Logger
+
+	expressions bool
+}
+
+// An Option changes how New reads SignalPointDeletions.
+type Option func(*Filter)
+
+// WithExpressions reads a SignalPointDeletion that is not a boolean as an XPath
+// expression, which deletes the cues it matches, evaluated against their XML
+// representation as Asserts are. This is an extension of the schema, which
+// declares SignalPointDeletion an xs:boolean; without it New rejects such values.
+func WithExpressions() Option {
+	return func(f *Filter) { f.expressions = true }
+}
+
+// New returns a Filter for the viewing policies in force. It fails for
+// SignalPointDeletions that are not a boolean, or with WithExpressions a valid
+// XPath expression, and for those that are true without a MatchSignal.
+func New(active []Active, opts ...Option) (*Filter, error) {
+	f := &Filter{}
+	for _, opt := range opts {
+		opt(f)
+	}
+	for _, a := range active {
+		vp := a.ViewingPolicy
+		if vp == nil || vp.SignalPointDeletion == nil {
+			continue
+		}
+		d := &deletion{viewingPolicy: vp.Id}
+		if d.viewingPolicy == "" {
+			d.viewingPolicy = vp.XLinkHRef
+		}
+		value := strings.TrimSpace(vp.SignalPointDeletion.SignalPointDeletion)
+		switch value {
+		case "", "false", "0":
+			continue
+		case "true", "1":
+			if a.MatchSignal == nil {
+				return nil, fmt.Errorf("cuefilter: ViewingPolicy %s deletes signals without a MatchSignal to identify them", d.viewingPolicy)
+			}
+			matcher, err := scte35.NewMatcher(a.MatchSignal)
+			if err != nil {
+				return nil, fmt.Errorf("cuefilter: ViewingPolicy %s: %v", d.viewingPolicy, err)
+			}
+			d.matcher, d.signal = matcher, a.MatchSignal
+		default:
+			if !f.expressions {
+				return nil, fmt.Errorf("cuefilter: ViewingPolicy %s: SignalPointDeletion %q is not a boolean", d.viewingPolicy, value)
+			}
+			expr, err := xpath.Compile(value)
+			if err != nil {
+				return nil, fmt.Errorf("cuefilter: ViewingPolicy %s: %v", d.viewingPolicy, err)
+			}
+			d.expression, d.expr = value, expr
+		}
+		f.deletions = append(f.deletions, d)
+	}
+	return f, nil
+}
+
+// Filter decides whether to pass cue, and logs the decision. A cue is dropped
+// by the first deletion that matches it.
+func (f *Filter) Filter(cue *scte35.SpliceInfoSection) Decision {
+	decision := f.decide(cue)
+	if f.Logger != nil {
+		f.Logger.Printf("cuefilter: %s", decision)
+	}
+	return decision
+}
+
+func (f *Filter) decide(cue *scte35.SpliceInfoSection) Decision {
+	if len(f.deletions) == 0 {
+		return Decision{Pass: true, Reason: "no viewing policy in force deletes signals"}
+	}
+	doc, err := xpath.FromValue(cue)
+	if err != nil {
+		// a cue that cannot be evaluated is passed on rather than lost
+		return Decision{Pass: true, Reason: fmt.Sprintf("cue cannot be evaluated: %v", err)}
+	}
+	for _, d := range f.deletions {
+		if d.expr != nil && d.expr.Matches(doc) {
+			return Decision{ViewingPolicy: d.viewingPolicy, Reason: fmt.Sprintf("ViewingPolicy %s deletes signals matching %s", d.viewingPolicy, d.expression)}
+		}
+		if d.matcher != nil && d.matcher.MatchesNode(doc) {
+			return Decision{ViewingPolicy: d.viewingPolicy, Reason: fmt.Sprintf("ViewingPolicy %s deletes signals matching %s", d.viewingPolicy, describe(d.signal))}
+		}
+	}
+	return Decision{Pass: true, Reason: fmt.Sprintf("none of the %d signal deletions in force matches", len(f.deletions))}
+}
+
+// describe returns the assertions of a MatchSignal and how they combine.
+func describe(ms *scte224.MatchSignal) string {
+	match := string(ms.Match)
+	if match == "" {
+		match = "ALL"
+	}
+	var assertions []string
+	for _, assert := range ms.Assertions {
+		if assert != nil {
+			assertions = append(assertions, strings.TrimSpace(assert.Declaration))
+		}
+	}
+	return fmt.Sprintf("the MatchSignal %s of %s", match, strings.Join(assertions, ", "))
+}
+
+// Apply filters cues, and returns those passed in order.
+func (f *Filter) Apply(cues []*scte35.SpliceInfoSection) []*scte35.SpliceInfoSection {
+	var passed []*scte35.SpliceInfoSection
+	for _, cue := range cues {
+		if f.Filter(cue).Pass {
+			passed = append(passed, cue)
+		}
+	}
+	return passed
+}
@@ -0,0 +1,134 @@
+package cuefilter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Comcast/scte224structs/scte35"
+	"github.com/Comcast/scte224structs/timeline"
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+const media = `<Media xmlns="http://www.scte.org/schemas/224" xmlns:action="urn:scte:224:action" id="m">
+	<MediaPoint id="start" matchTime="2021-07-26T09:00:00Z">
+		<MatchSignal match="ANY">
+			<Assert>/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=52]</Assert>
+		</MatchSignal>
+		<Apply>
+			<Policy id="p">
+				<ViewingPolicy id="strip-po">
+					<action:SignalPointDeletion>true</action:SignalPointDeletion>
+				</ViewingPolicy>
+				<ViewingPolicy id="strip-program">
+					<action:SignalPointDeletion>/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=17]</action:SignalPointDeletion>
+				</ViewingPolicy>
+				<ViewingPolicy id="keep">
+					<action:SignalPointDeletion>false</action:SignalPointDeletion>
+				</ViewingPolicy>
+			</Policy>
+		</Apply>
+	</MediaPoint>
+</Media>`
+
+const (
+	placementOpportunityStart = "/DA0AAAAAAAA///wBQb+cr0AUAAeAhxDVUVJSAAAjn/PAAGlmbAICAAAAAAsoKGKNAIAmsnRfg=="
+	spliceInsertOut           = "/DAvAAAAAAAA///wFAVIAACPf+/+c2nALv4AUsz1AAAAAAAKAAhDVUVJAAABNWLbowo="
+	programEndStart           = "/DBIAAAAAAAA///wBQb+ek2ItgAyAhdDVUVJSAAAGH+fCAgAAAAALMvDRBEAAAIXQ1VFSUgAABl/nwgIAAAAACyk26AQAACZcuND"
+)
+
+func decode(t *testing.T, cues ...string) []*scte35.SpliceInfoSection {
+	var sections []*scte35.SpliceInfoSection
+	for _, cue := range cues {
+		section, err := scte35.DecodeBase64(cue)
+		if nil != err {
+			t.Log(err)
+			t.FailNow()
+		}
+		sections = append(sections, section)
+	}
+	return sections
+}
+
+func TestFilter(t *testing.T) {
+	m := &scte224.Media{}
+	if err := xml.Unmarshal([]byte(media), m); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	report, err := timeline.Calculate(m, m.MediaPoints[0].MatchTime.Time(), m.MediaPoints[0].MatchTime.Time().Add(1))
+	if nil != err || len(report.Timelines) != 1 {
+		t.Log("Unexpected timelines", report, err)
+		t.FailNow()
+	}
+	active := FromActions(m, report.Timelines[0].Intervals[0].Actions)
+	// the expression of strip-program is only read with WithExpressions
+	if _, err := New(active); nil == err || !strings.Contains(err.Error(), "not a boolean") {
+		t.Log("Expected the expression to be rejected", err)
+		t.Fail()
+	}
+	f, err := New(active, WithExpressions())
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	var logged bytes.Buffer
+	f.Logger = log.New(&logged, "", 0)
+
+	cues := decode(t, placementOpportunityStart, spliceInsertOut, programEndStart)
+	passed := f.Apply(cues)
+	if len(passed) != 1 || passed[0] != cues[1] {
+		t.Log("Expected only the splice insert to pass, got", passed)
+		t.Fail()
+	}
+	expected := []string{
+		"cuefilter: drop: ViewingPolicy strip-po deletes signals matching the MatchSignal ANY of /SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=52]",
+		"cuefilter: pass: none of the 2 signal deletions in force matches",
+		"cuefilter: drop: ViewingPolicy strip-program deletes signals matching /SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=17]",
+	}
+	if lines := strings.Split(strings.TrimSpace(logged.String()), "\n"); strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Logf("Unexpected log\n%s", logged.String())
+		t.Fail()
+	}
+
+	if d := f.Filter(cues[2]); d.Pass || d.ViewingPolicy != "strip-program" {
+		t.Log("Unexpected decision", d)
+		t.Fail()
+	}
+	empty, _ := New(nil)
+	empty.Logger = f.Logger
+	if d := empty.Filter(cues[0]); !d.Pass {
+		t.Log("Expected cues to pass without deletions", d)
+		t.Fail()
+	}
+
+	// nothing goes to the standard logger without a Logger
+	var standard bytes.Buffer
+	log.SetOutput(&standard)
+	defer log.SetOutput(os.Stderr)
+	f.Logger = nil
+	f.Apply(cues)
+	if standard.Len() != 0 {
+		t.Log("Expected no log without a Logger, got", standard.String())
+		t.Fail()
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	for _, active := range []Active{
+		{ViewingPolicy: &scte224.ViewingPolicy{SignalPointDeletion: &scte224.SignalPointDeletionAction{SignalPointDeletion: "true"}}},
+		{ViewingPolicy: &scte224.ViewingPolicy{SignalPointDeletion: &scte224.SignalPointDeletionAction{SignalPointDeletion: "/SpliceInfoSection["}}},
+		{
+			ViewingPolicy: &scte224.ViewingPolicy{SignalPointDeletion: &scte224.SignalPointDeletionAction{SignalPointDeletion: "1"}},
+			MatchSignal:   &scte224.MatchSignal{Assertions: []*scte224.Assert{{Declaration: "unknown()"}}},
+		},
+	} {
+		if _, err := New([]Active{active}, WithExpressions()); nil == err {
+			t.Logf("Expected an error for %+v", active.ViewingPolicy.SignalPointDeletion)
+			t.Fail()
+		}
+	}
+}
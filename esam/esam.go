@@ -0,0 +1,102 @@
+// Package esam models the signal processing messages of the CableLabs Event
+// Signaling and Management API (ESAM) exchanged between an acquisition point,
+// such as a transcoder, and a Placement Opportunity Information Service (POIS),
+// and implements a POIS deciding them with SCTE 224 viewing policies:
+//
+//	decide, err := esam.MediaDecider(&media, audiences)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	http.Handle("/esam", &esam.Processor{Decide: decide})
+//
+// An acquisition point posts a SignalProcessingEvent with the SCTE 35 cues it
+// acquired; the POIS answers with a SignalProcessingNotification telling it to
+// delete, replace or keep each of them, and which cues to create.
+package esam
+
+import (
+	"encoding/xml"
+
+	"github.com/Comcast/scte224structs/types/xsd"
+)
+
+const (
+	SignalNamespace    = "urn:cablelabs:iptvservices:esam:xsd:signal:1"
+	SignalingNamespace = "urn:cablelabs:md:xsd:signaling:3.0"
+	CommonNamespace    = "urn:cablelabs:iptvservices:esam:xsd:common:1"
+	CoreNamespace      = "urn:cablelabs:md:xsd:core:3.0"
+)
+
+// Prefixes are the namespace prefixes messages are written with, keyed by
+// namespace; the signal namespace is the default namespace.
+var Prefixes = map[string]string{
+	SignalNamespace:    "",
+	SignalingNamespace: "sig",
+	CommonNamespace:    "common",
+	CoreNamespace:      "core",
+}
+
+// The actions of a ResponseSignal.
+const (
+	ActionCreate  = "create"
+	ActionReplace = "replace"
+	ActionDelete  = "delete"
+	ActionNoop    = "noop"
+)
+
+// SignalTypeSCTE35 is the signalType of BinaryData holding a base64 splice_info_section.
+const SignalTypeSCTE35 = "SCTE35"
+
+// The classCode of a StatusCode.
+const (
+	StatusSuccess = "0"
+	StatusError   = "1"
+)
+
+type SignalProcessingEvent struct {
+	XMLName         xml.Name          `xml:"urn:cablelabs:iptvservices:esam:xsd:signal:1 SignalProcessingEvent" json:"-"`
+	AcquiredSignals []*AcquiredSignal `xml:"urn:cablelabs:iptvservices:esam:xsd:signal:1 AcquiredSignal" json:"acquiredSignals"`
+}
+
+type AcquiredSignal struct {
+	AcquisitionPointIdentity string        `xml:"acquisitionPointIdentity,attr" json:"acquisitionPointIdentity"`
+	AcquisitionSignalId      string        `xml:"acquisitionSignalID,attr,omitempty" json:"acquisitionSignalId,omitempty"`
+	AcquisitionTime          *xsd.DateTime `xml:"acquisitionTime,attr,omitempty" json:"acquisitionTime,omitempty"`
+	ZoneIdentity             string        `xml:"zoneIdentity,attr,omitempty" json:"zoneIdentity,omitempty"`
+	UTCPoint                 *UTCPoint     `xml:"urn:cablelabs:md:xsd:signaling:3.0 UTCPoint,omitempty" json:"utcPoint,omitempty"`
+	BinaryData               *BinaryData   `xml:"urn:cablelabs:md:xsd:signaling:3.0 BinaryData,omitempty" json:"binaryData,omitempty"`
+}
+
+// UTCPoint is the wall clock time of a signal in the stream.
+type UTCPoint struct {
+	UTCPoint *xsd.DateTime `xml:"utcPoint,attr" json:"utcPoint"`
+}
+
+// BinaryData is a signal in base64, a splice_info_section for the signalType SCTE35.
+type BinaryData struct {
+	SignalType string `xml:"signalType,attr,omitempty" json:"signalType,omitempty"`
+	Value      string `xml:",chardata" json:"value"`
+}
+
+type SignalProcessingNotification struct {
+	XMLName                  xml.Name          `xml:"urn:cablelabs:iptvservices:esam:xsd:signal:1 SignalProcessingNotification" json:"-"`
+	AcquisitionPointIdentity string            `xml:"acquisitionPointIdentity,attr,omitempty" json:"acquisitionPointIdentity,omitempty"`
+	StatusCode               *StatusCode       `xml:"urn:cablelabs:iptvservices:esam:xsd:common:1 StatusCode,omitempty" json:"statusCode,omitempty"`
+	ResponseSignals          []*ResponseSignal `xml:"urn:cablelabs:iptvservices:esam:xsd:signal:1 ResponseSignal" json:"responseSignals"`
+}
+
+type StatusCode struct {
+	ClassCode  string   `xml:"classCode,attr" json:"classCode"`
+	DetailCode string   `xml:"detailCode,attr,omitempty" json:"detailCode,omitempty"`
+	Notes      []string `xml:"urn:cablelabs:md:xsd:core:3.0 Note,omitempty" json:"notes,omitempty"`
+}
+
+// A ResponseSignal is the decision for an acquired signal, or a signal to create.
+type ResponseSignal struct {
+	Action                   string      `xml:"action,attr" json:"action"`
+	AcquisitionPointIdentity string      `xml:"acquisitionPointIdentity,attr" json:"acquisitionPointIdentity"`
+	AcquisitionSignalId      string      `xml:"acquisitionSignalID,attr,omitempty" json:"acquisitionSignalId,omitempty"`
+	SignalPointId            string      `xml:"signalPointID,attr,omitempty" json:"signalPointId,omitempty"`
+	UTCPoint                 *UTCPoint   `xml:"urn:cablelabs:md:xsd:signaling:3.0 UTCPoint,omitempty" json:"utcPoint,omitempty"`
+	BinaryData               *BinaryData `xml:"urn:cablelabs:md:xsd:signaling:3.0 BinaryData,omitempty" json:"binaryData,omitempty"`
+}
@@ -0,0 +1,194 @@
+package esam
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Comcast/scte224structs/scte35"
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+	"github.com/Comcast/scte224structs/types/xsd"
+)
+
+const media = `<Media xmlns="http://www.scte.org/schemas/224" xmlns:action="urn:scte:224:action" id="m">
+	<MediaPoint id="start" matchTime="2021-07-26T09:00:00Z">
+		<MatchSignal match="ANY">
+			<Assert>/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=52]</Assert>
+		</MatchSignal>
+		<Apply>
+			<Policy id="p">
+				<ViewingPolicy id="local-ads">
+					<Audience id="zone-a"/>
+					<action:SignalPointDeletion>true</action:SignalPointDeletion>
+					<action:SignalPointInsertion>
+						<action:SignalPoint segmentationEventId="0x100" segmentationTypeId="48" segmentationDuration="2700000"/>
+						<action:SignalPoint offset="PT30S" segmentationEventId="0x100" segmentationTypeId="49"/>
+					</action:SignalPointInsertion>
+				</ViewingPolicy>
+			</Policy>
+		</Apply>
+	</MediaPoint>
+</Media>`
+
+// event is a SignalProcessingEvent recorded from an acquisition point.
+const event = `<SignalProcessingEvent xmlns="urn:cablelabs:iptvservices:esam:xsd:signal:1" xmlns:sig="urn:cablelabs:md:xsd:signaling:3.0">
+	<AcquiredSignal acquisitionPointIdentity="encoder-a" acquisitionSignalID="po-1" acquisitionTime="2021-07-26T09:05:00.2Z">
+		<sig:UTCPoint utcPoint="2021-07-26T09:05:00Z"/>
+		<sig:BinaryData signalType="SCTE35">/DA0AAAAAAAA///wBQb+cr0AUAAeAhxDVUVJSAAAjn/PAAGlmbAICAAAAAAsoKGKNAIAmsnRfg==</sig:BinaryData>
+	</AcquiredSignal>
+	<AcquiredSignal acquisitionPointIdentity="encoder-a" acquisitionSignalID="insert-1">
+		<sig:UTCPoint utcPoint="2021-07-26T09:06:00Z"/>
+		<sig:BinaryData signalType="SCTE35">/DAvAAAAAAAA///wFAVIAACPf+/+c2nALv4AUsz1AAAAAAAKAAhDVUVJAAABNWLbowo=</sig:BinaryData>
+	</AcquiredSignal>
+	<AcquiredSignal acquisitionPointIdentity="encoder-b" acquisitionSignalID="po-2">
+		<sig:UTCPoint utcPoint="2021-07-26T09:05:00Z"/>
+		<sig:BinaryData signalType="SCTE35">/DA0AAAAAAAA///wBQb+cr0AUAAeAhxDVUVJSAAAjn/PAAGlmbAICAAAAAAsoKGKNAIAmsnRfg==</sig:BinaryData>
+	</AcquiredSignal>
+</SignalProcessingEvent>`
+
+func processor(t *testing.T) (*Processor, *bytes.Buffer) {
+	m := &scte224.Media{}
+	if err := xml.Unmarshal([]byte(media), m); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	decide, err := MediaDecider(m, map[string]string{"encoder-a": "zone-a"})
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	var logged bytes.Buffer
+	return &Processor{Decide: decide, Logger: log.New(&logged, "", 0)}, &logged
+}
+
+func TestProcess(t *testing.T) {
+	p, logged := processor(t)
+	var e SignalProcessingEvent
+	if err := xml.Unmarshal([]byte(event), &e); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	n := p.Process(&e)
+	if n.AcquisitionPointIdentity != "encoder-a" || n.StatusCode.ClassCode != StatusSuccess {
+		t.Log("Unexpected notification", n.AcquisitionPointIdentity, n.StatusCode)
+		t.Fail()
+	}
+
+	type response struct{ action, signal, signalPoint, utc string }
+	expected := []response{
+		{ActionReplace, "po-1", "po-1/1", "2021-07-26T09:05:00Z"},
+		{ActionCreate, "", "po-1/2", "2021-07-26T09:05:30Z"},
+		{ActionNoop, "insert-1", "", "2021-07-26T09:06:00Z"},
+		{ActionNoop, "po-2", "", "2021-07-26T09:05:00Z"},
+	}
+	if len(n.ResponseSignals) != len(expected) {
+		t.Log("Unexpected responses", len(n.ResponseSignals))
+		t.FailNow()
+	}
+	for i, r := range n.ResponseSignals {
+		got := response{r.Action, r.AcquisitionSignalId, r.SignalPointId, r.UTCPoint.UTCPoint.String()}
+		if got != expected[i] {
+			t.Logf("Response %d: expected %+v, got %+v", i, expected[i], got)
+			t.Fail()
+		}
+	}
+
+	// the replacement keeps the time of the signal it replaces, the creation is
+	// 30 seconds later
+	for i, offset := range []uint64{0, 30 * 90000} {
+		section, err := scte35.DecodeBase64(n.ResponseSignals[i].BinaryData.Value)
+		if nil != err {
+			t.Log(err)
+			t.FailNow()
+		}
+		if pts := section.TimeSignal.SpliceTime.PTSTime; pts == nil || *pts != 0x72bd0050+offset {
+			t.Log("Unexpected pts", pts)
+			t.Fail()
+		}
+		if d := section.SegmentationDescriptors[0]; d.SegmentationEventId != 0x100 || d.SegmentationTypeId != uint8(48+i) {
+			t.Logf("Unexpected segmentation descriptor %+v", d)
+			t.Fail()
+		}
+	}
+	if !strings.Contains(logged.String(), "cuefilter: drop: ViewingPolicy local-ads deletes signals") {
+		t.Log("Expected the deletion to be logged", logged.String())
+		t.Fail()
+	}
+
+	// a Processor without a Logger, as in the package example, logs nothing
+	var standard bytes.Buffer
+	log.SetOutput(&standard)
+	defer log.SetOutput(os.Stderr)
+	p.Logger = nil
+	p.Process(&e)
+	if standard.Len() != 0 {
+		t.Log("Expected no log without a Logger, got", standard.String())
+		t.Fail()
+	}
+}
+
+func TestProcessErrors(t *testing.T) {
+	p, _ := processor(t)
+	at := time.Date(2021, 7, 26, 9, 5, 0, 0, time.UTC)
+	n := p.Process(&SignalProcessingEvent{AcquiredSignals: []*AcquiredSignal{
+		{AcquisitionPointIdentity: "encoder-a", AcquisitionSignalId: "untimed", BinaryData: &BinaryData{Value: "/DA0"}},
+		{AcquisitionPointIdentity: "encoder-a", AcquisitionSignalId: "garbled", UTCPoint: utcPoint(at), BinaryData: &BinaryData{Value: "not base64"}},
+		{AcquisitionPointIdentity: "encoder-a", AcquisitionSignalId: "other", UTCPoint: utcPoint(at), BinaryData: &BinaryData{SignalType: "DTMF", Value: "1*"}},
+	}})
+	if n.StatusCode.ClassCode != StatusError || len(n.StatusCode.Notes) != 2 {
+		t.Logf("Unexpected status %+v", n.StatusCode)
+		t.Fail()
+	}
+	for _, r := range n.ResponseSignals {
+		if r.Action != ActionNoop {
+			t.Log("Expected undecided signals to be kept, got", r.Action, "for", r.AcquisitionSignalId)
+			t.Fail()
+		}
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	p, _ := processor(t)
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, "application/xml", strings.NewReader(event))
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !bytes.HasPrefix(body, []byte(`<SignalProcessingNotification xmlns="urn:cablelabs:iptvservices:esam:xsd:signal:1"`)) {
+		t.Logf("Unexpected response %d\n%s", resp.StatusCode, body)
+		t.FailNow()
+	}
+	var n SignalProcessingNotification
+	if err := xml.Unmarshal(body, &n); nil != err || len(n.ResponseSignals) != 4 || n.StatusCode.Notes != nil {
+		t.Log("Unexpected notification", err, string(body))
+		t.Fail()
+	}
+
+	resp, err = http.Get(server.URL)
+	if nil != err || resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Log("Expected GET to be refused", err)
+		t.Fail()
+	}
+	resp, err = http.Post(server.URL, "application/xml", strings.NewReader("<SignalProcessingEvent"))
+	if nil != err || resp.StatusCode != http.StatusBadRequest {
+		t.Log("Expected invalid XML to be refused", err)
+		t.Fail()
+	}
+}
+
+func utcPoint(t time.Time) *UTCPoint {
+	dt := xsd.NewDateTime(t)
+	return &UTCPoint{UTCPoint: &dt}
+}
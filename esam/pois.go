@@ -0,0 +1,288 @@
+package esam
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Comcast/scte224structs/cuefilter"
+	"github.com/Comcast/scte224structs/namespace"
+	"github.com/Comcast/scte224structs/scte35"
+	"github.com/Comcast/scte224structs/timeline"
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+	"github.com/Comcast/scte224structs/types/xsd"
+)
+
+// DefaultHorizon bounds the repetitions of the signals a Processor creates when
+// its Horizon is zero.
+const DefaultHorizon = time.Hour
+
+// A Decider returns the viewing policies in force at an acquisition point at a
+// time, with the MatchSignals of the MediaPoints that applied them.
+type Decider func(acquisitionPointIdentity string, at time.Time) ([]cuefilter.Active, error)
+
+// A Processor is a POIS. For each acquired SCTE 35 signal, it asks Decide for the
+// viewing policies in force at the acquisition point at the time of the signal:
+//
+//   - a signal matched by a SignalPointDeletion is deleted;
+//   - a signal matched by the MatchSignal of a policy with a SignalPointInsertion
+//     triggers it: a time_signal is created for each SignalPoint, and for each of
+//     its repetitions within the Horizon;
+//   - a deleted signal that triggers an insertion at its own time is replaced by
+//     the first signal inserted;
+//   - other signals are kept, with the action noop.
+//
+// A Processor is safe for concurrent use when its Decider is.
+type Processor struct {
+	Decide Decider
+	// Horizon bounds repetitions without a repeatStop, from the time of the
+	// acquired signal; DefaultHorizon when zero.
+	Horizon time.Duration
+	// Logger receives the decisions on signals; nothing is logged when nil.
+	Logger *log.Logger
+}
+
+// Process returns the notification answering event. A signal that cannot be
+// decided is kept, with the action noop, and the notification has the status
+// StatusError with a note saying why; it has the status StatusSuccess otherwise.
+func (p *Processor) Process(event *SignalProcessingEvent) *SignalProcessingNotification {
+	notification := &SignalProcessingNotification{StatusCode: &StatusCode{ClassCode: StatusSuccess}}
+	for _, acquired := range event.AcquiredSignals {
+		if acquired == nil {
+			continue
+		}
+		if notification.AcquisitionPointIdentity == "" {
+			notification.AcquisitionPointIdentity = acquired.AcquisitionPointIdentity
+		}
+		responses, err := p.signal(acquired)
+		if err != nil {
+			notification.StatusCode.ClassCode = StatusError
+			notification.StatusCode.Notes = append(notification.StatusCode.Notes, err.Error())
+			responses = []*ResponseSignal{respond(acquired, ActionNoop)}
+		}
+		notification.ResponseSignals = append(notification.ResponseSignals, responses...)
+	}
+	return notification
+}
+
+// respond returns a ResponseSignal for acquired itself.
+func respond(acquired *AcquiredSignal, action string) *ResponseSignal {
+	return &ResponseSignal{
+		Action:                   action,
+		AcquisitionPointIdentity: acquired.AcquisitionPointIdentity,
+		AcquisitionSignalId:      acquired.AcquisitionSignalId,
+		UTCPoint:                 acquired.UTCPoint,
+	}
+}
+
+func (p *Processor) signal(acquired *AcquiredSignal) ([]*ResponseSignal, error) {
+	name := acquired.AcquisitionSignalId
+	if name == "" {
+		name = acquired.AcquisitionPointIdentity
+	}
+	var at time.Time
+	switch {
+	case acquired.UTCPoint != nil && acquired.UTCPoint.UTCPoint != nil && !acquired.UTCPoint.UTCPoint.IsZero():
+		at = acquired.UTCPoint.UTCPoint.Time()
+	case acquired.AcquisitionTime != nil && !acquired.AcquisitionTime.IsZero():
+		at = acquired.AcquisitionTime.Time()
+	default:
+		return nil, fmt.Errorf("esam: signal %s has neither a UTCPoint nor an acquisitionTime", name)
+	}
+	if acquired.BinaryData == nil || (acquired.BinaryData.SignalType != "" && acquired.BinaryData.SignalType != SignalTypeSCTE35) {
+		return []*ResponseSignal{respond(acquired, ActionNoop)}, nil
+	}
+	section, err := scte35.DecodeBase64(acquired.BinaryData.Value)
+	if err != nil {
+		return nil, fmt.Errorf("esam: signal %s: %v", name, err)
+	}
+	if p.Decide == nil {
+		return nil, errors.New("esam: no Decider")
+	}
+	active, err := p.Decide(acquired.AcquisitionPointIdentity, at)
+	if err != nil {
+		return nil, fmt.Errorf("esam: signal %s: %v", name, err)
+	}
+
+	filter, err := cuefilter.New(active)
+	if err != nil {
+		return nil, fmt.Errorf("esam: signal %s: %v", name, err)
+	}
+	filter.Logger = p.Logger
+	decision := filter.Filter(section)
+
+	created, err := p.insertions(acquired, name, section, at, active)
+	if err != nil {
+		return nil, err
+	}
+	if decision.Pass {
+		return append([]*ResponseSignal{respond(acquired, ActionNoop)}, created...), nil
+	}
+	for i, response := range created {
+		if response.UTCPoint.UTCPoint.Time().Equal(at) {
+			response.Action = ActionReplace
+			response.AcquisitionSignalId = acquired.AcquisitionSignalId
+			return append([]*ResponseSignal{response}, append(created[:i:i], created[i+1:]...)...), nil
+		}
+	}
+	return append([]*ResponseSignal{respond(acquired, ActionDelete)}, created...), nil
+}
+
+// insertions returns the signals to create for the SignalPointInsertions that
+// section triggers, in the order of their times.
+func (p *Processor) insertions(acquired *AcquiredSignal, name string, section *scte35.SpliceInfoSection, at time.Time, active []cuefilter.Active) ([]*ResponseSignal, error) {
+	horizon := p.Horizon
+	if horizon == 0 {
+		horizon = DefaultHorizon
+	}
+	pts := presentationTime(section)
+
+	var created []*ResponseSignal
+	for _, a := range active {
+		vp := a.ViewingPolicy
+		if vp == nil || vp.SignalPointInsertion == nil || a.MatchSignal == nil {
+			continue
+		}
+		matcher, err := scte35.NewMatcher(a.MatchSignal)
+		if err != nil {
+			return nil, fmt.Errorf("esam: ViewingPolicy %s: %v", vp.Id, err)
+		}
+		matched, err := matcher.Matches(section)
+		if err != nil {
+			return nil, fmt.Errorf("esam: signal %s: %v", name, err)
+		}
+		if !matched {
+			continue
+		}
+		signals, err := vp.SignalPointInsertion.Expand(at, at.Add(horizon))
+		if err != nil {
+			return nil, fmt.Errorf("esam: ViewingPolicy %s: %v", vp.Id, err)
+		}
+		for _, signal := range signals {
+			response, err := create(acquired, signal, pts)
+			if err != nil {
+				return nil, fmt.Errorf("esam: ViewingPolicy %s: %v", vp.Id, err)
+			}
+			response.SignalPointId = fmt.Sprintf("%s/%d", name, len(created)+1)
+			created = append(created, response)
+		}
+	}
+	return created, nil
+}
+
+// create returns a ResponseSignal creating a time_signal for signal, at pts
+// shifted by the offset of the signal when the time of the acquired signal is
+// known, and immediately otherwise.
+func create(acquired *AcquiredSignal, signal *scte224.ScheduledSignal, pts *uint64) (*ResponseSignal, error) {
+	var at *uint64
+	if pts != nil {
+		shifted := *pts + scte35.Ticks(signal.Offset)
+		at = &shifted
+	}
+	section, err := scte35.NewTimeSignal(signal.SignalPoint, at)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := scte35.EncodeBase64(section)
+	if err != nil {
+		return nil, err
+	}
+	utc := xsd.NewDateTime(signal.At.UTC())
+	return &ResponseSignal{
+		Action:                   ActionCreate,
+		AcquisitionPointIdentity: acquired.AcquisitionPointIdentity,
+		UTCPoint:                 &UTCPoint{UTCPoint: &utc},
+		BinaryData:               &BinaryData{SignalType: SignalTypeSCTE35, Value: encoded},
+	}, nil
+}
+
+// presentationTime returns the PTS of the splice of section, adjusted by its
+// pts_adjustment, or nil for splices without a time.
+func presentationTime(section *scte35.SpliceInfoSection) *uint64 {
+	var t *scte35.SpliceTime
+	switch {
+	case section.TimeSignal != nil:
+		t = &section.TimeSignal.SpliceTime
+	case section.SpliceInsert != nil && section.SpliceInsert.Program != nil:
+		t = section.SpliceInsert.Program.SpliceTime
+	}
+	if t == nil || t.PTSTime == nil {
+		return nil
+	}
+	pts := *t.PTSTime + section.PTSAdjustment
+	return &pts
+}
+
+// ServeHTTP answers a SignalProcessingEvent posted in the request body with a
+// SignalProcessingNotification.
+func (p *Processor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "a SignalProcessingEvent must be POSTed", http.StatusMethodNotAllowed)
+		return
+	}
+	var event SignalProcessingEvent
+	if err := xml.NewDecoder(r.Body).Decode(&event); err != nil {
+		http.Error(w, fmt.Sprintf("invalid SignalProcessingEvent: %v", err), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	if err := NewEncoder(w).Encode(p.Process(&event)); err != nil {
+		p.logf("esam: writing notification: %v", err)
+	}
+}
+
+func (p *Processor) logf(format string, v ...interface{}) {
+	if p.Logger != nil {
+		p.Logger.Printf(format, v...)
+	}
+}
+
+// NewEncoder returns a namespace.Encoder writing ESAM messages to w with the
+// Prefixes, and the SCTE 224 prefixes for the content of policies.
+func NewEncoder(w io.Writer) *namespace.Encoder {
+	enc := namespace.NewEncoder(w)
+	for ns, prefix := range Prefixes {
+		enc.SetPrefix(ns, prefix)
+	}
+	return enc
+}
+
+// MediaDecider returns a Decider for the viewing policies media applies, as
+// timeline.Calculate schedules them, by default at their matchTime or else their
+// effective time. audiences maps acquisition point identities to the key of the
+// audience they serve, as timeline.AudienceKey gives it; every acquisition point
+// also gets the policies of viewing policies without an audience.
+func MediaDecider(media *scte224.Media, audiences map[string]string, opts ...timeline.Option) (Decider, error) {
+	report, err := timeline.Calculate(media, time.Time{}, time.Time{}, append([]timeline.Option{timeline.WithScheduler(timeline.MatchTimeOrEffective)}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	timelines := make(map[string]*timeline.Timeline, len(report.Timelines))
+	for _, tl := range report.Timelines {
+		timelines[tl.Key] = tl
+	}
+	return func(acquisitionPointIdentity string, at time.Time) ([]cuefilter.Active, error) {
+		keys := []string{""}
+		if key, ok := audiences[acquisitionPointIdentity]; ok && key != "" {
+			keys = append(keys, key)
+		}
+		var active []cuefilter.Active
+		for _, key := range keys {
+			tl := timelines[key]
+			if tl == nil {
+				continue
+			}
+			for _, interval := range tl.Intervals {
+				if !at.Before(interval.Start) && (interval.End == nil || at.Before(*interval.End)) {
+					active = append(active, cuefilter.FromActions(media, interval.Actions)...)
+				}
+			}
+		}
+		return active, nil
+	}, nil
+}
@@ -0,0 +1,83 @@
+// Package extension lets applications decode the proprietary nodes partners put in
+// the Metadata and Ext elements of SCTE 224 objects into Go types of their own.
+//
+// A type is registered for the namespace and local name of an element:
+//
+//	extension.Register(xml.Name{Space: "urn:example:rights", Local: "RightsWindow"}, RightsWindow{})
+//
+// From then on, the Metadata and Ext structs of every schema version decode such
+// elements into a *RightsWindow held by the Typed field of the node, and encode
+// them back from it. Elements without a registered type are kept as raw XML.
+package extension
+
+import (
+	"encoding/xml"
+	"reflect"
+	"sync"
+)
+
+// Registry maps element names to the types their nodes are decoded into. It is
+// safe for concurrent use.
+type Registry struct {
+	mu    sync.RWMutex
+	types map[xml.Name]reflect.Type
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{types: make(map[xml.Name]reflect.Type)}
+}
+
+// Register records the type of prototype, which may be a value or a pointer, for
+// elements with the given name, replacing any type registered before. It panics
+// if prototype is nil, like other registration functions of the standard library.
+func (r *Registry) Register(name xml.Name, prototype interface{}) {
+	if prototype == nil {
+		panic("extension: Register of nil prototype for " + name.Space + " " + name.Local)
+	}
+	t := reflect.TypeOf(prototype)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.types[name] = t
+}
+
+// Unregister forgets the type registered for name, if any.
+func (r *Registry) Unregister(name xml.Name) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.types, name)
+}
+
+// New returns a pointer to a new value of the type registered for name, or false
+// if there is none.
+func (r *Registry) New(name xml.Name) (interface{}, bool) {
+	r.mu.RLock()
+	t, ok := r.types[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return reflect.New(t).Interface(), true
+}
+
+// Default is the registry consulted when decoding Metadata and Ext elements.
+var Default = NewRegistry()
+
+// Register records a type in the Default registry.
+func Register(name xml.Name, prototype interface{}) {
+	Default.Register(name, prototype)
+}
+
+// Unregister removes a type from the Default registry.
+func Unregister(name xml.Name) {
+	Default.Unregister(name)
+}
+
+// New returns a new value of the type registered for name in the Default registry.
+func New(name xml.Name) (interface{}, bool) {
+	return Default.New(name)
+}
@@ -0,0 +1,55 @@
+package extension
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+type window struct {
+	Start string `xml:"start,attr"`
+}
+
+func TestRegistry(t *testing.T) {
+	name := xml.Name{Space: "urn:example:rights", Local: "RightsWindow"}
+	registry := NewRegistry()
+	if _, ok := registry.New(name); ok {
+		t.Log("Nothing should be registered in a new registry")
+		t.Fail()
+	}
+
+	// values and pointers register the same type
+	for _, prototype := range []interface{}{window{}, &window{}} {
+		registry.Register(name, prototype)
+		v, ok := registry.New(name)
+		if _, isWindow := v.(*window); !ok || !isWindow {
+			t.Logf("Expected a new *window, got %T", v)
+			t.Fail()
+		}
+	}
+	first, _ := registry.New(name)
+	second, _ := registry.New(name)
+	if first.(*window) == second.(*window) {
+		t.Log("New should return a new value each time")
+		t.Fail()
+	}
+	if _, ok := registry.New(xml.Name{Local: "RightsWindow"}); ok {
+		t.Log("Names should match on namespace too")
+		t.Fail()
+	}
+
+	registry.Unregister(name)
+	if _, ok := registry.New(name); ok {
+		t.Log("Unregistered type was still returned")
+		t.Fail()
+	}
+}
+
+func TestRegisterNil(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Log("Registering nil should panic")
+			t.Fail()
+		}
+	}()
+	NewRegistry().Register(xml.Name{Local: "x"}, nil)
+}
@@ -0,0 +1,731 @@
+// Package generate produces random SCTE 224 documents that are valid against
+// the schema of their version, for property-based tests of code that reads,
+// writes and converts them:
+//
+//	g := generate.New(seed, generate.WithDepth(4), generate.WithAudienceNesting(2))
+//	for i := 0; i < 1000; i++ {
+//		media := g.Media()
+//		...
+//	}
+//
+// A Generator is deterministic: the same seed and options give the same
+// documents. Values of the 2020 schema are built as decoding their XML would
+// build them, so that encoding and decoding one gives an equal value. Values of
+// the 2018 and 2015 schemas use only constructs their schema has, and are
+// built by downgrading 2020 values with Get2018 and Get2015.
+package generate
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/Comcast/scte224structs/iso7064"
+	scte224_2015 "github.com/Comcast/scte224structs/types/scte224v20151115"
+	scte224_2018 "github.com/Comcast/scte224structs/types/scte224v20180501"
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+	"github.com/Comcast/scte224structs/types/xsd"
+)
+
+const (
+	esniNamespace     = "http://www.scte.org/schemas/224"
+	actionNamespace   = "urn:scte:224:action"
+	audienceNamespace = "urn:scte:224:audience"
+
+	// matchSignalSchema is the default schema of a MatchSignal, which decoding
+	// sets when it is missing.
+	matchSignalSchema = "http://www.scte.org/schemas/35"
+)
+
+const (
+	v2015 = 2015
+	v2018 = 2018
+	v2020 = 2020
+)
+
+// An ActionMix weighs the actions of generated ViewingPolicies. Each policy has
+// one to three distinct actions, drawn according to the weights; actions of zero
+// weight, and those the schema version lacks, are never drawn. Allocation is
+// only drawn for 2020 documents and SignalPointInsertion for 2018 and 2020 ones.
+type ActionMix struct {
+	Content              int
+	SignalPointDeletion  int
+	SignalPointInsertion int
+	Allocation           int
+	// Property is an ActionProperty without a field of its own, such as
+	// FastForward or PreviewPeriod.
+	Property int
+}
+
+// DefaultActionMix favors content replacement, as schedules of blackouts do.
+var DefaultActionMix = ActionMix{Content: 4, SignalPointDeletion: 2, SignalPointInsertion: 2, Allocation: 1, Property: 1}
+
+// An Option changes how a Generator shapes documents.
+type Option func(*Generator)
+
+// WithDepth sets how many levels of Media, MediaPoint, Policy, ViewingPolicy and
+// Audience are generated inline; deeper objects are referenced by xlink:href
+// instead, or left out. The default of 5 generates every level.
+func WithDepth(depth int) Option {
+	return func(g *Generator) { g.depth = depth }
+}
+
+// WithBreadth sets the most children an object has at each level, such as the
+// MediaPoints of a Media or the entries of Results. The default is 3.
+func WithBreadth(breadth int) Option {
+	return func(g *Generator) { g.breadth = breadth }
+}
+
+// WithAudienceNesting sets how many levels of Audiences may be nested in the
+// Audience of a ViewingPolicy. The default is 2.
+func WithAudienceNesting(nesting int) Option {
+	return func(g *Generator) { g.nesting = nesting }
+}
+
+// WithActionMix sets the weights of the actions of ViewingPolicies.
+func WithActionMix(mix ActionMix) Option {
+	return func(g *Generator) { g.actions = mix }
+}
+
+// WithStart sets the time effective, expires and match times are drawn around,
+// which is 2021-07-26T00:00:00Z by default.
+func WithStart(start time.Time) Option {
+	return func(g *Generator) { g.start = start }
+}
+
+// A Generator produces random documents. It is not safe for concurrent use.
+type Generator struct {
+	rand    *rand.Rand
+	depth   int
+	breadth int
+	nesting int
+	actions ActionMix
+	start   time.Time
+
+	// version is the schema version of the document being generated
+	version int
+	ids     int
+}
+
+// New returns a Generator drawing from seed.
+func New(seed int64, opts ...Option) *Generator {
+	g := &Generator{
+		rand:    rand.New(rand.NewSource(seed)),
+		depth:   5,
+		breadth: 3,
+		nesting: 2,
+		actions: DefaultActionMix,
+		start:   time.Date(2021, time.July, 26, 0, 0, 0, 0, time.UTC),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.breadth < 1 {
+		g.breadth = 1
+	}
+	return g
+}
+
+// Media returns a Media of the 2020 schema.
+func (g *Generator) Media() *scte224.Media {
+	g.version = v2020
+	return g.media(g.depth)
+}
+
+// Policy returns a Policy of the 2020 schema.
+func (g *Generator) Policy() *scte224.Policy {
+	g.version = v2020
+	return g.policy(g.depth)
+}
+
+// Audience returns an Audience of the 2020 schema.
+func (g *Generator) Audience() *scte224.Audience {
+	g.version = v2020
+	return g.audience(g.nesting)
+}
+
+// Results returns Results of the 2020 schema.
+func (g *Generator) Results() *scte224.Results {
+	g.version = v2020
+	return g.results()
+}
+
+// Media2018 returns a Media of the 2018 schema.
+func (g *Generator) Media2018() *scte224_2018.Media {
+	g.version = v2018
+	media := g.media(g.depth).Get2018()
+	return &media
+}
+
+// Policy2018 returns a Policy of the 2018 schema.
+func (g *Generator) Policy2018() *scte224_2018.Policy {
+	g.version = v2018
+	policy := g.policy(g.depth).Get2018()
+	return &policy
+}
+
+// Audience2018 returns an Audience of the 2018 schema.
+func (g *Generator) Audience2018() *scte224_2018.Audience {
+	g.version = v2018
+	aud := g.audience(g.nesting).Get2018()
+	return &aud
+}
+
+// Results2018 returns Results of the 2018 schema.
+func (g *Generator) Results2018() *scte224_2018.Results {
+	g.version = v2018
+	results := g.results()
+	dst := &scte224_2018.Results{XMLName: results.XMLName, Size: results.Size}
+	for _, media := range results.Medias {
+		downgraded := media.Get2018()
+		dst.Medias = append(dst.Medias, &downgraded)
+	}
+	for _, mp := range results.MediaPoints {
+		downgraded := mp.Get2018()
+		dst.MediaPoints = append(dst.MediaPoints, &downgraded)
+	}
+	for _, policy := range results.Policys {
+		downgraded := policy.Get2018()
+		dst.Policys = append(dst.Policys, &downgraded)
+	}
+	for _, vp := range results.ViewingPolicys {
+		downgraded := vp.Get2018()
+		dst.ViewingPolicys = append(dst.ViewingPolicys, &downgraded)
+	}
+	for _, aud := range results.Audiences {
+		downgraded := aud.Get2018()
+		dst.Audiences = append(dst.Audiences, &downgraded)
+	}
+	return dst
+}
+
+// Media2015 returns a Media of the 2015 schema.
+func (g *Generator) Media2015() *scte224_2015.Media {
+	g.version = v2015
+	media := g.media(g.depth).Get2015()
+	return &media
+}
+
+// Policy2015 returns a Policy of the 2015 schema.
+func (g *Generator) Policy2015() *scte224_2015.Policy {
+	g.version = v2015
+	policy := g.policy(g.depth).Get2015()
+	return &policy
+}
+
+// Audience2015 returns an Audience of the 2015 schema.
+func (g *Generator) Audience2015() *scte224_2015.Audience {
+	g.version = v2015
+	aud := g.audience(g.nesting).Get2015()
+	return &aud
+}
+
+// Results2015 returns Results of the 2015 schema.
+func (g *Generator) Results2015() *scte224_2015.Results {
+	g.version = v2015
+	results := g.results()
+	dst := &scte224_2015.Results{Size: results.Size}
+	for _, media := range results.Medias {
+		downgraded := media.Get2015()
+		dst.Medias = append(dst.Medias, &downgraded)
+	}
+	for _, mp := range results.MediaPoints {
+		downgraded := mp.Get2015()
+		dst.MediaPoints = append(dst.MediaPoints, &downgraded)
+	}
+	for _, policy := range results.Policys {
+		downgraded := policy.Get2015()
+		dst.Policys = append(dst.Policys, &downgraded)
+	}
+	for _, vp := range results.ViewingPolicys {
+		downgraded := vp.Get2015()
+		dst.ViewingPolicys = append(dst.ViewingPolicys, &downgraded)
+	}
+	for _, aud := range results.Audiences {
+		downgraded := aud.Get2015()
+		dst.Audiences = append(dst.Audiences, &downgraded)
+	}
+	return dst
+}
+
+func (g *Generator) results() *scte224.Results {
+	results := &scte224.Results{XMLName: esni("Results")}
+	for i := g.count(0); i > 0; i-- {
+		results.Medias = append(results.Medias, g.media(g.depth))
+	}
+	for i := g.count(0); i > 0; i-- {
+		results.MediaPoints = append(results.MediaPoints, g.mediaPoint(g.depth-1))
+	}
+	for i := g.count(0); i > 0; i-- {
+		results.Policys = append(results.Policys, g.policy(g.depth-2))
+	}
+	for i := g.count(0); i > 0; i-- {
+		results.ViewingPolicys = append(results.ViewingPolicys, g.viewingPolicy(g.depth-3))
+	}
+	for i := g.count(0); i > 0; i-- {
+		results.Audiences = append(results.Audiences, g.audience(g.nesting))
+	}
+	if g.chance(2) {
+		results.Size = len(results.Medias) + len(results.MediaPoints) + len(results.Policys) +
+			len(results.ViewingPolicys) + len(results.Audiences)
+	}
+	return results
+}
+
+// media returns a Media with levels of objects inline below it.
+func (g *Generator) media(levels int) *scte224.Media {
+	media := &scte224.Media{
+		ReusableType: scte224.ReusableType{IdentifiableType: g.identifiable("media")},
+		XMLName:      esni("Media"),
+	}
+	media.Effective, media.Expires = g.window()
+	if g.chance(2) {
+		media.Source = g.pick("EAST", "WEST", "generate.example/source/"+g.word())
+	}
+	if levels > 1 {
+		for i := g.count(0); i > 0; i-- {
+			media.MediaPoints = append(media.MediaPoints, g.mediaPoint(levels-1))
+		}
+	}
+	return media
+}
+
+func (g *Generator) mediaPoint(levels int) *scte224.MediaPoint {
+	mp := &scte224.MediaPoint{
+		IdentifiableType: g.identifiable("mediapoint"),
+		XMLName:          esni("MediaPoint"),
+	}
+	mp.Effective, mp.Expires = g.window()
+	if g.chance(2) {
+		mp.MatchTime = g.dateTime()
+	}
+	if g.chance(4) {
+		mp.MatchOffset = g.duration()
+	}
+	if g.chance(3) {
+		mp.Source = g.pick("EAST", "WEST")
+	}
+	if g.chance(3) {
+		mp.ExpectedDuration = g.duration()
+	}
+	if g.chance(2) {
+		mp.Order = g.uint(10)
+	}
+	mp.Reusable = g.chance(5)
+
+	for i := g.count(0); i > 0 && g.chance(2); i-- {
+		mp.Removes = append(mp.Removes, &scte224.Remove{XMLName: esni("Remove"), Policy: g.reference()})
+	}
+	for i := g.count(0); i > 0; i-- {
+		apply := &scte224.Apply{XMLName: esni("Apply")}
+		if g.chance(2) {
+			apply.Duration = g.duration()
+		}
+		if g.chance(3) {
+			apply.Priority = g.uint(5)
+		}
+		if levels > 1 && g.chance(2) {
+			apply.Policy = g.policy(levels - 1)
+		} else {
+			apply.Policy = g.reference()
+		}
+		mp.Applys = append(mp.Applys, apply)
+	}
+	if mp.MatchTime == nil || g.chance(3) {
+		mp.MatchSignal = g.matchSignal()
+	}
+	return mp
+}
+
+var assertions = []string{
+	"/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=16]",
+	"/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=17]",
+	"/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=52]",
+	"/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=53]",
+	"/SpliceInfoSection/SegmentationDescriptor/SegmentationUpid[@segmentationUpidType=8]",
+}
+
+func (g *Generator) matchSignal() *scte224.MatchSignal {
+	ms := &scte224.MatchSignal{
+		XMLName: esni("MatchSignal"),
+		Match:   scte224.Match(g.pick("", "ALL", "ANY", "NONE")),
+	}
+	if g.version == v2020 {
+		ms.Schema = matchSignalSchema
+	}
+	if g.chance(3) {
+		ms.SignalTolerance = scte224.Duration(fmt.Sprintf("PT%dS", 1+g.rand.Intn(10)))
+	}
+	for i := g.count(1); i > 0; i-- {
+		ms.Assertions = append(ms.Assertions, &scte224.Assert{
+			XMLName:     esni("Assert"),
+			Declaration: assertions[g.rand.Intn(len(assertions))],
+		})
+	}
+	return ms
+}
+
+// reference returns a Policy that only refers to one by xlink:href.
+func (g *Generator) reference() *scte224.Policy {
+	return &scte224.Policy{
+		ReusableType: scte224.ReusableType{XLinkHRef: g.id("policy")},
+		XMLName:      esni("Policy"),
+	}
+}
+
+func (g *Generator) policy(levels int) *scte224.Policy {
+	policy := &scte224.Policy{
+		ReusableType: scte224.ReusableType{IdentifiableType: g.identifiable("policy")},
+		XMLName:      esni("Policy"),
+	}
+	if levels > 1 {
+		for i := g.count(0); i > 0; i-- {
+			policy.ViewingPolicys = append(policy.ViewingPolicys, g.viewingPolicy(levels-1))
+		}
+	}
+	return policy
+}
+
+// viewingPolicy returns a ViewingPolicy with an Audience and actions, as the
+// schema requires, or a reference when there are no levels left for the Audience.
+func (g *Generator) viewingPolicy(levels int) *scte224.ViewingPolicy {
+	vp := &scte224.ViewingPolicy{XMLName: esni("ViewingPolicy")}
+	if levels <= 1 {
+		vp.XLinkHRef = g.id("viewingpolicy")
+		return vp
+	}
+	vp.IdentifiableType = g.identifiable("viewingpolicy")
+	vp.Audience = g.audience(g.nesting)
+
+	kinds := g.actionKinds()
+	for _, kind := range kinds {
+		switch kind {
+		case "Content":
+			vp.Content = &scte224.ContentAction{
+				XMLName: action("Content"),
+				Content: g.pick("slate", "alternate", g.id("content")),
+			}
+		case "SignalPointDeletion":
+			vp.SignalPointDeletion = &scte224.SignalPointDeletionAction{
+				XMLName:             action("SignalPointDeletion"),
+				SignalPointDeletion: g.pick("true", "false"),
+			}
+		case "SignalPointInsertion":
+			vp.SignalPointInsertion = g.signalPointInsertion()
+		case "Allocation":
+			vp.Allocation = g.allocation()
+		case "Property":
+			vp.ActionProperty = append(vp.ActionProperty, g.actionProperty())
+		}
+	}
+	return vp
+}
+
+// actionKinds draws one to three distinct actions from the mix.
+func (g *Generator) actionKinds() []string {
+	weights := []struct {
+		kind   string
+		weight int
+	}{
+		{"Content", g.actions.Content},
+		{"SignalPointDeletion", g.actions.SignalPointDeletion},
+		{"SignalPointInsertion", g.actions.SignalPointInsertion},
+		{"Allocation", g.actions.Allocation},
+		{"Property", g.actions.Property},
+	}
+	if g.version < v2020 {
+		weights[3].weight = 0
+	}
+	if g.version < v2018 {
+		weights[2].weight = 0
+	}
+
+	var kinds []string
+	for n := 1 + g.rand.Intn(3); n > 0; n-- {
+		total := 0
+		for _, w := range weights {
+			if w.weight > 0 {
+				total += w.weight
+			}
+		}
+		if total == 0 {
+			break
+		}
+		draw := g.rand.Intn(total)
+		for i, w := range weights {
+			if w.weight <= 0 {
+				continue
+			}
+			if draw < w.weight {
+				kinds = append(kinds, w.kind)
+				weights[i].weight = 0
+				break
+			}
+			draw -= w.weight
+		}
+	}
+	if len(kinds) == 0 {
+		// the schema requires an action after the Audience
+		kinds = append(kinds, "Content")
+	}
+	return kinds
+}
+
+func (g *Generator) signalPointInsertion() *scte224.SignalPointInsertionAction {
+	insertion := &scte224.SignalPointInsertionAction{}
+	if g.chance(3) {
+		insertion.Offset = g.duration()
+	}
+	for i := g.count(1); i > 0; i-- {
+		sp := &scte224.SignalPoint{
+			SegmentationTypeId: g.uintOf(16, 17, 52, 53, 54, 55),
+		}
+		if g.chance(2) {
+			sp.Offset = g.duration()
+		}
+		if g.chance(2) {
+			sp.SegmentationEventId = fmt.Sprint(g.rand.Intn(1 << 16))
+		}
+		if g.chance(2) {
+			sp.SegmentationDuration = int64(90000 * (1 + g.rand.Intn(120)))
+		}
+		if g.chance(2) {
+			sp.SegmentationUpidType = g.uintOf(8, 9, 12)
+			sp.SegmentationUpid = fmt.Sprintf("%08X", g.rand.Uint32())
+		}
+		insertion.SignalPoints = append(insertion.SignalPoints, sp)
+	}
+	return insertion
+}
+
+func (g *Generator) allocation() *scte224.Allocation {
+	alloc := &scte224.Allocation{
+		XMLName:   action("Allocation"),
+		OwnerType: g.pick("", "PROVIDER", "DISTRIBUTOR"),
+		Ads:       g.pick("", "FreeWheel/MRM"),
+	}
+	if alloc.OwnerType != "" {
+		alloc.OwnerName = g.word()
+	}
+	slots := &scte224.Slots{XMLName: action("Slots")}
+	offset := 0
+	for i := g.count(1); i > 0; i-- {
+		length := 15 * (1 + g.rand.Intn(2))
+		slot := &scte224.Slot{
+			XMLName:  action("Slot"),
+			Duration: scte224.Duration(fmt.Sprintf("PT%dS", length)),
+			Offset:   scte224.Duration(fmt.Sprintf("PT%dS", offset)),
+		}
+		offset += length
+		for j := g.count(0); j > 0; j-- {
+			slot.AdsReferenceId = append(slot.AdsReferenceId, &scte224.AdsReferenceId{
+				XMLName:       action("AdsReferenceId"),
+				ID:            fmt.Sprint(10000 + g.rand.Intn(90000)),
+				ReferenceType: g.pick("", "campaign", "advertiser"),
+				Exclude:       g.chance(4),
+			})
+		}
+		if g.chance(4) {
+			slot.SlotRules = &scte224.SlotRules{
+				XMLName: action("SlotRules"),
+				SlotRule: []*scte224.SlotRule{{
+					XMLName: action("SlotRule"),
+					Rule:    "maxDuration",
+					Parameters: []*scte224.Parameter{{
+						XMLName:       action("Parameter"),
+						ParameterName: "duration",
+						Value:         string(slot.Duration),
+					}},
+				}},
+			}
+		}
+		slots.AdSlots = append(slots.AdSlots, slot)
+	}
+	alloc.Slots = []*scte224.Slots{slots}
+	if g.chance(2) {
+		alloc.Duration = scte224.Duration(fmt.Sprintf("PT%dS", offset))
+	}
+	return alloc
+}
+
+// actionProperties are actions of the action schema without a field of their own,
+// and values of their types.
+var actionProperties = []struct {
+	name   string
+	values []string
+}{
+	{"FastForward", []string{"true", "false"}},
+	{"Rewind", []string{"true", "false"}},
+	{"PreviewPeriod", []string{"PT5M", "PT10M"}},
+	{"Drm", []string{"required"}},
+	{"KidVid", []string{"true"}},
+}
+
+func (g *Generator) actionProperty() scte224.Any {
+	property := actionProperties[g.rand.Intn(len(actionProperties))]
+	return anyNode(action(property.name), g.pick(property.values...))
+}
+
+func (g *Generator) audience(nesting int) *scte224.Audience {
+	aud := &scte224.Audience{
+		ReusableType: scte224.ReusableType{IdentifiableType: g.identifiable("audience")},
+		XMLName:      esni("Audience"),
+		Match:        scte224.Match(g.pick("", "ALL", "ANY", "NONE")),
+	}
+	if nesting > 0 && g.chance(2) {
+		for i := g.count(1); i > 0; i-- {
+			aud.Audiences = append(aud.Audiences, g.audience(nesting-1))
+		}
+		return aud
+	}
+	for i := g.count(0); i > 0; i-- {
+		aud.AudienceProperty = append(aud.AudienceProperty, g.audienceProperty())
+	}
+	return aud
+}
+
+func (g *Generator) audienceProperty() scte224.Any {
+	var name, value string
+	switch g.rand.Intn(5) {
+	case 0:
+		name, value = "Zip", fmt.Sprintf("%05d", g.rand.Intn(100000))
+	case 1:
+		name, value = "DMA", fmt.Sprint(500+g.rand.Intn(382))
+	case 2:
+		name, value = "State", g.pick("CO", "NY", "PA", "CA", "TX")
+	case 3:
+		name, value = "FIPS", fmt.Sprintf("%05d", g.rand.Intn(100000))
+	default:
+		name, value = "Device", g.pick("PHONE", "TABLET", "COMPUTER", "SMARTTV")
+	}
+	return anyNode(xml.Name{Space: audienceNamespace, Local: name}, value)
+}
+
+// identifiable returns the identity of an object of the given kind: always an
+// id, and sometimes a description, a lastUpdated time and AltIDs.
+func (g *Generator) identifiable(kind string) scte224.IdentifiableType {
+	idType := scte224.IdentifiableType{Id: g.id(kind)}
+	if g.chance(2) {
+		idType.Description = g.word() + " " + g.word()
+	}
+	if g.chance(2) {
+		idType.LastUpdated = g.dateTime()
+	}
+	for i := g.count(0); i > 0 && g.chance(2); i-- {
+		idType.AltIDs = append(idType.AltIDs, g.altID())
+	}
+	return idType
+}
+
+// altID returns an AltID whose value is valid for its type; AltIDs of 2015 and
+// 2018 documents have no type, and no description in 2015.
+func (g *Generator) altID() *scte224.AltID {
+	altID := &scte224.AltID{XMLName: esni("AltID")}
+	switch g.rand.Intn(4) {
+	case 0:
+		altID.Type, altID.Value = scte224.AltIDCallSign, g.pick("WABC", "KUSA", "WGN", "KWGN")
+	case 1:
+		altID.Type, altID.Value = scte224.AltIDEIDR, g.eidr()
+	case 2:
+		altID.Type, altID.Value = scte224.AltIDAdID, fmt.Sprintf("ABCD%07d", g.rand.Intn(10000000))
+	default:
+		altID.Type, altID.Value = "private:TMS", fmt.Sprintf("EP%012d", g.rand.Int63n(1000000000000))
+	}
+	if g.version != v2015 && g.chance(2) {
+		altID.Description = g.word()
+	}
+	if g.version != v2020 {
+		altID.Type = ""
+	}
+	return altID
+}
+
+// eidr returns an EIDR content id with its check character.
+func (g *Generator) eidr() string {
+	groups := make([]string, 5)
+	for i := range groups {
+		groups[i] = fmt.Sprintf("%04X", g.rand.Intn(1<<16))
+	}
+	check, _ := iso7064.Mod3736(strings.Join(groups, ""))
+	return fmt.Sprintf("10.5240/%s-%c", strings.Join(groups, "-"), check)
+}
+
+// id returns a new id for an object of the given kind.
+func (g *Generator) id(kind string) string {
+	g.ids++
+	return fmt.Sprintf("generate.example/%s/%d", kind, g.ids)
+}
+
+var words = []string{"news", "sports", "movie", "east", "west", "live", "replay", "local", "national", "special"}
+
+func (g *Generator) word() string {
+	return words[g.rand.Intn(len(words))]
+}
+
+// window returns optional effective and expires times, expires being after effective.
+func (g *Generator) window() (effective, expires *xsd.DateTime) {
+	if g.chance(2) {
+		effective = g.dateTime()
+	}
+	if g.chance(2) {
+		end := g.start.Add(time.Duration(48+g.rand.Intn(48)) * time.Hour)
+		dt := xsd.NewDateTime(end)
+		expires = &dt
+	}
+	return effective, expires
+}
+
+// dateTime returns a time within two days of the start, to the second.
+func (g *Generator) dateTime() *xsd.DateTime {
+	dt := xsd.NewDateTime(g.start.Add(time.Duration(g.rand.Intn(48*60*60)) * time.Second))
+	return &dt
+}
+
+func (g *Generator) duration() scte224.Duration {
+	if g.chance(2) {
+		return scte224.Duration(fmt.Sprintf("PT%dS", 1+g.rand.Intn(120)))
+	}
+	return scte224.Duration(fmt.Sprintf("PT%dM", 1+g.rand.Intn(180)))
+}
+
+// count returns a number of children between min and the breadth.
+func (g *Generator) count(min int) int {
+	if min >= g.breadth {
+		return min
+	}
+	return min + g.rand.Intn(g.breadth-min+1)
+}
+
+// chance returns true once in n draws.
+func (g *Generator) chance(n int) bool {
+	return g.rand.Intn(n) == 0
+}
+
+func (g *Generator) pick(values ...string) string {
+	return values[g.rand.Intn(len(values))]
+}
+
+func (g *Generator) uint(n int) *uint {
+	u := uint(g.rand.Intn(n))
+	return &u
+}
+
+func (g *Generator) uintOf(values ...uint) *uint {
+	u := values[g.rand.Intn(len(values))]
+	return &u
+}
+
+func esni(local string) xml.Name {
+	return xml.Name{Space: esniNamespace, Local: local}
+}
+
+func action(local string) xml.Name {
+	return xml.Name{Space: actionNamespace, Local: local}
+}
+
+// anyNode returns a node as decoding <name xmlns="...">value</name> gives it.
+func anyNode(name xml.Name, value string) scte224.Any {
+	return scte224.Any{XMLName: name, Namespace: scte224.NamespaceCleaner(name.Space), Value: value}
+}
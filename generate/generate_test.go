@@ -0,0 +1,161 @@
+package generate
+
+import (
+	"bytes"
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"github.com/Comcast/scte224structs/altid"
+	scte224_2015 "github.com/Comcast/scte224structs/types/scte224v20151115"
+	scte224_2018 "github.com/Comcast/scte224structs/types/scte224v20180501"
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+const seeds = 300
+
+// roundTrip encodes v, decodes it into decoded and encodes that again, failing
+// unless both encodings are the same.
+func roundTrip(t *testing.T, seed int64, v, decoded interface{}) {
+	raw, err := xml.Marshal(v)
+	if nil != err {
+		t.Logf("seed %d: %v", seed, err)
+		t.FailNow()
+	}
+	if err := xml.Unmarshal(raw, decoded); nil != err {
+		t.Logf("seed %d: %v\n%s", seed, err, raw)
+		t.FailNow()
+	}
+	again, err := xml.Marshal(decoded)
+	if nil != err {
+		t.Logf("seed %d: %v", seed, err)
+		t.FailNow()
+	}
+	if !bytes.Equal(raw, again) {
+		t.Logf("seed %d: encoding changed on decoding\n%s\n%s", seed, raw, again)
+		t.FailNow()
+	}
+}
+
+func TestRoundTrip2020(t *testing.T) {
+	for seed := int64(0); seed < seeds; seed++ {
+		g := New(seed)
+
+		media := g.Media()
+		var decodedMedia scte224.Media
+		roundTrip(t, seed, media, &decodedMedia)
+		if !reflect.DeepEqual(media, &decodedMedia) {
+			t.Logf("seed %d: decoded Media differs from the generated one", seed)
+			t.FailNow()
+		}
+
+		policy := g.Policy()
+		var decodedPolicy scte224.Policy
+		roundTrip(t, seed, policy, &decodedPolicy)
+		if !reflect.DeepEqual(policy, &decodedPolicy) {
+			t.Logf("seed %d: decoded Policy differs from the generated one", seed)
+			t.FailNow()
+		}
+
+		aud := g.Audience()
+		var decodedAudience scte224.Audience
+		roundTrip(t, seed, aud, &decodedAudience)
+		if !reflect.DeepEqual(aud, &decodedAudience) {
+			t.Logf("seed %d: decoded Audience differs from the generated one", seed)
+			t.FailNow()
+		}
+
+		roundTrip(t, seed, g.Results(), &scte224.Results{})
+	}
+}
+
+func TestRoundTrip2018(t *testing.T) {
+	for seed := int64(0); seed < seeds; seed++ {
+		g := New(seed)
+		roundTrip(t, seed, g.Media2018(), &scte224_2018.Media{})
+		roundTrip(t, seed, g.Policy2018(), &scte224_2018.Policy{})
+		roundTrip(t, seed, g.Audience2018(), &scte224_2018.Audience{})
+		roundTrip(t, seed, g.Results2018(), &scte224_2018.Results{})
+	}
+}
+
+func TestRoundTrip2015(t *testing.T) {
+	for seed := int64(0); seed < seeds; seed++ {
+		g := New(seed)
+		roundTrip(t, seed, g.Media2015(), &scte224_2015.Media{})
+		roundTrip(t, seed, g.Policy2015(), &scte224_2015.Policy{})
+		roundTrip(t, seed, g.Audience2015(), &scte224_2015.Audience{})
+		roundTrip(t, seed, g.Results2015(), &scte224_2015.Results{})
+	}
+}
+
+func TestUpgradeInvertsDowngrade(t *testing.T) {
+	for seed := int64(0); seed < seeds; seed++ {
+		media := New(seed).Media()
+		upgraded := scte224.UpgradeMedia(media.Get2018())
+		expected, _ := xml.Marshal(media)
+		got, _ := xml.Marshal(&upgraded)
+		if !bytes.Equal(expected, got) {
+			t.Logf("seed %d: upgrading the downgraded Media changed it\n%s\n%s", seed, expected, got)
+			t.FailNow()
+		}
+	}
+}
+
+func TestAltIDsAreValid(t *testing.T) {
+	g := New(1, WithBreadth(6))
+	for i := 0; i < seeds; i++ {
+		media := g.Media()
+		for _, altID := range media.AltIDs {
+			if err := altid.ValidateAltID(altID); nil != err {
+				t.Log(err)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestOptions(t *testing.T) {
+	a, _ := xml.Marshal(New(7).Results())
+	b, _ := xml.Marshal(New(7).Results())
+	if !bytes.Equal(a, b) {
+		t.Log("Expected the same seed to give the same Results")
+		t.Fail()
+	}
+
+	g := New(7, WithDepth(1))
+	for i := 0; i < seeds; i++ {
+		if media := g.Media(); len(media.MediaPoints) != 0 {
+			t.Log("Expected no MediaPoints at depth 1")
+			t.FailNow()
+		}
+	}
+
+	g = New(7, WithAudienceNesting(0))
+	for i := 0; i < seeds; i++ {
+		if aud := g.Audience(); len(aud.Audiences) != 0 {
+			t.Log("Expected no nested Audiences without nesting")
+			t.FailNow()
+		}
+	}
+
+	g = New(7, WithActionMix(ActionMix{Allocation: 1}))
+	for i := 0; i < seeds; i++ {
+		for _, vp := range g.Policy().ViewingPolicys {
+			if vp.Audience != nil && (vp.Allocation == nil || vp.Content != nil) {
+				t.Log("Expected only Allocation actions")
+				t.FailNow()
+			}
+		}
+	}
+
+	// Allocation is not in the 2018 schema, so the policies fall back to Content
+	for i := 0; i < seeds; i++ {
+		for _, vp := range g.Policy2018().ViewingPolicys {
+			if vp.Audience != nil && len(vp.ActionProperty) != 0 {
+				t.Log("Expected no Allocation in 2018 policies")
+				t.FailNow()
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Package duration parses the xs:duration values the tools of this module take
+// from their input, such as cue offsets and rule parameters.
+//
+// ConvertDuration in the version packages does not check what it is given and
+// reads durations without a time part, such as P1D, as zero; Parse checks the
+// lexical form and handles those.
+package duration
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lexical is the form of an xs:duration of days, hours, minutes and seconds. The
+// years and months of xs:duration have no fixed length, so they are not accepted.
+var lexical = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d*)?)S)?)?$`)
+
+// units are the lengths of the days, hours and minutes of lexical, in the order of
+// its groups.
+var units = []time.Duration{24 * time.Hour, time.Hour, time.Minute}
+
+// Parse returns the duration s stands for, or an error if s is not an
+// xs:duration of days, hours, minutes and seconds, or is too long for a
+// time.Duration.
+func Parse(s string) (time.Duration, error) {
+	match := lexical.FindStringSubmatch(s)
+	// the expression accepts the empty forms P and PT, which xs:duration does not
+	if match == nil || s == "P" || strings.HasSuffix(s, "T") {
+		return 0, fmt.Errorf("%q is not an xs:duration", s)
+	}
+	var total time.Duration
+	for i, unit := range units {
+		if match[i+1] == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(match[i+1], 10, 64)
+		if err != nil || n > int64(math.MaxInt64-total)/int64(unit) {
+			return 0, fmt.Errorf("xs:duration %q is out of range", s)
+		}
+		total += time.Duration(n) * unit
+	}
+	if match[4] != "" {
+		seconds, err := time.ParseDuration(match[4] + "s")
+		if err != nil || seconds > math.MaxInt64-total {
+			return 0, fmt.Errorf("xs:duration %q is out of range", s)
+		}
+		total += seconds
+	}
+	return total, nil
+}
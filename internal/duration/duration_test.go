@@ -0,0 +1,38 @@
+package duration
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	for s, expected := range map[string]time.Duration{
+		"P1D":          24 * time.Hour,
+		"PT0S":         0,
+		"PT30S":        30 * time.Second,
+		"PT1.5S":       1500 * time.Millisecond,
+		"PT2.S":        2 * time.Second,
+		"P1DT2H3M4S":   26*time.Hour + 3*time.Minute + 4*time.Second,
+		"PT90M":        90 * time.Minute,
+		"P2DT0.001S":   48*time.Hour + time.Millisecond,
+		"P106751DT23H": 106751*24*time.Hour + 23*time.Hour,
+	} {
+		d, err := Parse(s)
+		if nil != err || expected != d {
+			t.Log("Unexpected duration for", s, d, err)
+			t.Fail()
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, s := range []string{
+		"", "P", "PT", "P1DT", "1D", "P1Y", "P1M", "PT-1S", "P1H", "PT1S ", "PT.5S",
+		"P106752D", "P99999999999999999999D", "PT9223372037S",
+	} {
+		if _, err := Parse(s); nil == err {
+			t.Log("Expected an error for", s)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Package repeat computes the times a SignalPoint is inserted at, which the
+// versions of the schema that have repeatInterval share.
+package repeat
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Comcast/scte224structs/types/xsd"
+)
+
+// MaxRepetitions bounds the signals a single SignalPoint expands to, so that a
+// short repeatInterval with a distant repeatStop or horizon cannot make a
+// document allocate without limit.
+const MaxRepetitions = 10000
+
+var (
+	// ErrUnbounded is returned for a SignalPoint that repeats without a
+	// repeatStop, when no horizon bounds the repetition.
+	ErrUnbounded = errors.New("scte224: SignalPoint repeats without repeatStop and no horizon was given")
+	// ErrTooMany is returned for a SignalPoint that repeats more than
+	// MaxRepetitions times before its repeatStop or the horizon.
+	ErrTooMany = fmt.Errorf("scte224: SignalPoint repeats more than %d times", MaxRepetitions)
+)
+
+// Times returns the times a SignalPoint first inserted at first is inserted at:
+// first alone without an interval, and otherwise every interval until stop, or
+// else horizon, whichever is earlier. A start anchors the repetitions, which are
+// then at start plus a multiple of the interval, from the first of these times
+// not before first. Times past the horizon are dropped; a zero horizon leaves
+// repeats bounded by stop only.
+func Times(first time.Time, interval time.Duration, start, stop *xsd.DateTime, horizon time.Time) ([]time.Time, error) {
+	if interval <= 0 {
+		if !horizon.IsZero() && first.After(horizon) {
+			return nil, nil
+		}
+		return []time.Time{first}, nil
+	}
+
+	end := horizon
+	if stop != nil && !stop.IsZero() && (end.IsZero() || stop.Time().Before(end)) {
+		end = stop.Time()
+	}
+	if end.IsZero() {
+		return nil, ErrUnbounded
+	}
+	if start != nil && !start.IsZero() {
+		anchor := start.Time()
+		if anchor.After(first) {
+			first = anchor
+		} else if skipped := first.Sub(anchor); skipped%interval != 0 {
+			first = anchor.Add((skipped/interval + 1) * interval)
+		}
+	}
+	if first.After(end) {
+		return nil, nil
+	}
+	if end.Sub(first)/interval >= MaxRepetitions {
+		return nil, ErrTooMany
+	}
+
+	times := make([]time.Time, 0, end.Sub(first)/interval+1)
+	for at := first; !at.After(end); at = at.Add(interval) {
+		times = append(times, at)
+	}
+	return times, nil
+}
@@ -0,0 +1,80 @@
+package repeat
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/Comcast/scte224structs/types/xsd"
+)
+
+func dateTime(t *testing.T, lexical string) *xsd.DateTime {
+	var attr struct {
+		At *xsd.DateTime `xml:"at,attr"`
+	}
+	if err := xml.Unmarshal([]byte(`<x at="`+lexical+`"/>`), &attr); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	return attr.At
+}
+
+func TestTimes(t *testing.T) {
+	first := time.Date(2021, 7, 26, 9, 0, 15, 0, time.UTC)
+	tests := []struct {
+		name        string
+		interval    time.Duration
+		start, stop *xsd.DateTime
+		horizon     time.Time
+		expected    []time.Duration
+	}{
+		{"no interval", 0, nil, nil, time.Time{}, []time.Duration{0}},
+		{"past the horizon", 0, nil, nil, first.Add(-time.Second), nil},
+		{"until stop", 5 * time.Minute, nil, dateTime(t, "2021-07-26T09:10:15Z"), time.Time{}, []time.Duration{0, 5 * time.Minute, 10 * time.Minute}},
+		{"until the horizon", 5 * time.Minute, nil, dateTime(t, "2021-07-26T10:00:00Z"), first.Add(7 * time.Minute), []time.Duration{0, 5 * time.Minute}},
+		{"anchored by start", 15 * time.Minute, dateTime(t, "2021-07-26T08:00:00Z"), nil, first.Add(30 * time.Minute), []time.Duration{15*time.Minute - 15*time.Second, 30*time.Minute - 15*time.Second}},
+		{"start after the first time", time.Minute, dateTime(t, "2021-07-26T09:01:00Z"), dateTime(t, "2021-07-26T09:02:00Z"), time.Time{}, []time.Duration{45 * time.Second, 105 * time.Second}},
+	}
+	for _, test := range tests {
+		times, err := Times(first, test.interval, test.start, test.stop, test.horizon)
+		if nil != err {
+			t.Log(test.name, err)
+			t.Fail()
+			continue
+		}
+		var offsets []time.Duration
+		for _, at := range times {
+			offsets = append(offsets, at.Sub(first))
+		}
+		if len(offsets) != len(test.expected) {
+			t.Log(test.name, "expected", test.expected, "got", offsets)
+			t.Fail()
+			continue
+		}
+		for i := range offsets {
+			if offsets[i] != test.expected[i] {
+				t.Log(test.name, "expected", test.expected, "got", offsets)
+				t.Fail()
+				break
+			}
+		}
+	}
+}
+
+func TestTimesErrors(t *testing.T) {
+	first := time.Date(2021, 7, 26, 9, 0, 0, 0, time.UTC)
+	if _, err := Times(first, time.Minute, nil, nil, time.Time{}); err != ErrUnbounded {
+		t.Log("Expected ErrUnbounded, got", err)
+		t.Fail()
+	}
+	if _, err := Times(first, time.Millisecond, nil, nil, first.Add(time.Hour)); err != ErrTooMany {
+		t.Log("Expected ErrTooMany, got", err)
+		t.Fail()
+	}
+	// exactly MaxRepetitions times are allowed
+	times, err := Times(first, time.Second, nil, nil, first.Add((MaxRepetitions-1)*time.Second))
+	if nil != err || len(times) != MaxRepetitions {
+		t.Log("Expected", MaxRepetitions, "times, got", len(times), err)
+		t.Fail()
+	}
+}
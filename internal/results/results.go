@@ -0,0 +1,193 @@
+// Package results streams the entries of an SCTE 224 Results document. It does
+// the token work shared by the versions of the schema, which differ only in the
+// namespace of Results and in the types of its entries; each version package
+// wraps it with its own types.
+package results
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Decoder reads a Results document one top level entry at a time.
+type Decoder struct {
+	decoder   *xml.Decoder
+	namespace string
+	newEntry  func(local string) interface{}
+	size      int
+	started   bool
+	done      bool
+}
+
+// NewDecoder returns a Decoder for Results in namespace. newEntry returns the
+// value a child of Results in that namespace decodes into, given its local name,
+// or nil for children to skip.
+func NewDecoder(r io.Reader, namespace string, newEntry func(local string) interface{}) *Decoder {
+	return &Decoder{decoder: xml.NewDecoder(r), namespace: namespace, newEntry: newEntry}
+}
+
+// Size returns the size attribute of the Results element, which is only known
+// once the first call to Next has read the start of the document.
+func (rd *Decoder) Size() int { return rd.size }
+
+// Next returns the next top level entry of the Results, and io.EOF once the
+// Results element has been closed. Unknown children are skipped.
+func (rd *Decoder) Next() (interface{}, error) {
+	if rd.done {
+		return nil, io.EOF
+	}
+	for {
+		token, err := rd.decoder.Token()
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if !rd.started {
+				if err := rd.readStart(t); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			var entry interface{}
+			if t.Name.Space == rd.namespace {
+				entry = rd.newEntry(t.Name.Local)
+			}
+			if entry == nil {
+				if err := rd.decoder.Skip(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if err := rd.decoder.DecodeElement(entry, &t); err != nil {
+				return nil, err
+			}
+			return entry, nil
+		case xml.EndElement:
+			// the decoder checks nesting, so the only end seen here closes Results
+			rd.done = true
+			return nil, io.EOF
+		}
+	}
+}
+
+func (rd *Decoder) readStart(start xml.StartElement) error {
+	if start.Name.Space != rd.namespace || start.Name.Local != "Results" {
+		return fmt.Errorf("expected element {%s}Results but have {%s}%s", rd.namespace, start.Name.Space, start.Name.Local)
+	}
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "" && attr.Name.Local == "size" {
+			size, err := strconv.Atoi(attr.Value)
+			if err != nil {
+				return fmt.Errorf("invalid Results size %q: %v", attr.Value, err)
+			}
+			rd.size = size
+		}
+	}
+	rd.started = true
+	return nil
+}
+
+// Stream decodes the remaining entries on a separate goroutine and sends them on
+// the returned channel, which is closed at the end of the document, on the first
+// error, or when ctx is cancelled. The error channel then receives the reason, if
+// any, and is closed.
+func (rd *Decoder) Stream(ctx context.Context) (<-chan interface{}, <-chan error) {
+	entries := make(chan interface{})
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(entries)
+		for {
+			if err := ctx.Err(); err != nil {
+				errs <- err
+				return
+			}
+			entry, err := rd.Next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case entries <- entry:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+	return entries, errs
+}
+
+// Encoder writes a Results document one entry at a time. The Results element is
+// opened by the first Encode and closed by Close.
+type Encoder struct {
+	encoder   *xml.Encoder
+	namespace string
+	size      int
+	started   bool
+	closed    bool
+}
+
+// NewEncoder returns an Encoder for Results in namespace.
+func NewEncoder(w io.Writer, namespace string) *Encoder {
+	return &Encoder{encoder: xml.NewEncoder(w), namespace: namespace}
+}
+
+// Indent behaves like xml.Encoder.Indent.
+func (re *Encoder) Indent(prefix, indent string) { re.encoder.Indent(prefix, indent) }
+
+// SetSize sets the size attribute of the Results element. It has no effect once
+// the first entry has been encoded.
+func (re *Encoder) SetSize(size int) { re.size = size }
+
+// Encode writes v as the next entry of the Results; the caller checks that it is
+// one.
+func (re *Encoder) Encode(v interface{}) error {
+	if re.closed {
+		return errors.New("ResultsEncoder is closed")
+	}
+	if err := re.start(); err != nil {
+		return err
+	}
+	return re.encoder.Encode(v)
+}
+
+func (re *Encoder) start() error {
+	if re.started {
+		return nil
+	}
+	start := xml.StartElement{Name: xml.Name{Space: re.namespace, Local: "Results"}}
+	if re.size != 0 {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "size"}, Value: strconv.Itoa(re.size)})
+	}
+	re.started = true
+	return re.encoder.EncodeToken(start)
+}
+
+// Close ends the Results element and flushes the output. A Results with no entries
+// is still written.
+func (re *Encoder) Close() error {
+	if re.closed {
+		return nil
+	}
+	if err := re.start(); err != nil {
+		return err
+	}
+	re.closed = true
+	if err := re.encoder.EncodeToken(xml.EndElement{Name: xml.Name{Space: re.namespace, Local: "Results"}}); err != nil {
+		return err
+	}
+	return re.encoder.Flush()
+}
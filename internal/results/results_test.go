@@ -0,0 +1,138 @@
+package results
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+const namespace = "urn:example:results"
+
+type entry struct {
+	Id string `xml:"id,attr"`
+}
+
+func newEntry(local string) interface{} {
+	if local == "Entry" {
+		return &entry{}
+	}
+	return nil
+}
+
+func TestDecoderNext(t *testing.T) {
+	const raw = `<Results xmlns="urn:example:results" size="2"><Entry id="a"/><Other/><Entry id="b"/></Results>`
+	rd := NewDecoder(strings.NewReader(raw), namespace, newEntry)
+	var ids []string
+	for {
+		v, err := rd.Next()
+		if err == io.EOF {
+			break
+		}
+		if nil != err {
+			t.Log(err)
+			t.FailNow()
+		}
+		ids = append(ids, v.(*entry).Id)
+	}
+	if 2 != rd.Size() || "a,b" != strings.Join(ids, ",") {
+		t.Log("Unexpected entries", rd.Size(), ids)
+		t.Fail()
+	}
+
+	rd = NewDecoder(strings.NewReader(`<Results xmlns="urn:example:other"/>`), namespace, newEntry)
+	if _, err := rd.Next(); nil == err {
+		t.Log("Expected Results in another namespace to be refused")
+		t.Fail()
+	}
+}
+
+func TestDecoderStreamCancel(t *testing.T) {
+	const raw = `<Results xmlns="urn:example:results"><Entry id="a"/><Entry id="b"/><Entry id="c"/></Results>`
+	ctx, cancel := context.WithCancel(context.Background())
+	entries, errs := NewDecoder(strings.NewReader(raw), namespace, newEntry).Stream(ctx)
+
+	first, ok := <-entries
+	if !ok || "a" != first.(*entry).Id {
+		t.Log("Unexpected first entry", first)
+		t.FailNow()
+	}
+	cancel()
+	// the goroutine is blocked sending the second entry, or about to check ctx;
+	// either way it stops without sending the third
+	var received int
+	for range entries {
+		received++
+	}
+	if received > 1 {
+		t.Log("Expected streaming to stop once cancelled, got", received, "more entries")
+		t.Fail()
+	}
+	if err := <-errs; err != context.Canceled {
+		t.Log("Expected the cancellation to be reported, got", err)
+		t.Fail()
+	}
+	if _, ok := <-errs; ok {
+		t.Log("Expected the error channel to be closed")
+		t.Fail()
+	}
+}
+
+func TestDecoderStreamError(t *testing.T) {
+	const raw = `<Results xmlns="urn:example:results"><Entry id="a"/>`
+	entries, errs := NewDecoder(strings.NewReader(raw), namespace, newEntry).Stream(context.Background())
+	var received int
+	for range entries {
+		received++
+	}
+	if 1 != received {
+		t.Log("Expected the entry before the error, got", received)
+		t.Fail()
+	}
+	if err := <-errs; nil == err {
+		t.Log("Expected the truncated document to be reported")
+		t.Fail()
+	}
+}
+
+func TestEncoderCloseWithoutSize(t *testing.T) {
+	var buf bytes.Buffer
+	re := NewEncoder(&buf, namespace)
+	if err := re.Close(); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if `<Results xmlns="urn:example:results"></Results>` != buf.String() {
+		t.Log("Unexpected empty Results", buf.String())
+		t.Fail()
+	}
+	if err := re.Close(); nil != err {
+		t.Log("Expected a second Close to do nothing, got", err)
+		t.Fail()
+	}
+	if err := re.Encode(&entry{Id: "a"}); nil == err {
+		t.Log("Expected Encode after Close to fail")
+		t.Fail()
+	}
+}
+
+func TestEncoderSize(t *testing.T) {
+	var buf bytes.Buffer
+	re := NewEncoder(&buf, namespace)
+	re.SetSize(1)
+	if err := re.Encode(&entry{Id: "a"}); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	// too late to change the size once the Results element is written
+	re.SetSize(2)
+	if err := re.Close(); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if `<Results xmlns="urn:example:results" size="1"><entry id="a"></entry></Results>` != buf.String() {
+		t.Log("Unexpected Results", buf.String())
+		t.Fail()
+	}
+}
@@ -0,0 +1,43 @@
+// Package roundtrip holds the checks the fuzz targets of the version packages
+// share, which differ only in their root types and seeds.
+package roundtrip
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+)
+
+// Check decodes raw into each of the values newRoots returns, and fails t unless
+// each one that decodes encodes to XML that decodes and encodes to the same
+// bytes. check, if not nil, is given each decoded value and its encoding for the
+// checks of a version.
+func Check(t *testing.T, raw []byte, newRoots func() []interface{}, check func(t *testing.T, v interface{}, encoded []byte)) {
+	t.Helper()
+	roots, again := newRoots(), newRoots()
+	for i, v := range roots {
+		if err := xml.Unmarshal(raw, v); nil != err {
+			continue
+		}
+		encoded, err := xml.Marshal(v)
+		if nil != err {
+			continue
+		}
+		if nil != check {
+			check(t, v, encoded)
+		}
+		if err := xml.Unmarshal(encoded, again[i]); nil != err {
+			t.Logf("%T: decoding the encoding of a decoded value: %v\n%s", v, err, encoded)
+			t.FailNow()
+		}
+		reencoded, err := xml.Marshal(again[i])
+		if nil != err {
+			t.Logf("%T: %v", v, err)
+			t.FailNow()
+		}
+		if !bytes.Equal(encoded, reencoded) {
+			t.Logf("%T: encoding is not stable\n%s\n%s", v, encoded, reencoded)
+			t.FailNow()
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Package xmlns works around how encoding/xml handles namespace declarations
+// and prefixes, for the version packages.
+package xmlns
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+)
+
+const xmlnsPrefix = "xmlns"
+
+// LiteralAttrs returns the attributes of a decoded element with its namespace
+// declarations, and the attributes using a prefix they declare, named as they
+// were written, so that encoding/xml writes them back as they were.
+//
+// encoding/xml decodes xmlns:p="url" as an attribute in the namespace "xmlns",
+// which it encodes as _xmlns:p along with a declaration of the "xmlns" namespace,
+// and an element then grows one more of those every time it is decoded and
+// encoded again. The declarations matter to the raw XML of the children of Any
+// nodes, which may use their prefixes.
+func LiteralAttrs(attrs []xml.Attr) []xml.Attr {
+	prefixes := map[string]string{}
+	for _, attr := range attrs {
+		if attr.Name.Space == xmlnsPrefix {
+			prefixes[attr.Value] = attr.Name.Local
+		}
+	}
+	if len(prefixes) == 0 {
+		return attrs
+	}
+	literal := make([]xml.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		if attr.Name.Space == xmlnsPrefix {
+			attr.Name = xml.Name{Local: xmlnsPrefix + ":" + attr.Name.Local}
+		} else if prefix, ok := prefixes[attr.Name.Space]; ok && attr.Name.Space != "" {
+			attr.Name = xml.Name{Local: prefix + ":" + attr.Name.Local}
+		}
+		literal = append(literal, attr)
+	}
+	return literal
+}
+
+// Unmodeled returns the attributes of a decoded element that v, the value it was
+// decoded into, has no field for, so that they can be written back along with
+// v. The declaration of the default namespace is left out, as encoding/xml
+// writes it from the name of the element; so is every attribute when v keeps
+// them all in an ",any,attr" field.
+func Unmodeled(attrs []xml.Attr, v interface{}) []xml.Attr {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var fields []xml.Name
+	if t != nil && t.Kind() == reflect.Struct {
+		var all bool
+		fields, all = attrFields(t)
+		if all {
+			return nil
+		}
+	}
+	var unmodeled []xml.Attr
+	for _, attr := range attrs {
+		if attr.Name.Space == "" && attr.Name.Local == xmlnsPrefix {
+			continue
+		}
+		modeled := false
+		for _, field := range fields {
+			// the rule encoding/xml matches attributes to fields by
+			if field.Local == attr.Name.Local && (field.Space == "" || field.Space == attr.Name.Space) {
+				modeled = true
+				break
+			}
+		}
+		if !modeled {
+			unmodeled = append(unmodeled, attr)
+		}
+	}
+	return unmodeled
+}
+
+// attrFields returns the names of the attribute fields of a struct type, and
+// whether it has an ",any,attr" field.
+func attrFields(t reflect.Type) (names []xml.Name, all bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("xml")
+		if tag == "-" {
+			continue
+		}
+		name, flags := tag, ""
+		if i := strings.IndexByte(tag, ','); i >= 0 {
+			name, flags = tag[:i], tag[i:]
+		}
+		if field.Anonymous && name == "" && flags == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				more, anyAttr := attrFields(embedded)
+				if anyAttr {
+					return nil, true
+				}
+				names = append(names, more...)
+			}
+			continue
+		}
+		if !strings.Contains(flags+",", ",attr,") {
+			continue
+		}
+		if strings.Contains(flags+",", ",any,") {
+			return nil, true
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if i := strings.LastIndexByte(name, ' '); i >= 0 {
+			names = append(names, xml.Name{Space: name[:i], Local: name[i+1:]})
+		} else {
+			names = append(names, xml.Name{Local: name})
+		}
+	}
+	return names, false
+}
@@ -0,0 +1,85 @@
+package xmlns
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+type node struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Inner   string     `xml:",innerxml"`
+}
+
+func TestLiteralAttrs(t *testing.T) {
+	const raw = `<Other xmlns:x="urn:example:x" x:a="1" xml:lang="en" b="2"><x:c/></Other>`
+	var n node
+	if err := xml.Unmarshal([]byte(raw), &n); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	n.Attrs = LiteralAttrs(n.Attrs)
+	encoded, err := xml.Marshal(n)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if string(encoded) != raw {
+		t.Log("Expected the declaration and prefix to be kept, got", string(encoded))
+		t.Fail()
+	}
+
+	attrs := []xml.Attr{{Name: xml.Name{Space: "urn:example:x", Local: "a"}}}
+	if literal := LiteralAttrs(attrs); literal[0].Name.Space != "urn:example:x" {
+		t.Log("Expected attributes without a declaration to be left alone, got", literal)
+		t.Fail()
+	}
+}
+
+type base struct {
+	Id string `xml:"id,attr"`
+}
+
+type modeled struct {
+	base
+	Kind  string `xml:"urn:example:x kind,attr"`
+	Start string `xml:",attr"`
+	Text  string `xml:",chardata"`
+}
+
+type catchAll struct {
+	Attrs []xml.Attr `xml:",any,attr"`
+}
+
+func TestUnmodeled(t *testing.T) {
+	attrs := []xml.Attr{
+		{Name: xml.Name{Local: "xmlns"}, Value: "urn:example"},
+		{Name: xml.Name{Space: "xmlns", Local: "x"}, Value: "urn:example:x"},
+		{Name: xml.Name{Local: "id"}, Value: "1"},
+		{Name: xml.Name{Space: "urn:example:x", Local: "kind"}, Value: "a"},
+		{Name: xml.Name{Local: "kind"}, Value: "b"},
+		{Name: xml.Name{Local: "Start"}, Value: "c"},
+		{Name: xml.Name{Local: "other"}, Value: "d"},
+	}
+	unmodeled := Unmodeled(attrs, &modeled{})
+	expected := []xml.Attr{attrs[1], attrs[4], attrs[6]}
+	if len(unmodeled) != len(expected) {
+		t.Log("Expected", expected, "got", unmodeled)
+		t.FailNow()
+	}
+	for i := range expected {
+		if unmodeled[i] != expected[i] {
+			t.Log("Expected", expected, "got", unmodeled)
+			t.Fail()
+		}
+	}
+
+	if kept := Unmodeled(attrs, &catchAll{}); 0 != len(kept) {
+		t.Log("Expected no attributes for a type keeping them all, got", kept)
+		t.Fail()
+	}
+	if kept := Unmodeled(attrs, new(string)); len(kept) != len(attrs)-1 {
+		t.Log("Expected all but the default namespace for a type without fields, got", kept)
+		t.Fail()
+	}
+}
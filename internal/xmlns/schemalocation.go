@@ -0,0 +1,94 @@
+package xmlns
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+const xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// A Schema pairs a namespace with the location of the schema defining it.
+type Schema struct {
+	Namespace string
+	Location  string
+}
+
+// AddSchemaLocation returns raw with an xsi:schemaLocation attribute added to its
+// root element. The attribute lists, in the order given, the schemas whose
+// namespace is used by an element or attribute of the document; schemas of other
+// namespaces are left out. The xsi prefix is declared on the root unless it
+// already is.
+func AddSchemaLocation(raw []byte, schemas ...Schema) ([]byte, error) {
+	used := make(map[string]bool)
+	d := xml.NewDecoder(bytes.NewReader(raw))
+	var root *xml.StartElement
+	var rootEnd int64
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		t, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if root == nil {
+			root = &t
+			rootEnd = d.InputOffset()
+		}
+		used[t.Name.Space] = true
+		for _, a := range t.Attr {
+			if a.Name.Space != "xmlns" && !(a.Name.Space == "" && a.Name.Local == "xmlns") {
+				used[a.Name.Space] = true
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("no root element")
+	}
+
+	var pairs []string
+	for _, schema := range schemas {
+		if used[schema.Namespace] {
+			pairs = append(pairs, schema.Namespace, schema.Location)
+		}
+	}
+	if len(pairs) == 0 {
+		return raw, nil
+	}
+
+	// the attributes go at the end of the root start tag, before > or />
+	insert := int(rootEnd) - 1
+	if raw[insert-1] == '/' {
+		insert--
+	}
+	var attrs bytes.Buffer
+	if !declaresXSI(root) {
+		attrs.WriteString(` xmlns:xsi="` + xsiNamespace + `"`)
+	}
+	attrs.WriteString(` xsi:schemaLocation="`)
+	xml.EscapeText(&attrs, []byte(strings.Join(pairs, " ")))
+	attrs.WriteByte('"')
+
+	result := make([]byte, 0, len(raw)+attrs.Len())
+	result = append(result, raw[:insert]...)
+	result = append(result, attrs.Bytes()...)
+	return append(result, raw[insert:]...), nil
+}
+
+// declaresXSI reports whether the root element binds the xsi prefix to the
+// XMLSchema-instance namespace.
+func declaresXSI(root *xml.StartElement) bool {
+	for _, a := range root.Attr {
+		if a.Name.Space == "xmlns" && a.Name.Local == "xsi" && a.Value == xsiNamespace {
+			return true
+		}
+	}
+	return false
+}
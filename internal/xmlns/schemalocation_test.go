@@ -0,0 +1,44 @@
+package xmlns
+
+import (
+	"testing"
+)
+
+var schemas = []Schema{
+	{Namespace: "http://www.scte.org/schemas/224", Location: "esni.xsd"},
+	{Namespace: "urn:scte:224:action", Location: "action.xsd"},
+}
+
+func TestAddSchemaLocation(t *testing.T) {
+	tests := []struct {
+		raw, expected string
+	}{
+		// unused namespaces are left out, and self-closed roots keep their />
+		{`<Media xmlns="http://www.scte.org/schemas/224"/>`,
+			`<Media xmlns="http://www.scte.org/schemas/224" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://www.scte.org/schemas/224 esni.xsd"/>`},
+		// namespaces of nested elements count, and an xsi declaration is reused
+		{`<esni:ViewingPolicy xmlns:esni="http://www.scte.org/schemas/224" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"><Content xmlns="urn:scte:224:action">replace</Content></esni:ViewingPolicy>`,
+			`<esni:ViewingPolicy xmlns:esni="http://www.scte.org/schemas/224" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://www.scte.org/schemas/224 esni.xsd urn:scte:224:action action.xsd"><Content xmlns="urn:scte:224:action">replace</Content></esni:ViewingPolicy>`},
+		// nothing is added to documents without a listed namespace
+		{`<Other/>`, `<Other/>`},
+	}
+	for _, test := range tests {
+		actual, err := AddSchemaLocation([]byte(test.raw), schemas...)
+		if nil != err {
+			t.Log(err)
+			t.Fail()
+			continue
+		}
+		if test.expected != string(actual) {
+			t.Log(string(actual))
+			t.Log("did not match")
+			t.Log(test.expected)
+			t.Fail()
+		}
+	}
+
+	if _, err := AddSchemaLocation([]byte(`<Media>`), schemas...); nil == err {
+		t.Log("Malformed XML should not be accepted")
+		t.Fail()
+	}
+}
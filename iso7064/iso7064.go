@@ -0,0 +1,45 @@
+// Package iso7064 computes the ISO 7064 MOD 37,36 check characters of EIDR and
+// ISAN identifiers.
+package iso7064
+
+import (
+	"fmt"
+	"strings"
+)
+
+const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// Mod3736 returns the check character of s, a string of digits and letters, case
+// ignored.
+func Mod3736(s string) (byte, error) {
+	p := 36
+	for i := 0; i < len(s); i++ {
+		v := strings.IndexByte(alphabet, upper(s[i]))
+		if v < 0 {
+			return 0, fmt.Errorf("iso7064: %q is not alphanumeric", s[i])
+		}
+		sum := (p + v) % 36
+		if sum == 0 {
+			sum = 36
+		}
+		p = 2 * sum % 37
+	}
+	return alphabet[(37-p)%36], nil
+}
+
+// ValidMod3736 reports whether the last character of s is the check character of
+// the characters before it.
+func ValidMod3736(s string) bool {
+	if len(s) < 2 {
+		return false
+	}
+	check, err := Mod3736(s[:len(s)-1])
+	return err == nil && check == upper(s[len(s)-1])
+}
+
+func upper(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
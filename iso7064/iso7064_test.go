@@ -0,0 +1,29 @@
+package iso7064
+
+import "testing"
+
+func TestMod3736(t *testing.T) {
+	for s, expected := range map[string]byte{
+		"779185342C2390308610":     '5', // EIDR 10.5240/7791-8534-2C23-9030-8610-5
+		"000000003A8D0000":         'Z', // ISAN 0000-0000-3A8D-0000-Z
+		"000000003a8d000000000000": '6', // ISAN 0000-0000-3A8D-0000-Z-0000-0000-6
+	} {
+		check, err := Mod3736(s)
+		if nil != err || check != expected {
+			t.Logf("Expected %c for %s, got %c (%v)", expected, s, check, err)
+			t.Fail()
+		}
+		if !ValidMod3736(s + string(expected)) {
+			t.Logf("Expected %s%c to be valid", s, expected)
+			t.Fail()
+		}
+	}
+	if ValidMod3736("779185342C23903086104") || ValidMod3736("5") || ValidMod3736("7791-5") {
+		t.Log("Expected invalid check characters to be rejected")
+		t.Fail()
+	}
+	if _, err := Mod3736("7791-8534"); nil == err {
+		t.Log("Expected an error for a separator")
+		t.Fail()
+	}
+}
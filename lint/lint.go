@@ -0,0 +1,364 @@
+// Package lint reports semantic problems in SCTE 224 documents that are valid
+// against their schema, such as a blackout that is applied but never removed.
+//
+// Rules are plain values and can be added, disabled or given another severity:
+//
+//	linter := lint.New(lint.DefaultRules...)
+//	linter.Disable("duplicate-order")
+//	findings, err := linter.Lint(&media)
+//
+// Documents of every schema version are accepted; 2015 and 2018 documents are
+// upgraded to the 2020 structs before the rules see them, so rules are written
+// once, against the 2020 package.
+package lint
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Comcast/scte224structs/convert"
+	scte224_2015 "github.com/Comcast/scte224structs/types/scte224v20151115"
+	scte224_2018 "github.com/Comcast/scte224structs/types/scte224v20180501"
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+// Severity tells how serious a finding is.
+type Severity int
+
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+var severityNames = []string{"info", "warning", "error"}
+
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return "severity(" + strconv.Itoa(int(s)) + ")"
+	}
+	return severityNames[s]
+}
+
+// MarshalText writes the severity by name, as findings are reported in JSON.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText reads a severity name.
+func (s *Severity) UnmarshalText(text []byte) error {
+	for i, name := range severityNames {
+		if string(text) == name {
+			*s = Severity(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("lint: unknown severity %q", text)
+}
+
+// A Finding is a problem reported by a rule.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	// Path locates the object in the document, as in /Media[@id='m']/MediaPoint[2]/Apply[1].
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s %s %s: %s", f.Severity, f.Rule, f.Path, f.Message)
+}
+
+// A Rule checks a document and reports each problem it finds along with the path
+// of the object concerned.
+type Rule struct {
+	Name        string
+	Description string
+	Severity    Severity
+	Check       func(doc *Document, report func(path, message string))
+}
+
+// A Linter checks documents against a set of rules.
+type Linter struct {
+	rules []Rule
+}
+
+// New returns a Linter with the given rules.
+func New(rules ...Rule) *Linter {
+	return &Linter{rules: append([]Rule(nil), rules...)}
+}
+
+// Add adds a rule, replacing the rule of the same name if there is one.
+func (l *Linter) Add(rule Rule) {
+	for i := range l.rules {
+		if l.rules[i].Name == rule.Name {
+			l.rules[i] = rule
+			return
+		}
+	}
+	l.rules = append(l.rules, rule)
+}
+
+// Disable removes the named rule, and reports whether there was one.
+func (l *Linter) Disable(name string) bool {
+	for i := range l.rules {
+		if l.rules[i].Name == name {
+			l.rules = append(l.rules[:i], l.rules[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// SetSeverity changes the severity the named rule reports with, and reports
+// whether there is such a rule.
+func (l *Linter) SetSeverity(name string, severity Severity) bool {
+	for i := range l.rules {
+		if l.rules[i].Name == name {
+			l.rules[i].Severity = severity
+			return true
+		}
+	}
+	return false
+}
+
+// Rules returns the rules of the Linter, in the order they run.
+func (l *Linter) Rules() []Rule {
+	return append([]Rule(nil), l.rules...)
+}
+
+// Lint checks a Media, MediaPoint, Policy, ViewingPolicy, Audience or Results of
+// any version package and returns the findings of every rule, rule by rule.
+func (l *Linter) Lint(v interface{}) ([]Finding, error) {
+	doc, err := NewDocument(v)
+	if err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	for _, rule := range l.rules {
+		rule.Check(doc, func(path, message string) {
+			findings = append(findings, Finding{Rule: rule.Name, Severity: rule.Severity, Path: path, Message: message})
+		})
+	}
+	return findings, nil
+}
+
+// Lint checks v with the DefaultRules.
+func Lint(v interface{}) ([]Finding, error) {
+	return New(DefaultRules...).Lint(v)
+}
+
+//********************* Documents *************************//
+
+// A Document is a linted value in the 2020 structs, with the paths of its objects.
+type Document struct {
+	root interface{}
+}
+
+// NewDocument upgrades v to the 2020 structs if it is of an older version.
+func NewDocument(v interface{}) (*Document, error) {
+	upgraded, err := upgrade(v)
+	if err != nil {
+		return nil, err
+	}
+	switch upgraded.(type) {
+	case *scte224.Media, *scte224.MediaPoint, *scte224.Policy, *scte224.ViewingPolicy, *scte224.Audience, *scte224.Results:
+		return &Document{root: upgraded}, nil
+	}
+	return nil, fmt.Errorf("lint: cannot lint %T", v)
+}
+
+// upgrade converts 2015 values to 2018 with the convert package, and 2018 values to
+// 2020 by decoding their XML into the 2020 structs, as both share a namespace.
+func upgrade(v interface{}) (interface{}, error) {
+	switch v2015 := v.(type) {
+	case *scte224_2015.Media:
+		media := convert.UpgradeMedia(*v2015)
+		v = &media
+	case *scte224_2015.MediaPoint:
+		mp := convert.UpgradeMediaPoint(*v2015)
+		v = &mp
+	case *scte224_2015.Policy:
+		p := convert.UpgradePolicy(*v2015)
+		v = &p
+	case *scte224_2015.ViewingPolicy:
+		vp := convert.UpgradeViewingPolicy(*v2015)
+		v = &vp
+	case *scte224_2015.Audience:
+		aud := convert.UpgradeAudience(*v2015)
+		v = &aud
+	case *scte224_2015.Results:
+		results := &scte224_2018.Results{Size: v2015.Size}
+		for _, media := range v2015.Medias {
+			if media != nil {
+				upgraded := convert.UpgradeMedia(*media)
+				results.Medias = append(results.Medias, &upgraded)
+			}
+		}
+		for _, mp := range v2015.MediaPoints {
+			if mp != nil {
+				upgraded := convert.UpgradeMediaPoint(*mp)
+				results.MediaPoints = append(results.MediaPoints, &upgraded)
+			}
+		}
+		for _, p := range v2015.Policys {
+			if p != nil {
+				upgraded := convert.UpgradePolicy(*p)
+				results.Policys = append(results.Policys, &upgraded)
+			}
+		}
+		for _, vp := range v2015.ViewingPolicys {
+			if vp != nil {
+				upgraded := convert.UpgradeViewingPolicy(*vp)
+				results.ViewingPolicys = append(results.ViewingPolicys, &upgraded)
+			}
+		}
+		for _, aud := range v2015.Audiences {
+			if aud != nil {
+				upgraded := convert.UpgradeAudience(*aud)
+				results.Audiences = append(results.Audiences, &upgraded)
+			}
+		}
+		v = results
+	}
+
+	var v2020 interface{}
+	switch v.(type) {
+	case *scte224_2018.Media:
+		v2020 = &scte224.Media{}
+	case *scte224_2018.MediaPoint:
+		v2020 = &scte224.MediaPoint{}
+	case *scte224_2018.Policy:
+		v2020 = &scte224.Policy{}
+	case *scte224_2018.ViewingPolicy:
+		v2020 = &scte224.ViewingPolicy{}
+	case *scte224_2018.Audience:
+		v2020 = &scte224.Audience{}
+	case *scte224_2018.Results:
+		v2020 = &scte224.Results{}
+	default:
+		return v, nil
+	}
+	raw, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := xml.Unmarshal(raw, v2020); err != nil {
+		return nil, err
+	}
+	return v2020, nil
+}
+
+// Medias calls f for each Media of the document. MediaPoints outside of a Media,
+// at the root or in Results, are handed over as one Media with the path of their
+// parent, empty at the root, so that rules comparing the points of a Media cover
+// them too.
+func (doc *Document) Medias(f func(path string, media *scte224.Media)) {
+	switch root := doc.root.(type) {
+	case *scte224.Media:
+		f("/Media"+selector(root.Id, 0), root)
+	case *scte224.MediaPoint:
+		f("", &scte224.Media{MediaPoints: []*scte224.MediaPoint{root}})
+	case *scte224.Results:
+		for i, media := range root.Medias {
+			if media != nil {
+				f("/Results/Media"+selector(media.Id, i+1), media)
+			}
+		}
+		if len(root.MediaPoints) > 0 {
+			f("/Results", &scte224.Media{MediaPoints: root.MediaPoints})
+		}
+	}
+}
+
+// MediaPointPath returns the path of the MediaPoint at index i of a Media found
+// at mediaPath by Medias.
+func MediaPointPath(mediaPath string, mp *scte224.MediaPoint, i int) string {
+	if mediaPath == "" {
+		return "/MediaPoint" + selector(mp.Id, 0)
+	}
+	return mediaPath + "/MediaPoint" + selector(mp.Id, i+1)
+}
+
+// MediaPoints calls f for each MediaPoint of the document, wherever it is.
+func (doc *Document) MediaPoints(f func(path string, mp *scte224.MediaPoint)) {
+	doc.Medias(func(mediaPath string, media *scte224.Media) {
+		for i, mp := range media.MediaPoints {
+			if mp != nil {
+				f(MediaPointPath(mediaPath, mp, i), mp)
+			}
+		}
+	})
+}
+
+// Audiences calls f for each Audience of the document, nested ones included,
+// wherever it is: below the policies applied by MediaPoints, or at the root.
+func (doc *Document) Audiences(f func(path string, aud *scte224.Audience)) {
+	var audience func(path string, aud *scte224.Audience)
+	audience = func(path string, aud *scte224.Audience) {
+		f(path, aud)
+		for i, nested := range aud.Audiences {
+			if nested != nil {
+				audience(path+"/Audience"+selector(nested.Id, i+1), nested)
+			}
+		}
+	}
+	viewingPolicy := func(path string, vp *scte224.ViewingPolicy) {
+		if vp.Audience != nil {
+			audience(path+"/Audience", vp.Audience)
+		}
+	}
+	policy := func(path string, p *scte224.Policy) {
+		for i, vp := range p.ViewingPolicys {
+			if vp != nil {
+				viewingPolicy(path+"/ViewingPolicy"+selector(vp.Id, i+1), vp)
+			}
+		}
+	}
+
+	doc.MediaPoints(func(path string, mp *scte224.MediaPoint) {
+		for i, apply := range mp.Applys {
+			if apply != nil && apply.Policy != nil {
+				policy(path+"/Apply"+selector("", i+1)+"/Policy", apply.Policy)
+			}
+		}
+	})
+	switch root := doc.root.(type) {
+	case *scte224.Policy:
+		policy("/Policy"+selector(root.Id, 0), root)
+	case *scte224.ViewingPolicy:
+		viewingPolicy("/ViewingPolicy"+selector(root.Id, 0), root)
+	case *scte224.Audience:
+		audience("/Audience"+selector(root.Id, 0), root)
+	case *scte224.Results:
+		for i, p := range root.Policys {
+			if p != nil {
+				policy("/Results/Policy"+selector(p.Id, i+1), p)
+			}
+		}
+		for i, vp := range root.ViewingPolicys {
+			if vp != nil {
+				viewingPolicy("/Results/ViewingPolicy"+selector(vp.Id, i+1), vp)
+			}
+		}
+		for i, aud := range root.Audiences {
+			if aud != nil {
+				audience("/Results/Audience"+selector(aud.Id, i+1), aud)
+			}
+		}
+	}
+}
+
+// selector identifies an object among its siblings by id when it has one, or else
+// by its position, counted from 1; a position of 0 is left out.
+func selector(id string, position int) string {
+	switch {
+	case id != "":
+		return "[@id='" + strings.Replace(id, "'", "&apos;", -1) + "']"
+	case position > 0:
+		return "[" + strconv.Itoa(position) + "]"
+	}
+	return ""
+}
@@ -0,0 +1,168 @@
+package lint
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	scte224_2015 "github.com/Comcast/scte224structs/types/scte224v20151115"
+	scte224_2018 "github.com/Comcast/scte224structs/types/scte224v20180501"
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+const problems = `<Media xmlns="http://www.scte.org/schemas/224" id="m" effective="2020-01-01T00:00:00Z">
+	<MediaPoint id="start" effective="2020-01-02T00:00:00Z" expires="2020-01-01T00:00:00Z" order="1">
+		<Apply><Policy id="forever"/></Apply>
+		<Apply duration="PT1H"><Policy id="hour"/></Apply>
+		<Apply>
+			<Policy id="blackout">
+				<ViewingPolicy id="vp">
+					<Audience match="NONE"/>
+				</ViewingPolicy>
+			</Policy>
+		</Apply>
+		<MatchSignal match="ALL"/>
+	</MediaPoint>
+	<MediaPoint order="1">
+		<Remove><Policy id="blackout"/></Remove>
+		<Remove><Policy xlink:href="/policies/unknown" xmlns:xlink="http://www.w3.org/1999/xlink"/></Remove>
+	</MediaPoint>
+</Media>`
+
+func TestDefaultRules(t *testing.T) {
+	media := scte224.Media{}
+	if err := xml.Unmarshal([]byte(problems), &media); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	findings, err := Lint(&media)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	expected := []Finding{
+		{"apply-never-removed", Warning, "/Media[@id='m']/MediaPoint[@id='start']/Apply[1]", `policy "forever" is applied without a duration and never removed`},
+		{"expires-before-effective", Error, "/Media[@id='m']/MediaPoint[@id='start']", "expires 2020-01-01T00:00:00Z precedes effective 2020-01-02T00:00:00Z"},
+		{"match-signal-without-assert", Warning, "/Media[@id='m']/MediaPoint[@id='start']/MatchSignal", "MatchSignal has no Assert"},
+		{"duplicate-order", Warning, "/Media[@id='m']/MediaPoint[2]", "order 1 is also the order of /Media[@id='m']/MediaPoint[@id='start']"},
+		{"remove-unknown-policy", Warning, "/Media[@id='m']/MediaPoint[2]/Remove[2]", `policy "/policies/unknown" is removed but never applied`},
+		{"empty-none-audience", Warning, "/Media[@id='m']/MediaPoint[@id='start']/Apply[3]/Policy/ViewingPolicy[@id='vp']/Audience", "Audience matches NONE but has no children"},
+	}
+	if !reflect.DeepEqual(expected, findings) {
+		t.Logf("Expected %v, got %v", expected, findings)
+		t.Fail()
+	}
+}
+
+func TestAllVersions(t *testing.T) {
+	const media2015 = `<Media xmlns="http://www.scte.org/schemas/224/2015" id="m">
+	<MediaPoint id="mp"><MatchSignal match="ALL"/></MediaPoint>
+</Media>`
+	v2015 := &scte224_2015.Media{}
+	if err := xml.Unmarshal([]byte(media2015), v2015); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	v2018 := &scte224_2018.Media{}
+	if err := xml.Unmarshal([]byte(problems), v2018); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	v2020 := &scte224.Media{}
+	if err := xml.Unmarshal([]byte(problems), v2020); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+
+	findings, err := Lint(v2015)
+	if nil != err || len(findings) != 1 || findings[0].Path != "/Media[@id='m']/MediaPoint[@id='mp']/MatchSignal" {
+		t.Log("Unexpected findings for 2015", findings, err)
+		t.Fail()
+	}
+	from2018, err := Lint(v2018)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	from2020, _ := Lint(v2020)
+	if !reflect.DeepEqual(from2020, from2018) {
+		t.Logf("2018 findings %v differ from 2020 findings %v", from2018, from2020)
+		t.Fail()
+	}
+
+	if _, err := Lint(media2015); nil == err {
+		t.Log("Expected an error for a value that is not a document")
+		t.Fail()
+	}
+}
+
+func TestResultsPaths(t *testing.T) {
+	results := &scte224.Results{
+		MediaPoints: []*scte224.MediaPoint{{MatchSignal: &scte224.MatchSignal{}}},
+		Audiences:   []*scte224.Audience{{Match: "NONE"}},
+	}
+	findings, err := Lint(results)
+	if nil != err || len(findings) != 2 ||
+		findings[0].Path != "/Results/MediaPoint[1]/MatchSignal" ||
+		findings[1].Path != "/Results/Audience[1]" {
+		t.Log("Unexpected findings", findings, err)
+		t.Fail()
+	}
+
+	findings, _ = Lint(&scte224.MediaPoint{IdentifiableType: scte224.IdentifiableType{Id: "mp"}, MatchSignal: &scte224.MatchSignal{}})
+	if len(findings) != 1 || findings[0].Path != "/MediaPoint[@id='mp']/MatchSignal" {
+		t.Log("Unexpected findings", findings)
+		t.Fail()
+	}
+}
+
+func TestLinterConfiguration(t *testing.T) {
+	media := scte224.Media{}
+	if err := xml.Unmarshal([]byte(problems), &media); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	linter := New(DefaultRules...)
+	if !linter.Disable("apply-never-removed") || linter.Disable("no-such-rule") {
+		t.Log("Disable did not report whether the rule existed")
+		t.Fail()
+	}
+	linter.SetSeverity("duplicate-order", Error)
+	linter.Add(Rule{
+		Name:     "media-without-source",
+		Severity: Info,
+		Check: func(doc *Document, report func(path, message string)) {
+			doc.Medias(func(path string, media *scte224.Media) {
+				if media.Source == "" {
+					report(path, "no source")
+				}
+			})
+		},
+	})
+
+	findings, err := linter.Lint(&media)
+	if nil != err || len(findings) != 6 {
+		t.Log("Unexpected findings", findings, err)
+		t.FailNow()
+	}
+	if findings[0].Rule != "expires-before-effective" || findings[2].Severity != Error || findings[5].Rule != "media-without-source" {
+		t.Log("Configuration not applied", findings)
+		t.Fail()
+	}
+	if len(DefaultRules) != 6 || DefaultRules[3].Severity != Warning {
+		t.Log("Linter changed the default rules")
+		t.Fail()
+	}
+
+	encoded, err := json.Marshal(findings[5])
+	if nil != err || string(encoded) != `{"rule":"media-without-source","severity":"info","path":"/Media[@id='m']","message":"no source"}` {
+		t.Log("Unexpected JSON", string(encoded), err)
+		t.Fail()
+	}
+	var decoded Finding
+	if err := json.Unmarshal(encoded, &decoded); nil != err || decoded != findings[5] {
+		t.Log("Finding did not roundtrip", decoded, err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,191 @@
+package lint
+
+import (
+	"fmt"
+
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+	"github.com/Comcast/scte224structs/types/xsd"
+)
+
+// DefaultRules are the rules run by Lint.
+var DefaultRules = []Rule{
+	ApplyNeverRemoved,
+	ExpiresBeforeEffective,
+	MatchSignalWithoutAssert,
+	DuplicateOrder,
+	RemoveUnknownPolicy,
+	EmptyNoneAudience,
+}
+
+// ApplyNeverRemoved reports policies applied without a duration that no
+// MediaPoint of the Media removes: once applied, they stay in force for good.
+var ApplyNeverRemoved = Rule{
+	Name:        "apply-never-removed",
+	Description: "an Apply without a duration whose policy is never removed",
+	Severity:    Warning,
+	Check: func(doc *Document, report func(path, message string)) {
+		doc.Medias(func(mediaPath string, media *scte224.Media) {
+			removed := make(map[string]bool)
+			for _, mp := range media.MediaPoints {
+				if mp != nil {
+					for _, remove := range mp.Removes {
+						if remove != nil {
+							removed[policyKey(remove.Policy)] = true
+						}
+					}
+				}
+			}
+			for i, mp := range media.MediaPoints {
+				if mp == nil {
+					continue
+				}
+				for j, apply := range mp.Applys {
+					if apply == nil || apply.Policy == nil || apply.Duration != "" {
+						continue
+					}
+					key := policyKey(apply.Policy)
+					if key == "" || !removed[key] {
+						report(MediaPointPath(mediaPath, mp, i)+"/Apply"+selector("", j+1),
+							fmt.Sprintf("policy %q is applied without a duration and never removed", key))
+					}
+				}
+			}
+		})
+	},
+}
+
+// ExpiresBeforeEffective reports Media and MediaPoints that expire before they
+// become effective, and so are never in force.
+var ExpiresBeforeEffective = Rule{
+	Name:        "expires-before-effective",
+	Description: "a Media or MediaPoint whose expires precedes its effective",
+	Severity:    Error,
+	Check: func(doc *Document, report func(path, message string)) {
+		doc.Medias(func(mediaPath string, media *scte224.Media) {
+			if message, ok := expiresBeforeEffective(media.Effective, media.Expires); ok {
+				report(mediaPath, message)
+			}
+			for i, mp := range media.MediaPoints {
+				if mp != nil {
+					if message, ok := expiresBeforeEffective(mp.Effective, mp.Expires); ok {
+						report(MediaPointPath(mediaPath, mp, i), message)
+					}
+				}
+			}
+		})
+	},
+}
+
+// MatchSignalWithoutAssert reports MatchSignals without an Assert, which no
+// signal can match.
+var MatchSignalWithoutAssert = Rule{
+	Name:        "match-signal-without-assert",
+	Description: "a MatchSignal without an Assert",
+	Severity:    Warning,
+	Check: func(doc *Document, report func(path, message string)) {
+		doc.MediaPoints(func(path string, mp *scte224.MediaPoint) {
+			if mp.MatchSignal != nil && len(mp.MatchSignal.Assertions) == 0 {
+				report(path+"/MatchSignal", "MatchSignal has no Assert")
+			}
+		})
+	},
+}
+
+// DuplicateOrder reports MediaPoints of a Media with the same explicit order,
+// which leaves the order they are processed in undefined.
+var DuplicateOrder = Rule{
+	Name:        "duplicate-order",
+	Description: "MediaPoints of a Media with the same order",
+	Severity:    Warning,
+	Check: func(doc *Document, report func(path, message string)) {
+		doc.Medias(func(mediaPath string, media *scte224.Media) {
+			first := make(map[uint]string)
+			for i, mp := range media.MediaPoints {
+				if mp == nil || !mp.HasExplicitOrder() {
+					continue
+				}
+				path := MediaPointPath(mediaPath, mp, i)
+				if other, ok := first[mp.GetOrder()]; ok {
+					report(path, fmt.Sprintf("order %d is also the order of %s", mp.GetOrder(), other))
+					continue
+				}
+				first[mp.GetOrder()] = path
+			}
+		})
+	},
+}
+
+// RemoveUnknownPolicy reports Removes of a policy that no MediaPoint of the
+// Media applies.
+var RemoveUnknownPolicy = Rule{
+	Name:        "remove-unknown-policy",
+	Description: "a Remove of a policy that is never applied",
+	Severity:    Warning,
+	Check: func(doc *Document, report func(path, message string)) {
+		doc.Medias(func(mediaPath string, media *scte224.Media) {
+			applied := make(map[string]bool)
+			for _, mp := range media.MediaPoints {
+				if mp != nil {
+					for _, apply := range mp.Applys {
+						if apply != nil {
+							applied[policyKey(apply.Policy)] = true
+						}
+					}
+				}
+			}
+			for i, mp := range media.MediaPoints {
+				if mp == nil {
+					continue
+				}
+				for j, remove := range mp.Removes {
+					if remove == nil {
+						continue
+					}
+					key := policyKey(remove.Policy)
+					if key != "" && !applied[key] {
+						report(MediaPointPath(mediaPath, mp, i)+"/Remove"+selector("", j+1),
+							fmt.Sprintf("policy %q is removed but never applied", key))
+					}
+				}
+			}
+		})
+	},
+}
+
+// EmptyNoneAudience reports audiences matching NONE of nothing, which match every
+// viewer. Audiences referring to another by href are left alone.
+var EmptyNoneAudience = Rule{
+	Name:        "empty-none-audience",
+	Description: "an Audience with match NONE and no children",
+	Severity:    Warning,
+	Check: func(doc *Document, report func(path, message string)) {
+		doc.Audiences(func(path string, aud *scte224.Audience) {
+			if aud.Match.IsNone() && aud.XLinkHRef == "" && len(aud.Audiences) == 0 && len(aud.AudienceProperty) == 0 {
+				report(path, "Audience matches NONE but has no children")
+			}
+		})
+	},
+}
+
+// policyKey identifies a policy by its id, or by the href of a reference to it.
+func policyKey(p *scte224.Policy) string {
+	if p == nil {
+		return ""
+	}
+	if p.Id != "" {
+		return p.Id
+	}
+	return p.XLinkHRef
+}
+
+// expiresBeforeEffective reports whether expires is an instant before effective,
+// with the message to report if it is.
+func expiresBeforeEffective(effective, expires *xsd.DateTime) (string, bool) {
+	if effective == nil || expires == nil || effective.IsZero() || expires.IsZero() {
+		return "", false
+	}
+	if !expires.Time().Before(effective.Time()) {
+		return "", false
+	}
+	return fmt.Sprintf("expires %s precedes effective %s", expires, effective), true
+}
@@ -0,0 +1,458 @@
+// Package namespace writes SCTE 224 objects with their namespaces declared once,
+// on the root element, under conventional prefixes.
+//
+// encoding/xml repeats xmlns="http://www.scte.org/schemas/224" on nearly every
+// element and invents prefixes such as _XMLSchema-instance for attributes. The
+// Encoder of this package marshals a value with encoding/xml, resolves every name
+// of the output, and writes it again using the prefixes it was configured with:
+//
+//	<esni:Media xmlns:esni="http://www.scte.org/schemas/224" xmlns:xlink="http://www.w3.org/1999/xlink" ...>
+//
+// Elements in a namespace without a configured prefix declare it as their default
+// namespace, as encoding/xml does, which keeps the raw XML held by Any nodes
+// self-contained; attributes in such a namespace use a prefix declared as ns1,
+// ns2 and so on. Prefixes within xsi:type values are rewritten along with the
+// names.
+package namespace
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Comcast/scte224structs/types/scte224v20180501/adi30"
+)
+
+const (
+	ESNI2015Namespace = "http://www.scte.org/schemas/224/2015"
+	ESNINamespace     = "http://www.scte.org/schemas/224"
+	ActionNamespace   = "urn:scte:224:action"
+	AudienceNamespace = "urn:scte:224:audience"
+	XLinkNamespace    = "http://www.w3.org/1999/xlink"
+	XMLNamespace      = "http://www.w3.org/XML/1998/namespace"
+)
+
+// DefaultPrefixes are the prefixes an Encoder starts out with, keyed by namespace.
+var DefaultPrefixes = map[string]string{
+	ESNI2015Namespace:      "esni",
+	ESNINamespace:          "esni",
+	ActionNamespace:        "action",
+	AudienceNamespace:      "aud",
+	XLinkNamespace:         "xlink",
+	adi30.CoreNamespace:    "adi3",
+	adi30.OfferNamespace:   "offer",
+	adi30.TitleNamespace:   "title",
+	adi30.ContentNamespace: "content",
+	adi30.TermsNamespace:   "terms",
+	adi30.XSINamespace:     "xsi",
+}
+
+// An Encoder writes values as XML with namespace prefixes declared on the root.
+type Encoder struct {
+	w        io.Writer
+	prefixes map[string]string
+	prefix   string
+	indent   string
+}
+
+// NewEncoder returns an Encoder writing to w with the DefaultPrefixes.
+func NewEncoder(w io.Writer) *Encoder {
+	enc := &Encoder{w: w, prefixes: make(map[string]string, len(DefaultPrefixes))}
+	for namespace, prefix := range DefaultPrefixes {
+		enc.prefixes[namespace] = prefix
+	}
+	return enc
+}
+
+// SetPrefix sets the prefix used for a namespace. An empty prefix makes the
+// namespace the default namespace of the document, written without a prefix.
+func (enc *Encoder) SetPrefix(namespace, prefix string) {
+	enc.prefixes[namespace] = prefix
+}
+
+// Indent sets the encoder to generate XML in which each element begins on a new
+// indented line, as xml.Encoder.Indent does. Rewrite keeps the whitespace of the
+// document it is given.
+func (enc *Encoder) Indent(prefix, indent string) {
+	enc.prefix = prefix
+	enc.indent = indent
+}
+
+// appender is implemented by the types that can write their XML without reflection.
+type appender interface {
+	AppendXML(b []byte) ([]byte, error)
+}
+
+// Encode writes the XML encoding of v.
+func (enc *Encoder) Encode(v interface{}) error {
+	var raw []byte
+	var err error
+	if a, ok := v.(appender); ok && enc.prefix == "" && enc.indent == "" {
+		raw, err = a.AppendXML(nil)
+	} else {
+		raw, err = xml.MarshalIndent(v, enc.prefix, enc.indent)
+	}
+	if err != nil {
+		return err
+	}
+	return enc.Rewrite(raw)
+}
+
+// Rewrite writes an XML document again with the prefixes of the Encoder.
+func (enc *Encoder) Rewrite(raw []byte) error {
+	tokens, used, prefixed, err := resolve(raw)
+	if err != nil {
+		return err
+	}
+
+	w := &writer{prefixes: enc.assign(used, prefixed)}
+	for _, token := range tokens {
+		w.write(token)
+	}
+	w.closeStart()
+	_, err = enc.w.Write(w.buf.Bytes())
+	return err
+}
+
+// assign picks the prefix of each namespace used, in order of first use. Only the
+// namespaces that must be prefixed get a generated prefix.
+func (enc *Encoder) assign(used []string, prefixed map[string]bool) map[string]string {
+	assigned := map[string]string{XMLNamespace: "xml"}
+	taken := map[string]bool{"xml": true, "xmlns": true}
+	for _, namespace := range used {
+		if prefix, ok := enc.prefixes[namespace]; ok && !taken[prefix] {
+			assigned[namespace] = prefix
+			taken[prefix] = true
+		}
+	}
+	n := 0
+	for _, namespace := range used {
+		if _, ok := assigned[namespace]; ok || !prefixed[namespace] {
+			continue
+		}
+		var prefix string
+		for prefix == "" || taken[prefix] {
+			n++
+			prefix = "ns" + strconv.Itoa(n)
+		}
+		assigned[namespace] = prefix
+		taken[prefix] = true
+	}
+	return assigned
+}
+
+// Marshal returns v written by an Encoder with the DefaultPrefixes.
+func Marshal(v interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	if err := NewEncoder(&b).Encode(v); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// MarshalIndent is like Marshal, but indents the output as Encoder.Indent does.
+func MarshalIndent(v interface{}, prefix, indent string) ([]byte, error) {
+	var b bytes.Buffer
+	enc := NewEncoder(&b)
+	enc.Indent(prefix, indent)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+//********************* Name Resolution *************************//
+
+// start is a start element with every name resolved to its namespace.
+type start struct {
+	name  xml.Name
+	attrs []attr
+}
+
+type attr struct {
+	name  xml.Name
+	value string
+	// qname is set for xsi:type values whose prefix could be resolved
+	qname *xml.Name
+}
+
+type end struct{}
+
+// scope holds the namespace declarations in effect for an element.
+type scope struct {
+	defaultNamespace string
+	prefixes         map[string]string
+}
+
+func (s *scope) lookup(prefix string) (string, bool) {
+	if prefix == "xml" {
+		return XMLNamespace, true
+	}
+	namespace, ok := s.prefixes[prefix]
+	return namespace, ok
+}
+
+// resolve reads a document and returns its tokens with resolved names, along
+// with the namespaces they use in order of first use, and the namespaces of
+// attributes and xsi:type values, which need a prefix.
+func resolve(raw []byte) ([]interface{}, []string, map[string]bool, error) {
+	d := xml.NewDecoder(bytes.NewReader(raw))
+	var tokens []interface{}
+	var used []string
+	prefixed := make(map[string]bool)
+	seen := map[string]bool{"": true, XMLNamespace: true}
+	use := func(namespace string) {
+		if !seen[namespace] {
+			seen[namespace] = true
+			used = append(used, namespace)
+		}
+	}
+
+	scopes := []*scope{{prefixes: map[string]string{}}}
+	// RawToken does not check that end elements match their start
+	var open []xml.Name
+	for {
+		token, err := d.RawToken()
+		if err == io.EOF {
+			if len(open) > 0 {
+				return nil, nil, nil, io.ErrUnexpectedEOF
+			}
+			return tokens, used, prefixed, nil
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			parent := scopes[len(scopes)-1]
+			current := &scope{defaultNamespace: parent.defaultNamespace, prefixes: parent.prefixes}
+			copied := false
+			for _, a := range t.Attr {
+				switch {
+				case a.Name.Space == "" && a.Name.Local == "xmlns":
+					current.defaultNamespace = a.Value
+				case a.Name.Space == "xmlns":
+					if !copied {
+						current.prefixes = make(map[string]string, len(parent.prefixes)+1)
+						for prefix, namespace := range parent.prefixes {
+							current.prefixes[prefix] = namespace
+						}
+						copied = true
+					}
+					current.prefixes[a.Name.Local] = a.Value
+				}
+			}
+			scopes = append(scopes, current)
+			open = append(open, t.Name)
+
+			s := &start{name: current.element(t.Name)}
+			use(s.name.Space)
+			for _, a := range t.Attr {
+				if a.Name.Space == "xmlns" || a.Name.Space == "" && a.Name.Local == "xmlns" {
+					continue
+				}
+				resolved := attr{name: current.attribute(a.Name), value: a.Value}
+				use(resolved.name.Space)
+				prefixed[resolved.name.Space] = true
+				if resolved.name.Space == adi30.XSINamespace && resolved.name.Local == "type" {
+					resolved.qname = current.qname(a.Value)
+					if resolved.qname != nil {
+						use(resolved.qname.Space)
+						prefixed[resolved.qname.Space] = true
+					}
+				}
+				s.attrs = append(s.attrs, resolved)
+			}
+			tokens = append(tokens, s)
+		case xml.EndElement:
+			if len(open) == 0 || open[len(open)-1] != t.Name {
+				return nil, nil, nil, fmt.Errorf("namespace: unexpected end element </%s>", t.Name.Local)
+			}
+			open = open[:len(open)-1]
+			scopes = scopes[:len(scopes)-1]
+			tokens = append(tokens, end{})
+		default:
+			tokens = append(tokens, xml.CopyToken(t))
+		}
+	}
+}
+
+// element resolves the name of an element; a prefix that was never declared is
+// kept as part of the local name.
+func (s *scope) element(name xml.Name) xml.Name {
+	if name.Space == "" {
+		return xml.Name{Space: s.defaultNamespace, Local: name.Local}
+	}
+	if namespace, ok := s.lookup(name.Space); ok {
+		return xml.Name{Space: namespace, Local: name.Local}
+	}
+	return xml.Name{Local: name.Space + ":" + name.Local}
+}
+
+// attribute resolves the name of an attribute, which has no namespace unless it
+// is prefixed.
+func (s *scope) attribute(name xml.Name) xml.Name {
+	if name.Space == "" {
+		return name
+	}
+	if namespace, ok := s.lookup(name.Space); ok {
+		return xml.Name{Space: namespace, Local: name.Local}
+	}
+	return xml.Name{Local: name.Space + ":" + name.Local}
+}
+
+// qname resolves a QName value, or returns nil if its prefix is unknown.
+func (s *scope) qname(value string) *xml.Name {
+	for i := 0; i < len(value); i++ {
+		if value[i] == ':' {
+			if namespace, ok := s.lookup(value[:i]); ok {
+				return &xml.Name{Space: namespace, Local: value[i+1:]}
+			}
+			return nil
+		}
+	}
+	return &xml.Name{Space: s.defaultNamespace, Local: value}
+}
+
+//********************* Output *************************//
+
+// writer prints resolved tokens.
+type writer struct {
+	buf      bytes.Buffer
+	prefixes map[string]string
+	// names and defaults hold the name and the default namespace of each open element
+	names    []xml.Name
+	defaults []string
+	// open is set while the start tag of the last element has not been closed, so
+	// that it can be closed with /> if the element turns out to be empty
+	open bool
+}
+
+func (w *writer) write(token interface{}) {
+	switch t := token.(type) {
+	case *start:
+		w.closeStart()
+		w.buf.WriteByte('<')
+		w.name(t.name)
+
+		inherited := ""
+		if len(w.defaults) > 0 {
+			inherited = w.defaults[len(w.defaults)-1]
+		}
+		current := inherited
+		if t.name.Space == "" || w.prefixes[t.name.Space] == "" {
+			current = t.name.Space
+		}
+		if current != inherited {
+			w.attr("xmlns", current)
+		}
+		if len(w.names) == 0 {
+			w.declare()
+		}
+		w.names = append(w.names, t.name)
+		w.defaults = append(w.defaults, current)
+
+		for _, a := range t.attrs {
+			value := a.value
+			if a.qname != nil {
+				value = a.qname.Local
+				if prefix := w.prefixes[a.qname.Space]; prefix != "" {
+					value = prefix + ":" + value
+				}
+			}
+			local := a.name.Local
+			if a.name.Space != "" {
+				local = w.prefixes[a.name.Space] + ":" + local
+			}
+			w.attr(local, value)
+		}
+		w.open = true
+	case end:
+		if len(w.names) == 0 {
+			return
+		}
+		name := w.names[len(w.names)-1]
+		w.names = w.names[:len(w.names)-1]
+		w.defaults = w.defaults[:len(w.defaults)-1]
+		if w.open {
+			w.open = false
+			w.buf.WriteString("/>")
+			return
+		}
+		w.buf.WriteString("</")
+		w.name(name)
+		w.buf.WriteByte('>')
+	case xml.CharData:
+		w.closeStart()
+		textEscaper.WriteString(&w.buf, string(t))
+	case xml.Comment:
+		w.closeStart()
+		w.buf.WriteString("<!--")
+		w.buf.Write(t)
+		w.buf.WriteString("-->")
+	case xml.ProcInst:
+		w.closeStart()
+		w.buf.WriteString("<?")
+		w.buf.WriteString(t.Target)
+		if len(t.Inst) > 0 {
+			w.buf.WriteByte(' ')
+			w.buf.Write(t.Inst)
+		}
+		w.buf.WriteString("?>")
+	case xml.Directive:
+		w.closeStart()
+		w.buf.WriteString("<!")
+		w.buf.Write(t)
+		w.buf.WriteByte('>')
+	}
+}
+
+// declare writes the prefixes of the namespaces used on the root element. The
+// default namespace, if any, is declared by the elements in it.
+func (w *writer) declare() {
+	namespaces := make([]string, 0, len(w.prefixes))
+	for namespace, prefix := range w.prefixes {
+		if namespace != XMLNamespace && prefix != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	// declared in prefix order, so that output does not depend on map order
+	sort.Slice(namespaces, func(i, j int) bool {
+		return w.prefixes[namespaces[i]] < w.prefixes[namespaces[j]]
+	})
+	for _, namespace := range namespaces {
+		w.attr("xmlns:"+w.prefixes[namespace], namespace)
+	}
+}
+
+// textEscaper escapes character data as xml.Encoder does, keeping the newlines
+// of indented output.
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+
+func (w *writer) name(name xml.Name) {
+	if prefix := w.prefixes[name.Space]; name.Space != "" && prefix != "" {
+		w.buf.WriteString(prefix)
+		w.buf.WriteByte(':')
+	}
+	w.buf.WriteString(name.Local)
+}
+
+func (w *writer) attr(name, value string) {
+	w.buf.WriteByte(' ')
+	w.buf.WriteString(name)
+	w.buf.WriteString(`="`)
+	xml.EscapeText(&w.buf, []byte(value))
+	w.buf.WriteByte('"')
+}
+
+func (w *writer) closeStart() {
+	if w.open {
+		w.open = false
+		w.buf.WriteByte('>')
+	}
+}
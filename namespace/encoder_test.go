@@ -0,0 +1,139 @@
+package namespace
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20180501"
+	scte224_2020 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+const adiMedia = `<Media xmlns="http://www.scte.org/schemas/224" xmlns:core="http://www.scte.org/schemas/236/2017/core" xmlns:title="http://www.scte.org/schemas/236/2017/title" xmlns:lrm="https://lrm.aor.theplatform.com/lrm/schemas/esni/224" id="hbo.com/media/HBOHD" xlink:href="hbo.com/media" xmlns:xlink="http://www.w3.org/1999/xlink">
+  <MediaPoint id="hbo.com/media/HBOHD/program/start">
+    <Metadata>
+      <core:ADI3>
+        <core:Asset xsi:type="title:TitleType" uriId="hbo.com/title/HBO" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+          <core:Provider>HBO</core:Provider>
+          <title:LocalizableTitle xml:lang="en"><title:TitleBrief>Plot &amp; Against</title:TitleBrief></title:LocalizableTitle>
+        </core:Asset>
+      </core:ADI3>
+      <lrm:MetadataDetail name="StartOver" type="Boolean">false</lrm:MetadataDetail>
+      <Extra xmlns="" lrm:provider="HBO"><Inner/></Extra>
+    </Metadata>
+  </MediaPoint>
+</Media>`
+
+const expectedADIMedia = `<esni:Media xmlns:adi3="http://www.scte.org/schemas/236/2017/core" xmlns:esni="http://www.scte.org/schemas/224" xmlns:ns1="https://lrm.aor.theplatform.com/lrm/schemas/esni/224" xmlns:title="http://www.scte.org/schemas/236/2017/title" xmlns:xlink="http://www.w3.org/1999/xlink" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" id="hbo.com/media/HBOHD" xlink:href="hbo.com/media">
+  <esni:MediaPoint id="hbo.com/media/HBOHD/program/start">
+    <esni:Metadata>
+      <adi3:ADI3>
+        <adi3:Asset xsi:type="title:TitleType" uriId="hbo.com/title/HBO">
+          <adi3:Provider>HBO</adi3:Provider>
+          <title:LocalizableTitle xml:lang="en">
+            <title:TitleBrief>Plot &amp; Against</title:TitleBrief>
+          </title:LocalizableTitle>
+        </adi3:Asset>
+      </adi3:ADI3>
+      <ns1:MetadataDetail name="StartOver" type="Boolean">false</ns1:MetadataDetail>
+      <Extra ns1:provider="HBO"><Inner/></Extra>
+    </esni:Metadata>
+  </esni:MediaPoint>
+</esni:Media>`
+
+func TestMarshalIndent(t *testing.T) {
+	var media scte224.Media
+	if err := xml.Unmarshal([]byte(adiMedia), &media); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	compact, err := MarshalIndent(&media, "", "  ")
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if expectedADIMedia != string(compact) {
+		t.Log(string(compact))
+		t.Log("did not match")
+		t.Log(expectedADIMedia)
+		t.Fail()
+	}
+
+	// the output decodes to the same objects
+	var again scte224.Media
+	if err := xml.Unmarshal(compact, &again); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	expected, _ := xml.Marshal(&media)
+	actual, _ := xml.Marshal(&again)
+	if string(expected) != string(actual) {
+		t.Log(string(actual))
+		t.Log("did not decode to")
+		t.Log(string(expected))
+		t.Fail()
+	}
+}
+
+const actionPolicy = `<ViewingPolicy xmlns="http://www.scte.org/schemas/224" xmlns:action="urn:scte:224:action" xmlns:aud="urn:scte:224:audience" xmlns:xlink="http://www.w3.org/1999/xlink" id="vp">
+  <aud:Audience xlink:href="aud/1"/>
+  <action:Content>replace</action:Content>
+</ViewingPolicy>`
+
+func TestSetPrefix(t *testing.T) {
+	var vp scte224_2020.ViewingPolicy
+	if err := xml.Unmarshal([]byte(actionPolicy), &vp); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+
+	var b bytes.Buffer
+	enc := NewEncoder(&b)
+	enc.SetPrefix(ESNINamespace, "")
+	enc.SetPrefix(ActionNamespace, "act")
+	if err := enc.Encode(&vp); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	expected := `<ViewingPolicy xmlns="http://www.scte.org/schemas/224" xmlns:act="urn:scte:224:action" xmlns:aud="urn:scte:224:audience" xmlns:xlink="http://www.w3.org/1999/xlink" id="vp">` +
+		`<act:Content>replace</act:Content><aud:Audience xlink:href="aud/1"/></ViewingPolicy>`
+	if expected != b.String() {
+		t.Log(b.String())
+		t.Log("did not match")
+		t.Log(expected)
+		t.Fail()
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	var b bytes.Buffer
+	enc := NewEncoder(&b)
+	enc.SetPrefix(ESNINamespace, "")
+	// a prefix taken by another namespace is not declared twice
+	enc.SetPrefix("urn:example:a", "x")
+	enc.SetPrefix("urn:example:b", "x")
+	raw := `<Media xmlns="http://www.scte.org/schemas/224"><a:One xmlns:a="urn:example:a"/><Two xmlns="urn:example:b" xmlns:c="urn:example:c" c:attr="1"/><None xmlns=""><Media xmlns="http://www.scte.org/schemas/224"/></None><!--note--></Media>`
+	if err := enc.Rewrite([]byte(raw)); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	// an element in a namespace without a prefix declares it as its default namespace
+	expected := `<Media xmlns="http://www.scte.org/schemas/224" xmlns:ns1="urn:example:c" xmlns:x="urn:example:a"><x:One/><Two xmlns="urn:example:b" ns1:attr="1"/><None xmlns=""><Media xmlns="http://www.scte.org/schemas/224"/></None><!--note--></Media>`
+	if expected != b.String() {
+		t.Log(b.String())
+		t.Log("did not match")
+		t.Log(expected)
+		t.Fail()
+	}
+
+	if err := NewEncoder(ioutil.Discard).Rewrite([]byte("<Media><unclosed></Media>")); nil == err {
+		t.Log("Malformed XML should not be rewritten")
+		t.Fail()
+	}
+	if _, err := Marshal(make(chan int)); nil == err || !strings.Contains(err.Error(), "unsupported type") {
+		t.Log("Marshaling errors should be returned", err)
+		t.Fail()
+	}
+}
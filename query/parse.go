@@ -0,0 +1,144 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Comcast/scte224structs/types/xsd"
+)
+
+// Parse reads a query written as terms separated by whitespace, each a key and a
+// value separated by a colon:
+//
+//	source:SOURCE[,SOURCE...]
+//	effective:DATETIME
+//	window:[DATETIME]/[DATETIME]
+//	zip:ZIP[,ZIP...]
+//	dma:DMA[,DMA...]
+//	audience:NAME=VALUE[,VALUE...]
+//	action:NAME[,NAME...]
+//	altid:[TYPE=]PATTERN[,PATTERN...]
+//
+// DATETIME is an xs:dateTime. A value with whitespace or quotes is written as a
+// double-quoted Go string, as in source:"East Coast". An empty string is the
+// query without terms.
+func Parse(s string) (*Query, error) {
+	q := New()
+	rest := s
+	for {
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		if rest == "" {
+			return q, nil
+		}
+		colon := strings.IndexByte(rest, ':')
+		if colon < 0 || strings.IndexFunc(rest[:colon], unicode.IsSpace) >= 0 {
+			return nil, fmt.Errorf("query: term %q is not key:value", firstField(rest))
+		}
+		key := rest[:colon]
+		rest = rest[colon+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			quoted := quotedPrefix(rest)
+			unquoted, err := strconv.Unquote(quoted)
+			if err != nil {
+				return nil, fmt.Errorf("query: %s: bad quoted value %s", key, quoted)
+			}
+			value = unquoted
+			rest = rest[len(quoted):]
+		} else {
+			value = firstField(rest)
+			rest = rest[len(value):]
+		}
+		if err := q.parseTerm(key, value); err != nil {
+			return nil, fmt.Errorf("query: %s: %v", key, err)
+		}
+	}
+}
+
+func (q *Query) parseTerm(key, value string) error {
+	if value == "" {
+		return fmt.Errorf("no value")
+	}
+	switch key {
+	case "source":
+		q.Source(strings.Split(value, ",")...)
+	case "effective":
+		at, err := parseTime(value)
+		if err != nil {
+			return err
+		}
+		q.EffectiveAt(at)
+	case "window":
+		slash := strings.IndexByte(value, '/')
+		if slash < 0 {
+			return fmt.Errorf("%q is not FROM/TO", value)
+		}
+		from, err := parseTime(value[:slash])
+		if err != nil {
+			return err
+		}
+		to, err := parseTime(value[slash+1:])
+		if err != nil {
+			return err
+		}
+		q.Overlaps(from, to)
+	case "zip":
+		q.Zip(strings.Split(value, ",")...)
+	case "dma":
+		q.DMA(strings.Split(value, ",")...)
+	case "audience":
+		eq := strings.IndexByte(value, '=')
+		if eq <= 0 {
+			return fmt.Errorf("%q is not NAME=VALUE", value)
+		}
+		q.AudienceProperty(value[:eq], strings.Split(value[eq+1:], ",")...)
+	case "action":
+		q.Action(strings.Split(value, ",")...)
+	case "altid":
+		var altIDType string
+		if eq := strings.IndexByte(value, '='); eq >= 0 {
+			altIDType, value = value[:eq], value[eq+1:]
+		}
+		q.AltID(altIDType, strings.Split(value, ",")...)
+	default:
+		return fmt.Errorf("unknown key (expected source, effective, window, zip, dma, audience, action or altid)")
+	}
+	return nil
+}
+
+// parseTime reads an xs:dateTime; the empty string is the zero time.
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	dt, err := xsd.ParseDateTime(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return dt.Time(), nil
+}
+
+// quotedPrefix returns the double-quoted string s starts with, up to the first
+// unescaped quote after the opening one, or all of s when there is none.
+func quotedPrefix(s string) string {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return s[:i+1]
+		}
+	}
+	return s
+}
+
+func firstField(s string) string {
+	if end := strings.IndexFunc(s, unicode.IsSpace); end >= 0 {
+		return s[:end]
+	}
+	return s
+}
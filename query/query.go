@@ -0,0 +1,482 @@
+// Package query selects SCTE 224 objects: Media and MediaPoints by source and by
+// their effective window, and any object by the audiences, actions and AltIDs it
+// contains. A Query is built in code or parsed from a compact string, which suits
+// command lines and the query parameters of REST endpoints:
+//
+//	q := query.New().Source("TEST").Overlaps(from, to).Zip("80202")
+//	q, err := query.Parse(`source:TEST window:2021-07-26T08:00:00Z/2021-07-26T10:00:00Z zip:80202`)
+//	matching := q.Filter(results)
+//
+// Every term of a query must hold. A term with several values, as in
+// zip:80202,80203, holds when one of them does.
+//
+// Source, effective and window terms apply to Media and MediaPoints; other
+// objects never match them. Zip, DMA, audience, action and AltID terms match an
+// object when it or an object it contains matches: a Media whose MediaPoints
+// apply a policy for zip 80202 matches zip:80202, as does the policy, its
+// ViewingPolicy and its Audience.
+package query
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+	"github.com/Comcast/scte224structs/types/xsd"
+)
+
+// AudienceNamespace is the namespace of the audience properties of the 2020
+// schema, such as Zip and DMA.
+const AudienceNamespace = "urn:scte:224:audience"
+
+// A Query is a conjunction of terms. The zero Query matches every object.
+type Query struct {
+	terms []term
+}
+
+// A term is one condition of a query.
+type term interface {
+	// match reports whether the object, and not those it contains, satisfies the term.
+	match(v interface{}) bool
+	// deep reports whether objects containing one satisfying the term satisfy it too.
+	deep() bool
+	// String returns the term in the syntax of Parse.
+	String() string
+}
+
+// New returns a Query without terms, for the builder methods to add to.
+func New() *Query {
+	return &Query{}
+}
+
+func (q *Query) add(t term) *Query {
+	q.terms = append(q.terms, t)
+	return q
+}
+
+// Source requires Media and MediaPoints to have one of the sources; a Media also
+// matches when one of its MediaPoints does.
+func (q *Query) Source(sources ...string) *Query {
+	return q.add(&sourceTerm{sources: sources})
+}
+
+// EffectiveAt requires Media and MediaPoints to be effective at t: not before
+// their effective time, and before their expires time.
+func (q *Query) EffectiveAt(t time.Time) *Query {
+	return q.add(&effectiveTerm{at: t})
+}
+
+// Overlaps requires the effective window of Media and MediaPoints to overlap the
+// window from from to to, either of which is unbounded when zero.
+func (q *Query) Overlaps(from, to time.Time) *Query {
+	return q.add(&windowTerm{from: from, to: to})
+}
+
+// Zip requires an audience with one of the Zip properties.
+func (q *Query) Zip(zips ...string) *Query {
+	return q.AudienceProperty("Zip", zips...)
+}
+
+// DMA requires an audience with one of the DMA properties.
+func (q *Query) DMA(dmas ...string) *Query {
+	return q.AudienceProperty("DMA", dmas...)
+}
+
+// AudienceProperty requires an audience with a property of the audience namespace
+// of the given name and one of the values.
+func (q *Query) AudienceProperty(name string, values ...string) *Query {
+	return q.add(&audienceTerm{name: name, values: values})
+}
+
+// Action requires a ViewingPolicy with one of the actions, by local name, such as
+// Content, SignalPointInsertion or Allocation; other names are looked for among
+// the ActionProperty of the policy.
+func (q *Query) Action(names ...string) *Query {
+	return q.add(&actionTerm{names: names})
+}
+
+// AltID requires an AltID of the given type, or of any type when it is "", whose
+// value matches one of the patterns. A pattern matches the whole value, with
+// surrounding whitespace removed; its * matches any run of characters.
+func (q *Query) AltID(altIDType string, patterns ...string) *Query {
+	t := &altIDTerm{altIDType: altIDType, patterns: patterns}
+	for _, pattern := range patterns {
+		t.regexps = append(t.regexps, wildcard(pattern))
+	}
+	return q.add(t)
+}
+
+// Matches reports whether v satisfies every term of the query. v is a pointer to a
+// Media, MediaPoint, Policy, ViewingPolicy, Audience or Audit of the 2020 schema;
+// other values never match a query with terms.
+func (q *Query) Matches(v interface{}) bool {
+	for _, t := range q.terms {
+		if !satisfies(t, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the objects of results that match the query, in their order.
+// The objects are shared with results, not copied, and are kept whole: a
+// matching Media keeps all its MediaPoints, which MediaPoints selects among.
+func (q *Query) Filter(results *scte224.Results) *scte224.Results {
+	filtered := &scte224.Results{XMLName: results.XMLName}
+	for _, m := range results.Medias {
+		if m != nil && q.Matches(m) {
+			filtered.Medias = append(filtered.Medias, m)
+		}
+	}
+	for _, mp := range results.MediaPoints {
+		if mp != nil && q.Matches(mp) {
+			filtered.MediaPoints = append(filtered.MediaPoints, mp)
+		}
+	}
+	for _, p := range results.Policys {
+		if p != nil && q.Matches(p) {
+			filtered.Policys = append(filtered.Policys, p)
+		}
+	}
+	for _, vp := range results.ViewingPolicys {
+		if vp != nil && q.Matches(vp) {
+			filtered.ViewingPolicys = append(filtered.ViewingPolicys, vp)
+		}
+	}
+	for _, aud := range results.Audiences {
+		if aud != nil && q.Matches(aud) {
+			filtered.Audiences = append(filtered.Audiences, aud)
+		}
+	}
+	for _, audit := range results.Audits {
+		if audit != nil && q.Matches(audit) {
+			filtered.Audits = append(filtered.Audits, audit)
+		}
+	}
+	if results.Size != 0 {
+		filtered.Size = len(filtered.Medias) + len(filtered.MediaPoints) + len(filtered.Policys) +
+			len(filtered.ViewingPolicys) + len(filtered.Audiences) + len(filtered.Audits)
+	}
+	return filtered
+}
+
+// MediaPoints returns the MediaPoints of m that match the query, in their order.
+// The source and window of m are not considered, only those of its MediaPoints.
+func (q *Query) MediaPoints(m *scte224.Media) []*scte224.MediaPoint {
+	var points []*scte224.MediaPoint
+	for _, mp := range m.MediaPoints {
+		if mp != nil && q.Matches(mp) {
+			points = append(points, mp)
+		}
+	}
+	return points
+}
+
+// String returns the query in the syntax of Parse.
+func (q *Query) String() string {
+	terms := make([]string, 0, len(q.terms))
+	for _, t := range q.terms {
+		terms = append(terms, t.String())
+	}
+	return strings.Join(terms, " ")
+}
+
+// satisfies reports whether v, or for deep terms an object it contains, satisfies t.
+func satisfies(t term, v interface{}) bool {
+	if t.match(v) {
+		return true
+	}
+	if !t.deep() {
+		return false
+	}
+	for _, child := range children(v) {
+		if satisfies(t, child) {
+			return true
+		}
+	}
+	return false
+}
+
+// children returns the objects v contains.
+func children(v interface{}) []interface{} {
+	var objects []interface{}
+	switch v := v.(type) {
+	case *scte224.Media:
+		for _, mp := range v.MediaPoints {
+			if mp != nil {
+				objects = append(objects, mp)
+			}
+		}
+	case *scte224.MediaPoint:
+		for _, apply := range v.Applys {
+			if apply != nil && apply.Policy != nil {
+				objects = append(objects, apply.Policy)
+			}
+		}
+		for _, remove := range v.Removes {
+			if remove != nil && remove.Policy != nil {
+				objects = append(objects, remove.Policy)
+			}
+		}
+	case *scte224.Policy:
+		for _, vp := range v.ViewingPolicys {
+			if vp != nil {
+				objects = append(objects, vp)
+			}
+		}
+	case *scte224.ViewingPolicy:
+		if v.Audience != nil {
+			objects = append(objects, v.Audience)
+		}
+	case *scte224.Audience:
+		for _, aud := range v.Audiences {
+			if aud != nil {
+				objects = append(objects, aud)
+			}
+		}
+	case *scte224.Audit:
+		for _, audit := range v.Audits {
+			if audit != nil {
+				objects = append(objects, audit)
+			}
+		}
+	}
+	return objects
+}
+
+// window returns the effective and expires times of a Media or MediaPoint.
+func window(v interface{}) (effective, expires *xsd.DateTime, ok bool) {
+	switch v := v.(type) {
+	case *scte224.Media:
+		return v.Effective, v.Expires, true
+	case *scte224.MediaPoint:
+		return v.Effective, v.Expires, true
+	}
+	return nil, nil, false
+}
+
+func bound(dt *xsd.DateTime) (time.Time, bool) {
+	if dt == nil || dt.IsZero() {
+		return time.Time{}, false
+	}
+	return dt.Time(), true
+}
+
+type sourceTerm struct {
+	sources []string
+}
+
+func (t *sourceTerm) match(v interface{}) bool {
+	var source string
+	switch v := v.(type) {
+	case *scte224.Media:
+		source = v.Source
+	case *scte224.MediaPoint:
+		source = v.Source
+	default:
+		return false
+	}
+	return contains(t.sources, source)
+}
+
+// deep lets a Media match by the source of its MediaPoints; no other object
+// contains an object with a source.
+func (t *sourceTerm) deep() bool { return true }
+
+func (t *sourceTerm) String() string { return "source:" + values(t.sources) }
+
+type effectiveTerm struct {
+	at time.Time
+}
+
+func (t *effectiveTerm) match(v interface{}) bool {
+	effective, expires, ok := window(v)
+	if !ok {
+		return false
+	}
+	if start, bounded := bound(effective); bounded && t.at.Before(start) {
+		return false
+	}
+	end, bounded := bound(expires)
+	return !bounded || t.at.Before(end)
+}
+
+func (t *effectiveTerm) deep() bool { return false }
+
+func (t *effectiveTerm) String() string { return "effective:" + formatTime(t.at) }
+
+type windowTerm struct {
+	from, to time.Time
+}
+
+func (t *windowTerm) match(v interface{}) bool {
+	effective, expires, ok := window(v)
+	if !ok {
+		return false
+	}
+	if start, bounded := bound(effective); bounded && !t.to.IsZero() && !start.Before(t.to) {
+		return false
+	}
+	end, bounded := bound(expires)
+	return !bounded || t.from.IsZero() || t.from.Before(end)
+}
+
+func (t *windowTerm) deep() bool { return false }
+
+func (t *windowTerm) String() string {
+	return "window:" + formatTime(t.from) + "/" + formatTime(t.to)
+}
+
+type audienceTerm struct {
+	name   string
+	values []string
+}
+
+func (t *audienceTerm) match(v interface{}) bool {
+	aud, ok := v.(*scte224.Audience)
+	if !ok {
+		return false
+	}
+	for _, property := range aud.AudienceProperty {
+		if property.XMLName.Space == AudienceNamespace && property.XMLName.Local == t.name &&
+			contains(t.values, strings.TrimSpace(property.Value)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *audienceTerm) deep() bool { return true }
+
+func (t *audienceTerm) String() string {
+	switch t.name {
+	case "Zip":
+		return "zip:" + values(t.values)
+	case "DMA":
+		return "dma:" + values(t.values)
+	}
+	return "audience:" + quote(t.name+"="+strings.Join(t.values, ","))
+}
+
+type actionTerm struct {
+	names []string
+}
+
+func (t *actionTerm) match(v interface{}) bool {
+	vp, ok := v.(*scte224.ViewingPolicy)
+	if !ok {
+		return false
+	}
+	for _, name := range t.names {
+		switch {
+		case name == "SignalPointDeletion" && vp.SignalPointDeletion != nil,
+			name == "SignalPointInsertion" && vp.SignalPointInsertion != nil,
+			name == "Content" && vp.Content != nil,
+			name == "Allocation" && vp.Allocation != nil:
+			return true
+		}
+		for _, property := range vp.ActionProperty {
+			if property.XMLName.Local == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (t *actionTerm) deep() bool { return true }
+
+func (t *actionTerm) String() string { return "action:" + values(t.names) }
+
+type altIDTerm struct {
+	altIDType string
+	patterns  []string
+	regexps   []*regexp.Regexp
+}
+
+func (t *altIDTerm) match(v interface{}) bool {
+	idType := identifiable(v)
+	if idType == nil {
+		return false
+	}
+	for _, altID := range idType.AltIDs {
+		if altID == nil || t.altIDType != "" && altID.Type != t.altIDType {
+			continue
+		}
+		value := strings.TrimSpace(altID.Value)
+		for _, re := range t.regexps {
+			if re.MatchString(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (t *altIDTerm) deep() bool { return true }
+
+func (t *altIDTerm) String() string {
+	patterns := strings.Join(t.patterns, ",")
+	if t.altIDType == "" {
+		return "altid:" + quote(patterns)
+	}
+	return "altid:" + quote(t.altIDType+"="+patterns)
+}
+
+func identifiable(v interface{}) *scte224.IdentifiableType {
+	switch v := v.(type) {
+	case *scte224.Media:
+		return &v.IdentifiableType
+	case *scte224.MediaPoint:
+		return &v.IdentifiableType
+	case *scte224.Policy:
+		return &v.IdentifiableType
+	case *scte224.ViewingPolicy:
+		return &v.IdentifiableType
+	case *scte224.Audience:
+		return &v.IdentifiableType
+	case *scte224.Audit:
+		return &v.IdentifiableType
+	}
+	return nil
+}
+
+// wildcard compiles a pattern whose * matches any run of characters.
+func wildcard(pattern string) *regexp.Regexp {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// values returns a comma-separated list in the syntax of Parse.
+func values(list []string) string {
+	return quote(strings.Join(list, ","))
+}
+
+// quote returns value, quoted when Parse would not read it back as a single value.
+func quote(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\r\n\"\\") {
+		return strconv.Quote(value)
+	}
+	return value
+}
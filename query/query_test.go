@@ -0,0 +1,177 @@
+package query
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+const results = `<Results xmlns="http://www.scte.org/schemas/224" size="5">
+	<Media id="east" source="EAST" effective="2021-07-26T00:00:00Z" expires="2021-07-27T00:00:00Z">
+		<AltID type="EIDR">10.5240/7791-8534-2C23-9030-8610-5</AltID>
+		<MediaPoint id="east/start" source="EAST-SCHEDULE" effective="2021-07-26T08:58:00Z" expires="2021-07-26T09:02:00Z" matchTime="2021-07-26T09:00:00Z">
+			<Apply>
+				<Policy id="blackout">
+					<ViewingPolicy id="blackout/denver">
+						<Audience id="denver" match="ANY">
+							<Zip xmlns="urn:scte:224:audience">80202</Zip>
+							<Audience id="denver/dma"><DMA xmlns="urn:scte:224:audience">751</DMA></Audience>
+						</Audience>
+						<Content xmlns="urn:scte:224:action">slate</Content>
+					</ViewingPolicy>
+				</Policy>
+			</Apply>
+		</MediaPoint>
+		<MediaPoint id="east/end" effective="2021-07-26T09:58:00Z" expires="2021-07-26T10:02:00Z"/>
+	</Media>
+	<Media id="west" source="WEST">
+		<AltID type="CallSign">KWST</AltID>
+	</Media>
+	<MediaPoint id="loose" source="EAST" effective="2021-07-26T12:00:00Z"/>
+	<ViewingPolicy id="ads">
+		<Audience id="everyone"/>
+		<Allocation xmlns="urn:scte:224:action" duration="PT30S"/>
+	</ViewingPolicy>
+	<Audience id="boston"><Zip xmlns="urn:scte:224:audience">02108</Zip></Audience>
+</Results>`
+
+func at(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func ids(r *scte224.Results) []string {
+	var ids []string
+	for _, m := range r.Medias {
+		ids = append(ids, m.Id)
+	}
+	for _, mp := range r.MediaPoints {
+		ids = append(ids, mp.Id)
+	}
+	for _, p := range r.Policys {
+		ids = append(ids, p.Id)
+	}
+	for _, vp := range r.ViewingPolicys {
+		ids = append(ids, vp.Id)
+	}
+	for _, aud := range r.Audiences {
+		ids = append(ids, aud.Id)
+	}
+	return ids
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFilter(t *testing.T) {
+	var r scte224.Results
+	if err := xml.Unmarshal([]byte(results), &r); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+
+	for _, test := range []struct {
+		query    *Query
+		expected []string
+	}{
+		{New(), []string{"east", "west", "loose", "ads", "boston"}},
+		{New().Source("EAST"), []string{"east", "loose"}},
+		{New().Source("EAST-SCHEDULE"), []string{"east"}},
+		{New().Source("WEST", "NORTH"), []string{"west"}},
+		{New().EffectiveAt(at("2021-07-26T12:00:00Z")), []string{"east", "west", "loose"}},
+		{New().EffectiveAt(at("2021-07-27T00:00:00Z")), []string{"west", "loose"}},
+		{New().Overlaps(at("2021-07-25T00:00:00Z"), at("2021-07-26T00:00:00Z")), []string{"west"}},
+		{New().Overlaps(time.Time{}, at("2021-07-26T00:00:01Z")), []string{"east", "west"}},
+		{New().Zip("80202"), []string{"east"}},
+		{New().Zip("80202", "02108"), []string{"east", "boston"}},
+		{New().DMA("751"), []string{"east"}},
+		{New().AudienceProperty("Zip", "02108"), []string{"boston"}},
+		{New().Action("Content"), []string{"east"}},
+		{New().Action("Allocation", "SignalPointDeletion"), []string{"ads"}},
+		{New().AltID("EIDR", "10.5240/*"), []string{"east"}},
+		{New().AltID("", "K*"), []string{"west"}},
+		{New().AltID("EIDR", "K*"), nil},
+		{New().Source("EAST").Zip("80202"), []string{"east"}},
+	} {
+		filtered := test.query.Filter(&r)
+		if got := ids(filtered); !equal(got, test.expected) {
+			t.Logf("%s: expected %v, got %v", test.query, test.expected, got)
+			t.Fail()
+		}
+		if filtered.Size != len(test.expected) {
+			t.Logf("%s: expected size %d, got %d", test.query, len(test.expected), filtered.Size)
+			t.Fail()
+		}
+	}
+
+	q := New().Overlaps(at("2021-07-26T09:00:00Z"), at("2021-07-26T09:30:00Z"))
+	if points := q.MediaPoints(r.Medias[0]); len(points) != 1 || points[0].Id != "east/start" {
+		t.Log("Expected only the start MediaPoint, got", points)
+		t.Fail()
+	}
+	if New().Zip("751").Matches(r.Medias[0]) {
+		t.Log("Expected a DMA not to match as a zip")
+		t.Fail()
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, test := range []struct {
+		raw, canonical string
+	}{
+		{"", ""},
+		{"  source:EAST,WEST   zip:80202 ", "source:EAST,WEST zip:80202"},
+		{`source:"East Coast" dma:751`, `source:"East Coast" dma:751`},
+		{"effective:2021-07-26T09:00:00Z", "effective:2021-07-26T09:00:00Z"},
+		{"window:2021-07-26T09:00:00Z/", "window:2021-07-26T09:00:00Z/"},
+		{"window:/2021-07-26T09:00:00.5+01:00", "window:/2021-07-26T09:00:00.5+01:00"},
+		{"audience:HomeZip=80202,80203 action:Content", "audience:HomeZip=80202,80203 action:Content"},
+		{"altid:private:TMS=EP*", "altid:private:TMS=EP*"},
+		{`altid:"12 34"`, `altid:"12 34"`},
+	} {
+		q, err := Parse(test.raw)
+		if nil != err {
+			t.Logf("%q: %v", test.raw, err)
+			t.Fail()
+			continue
+		}
+		if q.String() != test.canonical {
+			t.Logf("%q: expected %q, got %q", test.raw, test.canonical, q.String())
+			t.Fail()
+		}
+		if again, err := Parse(q.String()); nil != err || again.String() != q.String() {
+			t.Logf("%q does not parse back: %v", q.String(), err)
+			t.Fail()
+		}
+	}
+
+	for _, raw := range []string{
+		"EAST",
+		"zip:",
+		"color:red",
+		"effective:yesterday",
+		"window:2021-07-26T09:00:00Z",
+		"audience:80202",
+		`source:"East`,
+		`source "East":x`,
+	} {
+		if _, err := Parse(raw); nil == err {
+			t.Logf("%q: expected an error", raw)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,363 @@
+package scte35
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrCRC is returned for sections whose CRC_32 does not match their content.
+var ErrCRC = errors.New("scte35: CRC_32 mismatch")
+
+// DecodeBase64 decodes a section encoded in base64, as cues are usually exchanged.
+func DecodeBase64(s string) (*SpliceInfoSection, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("scte35: %v", err)
+	}
+	return Decode(raw)
+}
+
+// DecodeHex decodes a section encoded in hexadecimal, with or without a 0x prefix.
+func DecodeHex(s string) (*SpliceInfoSection, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+	}
+	raw, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("scte35: %v", err)
+	}
+	return Decode(raw)
+}
+
+// Decode decodes a binary splice_info_section. Encrypted sections and
+// splice_schedule commands are not supported; descriptors that SCTE 35 does not
+// define are skipped.
+func Decode(raw []byte) (*SpliceInfoSection, error) {
+	if len(raw) < 3 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if raw[0] != 0xfc {
+		return nil, fmt.Errorf("scte35: table_id 0x%02x is not a splice_info_section", raw[0])
+	}
+	length := 3 + int(binary.BigEndian.Uint16(raw[1:3])&0x0fff)
+	if len(raw) < length || length < 4 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	raw = raw[:length]
+	if crc32(raw[:length-4]) != binary.BigEndian.Uint32(raw[length-4:]) {
+		return nil, ErrCRC
+	}
+
+	r := &bitReader{data: raw[:length-4]}
+	section := &SpliceInfoSection{}
+	r.skip(8 + 1 + 1) // table_id, section_syntax_indicator, private_indicator
+	section.SAPType = uint8(r.bits(2))
+	r.skip(12) // section_length
+	section.ProtocolVersion = uint8(r.bits(8))
+	if r.flag() {
+		return nil, errors.New("scte35: encrypted sections are not supported")
+	}
+	r.skip(6) // encryption_algorithm
+	section.PTSAdjustment = r.bits(33)
+	r.skip(8) // cw_index
+	section.Tier = uint16(r.bits(12))
+	commandLength := int(r.bits(12))
+	commandType := r.bits(8)
+
+	start := r.pos
+	switch commandType {
+	case SpliceNullType:
+		section.SpliceNull = &SpliceNull{}
+	case SpliceInsertType:
+		section.SpliceInsert = r.spliceInsert()
+	case TimeSignalType:
+		section.TimeSignal = &TimeSignal{SpliceTime: *r.spliceTime()}
+	case BandwidthReservationType:
+		section.BandwidthReservation = &BandwidthReservation{}
+	case PrivateCommandType:
+		if commandLength == 0xfff || commandLength < 4 {
+			return nil, errors.New("scte35: private_command without a splice_command_length")
+		}
+		section.PrivateCommand = &PrivateCommand{Identifier: uint32(r.bits(32))}
+		section.PrivateCommand.Data = strings.ToUpper(hex.EncodeToString(r.bytes(commandLength - 4)))
+	default:
+		return nil, fmt.Errorf("scte35: unsupported splice_command_type 0x%02x", commandType)
+	}
+	// a splice_command_length of 0xfff, allowed by older versions, leaves the
+	// command to tell its own length
+	if commandLength != 0xfff {
+		if r.pos > start+commandLength*8 {
+			return nil, fmt.Errorf("scte35: splice command longer than its splice_command_length %d", commandLength)
+		}
+		r.pos = start + commandLength*8
+	}
+
+	descriptors := r.bytes(int(r.bits(16)))
+	if r.err != nil {
+		return nil, r.err
+	}
+	for len(descriptors) > 0 {
+		if len(descriptors) < 2 || len(descriptors) < 2+int(descriptors[1]) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		tag, body := descriptors[0], descriptors[2:2+int(descriptors[1])]
+		descriptors = descriptors[2+len(body):]
+		if len(body) < 4 || binary.BigEndian.Uint32(body) != CUEIdentifier {
+			continue
+		}
+		if err := section.descriptor(tag, &bitReader{data: body[4:]}); err != nil {
+			return nil, err
+		}
+	}
+	return section, nil
+}
+
+func (section *SpliceInfoSection) descriptor(tag byte, r *bitReader) error {
+	switch tag {
+	case AvailDescriptorTag:
+		section.AvailDescriptors = append(section.AvailDescriptors, &AvailDescriptor{ProviderAvailId: uint32(r.bits(32))})
+	case DTMFDescriptorTag:
+		d := &DTMFDescriptor{Preroll: uint8(r.bits(8))}
+		count := int(r.bits(3))
+		r.skip(5)
+		d.Chars = string(r.bytes(count))
+		section.DTMFDescriptors = append(section.DTMFDescriptors, d)
+	case SegmentationDescriptorTag:
+		d, err := r.segmentationDescriptor()
+		if err != nil {
+			return err
+		}
+		section.SegmentationDescriptors = append(section.SegmentationDescriptors, d)
+	case TimeDescriptorTag:
+		section.TimeDescriptors = append(section.TimeDescriptors, &TimeDescriptor{
+			TAISeconds: r.bits(48),
+			TAINs:      uint32(r.bits(32)),
+			UTCOffset:  uint16(r.bits(16)),
+		})
+	}
+	return r.err
+}
+
+func (r *bitReader) spliceTime() *SpliceTime {
+	if !r.flag() {
+		r.skip(7)
+		return &SpliceTime{}
+	}
+	r.skip(6)
+	pts := r.bits(33)
+	return &SpliceTime{PTSTime: &pts}
+}
+
+func (r *bitReader) spliceInsert() *SpliceInsert {
+	si := &SpliceInsert{SpliceEventId: uint32(r.bits(32))}
+	si.SpliceEventCancelIndicator = r.flag()
+	r.skip(7)
+	if si.SpliceEventCancelIndicator {
+		return si
+	}
+	si.OutOfNetworkIndicator = r.flag()
+	programSplice := r.flag()
+	durationFlag := r.flag()
+	si.SpliceImmediateFlag = r.flag()
+	r.skip(4) // event_id_compliance_flag and reserved
+	if programSplice {
+		si.Program = &SpliceProgram{}
+		if !si.SpliceImmediateFlag {
+			si.Program.SpliceTime = r.spliceTime()
+		}
+	} else {
+		for count := r.bits(8); count > 0 && r.err == nil; count-- {
+			component := &SpliceComponent{ComponentTag: uint8(r.bits(8))}
+			if !si.SpliceImmediateFlag {
+				component.SpliceTime = r.spliceTime()
+			}
+			si.Components = append(si.Components, component)
+		}
+	}
+	if durationFlag {
+		si.BreakDuration = &BreakDuration{AutoReturn: r.flag()}
+		r.skip(6)
+		si.BreakDuration.Duration = r.bits(33)
+	}
+	si.UniqueProgramId = uint16(r.bits(16))
+	si.AvailNum = uint8(r.bits(8))
+	si.AvailsExpected = uint8(r.bits(8))
+	return si
+}
+
+// subSegmentTypes are the segmentation types whose descriptors may carry
+// sub_segment_num and sub_segments_expected.
+var subSegmentTypes = map[uint8]bool{
+	ProviderAdvertisementStart: true, DistributorAdvertisementStart: true,
+	ProviderPlacementOpportunityStart: true, DistributorPlacementOpportunityStart: true,
+	ProviderOverlayPlacementOpportunityStart: true, DistributorOverlayPlacementOpportunityStart: true,
+	ProviderAdBlockStart: true, DistributorAdBlockStart: true,
+}
+
+func (r *bitReader) segmentationDescriptor() (*SegmentationDescriptor, error) {
+	d := &SegmentationDescriptor{SegmentationEventId: uint32(r.bits(32))}
+	d.SegmentationEventCancelIndicator = r.flag()
+	r.skip(7)
+	if d.SegmentationEventCancelIndicator {
+		return d, r.err
+	}
+	programSegmentation := r.flag()
+	durationFlag := r.flag()
+	if deliveryNotRestricted := r.flag(); deliveryNotRestricted {
+		r.skip(5)
+	} else {
+		d.DeliveryRestrictions = &DeliveryRestrictions{
+			WebDeliveryAllowedFlag: r.flag(),
+			NoRegionalBlackoutFlag: r.flag(),
+			ArchiveAllowedFlag:     r.flag(),
+			DeviceRestrictions:     uint8(r.bits(2)),
+		}
+	}
+	if !programSegmentation {
+		for count := r.bits(8); count > 0 && r.err == nil; count-- {
+			component := &SegmentationComponent{ComponentTag: uint8(r.bits(8))}
+			r.skip(7)
+			component.PTSOffset = r.bits(33)
+			d.Components = append(d.Components, component)
+		}
+	}
+	if durationFlag {
+		duration := r.bits(40)
+		d.SegmentationDuration = &duration
+	}
+	upidType := uint8(r.bits(8))
+	upid := r.bytes(int(r.bits(8)))
+	d.SegmentationTypeId = uint8(r.bits(8))
+	d.SegmentNum = uint8(r.bits(8))
+	d.SegmentsExpected = uint8(r.bits(8))
+	// the sub-segment fields were added in 2016 and may be left out by older encoders
+	if subSegmentTypes[d.SegmentationTypeId] && r.remaining() >= 16 {
+		num, expected := uint8(r.bits(8)), uint8(r.bits(8))
+		d.SubSegmentNum, d.SubSegmentsExpected = &num, &expected
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if upidType != UpidMID {
+		if upidType != 0 || len(upid) > 0 {
+			d.SegmentationUpids = []*SegmentationUpid{newSegmentationUpid(upidType, upid)}
+		}
+		return d, nil
+	}
+	// an MID is a sequence of UPIDs, each with its type and length
+	for len(upid) > 0 {
+		if len(upid) < 2 || len(upid) < 2+int(upid[1]) {
+			return nil, fmt.Errorf("scte35: truncated MID in segmentation descriptor %d", d.SegmentationEventId)
+		}
+		d.SegmentationUpids = append(d.SegmentationUpids, newSegmentationUpid(upid[0], upid[2:2+int(upid[1])]))
+		upid = upid[2+int(upid[1]):]
+	}
+	return d, nil
+}
+
+// binaryUpidTypes are the UPID types whose value is not text: UMID, ISAN, TI,
+// EIDR, ATSC content identifier, MPU and UUID.
+var binaryUpidTypes = map[uint8]bool{
+	UpidUMID: true, UpidISANDeprecated: true, UpidISAN: true, UpidTI: true, UpidEIDR: true, UpidATSC: true, UpidMPU: true, UpidUUID: true,
+}
+
+func newSegmentationUpid(upidType uint8, value []byte) *SegmentationUpid {
+	if !binaryUpidTypes[upidType] && printable(value) {
+		return &SegmentationUpid{SegmentationUpidType: upidType, SegmentationUpidFormat: UpidFormatText, Value: string(value)}
+	}
+	return &SegmentationUpid{SegmentationUpidType: upidType, SegmentationUpidFormat: UpidFormatHexBinary, Value: strings.ToUpper(hex.EncodeToString(value))}
+}
+
+func printable(value []byte) bool {
+	for _, b := range value {
+		if b < 0x20 || b > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+//********************* Bit Reader *************************//
+
+// bitReader reads big-endian fields of any width, remembering the first error.
+type bitReader struct {
+	data []byte
+	pos  int // in bits
+	err  error
+}
+
+func (r *bitReader) remaining() int {
+	return len(r.data)*8 - r.pos
+}
+
+func (r *bitReader) bits(n int) uint64 {
+	if r.err != nil {
+		return 0
+	}
+	if r.remaining() < n {
+		r.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	var v uint64
+	for i := 0; i < n; i++ {
+		bit := r.data[r.pos/8] >> (7 - uint(r.pos%8)) & 1
+		v = v<<1 | uint64(bit)
+		r.pos++
+	}
+	return v
+}
+
+func (r *bitReader) flag() bool {
+	return r.bits(1) == 1
+}
+
+func (r *bitReader) skip(n int) {
+	r.bits(n)
+}
+
+// bytes reads n bytes, from a byte boundary.
+func (r *bitReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if r.pos%8 != 0 || r.remaining() < n*8 {
+		r.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := r.data[r.pos/8 : r.pos/8+n]
+	r.pos += n * 8
+	return b
+}
+
+//********************* CRC *************************//
+
+// crcTable is the table of CRC-32/MPEG-2: polynomial 0x04C11DB7, not reflected.
+var crcTable = func() (table [256]uint32) {
+	for i := range table {
+		crc := uint32(i) << 24
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04c11db7
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}()
+
+func crc32(data []byte) uint32 {
+	crc := uint32(0xffffffff)
+	for _, b := range data {
+		crc = crc<<8 ^ crcTable[byte(crc>>24)^b]
+	}
+	return crc
+}
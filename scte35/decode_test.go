@@ -0,0 +1,189 @@
+package scte35
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// cue vectors from the examples of SCTE 35
+const (
+	timeSignalPlacementOpportunityStart = "/DA0AAAAAAAA///wBQb+cr0AUAAeAhxDVUVJSAAAjn/PAAGlmbAICAAAAAAsoKGKNAIAmsnRfg=="
+	spliceInsertOut                     = "/DAvAAAAAAAA///wFAVIAACPf+/+c2nALv4AUsz1AAAAAAAKAAhDVUVJAAABNWLbowo="
+	timeSignalProgramEndStart           = "/DBIAAAAAAAA///wBQb+ek2ItgAyAhdDVUVJSAAAGH+fCAgAAAAALMvDRBEAAAIXQ1VFSUgAABl/nwgIAAAAACyk26AQAACZcuND"
+)
+
+func TestDecodeTimeSignal(t *testing.T) {
+	section, err := DecodeBase64(timeSignalPlacementOpportunityStart)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	pts, duration := uint64(0x072bd0050), uint64(0x0001a599b0)
+	expected := &SpliceInfoSection{
+		SAPType:    3,
+		Tier:       0xfff,
+		TimeSignal: &TimeSignal{SpliceTime: SpliceTime{PTSTime: &pts}},
+		SegmentationDescriptors: []*SegmentationDescriptor{{
+			SegmentationEventId:  0x4800008e,
+			SegmentationDuration: &duration,
+			SegmentationTypeId:   0x34,
+			SegmentNum:           2,
+			DeliveryRestrictions: &DeliveryRestrictions{NoRegionalBlackoutFlag: true, ArchiveAllowedFlag: true, DeviceRestrictions: 3},
+			SegmentationUpids:    []*SegmentationUpid{{SegmentationUpidType: 8, SegmentationUpidFormat: UpidFormatHexBinary, Value: "000000002CA0A18A"}},
+		}},
+	}
+	if !reflect.DeepEqual(expected, section) {
+		t.Logf("Expected %+v, got %+v", expected, section)
+		t.Fail()
+	}
+
+	out, err := xml.Marshal(section)
+	if nil != err || !strings.Contains(string(out), `<SegmentationDescriptor xmlns="http://www.scte.org/schemas/35" segmentationEventId="1207959694" segmentationDuration="27630000" segmentationTypeId="52" segmentNum="2" segmentsExpected="0">`) {
+		t.Log("Unexpected XML", string(out), err)
+		t.Fail()
+	}
+}
+
+func TestDecodeSpliceInsert(t *testing.T) {
+	section, err := DecodeBase64(spliceInsertOut)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	pts := uint64(0x07369c02e)
+	expected := &SpliceInsert{
+		SpliceEventId:         0x4800008f,
+		OutOfNetworkIndicator: true,
+		Program:               &SpliceProgram{SpliceTime: &SpliceTime{PTSTime: &pts}},
+		BreakDuration:         &BreakDuration{AutoReturn: true, Duration: 0x00052ccf5},
+	}
+	if !reflect.DeepEqual(expected, section.SpliceInsert) || len(section.AvailDescriptors) != 1 || section.AvailDescriptors[0].ProviderAvailId != 309 {
+		t.Logf("Unexpected splice insert %+v", section)
+		t.Fail()
+	}
+}
+
+func TestDecodeSeveralDescriptors(t *testing.T) {
+	section, err := DecodeBase64(timeSignalProgramEndStart)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if len(section.SegmentationDescriptors) != 2 ||
+		section.SegmentationDescriptors[0].SegmentationTypeId != 0x11 ||
+		section.SegmentationDescriptors[1].SegmentationTypeId != 0x10 {
+		t.Logf("Unexpected descriptors %+v", section.SegmentationDescriptors)
+		t.Fail()
+	}
+}
+
+// build returns a splice_info_section with a time_signal and the given descriptors.
+func build(descriptors ...[]byte) []byte {
+	command := []byte{0xfe, 0x00, 0x00, 0x00, 0x10}
+	var loop []byte
+	for _, d := range descriptors {
+		loop = append(loop, d...)
+	}
+	section := []byte{0xfc, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0xf0 | byte(len(command)>>8), byte(len(command)), TimeSignalType}
+	section = append(section, command...)
+	section = append(section, byte(len(loop)>>8), byte(len(loop)))
+	section = append(section, loop...)
+	length := len(section) + 4 - 3
+	section[1], section[2] = 0x30|byte(length>>8), byte(length)
+	crc := make([]byte, 4)
+	binary.BigEndian.PutUint32(crc, crc32(section))
+	return append(section, crc...)
+}
+
+// segmentation returns a segmentation_descriptor of a program, without duration
+// and delivery restrictions.
+func segmentation(typeID byte, upidType byte, upid []byte, trailer ...byte) []byte {
+	body := []byte{'C', 'U', 'E', 'I', 0, 0, 0, 1, 0x7f, 0xbf, upidType, byte(len(upid))}
+	body = append(body, upid...)
+	body = append(body, typeID, 0, 0)
+	body = append(body, trailer...)
+	return append([]byte{SegmentationDescriptorTag, byte(len(body))}, body...)
+}
+
+func TestDecodeUpids(t *testing.T) {
+	mid := append([]byte{0x09, 5}, "SIG:1"...)
+	mid = append(mid, 0x08, 8, 0, 0, 0, 0, 0x2c, 0xa0, 0xa1, 0x8a)
+	private := []byte{0xf0, 6, 'A', 'B', 'C', 'D', 0, 0}
+	raw := build(
+		segmentation(0x10, 0x01, []byte("00044MA000000037610T0318201400")),
+		segmentation(0x34, 0x0d, mid, 1, 3),
+		private,
+		segmentation(0x11, 0x01, []byte{0x00, 0xff}),
+	)
+	section, err := Decode(raw)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if len(section.SegmentationDescriptors) != 3 {
+		t.Log("Expected 3 segmentation descriptors, got", len(section.SegmentationDescriptors))
+		t.FailNow()
+	}
+
+	text := section.SegmentationDescriptors[0].SegmentationUpids
+	if !reflect.DeepEqual(text, []*SegmentationUpid{{SegmentationUpidType: 1, SegmentationUpidFormat: UpidFormatText, Value: "00044MA000000037610T0318201400"}}) {
+		t.Logf("Unexpected UPID %+v", text[0])
+		t.Fail()
+	}
+	ppo := section.SegmentationDescriptors[1]
+	if len(ppo.SegmentationUpids) != 2 || ppo.SegmentationUpids[0].Value != "SIG:1" || ppo.SegmentationUpids[1].Value != "000000002CA0A18A" ||
+		ppo.SubSegmentNum == nil || *ppo.SubSegmentNum != 1 || *ppo.SubSegmentsExpected != 3 {
+		t.Logf("Unexpected MID descriptor %+v", ppo)
+		t.Fail()
+	}
+	if upid := section.SegmentationDescriptors[2].SegmentationUpids[0]; upid.SegmentationUpidFormat != UpidFormatHexBinary || upid.Value != "00FF" {
+		t.Logf("Unexpected binary UPID %+v", upid)
+		t.Fail()
+	}
+
+	if _, err := DecodeHex("0x" + strings.ToUpper(base64ToHex(timeSignalPlacementOpportunityStart))); nil != err {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func base64ToHex(s string) string {
+	raw, _ := base64.StdEncoding.DecodeString(s)
+	return hex.EncodeToString(raw)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	raw, _ := base64.StdEncoding.DecodeString(timeSignalPlacementOpportunityStart)
+
+	corrupted := append([]byte(nil), raw...)
+	corrupted[20] ^= 1
+	if _, err := Decode(corrupted); err != ErrCRC {
+		t.Log("Expected a CRC error, got", err)
+		t.Fail()
+	}
+	if _, err := Decode(raw[:len(raw)-1]); nil == err {
+		t.Log("Expected an error for a truncated section")
+		t.Fail()
+	}
+	if _, err := Decode(append([]byte{0xfd}, raw[1:]...)); nil == err {
+		t.Log("Expected an error for another table")
+		t.Fail()
+	}
+	if _, err := DecodeBase64("not base64!"); nil == err {
+		t.Log("Expected an error for invalid base64")
+		t.Fail()
+	}
+
+	// a segmentation descriptor whose UPID runs past its end
+	truncated := segmentation(0x10, 0x01, []byte("ABC"))
+	truncated[1] -= 4
+	if _, err := Decode(build(truncated[:len(truncated)-4])); nil == err {
+		t.Log("Expected an error for a truncated descriptor")
+		t.Fail()
+	}
+}
@@ -0,0 +1,295 @@
+package scte35
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Ticks converts d to 90 kHz ticks, the unit of PTS times and durations,
+// truncating fractions of a tick. Negative durations are 0 ticks.
+func Ticks(d time.Duration) uint64 {
+	if d <= 0 {
+		return 0
+	}
+	return uint64(d/time.Second)*90000 + uint64(d%time.Second*90000/time.Second)
+}
+
+// TicksDuration converts 90 kHz ticks to a duration.
+func TicksDuration(ticks uint64) time.Duration {
+	return time.Duration(ticks/90000)*time.Second + time.Duration(ticks%90000)*time.Second/90000
+}
+
+// EncodeBase64 encodes s as Encode does, in base64.
+func EncodeBase64(s *SpliceInfoSection) (string, error) {
+	raw, err := Encode(s)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+// Encode returns the binary splice_info_section of s, unencrypted and ending with
+// its CRC_32. It encodes the commands and descriptors Decode decodes; several
+// UPIDs of a segmentation descriptor are encoded as an MID.
+func Encode(s *SpliceInfoSection) ([]byte, error) {
+	command := &bitWriter{}
+	var commandType uint64
+	switch {
+	case s.SpliceNull != nil:
+		commandType = SpliceNullType
+	case s.SpliceInsert != nil:
+		commandType = SpliceInsertType
+		command.spliceInsert(s.SpliceInsert)
+	case s.TimeSignal != nil:
+		commandType = TimeSignalType
+		command.spliceTime(&s.TimeSignal.SpliceTime)
+	case s.BandwidthReservation != nil:
+		commandType = BandwidthReservationType
+	case s.PrivateCommand != nil:
+		commandType = PrivateCommandType
+		data, err := hex.DecodeString(s.PrivateCommand.Data)
+		if err != nil {
+			return nil, fmt.Errorf("scte35: private command: %v", err)
+		}
+		command.bits(32, uint64(s.PrivateCommand.Identifier))
+		command.data = append(command.data, data...)
+	default:
+		return nil, errors.New("scte35: section without a splice command")
+	}
+
+	var descriptors []byte
+	for _, d := range s.AvailDescriptors {
+		w := &bitWriter{}
+		w.bits(32, uint64(d.ProviderAvailId))
+		descriptors = appendDescriptor(descriptors, AvailDescriptorTag, w.data)
+	}
+	for _, d := range s.DTMFDescriptors {
+		if len(d.Chars) > 7 {
+			return nil, fmt.Errorf("scte35: DTMF descriptor of %d chars, more than 7", len(d.Chars))
+		}
+		w := &bitWriter{}
+		w.bits(8, uint64(d.Preroll))
+		w.bits(3, uint64(len(d.Chars)))
+		w.reserved(5)
+		w.data = append(w.data, d.Chars...)
+		descriptors = appendDescriptor(descriptors, DTMFDescriptorTag, w.data)
+	}
+	for _, d := range s.SegmentationDescriptors {
+		body, err := segmentationDescriptor(d)
+		if err != nil {
+			return nil, err
+		}
+		descriptors = appendDescriptor(descriptors, SegmentationDescriptorTag, body)
+	}
+	for _, d := range s.TimeDescriptors {
+		w := &bitWriter{}
+		w.bits(48, d.TAISeconds)
+		w.bits(32, uint64(d.TAINs))
+		w.bits(16, uint64(d.UTCOffset))
+		descriptors = appendDescriptor(descriptors, TimeDescriptorTag, w.data)
+	}
+	if len(command.data) >= 0xfff || len(descriptors) > 0xffff {
+		return nil, errors.New("scte35: splice command or descriptors too long")
+	}
+
+	w := &bitWriter{}
+	w.bits(8, 0xfc)
+	w.bits(2, 0) // section_syntax_indicator, private_indicator
+	w.bits(2, uint64(s.SAPType))
+	// section_length counts from after itself to the end of the CRC_32
+	w.bits(12, uint64(11+len(command.data)+2+len(descriptors)+4))
+	w.bits(8, uint64(s.ProtocolVersion))
+	w.bits(7, 0) // encrypted_packet, encryption_algorithm
+	w.bits(33, s.PTSAdjustment)
+	w.bits(8, 0xff) // cw_index
+	w.bits(12, uint64(s.Tier))
+	w.bits(12, uint64(len(command.data)))
+	w.bits(8, commandType)
+	w.data = append(w.data, command.data...)
+	w.bits(16, uint64(len(descriptors)))
+	w.data = append(w.data, descriptors...)
+	if len(w.data)+4-3 > 0xfff {
+		return nil, errors.New("scte35: section too long")
+	}
+	w.bits(32, uint64(crc32(w.data)))
+	return w.data, nil
+}
+
+func appendDescriptor(descriptors []byte, tag byte, body []byte) []byte {
+	descriptors = append(descriptors, tag, byte(4+len(body)))
+	descriptors = append(descriptors, 'C', 'U', 'E', 'I')
+	return append(descriptors, body...)
+}
+
+func (w *bitWriter) spliceTime(t *SpliceTime) {
+	if t == nil || t.PTSTime == nil {
+		w.bits(1, 0)
+		w.reserved(7)
+		return
+	}
+	w.bits(1, 1)
+	w.reserved(6)
+	w.bits(33, *t.PTSTime)
+}
+
+func (w *bitWriter) spliceInsert(si *SpliceInsert) {
+	w.bits(32, uint64(si.SpliceEventId))
+	w.flag(si.SpliceEventCancelIndicator)
+	w.reserved(7)
+	if si.SpliceEventCancelIndicator {
+		return
+	}
+	w.flag(si.OutOfNetworkIndicator)
+	w.flag(si.Program != nil)
+	w.flag(si.BreakDuration != nil)
+	w.flag(si.SpliceImmediateFlag)
+	w.reserved(4) // event_id_compliance_flag and reserved
+	if si.Program != nil {
+		if !si.SpliceImmediateFlag {
+			w.spliceTime(si.Program.SpliceTime)
+		}
+	} else {
+		w.bits(8, uint64(len(si.Components)))
+		for _, component := range si.Components {
+			w.bits(8, uint64(component.ComponentTag))
+			if !si.SpliceImmediateFlag {
+				w.spliceTime(component.SpliceTime)
+			}
+		}
+	}
+	if si.BreakDuration != nil {
+		w.flag(si.BreakDuration.AutoReturn)
+		w.reserved(6)
+		w.bits(33, si.BreakDuration.Duration)
+	}
+	w.bits(16, uint64(si.UniqueProgramId))
+	w.bits(8, uint64(si.AvailNum))
+	w.bits(8, uint64(si.AvailsExpected))
+}
+
+func segmentationDescriptor(d *SegmentationDescriptor) ([]byte, error) {
+	w := &bitWriter{}
+	w.bits(32, uint64(d.SegmentationEventId))
+	w.flag(d.SegmentationEventCancelIndicator)
+	w.reserved(7)
+	if d.SegmentationEventCancelIndicator {
+		return w.data, nil
+	}
+
+	var upidType uint8
+	var upid []byte
+	switch len(d.SegmentationUpids) {
+	case 0:
+	case 1:
+		value, err := d.SegmentationUpids[0].bytes()
+		if err != nil {
+			return nil, err
+		}
+		upidType, upid = d.SegmentationUpids[0].SegmentationUpidType, value
+	default:
+		upidType = UpidMID
+		for _, u := range d.SegmentationUpids {
+			value, err := u.bytes()
+			if err != nil {
+				return nil, err
+			}
+			if len(value) > 0xff {
+				return nil, fmt.Errorf("scte35: UPID of %d bytes", len(value))
+			}
+			upid = append(upid, u.SegmentationUpidType, byte(len(value)))
+			upid = append(upid, value...)
+		}
+	}
+	if len(upid) > 0xff {
+		return nil, fmt.Errorf("scte35: UPID of %d bytes", len(upid))
+	}
+	if d.SegmentationDuration != nil && *d.SegmentationDuration >= 1<<40 {
+		return nil, fmt.Errorf("scte35: segmentation_duration %d exceeds 40 bits", *d.SegmentationDuration)
+	}
+
+	w.flag(len(d.Components) == 0) // program_segmentation_flag
+	w.flag(d.SegmentationDuration != nil)
+	if r := d.DeliveryRestrictions; r == nil {
+		w.bits(1, 1) // delivery_not_restricted_flag
+		w.reserved(5)
+	} else {
+		w.bits(1, 0)
+		w.flag(r.WebDeliveryAllowedFlag)
+		w.flag(r.NoRegionalBlackoutFlag)
+		w.flag(r.ArchiveAllowedFlag)
+		w.bits(2, uint64(r.DeviceRestrictions))
+	}
+	if len(d.Components) > 0 {
+		w.bits(8, uint64(len(d.Components)))
+		for _, component := range d.Components {
+			w.bits(8, uint64(component.ComponentTag))
+			w.reserved(7)
+			w.bits(33, component.PTSOffset)
+		}
+	}
+	if d.SegmentationDuration != nil {
+		w.bits(40, *d.SegmentationDuration)
+	}
+	w.bits(8, uint64(upidType))
+	w.bits(8, uint64(len(upid)))
+	w.data = append(w.data, upid...)
+	w.bits(8, uint64(d.SegmentationTypeId))
+	w.bits(8, uint64(d.SegmentNum))
+	w.bits(8, uint64(d.SegmentsExpected))
+	if d.SubSegmentNum != nil && d.SubSegmentsExpected != nil {
+		w.bits(8, uint64(*d.SubSegmentNum))
+		w.bits(8, uint64(*d.SubSegmentsExpected))
+	}
+	if len(w.data) > 0xff-4 {
+		return nil, fmt.Errorf("scte35: segmentation descriptor %d too long", d.SegmentationEventId)
+	}
+	return w.data, nil
+}
+
+// bytes returns the value of u, decoding it from hexadecimal when its format is
+// hexbinary, or when it has no format and its type is a binary one.
+func (u *SegmentationUpid) bytes() ([]byte, error) {
+	if u.SegmentationUpidFormat == UpidFormatHexBinary || (u.SegmentationUpidFormat == "" && binaryUpidTypes[u.SegmentationUpidType]) {
+		value, err := hex.DecodeString(u.Value)
+		if err != nil {
+			return nil, fmt.Errorf("scte35: UPID of type 0x%02x: %v", u.SegmentationUpidType, err)
+		}
+		return value, nil
+	}
+	return []byte(u.Value), nil
+}
+
+//********************* Bit Writer *************************//
+
+// bitWriter appends big-endian fields of any width. Fields written by bytes must
+// start on a byte boundary.
+type bitWriter struct {
+	data []byte
+	n    int // bits used in the last byte, 0 when it is full
+}
+
+func (w *bitWriter) bits(n int, v uint64) {
+	for i := n - 1; i >= 0; i-- {
+		if w.n == 0 {
+			w.data = append(w.data, 0)
+		}
+		w.data[len(w.data)-1] |= byte(v>>uint(i)&1) << (7 - uint(w.n))
+		w.n = (w.n + 1) % 8
+	}
+}
+
+func (w *bitWriter) flag(b bool) {
+	if b {
+		w.bits(1, 1)
+	} else {
+		w.bits(1, 0)
+	}
+}
+
+// reserved writes n reserved bits, which are set.
+func (w *bitWriter) reserved(n int) {
+	w.bits(n, 1<<uint(n)-1)
+}
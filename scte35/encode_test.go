@@ -0,0 +1,110 @@
+package scte35
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+func TestEncodeRoundtrip(t *testing.T) {
+	mid := append([]byte{0x09, 5}, "SIG:1"...)
+	mid = append(mid, 0x08, 8, 0, 0, 0, 0, 0x2c, 0xa0, 0xa1, 0x8a)
+	built := base64.StdEncoding.EncodeToString(build(
+		segmentation(0x10, 0x01, []byte("00044MA000000037610T0318201400")),
+		segmentation(0x34, 0x0d, mid, 1, 3),
+	))
+	for _, vector := range []string{timeSignalPlacementOpportunityStart, spliceInsertOut, timeSignalProgramEndStart, built} {
+		section, err := DecodeBase64(vector)
+		if nil != err {
+			t.Log(err)
+			t.FailNow()
+		}
+		encoded, err := EncodeBase64(section)
+		if nil != err {
+			t.Log(err)
+			t.Fail()
+			continue
+		}
+		if encoded != vector {
+			t.Logf("Expected %s, got %s", vector, encoded)
+			t.Fail()
+		}
+	}
+}
+
+func TestNewTimeSignal(t *testing.T) {
+	var sp *scte224.SignalPoint
+	err := xml.Unmarshal([]byte(`<SignalPoint xmlns="urn:scte:224:action" segmentationEventId="0x4800008E" segmentationDuration="27630000"
+		segmentationTypeId="52" segmentationUpidType="8" segmentationUpid="000000002CA0A18A"/>`), &sp)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	pts := uint64(0x072bd0050)
+	section, err := NewTimeSignal(sp, &pts)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	// the vector is the second of a placement opportunity, restricted to archive
+	d := section.SegmentationDescriptors[0]
+	d.SegmentNum = 2
+	d.DeliveryRestrictions = &DeliveryRestrictions{NoRegionalBlackoutFlag: true, ArchiveAllowedFlag: true, DeviceRestrictions: 3}
+	if encoded, err := EncodeBase64(section); nil != err || encoded != timeSignalPlacementOpportunityStart {
+		t.Logf("Expected %s, got %s (%v)", timeSignalPlacementOpportunityStart, encoded, err)
+		t.Fail()
+	}
+	if TicksDuration(*d.SegmentationDuration) != 307*time.Second {
+		t.Log("Unexpected duration", TicksDuration(*d.SegmentationDuration))
+		t.Fail()
+	}
+
+	spi := &scte224.SignalPointInsertionAction{Offset: "PT1S", SignalPoints: []*scte224.SignalPoint{sp, {Offset: "PT0.5S"}}}
+	cues, err := InsertionCues(spi, ptsWrap-90000)
+	if nil != err || len(cues) != 2 {
+		t.Log("Unexpected cues", cues, err)
+		t.FailNow()
+	}
+	for i, expected := range []uint64{0, 45000} {
+		decoded, err := Decode(cues[i].Binary)
+		if nil != err || *decoded.TimeSignal.SpliceTime.PTSTime != expected || !bytes.Equal(cues[i].Binary, mustDecodeBase64(cues[i].Base64)) {
+			t.Logf("Unexpected cue %d: %+v (%v)", i, decoded, err)
+			t.Fail()
+		}
+	}
+
+	for _, bad := range []*scte224.SignalPoint{
+		{SegmentationEventId: "event"},
+		{SegmentationDuration: -1},
+		{SegmentationUpid: "SIGNAL:abc"},
+	} {
+		if _, err := NewTimeSignal(bad, nil); nil == err {
+			t.Logf("Expected an error for %+v", bad)
+			t.Fail()
+		}
+	}
+	upidType := uint(8)
+	if _, err := InsertionCues(&scte224.SignalPointInsertionAction{SignalPoints: []*scte224.SignalPoint{{SegmentationUpidType: &upidType, SegmentationUpid: "XYZ"}}}, 0); nil == err {
+		t.Log("Expected an error for a binary UPID that is not hexadecimal")
+		t.Fail()
+	}
+}
+
+func TestTicks(t *testing.T) {
+	if Ticks(time.Second+time.Millisecond) != 90090 || Ticks(-time.Second) != 0 || TicksDuration(90090) != time.Second+time.Millisecond {
+		t.Log("Unexpected conversion", Ticks(time.Second+time.Millisecond), TicksDuration(90090))
+		t.Fail()
+	}
+}
+
+func mustDecodeBase64(s string) []byte {
+	raw, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return raw
+}
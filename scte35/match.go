@@ -0,0 +1,60 @@
+package scte35
+
+import (
+	"fmt"
+	"strings"
+
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+	"github.com/Comcast/scte224structs/xpath"
+)
+
+// A Matcher evaluates the assertions of a MatchSignal against cues.
+type Matcher struct {
+	match      scte224.Match
+	assertions []*xpath.Expr
+}
+
+// NewMatcher compiles the assertions of ms.
+func NewMatcher(ms *scte224.MatchSignal) (*Matcher, error) {
+	m := &Matcher{match: ms.Match}
+	for _, assert := range ms.Assertions {
+		if assert == nil {
+			continue
+		}
+		e, err := xpath.Compile(strings.TrimSpace(assert.Declaration))
+		if err != nil {
+			return nil, fmt.Errorf("scte35: %v", err)
+		}
+		m.assertions = append(m.assertions, e)
+	}
+	return m, nil
+}
+
+// Matches reports whether the assertions hold for section as the match attribute
+// of the MatchSignal requires: all of them when it is ALL or absent, at least one
+// when it is ANY, and none when it is NONE.
+func (m *Matcher) Matches(section *SpliceInfoSection) (bool, error) {
+	doc, err := xpath.FromValue(section)
+	if err != nil {
+		return false, err
+	}
+	return m.MatchesNode(doc), nil
+}
+
+// MatchesNode is Matches for the XML document of a cue, parsed already.
+func (m *Matcher) MatchesNode(doc *xpath.Node) bool {
+	held := 0
+	for _, e := range m.assertions {
+		if e.Matches(doc) {
+			held++
+		}
+	}
+	switch {
+	case m.match.IsAny():
+		return held > 0
+	case m.match.IsNone():
+		return held == 0
+	default:
+		return held == len(m.assertions)
+	}
+}
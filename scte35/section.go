@@ -0,0 +1,171 @@
+// Package scte35 decodes SCTE 35 splice_info_sections, the cues MatchSignal
+// assertions are evaluated against, and encodes the cues SignalPointInsertion
+// actions call for.
+//
+// Sections decode into structs that marshal to the XML representation of the
+// SCTE 35 schema, so that the XPath of an Assert such as
+//
+//	/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=52]
+//
+// can be evaluated against the XML of a decoded cue.
+package scte35
+
+import (
+	"encoding/xml"
+)
+
+// Namespace is the namespace of the XML representation of SCTE 35.
+const Namespace = "http://www.scte.org/schemas/35"
+
+// Splice command types
+const (
+	SpliceNullType           = 0x00
+	SpliceScheduleType       = 0x04
+	SpliceInsertType         = 0x05
+	TimeSignalType           = 0x06
+	BandwidthReservationType = 0x07
+	PrivateCommandType       = 0xff
+)
+
+// Splice descriptor tags
+const (
+	AvailDescriptorTag        = 0x00
+	DTMFDescriptorTag         = 0x01
+	SegmentationDescriptorTag = 0x02
+	TimeDescriptorTag         = 0x03
+)
+
+// CUEIdentifier is the identifier of the splice descriptors defined by SCTE 35, "CUEI".
+const CUEIdentifier = 0x43554549
+
+// SpliceInfoSection is a decoded cue. Exactly one of the command fields is set.
+type SpliceInfoSection struct {
+	XMLName         xml.Name `xml:"http://www.scte.org/schemas/35 SpliceInfoSection" json:"-"`
+	SAPType         uint8    `xml:"sapType,attr" json:"sapType"`
+	ProtocolVersion uint8    `xml:"protocolVersion,attr" json:"protocolVersion"`
+	PTSAdjustment   uint64   `xml:"ptsAdjustment,attr" json:"ptsAdjustment"`
+	Tier            uint16   `xml:"tier,attr" json:"tier"`
+
+	SpliceNull           *SpliceNull           `xml:"http://www.scte.org/schemas/35 SpliceNull,omitempty" json:"spliceNull,omitempty"`
+	SpliceInsert         *SpliceInsert         `xml:"http://www.scte.org/schemas/35 SpliceInsert,omitempty" json:"spliceInsert,omitempty"`
+	TimeSignal           *TimeSignal           `xml:"http://www.scte.org/schemas/35 TimeSignal,omitempty" json:"timeSignal,omitempty"`
+	BandwidthReservation *BandwidthReservation `xml:"http://www.scte.org/schemas/35 BandwidthReservation,omitempty" json:"bandwidthReservation,omitempty"`
+	PrivateCommand       *PrivateCommand       `xml:"http://www.scte.org/schemas/35 PrivateCommand,omitempty" json:"privateCommand,omitempty"`
+
+	AvailDescriptors        []*AvailDescriptor        `xml:"http://www.scte.org/schemas/35 AvailDescriptor,omitempty" json:"availDescriptors,omitempty"`
+	DTMFDescriptors         []*DTMFDescriptor         `xml:"http://www.scte.org/schemas/35 DTMFDescriptor,omitempty" json:"dtmfDescriptors,omitempty"`
+	SegmentationDescriptors []*SegmentationDescriptor `xml:"http://www.scte.org/schemas/35 SegmentationDescriptor,omitempty" json:"segmentationDescriptors,omitempty"`
+	TimeDescriptors         []*TimeDescriptor         `xml:"http://www.scte.org/schemas/35 TimeDescriptor,omitempty" json:"timeDescriptors,omitempty"`
+}
+
+//********************* Splice Commands *************************//
+
+type SpliceNull struct{}
+
+// SpliceTime is a presentation time in 90 kHz ticks; PTSTime is nil when the time
+// is not specified.
+type SpliceTime struct {
+	PTSTime *uint64 `xml:"ptsTime,attr,omitempty" json:"ptsTime,omitempty"`
+}
+
+type SpliceInsert struct {
+	SpliceEventId              uint32             `xml:"spliceEventId,attr" json:"spliceEventId"`
+	SpliceEventCancelIndicator bool               `xml:"spliceEventCancelIndicator,attr,omitempty" json:"spliceEventCancelIndicator,omitempty"`
+	OutOfNetworkIndicator      bool               `xml:"outOfNetworkIndicator,attr,omitempty" json:"outOfNetworkIndicator,omitempty"`
+	SpliceImmediateFlag        bool               `xml:"spliceImmediateFlag,attr,omitempty" json:"spliceImmediateFlag,omitempty"`
+	UniqueProgramId            uint16             `xml:"uniqueProgramId,attr,omitempty" json:"uniqueProgramId,omitempty"`
+	AvailNum                   uint8              `xml:"availNum,attr,omitempty" json:"availNum,omitempty"`
+	AvailsExpected             uint8              `xml:"availsExpected,attr,omitempty" json:"availsExpected,omitempty"`
+	Program                    *SpliceProgram     `xml:"http://www.scte.org/schemas/35 Program,omitempty" json:"program,omitempty"`
+	Components                 []*SpliceComponent `xml:"http://www.scte.org/schemas/35 Component,omitempty" json:"components,omitempty"`
+	BreakDuration              *BreakDuration     `xml:"http://www.scte.org/schemas/35 BreakDuration,omitempty" json:"breakDuration,omitempty"`
+}
+
+// SpliceProgram is the splice time of a program splice; SpliceTime is nil for
+// immediate splices.
+type SpliceProgram struct {
+	SpliceTime *SpliceTime `xml:"http://www.scte.org/schemas/35 SpliceTime,omitempty" json:"spliceTime,omitempty"`
+}
+
+type SpliceComponent struct {
+	ComponentTag uint8       `xml:"componentTag,attr" json:"componentTag"`
+	SpliceTime   *SpliceTime `xml:"http://www.scte.org/schemas/35 SpliceTime,omitempty" json:"spliceTime,omitempty"`
+}
+
+// BreakDuration is a duration in 90 kHz ticks.
+type BreakDuration struct {
+	AutoReturn bool   `xml:"autoReturn,attr" json:"autoReturn"`
+	Duration   uint64 `xml:"duration,attr" json:"duration"`
+}
+
+type TimeSignal struct {
+	SpliceTime SpliceTime `xml:"http://www.scte.org/schemas/35 SpliceTime" json:"spliceTime"`
+}
+
+type BandwidthReservation struct{}
+
+// PrivateCommand keeps the bytes of a private command, in hexadecimal.
+type PrivateCommand struct {
+	Identifier uint32 `xml:"identifier,attr" json:"identifier"`
+	Data       string `xml:",chardata" json:"data,omitempty"`
+}
+
+//********************* Splice Descriptors *************************//
+
+type AvailDescriptor struct {
+	ProviderAvailId uint32 `xml:"providerAvailId,attr" json:"providerAvailId"`
+}
+
+type DTMFDescriptor struct {
+	Preroll uint8  `xml:"preroll,attr" json:"preroll"`
+	Chars   string `xml:"chars,attr" json:"chars"`
+}
+
+type TimeDescriptor struct {
+	TAISeconds uint64 `xml:"taiSeconds,attr" json:"taiSeconds"`
+	TAINs      uint32 `xml:"taiNs,attr" json:"taiNs"`
+	UTCOffset  uint16 `xml:"utcOffset,attr" json:"utcOffset"`
+}
+
+// SegmentationDescriptor identifies a segment of content, such as a program or a
+// placement opportunity. Durations are in 90 kHz ticks.
+type SegmentationDescriptor struct {
+	SegmentationEventId              uint32                `xml:"segmentationEventId,attr" json:"segmentationEventId"`
+	SegmentationEventCancelIndicator bool                  `xml:"segmentationEventCancelIndicator,attr,omitempty" json:"segmentationEventCancelIndicator,omitempty"`
+	SegmentationDuration             *uint64               `xml:"segmentationDuration,attr,omitempty" json:"segmentationDuration,omitempty"`
+	SegmentationTypeId               uint8                 `xml:"segmentationTypeId,attr" json:"segmentationTypeId"`
+	SegmentNum                       uint8                 `xml:"segmentNum,attr" json:"segmentNum"`
+	SegmentsExpected                 uint8                 `xml:"segmentsExpected,attr" json:"segmentsExpected"`
+	SubSegmentNum                    *uint8                `xml:"subSegmentNum,attr,omitempty" json:"subSegmentNum,omitempty"`
+	SubSegmentsExpected              *uint8                `xml:"subSegmentsExpected,attr,omitempty" json:"subSegmentsExpected,omitempty"`
+	DeliveryRestrictions             *DeliveryRestrictions `xml:"http://www.scte.org/schemas/35 DeliveryRestrictions,omitempty" json:"deliveryRestrictions,omitempty"`
+	// SegmentationUpids holds one UPID, or the UPIDs of an MID (type 0x0D).
+	SegmentationUpids []*SegmentationUpid      `xml:"http://www.scte.org/schemas/35 SegmentationUpid,omitempty" json:"segmentationUpids,omitempty"`
+	Components        []*SegmentationComponent `xml:"http://www.scte.org/schemas/35 Component,omitempty" json:"components,omitempty"`
+}
+
+type DeliveryRestrictions struct {
+	WebDeliveryAllowedFlag bool  `xml:"webDeliveryAllowedFlag,attr" json:"webDeliveryAllowedFlag"`
+	NoRegionalBlackoutFlag bool  `xml:"noRegionalBlackoutFlag,attr" json:"noRegionalBlackoutFlag"`
+	ArchiveAllowedFlag     bool  `xml:"archiveAllowedFlag,attr" json:"archiveAllowedFlag"`
+	DeviceRestrictions     uint8 `xml:"deviceRestrictions,attr" json:"deviceRestrictions"`
+}
+
+// Formats of the value of a SegmentationUpid.
+const (
+	UpidFormatText      = "text"
+	UpidFormatHexBinary = "hexbinary"
+)
+
+// SegmentationUpid is a UPID, with its value written as text when the bytes are
+// printable and the type is not a binary one, and in hexadecimal otherwise.
+type SegmentationUpid struct {
+	SegmentationUpidType   uint8  `xml:"segmentationUpidType,attr" json:"segmentationUpidType"`
+	SegmentationUpidFormat string `xml:"segmentationUpidFormat,attr" json:"segmentationUpidFormat"`
+	Value                  string `xml:",chardata" json:"value"`
+}
+
+type SegmentationComponent struct {
+	ComponentTag uint8  `xml:"componentTag,attr" json:"componentTag"`
+	PTSOffset    uint64 `xml:"ptsOffset,attr" json:"ptsOffset"`
+}
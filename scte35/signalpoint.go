@@ -0,0 +1,94 @@
+package scte35
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+// ptsWrap is the modulus of the 33 bit PTS clock.
+const ptsWrap = 1 << 33
+
+// NewTimeSignal returns a splice_info_section with a time_signal at pts and the
+// segmentation_descriptor sp describes: its segmentationEventId, decimal or
+// 0x-prefixed hexadecimal, segmentationTypeId, segmentationUpidType,
+// segmentationUpid, in the string form ParseUpid parses, and segmentationDuration,
+// in 90 kHz ticks. A nil pts signals immediately.
+//
+// The section has the SAP type 3 (not specified), the
+// tier 0xFFF and no delivery restriction, which callers may change before
+// encoding it.
+func NewTimeSignal(sp *scte224.SignalPoint, pts *uint64) (*SpliceInfoSection, error) {
+	d := &SegmentationDescriptor{}
+	if sp.SegmentationEventId != "" {
+		id, err := strconv.ParseUint(sp.SegmentationEventId, 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("scte35: segmentationEventId %q: %v", sp.SegmentationEventId, err)
+		}
+		d.SegmentationEventId = uint32(id)
+	}
+	if sp.SegmentationDuration < 0 {
+		return nil, fmt.Errorf("scte35: negative segmentationDuration %d", sp.SegmentationDuration)
+	}
+	if sp.SegmentationDuration > 0 {
+		duration := uint64(sp.SegmentationDuration)
+		d.SegmentationDuration = &duration
+	}
+	if sp.SegmentationTypeId != nil {
+		if *sp.SegmentationTypeId > 0xff {
+			return nil, fmt.Errorf("scte35: segmentationTypeId %d exceeds a byte", *sp.SegmentationTypeId)
+		}
+		d.SegmentationTypeId = uint8(*sp.SegmentationTypeId)
+	}
+	upid, err := SignalPointUpid(sp)
+	if err != nil {
+		return nil, err
+	}
+	if upid != nil {
+		d.SegmentationUpids = []*SegmentationUpid{upid.segmentationUpid()}
+	}
+	if pts != nil {
+		wrapped := *pts % ptsWrap
+		pts = &wrapped
+	}
+	return &SpliceInfoSection{
+		SAPType:                 3,
+		Tier:                    0xfff,
+		TimeSignal:              &TimeSignal{SpliceTime: SpliceTime{PTSTime: pts}},
+		SegmentationDescriptors: []*SegmentationDescriptor{d},
+	}, nil
+}
+
+// A Cue is an encoded section.
+type Cue struct {
+	SignalPoint *scte224.SignalPoint `json:"signalPoint,omitempty"`
+	Section     *SpliceInfoSection   `json:"section"`
+	Binary      []byte               `json:"-"`
+	Base64      string               `json:"base64"`
+}
+
+// InsertionCues encodes a time_signal cue for each SignalPoint of spi, at pts
+// shifted by the offset of spi and of the SignalPoint. Repetitions are not
+// expanded: use SignalPointInsertionAction.Expand for their times, and
+// NewTimeSignal to encode them.
+func InsertionCues(spi *scte224.SignalPointInsertionAction, pts uint64) ([]*Cue, error) {
+	var cues []*Cue
+	for _, sp := range spi.SignalPoints {
+		if sp == nil {
+			continue
+		}
+		at := pts + Ticks(spi.Offset.GoDuration()) + Ticks(sp.Offset.GoDuration())
+		section, err := NewTimeSignal(sp, &at)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := Encode(section)
+		if err != nil {
+			return nil, err
+		}
+		cues = append(cues, &Cue{SignalPoint: sp, Section: section, Binary: raw, Base64: base64.StdEncoding.EncodeToString(raw)})
+	}
+	return cues, nil
+}
@@ -0,0 +1,438 @@
+package scte35
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Comcast/scte224structs/iso7064"
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+// Segmentation UPID types, the segmentation_upid_type of a segmentation_descriptor
+const (
+	UpidNotUsed        = 0x00
+	UpidUserDefined    = 0x01 // deprecated
+	UpidISCI           = 0x02 // deprecated
+	UpidAdId           = 0x03
+	UpidUMID           = 0x04
+	UpidISANDeprecated = 0x05 // ISAN without its version
+	UpidISAN           = 0x06
+	UpidTID            = 0x07 // Tribune Media Systems program identifier
+	UpidTI             = 0x08 // Turner identifier, or Airing ID
+	UpidADI            = 0x09
+	UpidEIDR           = 0x0a
+	UpidATSC           = 0x0b // ATSC content identifier
+	UpidMPU            = 0x0c // managed private UPID
+	UpidMID            = 0x0d // multiple UPIDs
+	UpidADS            = 0x0e // advertising information
+	UpidURI            = 0x0f
+	UpidUUID           = 0x10
+	UpidSCR            = 0x11 // subscriber company reporting
+)
+
+// Segmentation types, the segmentation_type_id of a segmentation_descriptor, as
+// SignalPoints and Asserts refer to them
+const (
+	SegmentationNotIndicated                    = 0x00
+	ContentIdentification                       = 0x01
+	ProgramStart                                = 0x10
+	ProgramEnd                                  = 0x11
+	ProgramEarlyTermination                     = 0x12
+	ProgramBreakaway                            = 0x13
+	ProgramResumption                           = 0x14
+	ProgramRunoverPlanned                       = 0x15
+	ProgramRunoverUnplanned                     = 0x16
+	ProgramOverlapStart                         = 0x17
+	ProgramBlackoutOverride                     = 0x18
+	ProgramJoin                                 = 0x19
+	ChapterStart                                = 0x20
+	ChapterEnd                                  = 0x21
+	BreakStart                                  = 0x22
+	BreakEnd                                    = 0x23
+	OpeningCreditStart                          = 0x24
+	OpeningCreditEnd                            = 0x25
+	ClosingCreditStart                          = 0x26
+	ClosingCreditEnd                            = 0x27
+	ProviderAdvertisementStart                  = 0x30
+	ProviderAdvertisementEnd                    = 0x31
+	DistributorAdvertisementStart               = 0x32
+	DistributorAdvertisementEnd                 = 0x33
+	ProviderPlacementOpportunityStart           = 0x34
+	ProviderPlacementOpportunityEnd             = 0x35
+	DistributorPlacementOpportunityStart        = 0x36
+	DistributorPlacementOpportunityEnd          = 0x37
+	ProviderOverlayPlacementOpportunityStart    = 0x38
+	ProviderOverlayPlacementOpportunityEnd      = 0x39
+	DistributorOverlayPlacementOpportunityStart = 0x3a
+	DistributorOverlayPlacementOpportunityEnd   = 0x3b
+	ProviderPromoStart                          = 0x3c
+	ProviderPromoEnd                            = 0x3d
+	DistributorPromoStart                       = 0x3e
+	DistributorPromoEnd                         = 0x3f
+	UnscheduledEventStart                       = 0x40
+	UnscheduledEventEnd                         = 0x41
+	AlternateContentOpportunityStart            = 0x42
+	AlternateContentOpportunityEnd              = 0x43
+	ProviderAdBlockStart                        = 0x44
+	ProviderAdBlockEnd                          = 0x45
+	DistributorAdBlockStart                     = 0x46
+	DistributorAdBlockEnd                       = 0x47
+	NetworkStart                                = 0x50
+	NetworkEnd                                  = 0x51
+)
+
+// A Upid is a segmentation_upid checked against the format of its type.
+type Upid struct {
+	Type uint8
+	// Value is the segmentation_upid as encoded in a cue; that of an MID is the
+	// sequence of its UPIDs, each with its type and length.
+	Value []byte
+}
+
+// upidFormat is how the UPIDs of a type are written as strings.
+type upidFormat struct {
+	name   string
+	length int // of the values of fixed-length types, 0 for the others
+	parse  func(s string) ([]byte, error)
+	format func(value []byte) string
+	// check validates a value of the right length
+	check func(value []byte) error
+}
+
+var (
+	textFormat = upidFormat{parse: parseText, format: formatText, check: printableText}
+	hexFormat  = upidFormat{parse: parseHex, format: formatHex}
+)
+
+func init() {
+	upidFormats[UpidMID] = upidFormat{name: "MID", parse: parseHex, format: formatHex, check: checkMID}
+}
+
+// upidFormats are the formats of the known UPID types; the others are written in
+// hexadecimal.
+var upidFormats = map[uint8]upidFormat{
+	UpidNotUsed:        {name: "not used", parse: parseText, format: formatText},
+	UpidUserDefined:    named("user defined", textFormat),
+	UpidISCI:           {name: "ISCI", length: 8, parse: parseText, format: formatText, check: alphanumeric},
+	UpidAdId:           {name: "Ad-ID", length: 12, parse: parseText, format: formatText, check: alphanumeric},
+	UpidUMID:           {name: "UMID", length: 32, parse: parseUMID, format: formatUMID},
+	UpidISANDeprecated: {name: "ISAN", length: 8, parse: parseISAN, format: formatISAN},
+	UpidISAN:           {name: "ISAN", length: 12, parse: parseISAN, format: formatISAN},
+	UpidTID:            {name: "TID", length: 12, parse: parseText, format: formatText, check: checkTID},
+	UpidTI:             {name: "TI", length: 8, parse: parseHex, format: formatHex},
+	UpidADI:            named("ADI", textFormat),
+	UpidEIDR:           {name: "EIDR", length: 12, parse: parseEIDR, format: formatEIDR},
+	UpidATSC:           {name: "ATSC content identifier", parse: parseHex, format: formatHex, check: minimum(4)},
+	UpidMPU:            {name: "MPU", parse: parseHex, format: formatHex, check: minimum(4)},
+	UpidADS:            named("ADS", textFormat),
+	UpidURI:            {name: "URI", parse: parseText, format: formatText, check: checkURI},
+	UpidUUID:           {name: "UUID", length: 16, parse: parseUUID, format: formatUUID},
+	UpidSCR:            named("SCR", textFormat),
+}
+
+func named(name string, f upidFormat) upidFormat {
+	f.name = name
+	return f
+}
+
+func formatOf(upidType uint8) upidFormat {
+	if f, ok := upidFormats[upidType]; ok {
+		return f
+	}
+	return named(fmt.Sprintf("type 0x%02x", upidType), hexFormat)
+}
+
+// NewUpid returns the UPID of a type with the given value, checking that it is
+// valid for the type.
+func NewUpid(upidType uint8, value []byte) (*Upid, error) {
+	f := formatOf(upidType)
+	if len(value) > 0xff {
+		return nil, fmt.Errorf("scte35: %s UPID of %d bytes", f.name, len(value))
+	}
+	if upidType == UpidNotUsed && len(value) > 0 {
+		return nil, errors.New("scte35: UPID of the type not used with a value")
+	}
+	if f.length > 0 && len(value) != f.length {
+		return nil, fmt.Errorf("scte35: %s UPID of %d bytes instead of %d", f.name, len(value), f.length)
+	}
+	if f.check != nil {
+		if err := f.check(value); err != nil {
+			return nil, fmt.Errorf("scte35: %s UPID: %v", f.name, err)
+		}
+	}
+	return &Upid{Type: upidType, Value: value}, nil
+}
+
+// ParseUpid parses the string form of a UPID of the given type:
+//
+//   - ISCI, Ad-ID, TID, ADI, URI, ADS, SCR and user defined UPIDs are text;
+//   - UMIDs are 64 hexadecimal digits, in groups of 8 separated by dots or not;
+//   - ISANs are hexadecimal digits in groups of 4 separated by hyphens, as in
+//     0000-0000-3A8D-0000-Z-0000-0000-6, whose check characters are optional
+//     but must be right when present;
+//   - EIDRs are DOIs such as 10.5240/7791-8534-2C23-9030-8610-5, whose check
+//     character is optional but must be right when present;
+//   - UUIDs are 32 hexadecimal digits, in the groups of RFC 4122 or not;
+//   - TIs, or Airing IDs, ATSC content identifiers, MPUs and MIDs, and UPIDs of
+//     other types, are hexadecimal, with or without a 0x prefix; an MID is the
+//     sequence of its UPIDs, each with its type and length.
+func ParseUpid(upidType uint8, s string) (*Upid, error) {
+	f := formatOf(upidType)
+	value, err := f.parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("scte35: %s UPID %q: %v", f.name, s, err)
+	}
+	return NewUpid(upidType, value)
+}
+
+// String returns the string form of u, which ParseUpid parses back to u. Check
+// characters are included.
+func (u *Upid) String() string {
+	return formatOf(u.Type).format(u.Value)
+}
+
+// Upids returns the UPIDs of an MID, or u itself for other types.
+func (u *Upid) Upids() []*Upid {
+	if u.Type != UpidMID {
+		return []*Upid{u}
+	}
+	var upids []*Upid
+	for value := u.Value; len(value) >= 2 && len(value) >= 2+int(value[1]); value = value[2+int(value[1]):] {
+		upids = append(upids, &Upid{Type: value[0], Value: value[2 : 2+int(value[1])]})
+	}
+	return upids
+}
+
+// Upid returns the typed UPID of u.
+func (u *SegmentationUpid) Upid() (*Upid, error) {
+	value, err := u.bytes()
+	if err != nil {
+		return nil, err
+	}
+	return NewUpid(u.SegmentationUpidType, value)
+}
+
+// SignalPointUpid parses the segmentationUpid of sp according to its
+// segmentationUpidType, and returns nil when sp has no UPID type.
+func SignalPointUpid(sp *scte224.SignalPoint) (*Upid, error) {
+	if sp.SegmentationUpidType == nil {
+		if sp.SegmentationUpid != "" {
+			return nil, errors.New("scte35: segmentationUpid without a segmentationUpidType")
+		}
+		return nil, nil
+	}
+	if *sp.SegmentationUpidType > 0xff {
+		return nil, fmt.Errorf("scte35: segmentationUpidType %d exceeds a byte", *sp.SegmentationUpidType)
+	}
+	return ParseUpid(uint8(*sp.SegmentationUpidType), sp.SegmentationUpid)
+}
+
+// segmentationUpid returns u as decoded sections hold it.
+func (u *Upid) segmentationUpid() *SegmentationUpid {
+	if u.Type == UpidMID {
+		return &SegmentationUpid{SegmentationUpidType: u.Type, SegmentationUpidFormat: UpidFormatHexBinary, Value: formatHex(u.Value)}
+	}
+	return newSegmentationUpid(u.Type, u.Value)
+}
+
+//********************* Formats *************************//
+
+func parseText(s string) ([]byte, error) {
+	return []byte(s), nil
+}
+
+func formatText(value []byte) string {
+	return string(value)
+}
+
+func printableText(value []byte) error {
+	if !printable(value) {
+		return errors.New("not printable text")
+	}
+	return nil
+}
+
+func alphanumeric(value []byte) error {
+	for _, b := range value {
+		if !('0' <= b && b <= '9' || 'A' <= b && b <= 'Z' || 'a' <= b && b <= 'z') {
+			return fmt.Errorf("%q is not alphanumeric", b)
+		}
+	}
+	return nil
+}
+
+// checkTID checks for two capital letters followed by ten digits, twelve
+// characters in all, as in EP0123456789.
+func checkTID(value []byte) error {
+	for i, b := range value {
+		if i < 2 && !('A' <= b && b <= 'Z') || i >= 2 && !('0' <= b && b <= '9') {
+			return errors.New("not two capital letters followed by ten digits")
+		}
+	}
+	return nil
+}
+
+func checkURI(value []byte) error {
+	u, err := url.Parse(string(value))
+	if err != nil {
+		return err
+	}
+	if !u.IsAbs() {
+		return errors.New("not an absolute URI")
+	}
+	return nil
+}
+
+func minimum(n int) func([]byte) error {
+	return func(value []byte) error {
+		if len(value) < n {
+			return fmt.Errorf("%d bytes, fewer than %d", len(value), n)
+		}
+		return nil
+	}
+}
+
+func checkMID(value []byte) error {
+	for len(value) > 0 {
+		if len(value) < 2 || len(value) < 2+int(value[1]) {
+			return errors.New("truncated")
+		}
+		if value[0] == UpidMID {
+			return errors.New("nested MID")
+		}
+		if _, err := NewUpid(value[0], value[2:2+int(value[1])]); err != nil {
+			return err
+		}
+		value = value[2+int(value[1]):]
+	}
+	return nil
+}
+
+func parseHex(s string) ([]byte, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+	}
+	return hex.DecodeString(s)
+}
+
+func formatHex(value []byte) string {
+	return strings.ToUpper(hex.EncodeToString(value))
+}
+
+func parseUMID(s string) ([]byte, error) {
+	return hex.DecodeString(strings.Replace(s, ".", "", -1))
+}
+
+func formatUMID(value []byte) string {
+	digits := formatHex(value)
+	var groups []string
+	for i := 0; i < len(digits); i += 8 {
+		groups = append(groups, digits[i:min(i+8, len(digits))])
+	}
+	return strings.Join(groups, ".")
+}
+
+func parseUUID(s string) ([]byte, error) {
+	return hex.DecodeString(strings.Replace(s, "-", "", -1))
+}
+
+func formatUUID(value []byte) string {
+	digits := hex.EncodeToString(value)
+	if len(digits) != 32 {
+		return digits
+	}
+	return digits[:8] + "-" + digits[8:12] + "-" + digits[12:16] + "-" + digits[16:20] + "-" + digits[20:]
+}
+
+// parseISAN parses groups of 4 hexadecimal digits, checking the check characters
+// found after the 4th and 6th groups.
+func parseISAN(s string) ([]byte, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "ISAN ")
+	var digits string
+	for _, group := range strings.Split(s, "-") {
+		switch {
+		case len(group) == 1 && (len(digits) == 16 || len(digits) == 24):
+			if !iso7064.ValidMod3736(digits + group) {
+				return nil, fmt.Errorf("wrong check character %s", group)
+			}
+		case len(group) == 4:
+			digits += group
+		default:
+			return nil, fmt.Errorf("unexpected group %q", group)
+		}
+	}
+	return hex.DecodeString(digits)
+}
+
+func formatISAN(value []byte) string {
+	digits := formatHex(value)
+	var groups []string
+	for i := 0; i+4 <= len(digits); i += 4 {
+		groups = append(groups, digits[i:i+4])
+		if i+4 == 16 || i+4 == 24 {
+			check, _ := iso7064.Mod3736(digits[:i+4])
+			groups = append(groups, string(check))
+		}
+	}
+	return strings.Join(groups, "-")
+}
+
+// parseEIDR parses a DOI of the 10. directory, whose suffix is 20 hexadecimal
+// digits in groups of 4, optionally followed by a check character.
+func parseEIDR(s string) ([]byte, error) {
+	slash := strings.IndexByte(s, '/')
+	if !strings.HasPrefix(s, "10.") || slash < 0 {
+		return nil, errors.New("not a 10. DOI")
+	}
+	prefix, err := strconv.ParseUint(s[3:slash], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("prefix: %v", err)
+	}
+	groups := strings.Split(s[slash+1:], "-")
+	if len(groups) == 6 {
+		if len(groups[5]) != 1 || !iso7064.ValidMod3736(strings.Join(groups, "")) {
+			return nil, fmt.Errorf("wrong check character %s", groups[5])
+		}
+		groups = groups[:5]
+	}
+	for _, group := range groups {
+		if len(group) != 4 {
+			return nil, fmt.Errorf("unexpected group %q", group)
+		}
+	}
+	suffix, err := hex.DecodeString(strings.Join(groups, ""))
+	if err != nil || len(suffix) != 10 {
+		return nil, errors.New("suffix is not 20 hexadecimal digits")
+	}
+	value := make([]byte, 2, 12)
+	binary.BigEndian.PutUint16(value, uint16(prefix))
+	return append(value, suffix...), nil
+}
+
+func formatEIDR(value []byte) string {
+	if len(value) != 12 {
+		return formatHex(value)
+	}
+	digits := formatHex(value[2:])
+	check, _ := iso7064.Mod3736(digits)
+	var b strings.Builder
+	fmt.Fprintf(&b, "10.%d/", binary.BigEndian.Uint16(value))
+	for i := 0; i < len(digits); i += 4 {
+		b.WriteString(digits[i : i+4])
+		b.WriteByte('-')
+	}
+	b.WriteByte(check)
+	return b.String()
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
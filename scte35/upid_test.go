@@ -0,0 +1,143 @@
+package scte35
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+func TestParseUpid(t *testing.T) {
+	for _, test := range []struct {
+		upidType  uint8
+		input     string
+		length    int
+		canonical string
+	}{
+		{UpidISCI, "ABCD1234", 8, "ABCD1234"},
+		{UpidAdId, "ABCD01234567", 12, "ABCD01234567"},
+		{UpidUMID, "060A2B34.01010105.01010D20.13000000.D2C9036C.8F195343.AB7014D2.D718BFDA", 32, ""},
+		{UpidUMID, "060a2b340101010501010d2013000000d2c9036c8f195343ab7014d2d718bfda", 32, "060A2B34.01010105.01010D20.13000000.D2C9036C.8F195343.AB7014D2.D718BFDA"},
+		{UpidISANDeprecated, "0000-0000-3A8D-0000", 8, "0000-0000-3A8D-0000-Z"},
+		{UpidISAN, "ISAN 0000-0000-3A8D-0000-Z-0000-0000-6", 12, "0000-0000-3A8D-0000-Z-0000-0000-6"},
+		{UpidTID, "EP012345678901", 0, ""},
+		{UpidTID, "MV0123456789", 12, "MV0123456789"},
+		{UpidTI, "0x000000002CA0A18A", 8, "000000002CA0A18A"},
+		{UpidADI, "SIGNAL:0RepxyxtQk65aT7clrgNRA==", 31, "SIGNAL:0RepxyxtQk65aT7clrgNRA=="},
+		{UpidEIDR, "10.5240/7791-8534-2C23-9030-8610-5", 12, ""},
+		{UpidEIDR, "10.5240/7791-8534-2c23-9030-8610", 12, "10.5240/7791-8534-2C23-9030-8610-5"},
+		{UpidATSC, "00010203", 4, "00010203"},
+		{UpidMPU, "4D504531DEADBEEF", 8, "4D504531DEADBEEF"},
+		{UpidMID, "09055349473A310808000000002CA0A18A", 17, ""},
+		{UpidURI, "urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6", 45, "urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6"},
+		{UpidUUID, "F81D4FAE7DEC11D0A76500A0C91E6BF6", 16, "f81d4fae-7dec-11d0-a765-00a0c91e6bf6"},
+		{0x7f, "ff00", 2, "FF00"},
+	} {
+		upid, err := ParseUpid(test.upidType, test.input)
+		if test.length == 0 {
+			if nil == err {
+				t.Logf("Expected %q not to parse as type 0x%02x", test.input, test.upidType)
+				t.Fail()
+			}
+			continue
+		}
+		if nil != err {
+			t.Log(err)
+			t.Fail()
+			continue
+		}
+		canonical := test.canonical
+		if canonical == "" {
+			canonical = test.input
+		}
+		if len(upid.Value) != test.length || upid.String() != canonical {
+			t.Logf("Expected %d bytes formatted as %s, got %d bytes formatted as %s", test.length, canonical, len(upid.Value), upid)
+			t.Fail()
+		}
+		// the canonical form parses back to the same UPID
+		if again, err := ParseUpid(test.upidType, upid.String()); nil != err || !bytes.Equal(again.Value, upid.Value) {
+			t.Logf("Expected %s to parse back, got %v (%v)", upid, again, err)
+			t.Fail()
+		}
+	}
+}
+
+func TestParseUpidErrors(t *testing.T) {
+	for _, test := range []struct {
+		upidType uint8
+		input    string
+	}{
+		{UpidISCI, "ABCD123"},
+		{UpidAdId, "ABCD-1234567"},
+		{UpidISAN, "0000-0000-3A8D-0000-Y-0000-0000-6"},
+		{UpidISAN, "0000-0000-3A8D"},
+		{UpidTI, "2CA0A18A"},
+		{UpidADI, "SIGNAL:\x01"},
+		{UpidEIDR, "10.5240/7791-8534-2C23-9030-8610-6"},
+		{UpidEIDR, "11.5240/7791-8534-2C23-9030-8610"},
+		{UpidEIDR, "10.99999/7791-8534-2C23-9030-8610"},
+		{UpidMPU, "4D50"},
+		{UpidMID, "0D00"},
+		{UpidMID, "0905534947"},
+		{UpidURI, "not/absolute"},
+		{UpidUUID, "f81d4fae"},
+		{UpidNotUsed, "x"},
+	} {
+		if upid, err := ParseUpid(test.upidType, test.input); nil == err {
+			t.Logf("Expected %q not to parse as type 0x%02x, got %v", test.input, test.upidType, upid)
+			t.Fail()
+		}
+	}
+}
+
+func TestUpidOfCues(t *testing.T) {
+	section, err := DecodeBase64(timeSignalPlacementOpportunityStart)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	d := section.SegmentationDescriptors[0]
+	upid, err := d.SegmentationUpids[0].Upid()
+	if nil != err || upid.Type != UpidTI || upid.String() != "000000002CA0A18A" || d.SegmentationTypeId != ProviderPlacementOpportunityStart {
+		t.Log("Unexpected UPID", upid, err)
+		t.Fail()
+	}
+
+	mid, _ := ParseUpid(UpidMID, "09055349473A310808000000002CA0A18A")
+	if parts := mid.Upids(); len(parts) != 2 || parts[0].String() != "SIG:1" || parts[1].Type != UpidTI {
+		t.Log("Unexpected MID parts", parts)
+		t.Fail()
+	}
+
+	var sp *scte224.SignalPoint
+	if err := xml.Unmarshal([]byte(`<SignalPoint xmlns="urn:scte:224:action" segmentationTypeId="52" segmentationUpidType="10" segmentationUpid="10.5240/7791-8534-2C23-9030-8610-5"/>`), &sp); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	section, err = NewTimeSignal(sp, nil)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	raw, err := Encode(section)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	decoded, err := Decode(raw)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if upid, err := decoded.SegmentationDescriptors[0].SegmentationUpids[0].Upid(); nil != err || upid.String() != sp.SegmentationUpid {
+		t.Log("Expected the EIDR to survive encoding, got", upid, err)
+		t.Fail()
+	}
+
+	sp.SegmentationUpid = "10.5240/7791"
+	if _, err := NewTimeSignal(sp, nil); nil == err {
+		t.Log("Expected an error for an invalid EIDR")
+		t.Fail()
+	}
+}
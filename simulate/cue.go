@@ -0,0 +1,112 @@
+package simulate
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Comcast/scte224structs/internal/duration"
+	"github.com/Comcast/scte224structs/scte35"
+	"github.com/Comcast/scte224structs/types/xsd"
+	"github.com/Comcast/scte224structs/xpath"
+)
+
+// A Cue is an SCTE 35 cue of a recording, and the time it was received at.
+type Cue struct {
+	At time.Time `json:"at"`
+	// Line is the line of the recording the cue is on.
+	Line int `json:"line"`
+	// Raw is the cue as recorded.
+	Raw     string                    `json:"raw"`
+	Section *scte35.SpliceInfoSection `json:"section"`
+	doc     *xpath.Node
+}
+
+// document returns the XML document assertions are evaluated against.
+func (c *Cue) document() (*xpath.Node, error) {
+	if c.doc == nil {
+		doc, err := xpath.FromValue(c.Section)
+		if err != nil {
+			return nil, err
+		}
+		c.doc = doc
+	}
+	return c.doc, nil
+}
+
+// ReadCues reads a recording of cues: one cue per line, as a time followed by the
+// cue, blank lines and lines starting with # being ignored.
+//
+// The time is an xs:dateTime, or an xs:duration after start such as PT90S. The
+// cue is a base64 or 0x-prefixed hexadecimal splice_info_section, or its XML
+// representation on one line. The cues are returned in the order of their times,
+// cues received together in the order they were recorded.
+func ReadCues(r io.Reader, start time.Time) ([]*Cue, error) {
+	var cues []*Cue
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		cue, err := parseCue(text, start)
+		if err != nil {
+			return nil, fmt.Errorf("simulate: line %d: %v", line, err)
+		}
+		cue.Line = line
+		cues = append(cues, cue)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(cues, func(i, j int) bool {
+		return cues[i].At.Before(cues[j].At)
+	})
+	return cues, nil
+}
+
+func parseCue(text string, start time.Time) (*Cue, error) {
+	fields := strings.Fields(text)
+	if len(fields) < 2 {
+		return nil, errors.New("expected a time and a cue")
+	}
+	cue := &Cue{Raw: strings.TrimSpace(strings.TrimPrefix(text, fields[0]))}
+
+	if strings.HasPrefix(fields[0], "P") {
+		offset, err := duration.Parse(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid offset: %v", err)
+		}
+		if start.IsZero() {
+			return nil, fmt.Errorf("offset %s without a start time", fields[0])
+		}
+		cue.At = start.Add(offset)
+	} else {
+		at, err := xsd.ParseDateTime(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		cue.At = at.Time()
+	}
+
+	var err error
+	switch {
+	case strings.HasPrefix(cue.Raw, "<"):
+		cue.Section = &scte35.SpliceInfoSection{}
+		err = xml.Unmarshal([]byte(cue.Raw), cue.Section)
+	case strings.HasPrefix(cue.Raw, "0x") || strings.HasPrefix(cue.Raw, "0X"):
+		cue.Section, err = scte35.DecodeHex(cue.Raw)
+	default:
+		cue.Section, err = scte35.DecodeBase64(cue.Raw)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cue, nil
+}
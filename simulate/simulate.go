@@ -0,0 +1,454 @@
+// Package simulate replays a recording of SCTE 35 cues against a Media on a
+// virtual clock, and reports the MediaPoints they match, the policies applied and
+// removed, and the actions in force for each audience as a result:
+//
+//	cues, err := simulate.ReadCues(recording, start)
+//	events, err := simulate.Run(&media, cues, simulate.Clock{Start: start})
+//	for _, e := range events {
+//		fmt.Println(e)
+//	}
+//
+// The clock does not wait: it jumps from one cue, matchTime or end of an Apply
+// duration to the next. A MediaPoint with a MatchSignal is matched by the cues
+// received within its effective and expires window for which its assertions hold,
+// as required by its match attribute, ALL when absent; a match repeated within
+// the signalTolerance of the previous one is ignored. A MediaPoint without a
+// MatchSignal occurs at its matchTime, shifted by its matchOffset.
+//
+// The Removes of a MediaPoint are processed before its Applys, and MediaPoints
+// matched together are processed by order, as timeline.Calculate does.
+package simulate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Comcast/scte224structs/scte35"
+	"github.com/Comcast/scte224structs/timeline"
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+// A Clock is the virtual time of a simulation. A zero Start starts it at the
+// first cue or matchTime; a zero Stop runs it until nothing is left to happen.
+type Clock struct {
+	Start, Stop time.Time
+}
+
+// contains reports whether t is on the clock.
+func (c Clock) contains(t time.Time) bool {
+	return !t.Before(c.Start) && (c.Stop.IsZero() || !t.After(c.Stop))
+}
+
+// Kind is the kind of an Event.
+type Kind string
+
+const (
+	// CueKind is a cue received, whether it matches a MediaPoint or not.
+	CueKind Kind = "cue"
+	// MatchKind is a MediaPoint matched by a cue, or occurring at its matchTime.
+	MatchKind Kind = "match"
+	// ApplyKind is a policy applied, or applied again.
+	ApplyKind Kind = "apply"
+	// RemoveKind is a policy in force removed.
+	RemoveKind Kind = "remove"
+	// ExpireKind is a policy reaching the end of the duration it was applied for.
+	ExpireKind Kind = "expire"
+	// ActionsKind is a change of the actions in force for an audience.
+	ActionsKind Kind = "actions"
+)
+
+// An Event is something happening during a simulation.
+type Event struct {
+	At   time.Time `json:"at"`
+	Kind Kind      `json:"kind"`
+	// Cue is the cue the event follows from, nil for events on a matchTime or the
+	// end of a duration.
+	Cue        *Cue   `json:"cue,omitempty"`
+	MediaPoint string `json:"mediaPoint,omitempty"`
+	// Policy is the id of the policy applied, removed or expired, or its href when
+	// it has no id.
+	Policy string `json:"policy,omitempty"`
+	// Audience is the key of the audience of an ActionsKind event, as given by
+	// timeline.AudienceKey; it is empty for the actions applying to every viewer.
+	Audience string `json:"audience,omitempty"`
+	// Actions are those in force for Audience, sorted as in a timeline.Interval;
+	// none when the last were removed.
+	Actions []*timeline.Action `json:"actions,omitempty"`
+}
+
+func (e *Event) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s", e.At.Format(time.RFC3339Nano), e.Kind)
+	switch e.Kind {
+	case CueKind:
+		fmt.Fprintf(&b, " line %d", e.Cue.Line)
+	case MatchKind:
+		fmt.Fprintf(&b, " MediaPoint %s", e.MediaPoint)
+		if e.Cue != nil {
+			fmt.Fprintf(&b, " by cue on line %d", e.Cue.Line)
+		}
+	case ApplyKind, RemoveKind, ExpireKind:
+		fmt.Fprintf(&b, " Policy %s", e.Policy)
+		if e.MediaPoint != "" {
+			fmt.Fprintf(&b, " by MediaPoint %s", e.MediaPoint)
+		}
+	case ActionsKind:
+		if e.Audience == "" {
+			b.WriteString(" (every viewer)")
+		} else {
+			fmt.Fprintf(&b, " %s", e.Audience)
+		}
+		if len(e.Actions) == 0 {
+			b.WriteString(" none")
+		}
+		for i, action := range e.Actions {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(&b, " %s/%s", action.Policy, viewingPolicyKey(action.ViewingPolicy))
+			if c := action.ViewingPolicy.Content; c != nil {
+				fmt.Fprintf(&b, " (content %s)", c.Content)
+			}
+		}
+	}
+	return b.String()
+}
+
+func viewingPolicyKey(vp *scte224.ViewingPolicy) string {
+	if vp.Id != "" {
+		return vp.Id
+	}
+	return vp.XLinkHRef
+}
+
+// An Option changes how a simulation runs.
+type Option func(*simulation)
+
+// WithPolicyResolver resolves the policies Applys refer to by xlink:href without
+// defining them; the Applys of policies that cannot be resolved still show as
+// events, but bring no actions.
+func WithPolicyResolver(resolve timeline.PolicyResolver) Option {
+	return func(s *simulation) {
+		s.resolve = resolve
+	}
+}
+
+// point is a MediaPoint with its compiled assertions.
+type point struct {
+	mp      *scte224.MediaPoint
+	matcher *scte35.Matcher
+	matched *time.Time // last matched by a cue
+}
+
+// application is a policy in force.
+type application struct {
+	key        string
+	mediaPoint string
+	priority   uint
+	applied    time.Time
+	end        *time.Time
+	policy     *scte224.Policy // resolved, nil if unknown
+}
+
+type simulation struct {
+	media   *scte224.Media
+	resolve timeline.PolicyResolver
+	points  []*point
+	active  map[string]*application
+	// audiences are the keys of the audiences actions were in force for, in the
+	// order they first were; actions are those last reported for each
+	audiences []string
+	actions   map[string][]*timeline.Action
+	events    []*Event
+}
+
+// Run replays cues against media on clock, and returns what happened in order.
+// Cues off the clock, or outside of the effective and expires window of media,
+// are ignored.
+func Run(media *scte224.Media, cues []*Cue, clock Clock, opts ...Option) ([]*Event, error) {
+	s := &simulation{
+		media:   media,
+		active:  make(map[string]*application),
+		actions: make(map[string][]*timeline.Action),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	for _, mp := range media.MediaPoints {
+		if mp == nil {
+			continue
+		}
+		p := &point{mp: mp}
+		if mp.MatchSignal != nil {
+			matcher, err := scte35.NewMatcher(mp.MatchSignal)
+			if err != nil {
+				return nil, fmt.Errorf("simulate: MediaPoint %s: %v", mp.Id, err)
+			}
+			p.matcher = matcher
+		}
+		s.points = append(s.points, p)
+	}
+
+	if clock.Start.IsZero() {
+		for _, t := range s.times(cues) {
+			if clock.Start.IsZero() || t.Before(clock.Start) {
+				clock.Start = t
+			}
+		}
+	}
+
+	// the clock jumps from one instant to the next, until nothing is left
+	for now, ok := s.next(cues, clock.Start, true); ok && clock.contains(now); now, ok = s.next(cues, now, false) {
+		if err := s.step(cues, now); err != nil {
+			return nil, err
+		}
+	}
+	return s.events, nil
+}
+
+// times returns the times of cues and of the MediaPoints occurring at their
+// matchTime.
+func (s *simulation) times(cues []*Cue) []time.Time {
+	var times []time.Time
+	for _, cue := range cues {
+		times = append(times, cue.At)
+	}
+	for _, p := range s.points {
+		if at, ok := s.scheduled(p); ok {
+			times = append(times, at)
+		}
+	}
+	return times
+}
+
+// scheduled returns the time p occurs at, if it is not matched by signal.
+func (s *simulation) scheduled(p *point) (time.Time, bool) {
+	if p.mp.MatchSignal != nil {
+		return time.Time{}, false
+	}
+	return timeline.MatchTime(p.mp)
+}
+
+// next returns the first instant after t, or at t when inclusive, at which
+// something happens.
+func (s *simulation) next(cues []*Cue, t time.Time, inclusive bool) (time.Time, bool) {
+	var next time.Time
+	found := false
+	consider := func(at time.Time) {
+		if (at.After(t) || (inclusive && at.Equal(t))) && (!found || at.Before(next)) {
+			next, found = at, true
+		}
+	}
+	for _, at := range s.times(cues) {
+		consider(at)
+	}
+	for _, a := range s.active {
+		if a.end != nil {
+			consider(*a.end)
+		}
+	}
+	return next, found
+}
+
+// inWindow reports whether t is within the effective and expires window of mp
+// and of the media.
+func (s *simulation) inWindow(mp *scte224.MediaPoint, t time.Time) bool {
+	if s.media.Effective != nil && !s.media.Effective.IsZero() && t.Before(s.media.Effective.Time()) {
+		return false
+	}
+	if s.media.Expires != nil && !s.media.Expires.IsZero() && !t.Before(s.media.Expires.Time()) {
+		return false
+	}
+	if mp == nil {
+		return true
+	}
+	if mp.Effective != nil && !mp.Effective.IsZero() && t.Before(mp.Effective.Time()) {
+		return false
+	}
+	return mp.Expires == nil || mp.Expires.IsZero() || t.Before(mp.Expires.Time())
+}
+
+type match struct {
+	p   *point
+	cue *Cue
+}
+
+// step processes everything happening at now.
+func (s *simulation) step(cues []*Cue, now time.Time) error {
+	var keys []string
+	for key, a := range s.active {
+		if a.end != nil && !a.end.After(now) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		a := s.active[key]
+		s.events = append(s.events, &Event{At: now, Kind: ExpireKind, MediaPoint: a.mediaPoint, Policy: key})
+		delete(s.active, key)
+	}
+
+	var matches []match
+	for _, p := range s.points {
+		if at, ok := s.scheduled(p); ok && at.Equal(now) && s.inWindow(p.mp, now) {
+			matches = append(matches, match{p: p})
+		}
+	}
+	for _, cue := range cues {
+		if !cue.At.Equal(now) || !s.inWindow(nil, now) {
+			continue
+		}
+		s.events = append(s.events, &Event{At: now, Kind: CueKind, Cue: cue})
+		for _, p := range s.points {
+			if p.mp.MatchSignal == nil || !s.inWindow(p.mp, now) {
+				continue
+			}
+			ok, err := p.matches(cue)
+			if err != nil {
+				return fmt.Errorf("simulate: line %d: %v", cue.Line, err)
+			}
+			if !ok {
+				continue
+			}
+			tolerance := p.mp.MatchSignal.SignalTolerance.GoDuration()
+			if p.matched != nil && now.Sub(*p.matched) < tolerance {
+				continue
+			}
+			matched := now
+			p.matched = &matched
+			matches = append(matches, match{p, cue})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].p.mp.GetOrder() < matches[j].p.mp.GetOrder()
+	})
+
+	for _, m := range matches {
+		mp := m.p.mp
+		s.events = append(s.events, &Event{At: now, Kind: MatchKind, Cue: m.cue, MediaPoint: mp.Id})
+		for _, remove := range mp.Removes {
+			if remove == nil {
+				continue
+			}
+			key := timeline.PolicyKey(remove.Policy)
+			if s.active[key] == nil {
+				continue
+			}
+			delete(s.active, key)
+			s.events = append(s.events, &Event{At: now, Kind: RemoveKind, Cue: m.cue, MediaPoint: mp.Id, Policy: key})
+		}
+		for _, apply := range mp.Applys {
+			if apply == nil || apply.Policy == nil {
+				continue
+			}
+			a := &application{
+				key:        timeline.PolicyKey(apply.Policy),
+				mediaPoint: mp.Id,
+				priority:   apply.GetPriority(),
+				applied:    now,
+			}
+			if duration := apply.Duration.GoDuration(); duration > 0 {
+				end := now.Add(duration)
+				a.end = &end
+			}
+			policy, err := s.resolvePolicy(apply.Policy)
+			if err != nil {
+				return err
+			}
+			a.policy = policy
+			s.active[a.key] = a
+			s.events = append(s.events, &Event{At: now, Kind: ApplyKind, Cue: m.cue, MediaPoint: mp.Id, Policy: a.key})
+		}
+	}
+	return s.report(now)
+}
+
+// matches reports whether the MatchSignal of p matches cue.
+func (p *point) matches(cue *Cue) (bool, error) {
+	doc, err := cue.document()
+	if err != nil {
+		return false, err
+	}
+	return p.matcher.MatchesNode(doc), nil
+}
+
+// resolvePolicy returns p, or the policy it refers to when it defines no viewing
+// policy; nil if there is none.
+func (s *simulation) resolvePolicy(p *scte224.Policy) (*scte224.Policy, error) {
+	if len(p.ViewingPolicys) > 0 || p.XLinkHRef == "" {
+		return p, nil
+	}
+	if s.resolve == nil {
+		return nil, nil
+	}
+	resolved, err := s.resolve(p.XLinkHRef)
+	if err != nil {
+		return nil, fmt.Errorf("simulate: resolving policy %s: %v", p.XLinkHRef, err)
+	}
+	return resolved, nil
+}
+
+// report adds an ActionsKind event for each audience whose actions changed.
+func (s *simulation) report(now time.Time) error {
+	actions := make(map[string][]*timeline.Action)
+	var keys []string
+	for key := range s.active {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		a := s.active[key]
+		if a.policy == nil {
+			continue
+		}
+		for _, vp := range a.policy.ViewingPolicys {
+			if vp == nil {
+				continue
+			}
+			audience, err := timeline.AudienceKey(vp.Audience)
+			if err != nil {
+				return err
+			}
+			if _, ok := s.actions[audience]; !ok {
+				s.actions[audience] = nil
+				s.audiences = append(s.audiences, audience)
+			}
+			actions[audience] = append(actions[audience], &timeline.Action{
+				Policy:        a.key,
+				MediaPoint:    a.mediaPoint,
+				Priority:      a.priority,
+				Applied:       a.applied,
+				ViewingPolicy: vp,
+			})
+		}
+	}
+	for _, audience := range s.audiences {
+		list := actions[audience]
+		sort.SliceStable(list, func(i, j int) bool {
+			if list[i].Priority != list[j].Priority {
+				return list[i].Priority < list[j].Priority
+			}
+			return list[i].Applied.After(list[j].Applied)
+		})
+		if sameActions(s.actions[audience], list) {
+			continue
+		}
+		s.actions[audience] = list
+		s.events = append(s.events, &Event{At: now, Kind: ActionsKind, Audience: audience, Actions: list})
+	}
+	return nil
+}
+
+func sameActions(a, b []*timeline.Action) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Policy != b[i].Policy || a[i].ViewingPolicy != b[i].ViewingPolicy || !a[i].Applied.Equal(b[i].Applied) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,169 @@
+package simulate
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+const media = `<Media xmlns="http://www.scte.org/schemas/224" xmlns:action="urn:scte:224:action" xmlns:xlink="http://www.w3.org/1999/xlink" id="m" effective="2021-07-26T09:00:00Z">
+	<MediaPoint id="start" order="1">
+		<MatchSignal match="ANY" signalTolerance="PT10S">
+			<Assert>/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=52]</Assert>
+			<Assert>/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=16]</Assert>
+		</MatchSignal>
+		<Apply duration="PT1M">
+			<Policy id="blackout">
+				<ViewingPolicy id="slate">
+					<Audience id="zip-a"/>
+					<action:Content>slate</action:Content>
+				</ViewingPolicy>
+			</Policy>
+		</Apply>
+	</MediaPoint>
+	<MediaPoint id="end" order="2">
+		<MatchSignal>
+			<Assert>/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=17]</Assert>
+		</MatchSignal>
+		<Remove><Policy xlink:href="blackout"/></Remove>
+	</MediaPoint>
+	<MediaPoint id="scheduled" matchTime="2021-07-26T09:00:00Z" matchOffset="PT5M">
+		<Apply><Policy xlink:href="reusable"/></Apply>
+	</MediaPoint>
+</Media>`
+
+// recording has a placement opportunity start, its repetition within tolerance,
+// and a program end followed by a program start in one cue.
+const recording = `# recorded cues
+PT0S /DA0AAAAAAAA///wBQb+cr0AUAAeAhxDVUVJSAAAjn/PAAGlmbAICAAAAAAsoKGKNAIAmsnRfg==
+PT5S /DA0AAAAAAAA///wBQb+cr0AUAAeAhxDVUVJSAAAjn/PAAGlmbAICAAAAAAsoKGKNAIAmsnRfg==
+
+2021-07-26T09:00:30Z /DBIAAAAAAAA///wBQb+ek2ItgAyAhdDVUVJSAAAGH+fCAgAAAAALMvDRBEAAAIXQ1VFSUgAABl/nwgIAAAAACyk26AQAACZcuND
+PT20M <SpliceInfoSection xmlns="http://www.scte.org/schemas/35"><SpliceNull/></SpliceInfoSection>
+`
+
+var start = time.Date(2021, 7, 26, 9, 0, 0, 0, time.UTC)
+
+func decode(t *testing.T) *scte224.Media {
+	m := &scte224.Media{}
+	if err := xml.Unmarshal([]byte(media), m); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	return m
+}
+
+func TestReadCues(t *testing.T) {
+	cues, err := ReadCues(strings.NewReader(recording), start)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if len(cues) != 4 {
+		t.Log("Expected 4 cues, got", len(cues))
+		t.FailNow()
+	}
+	if cues[2].Line != 5 || !cues[2].At.Equal(start.Add(30*time.Second)) || len(cues[2].Section.SegmentationDescriptors) != 2 {
+		t.Logf("Unexpected cue %+v", cues[2])
+		t.Fail()
+	}
+	if !cues[3].At.Equal(start.Add(20*time.Minute)) || cues[3].Section.SpliceNull == nil {
+		t.Logf("Unexpected XML cue %+v", cues[3])
+		t.Fail()
+	}
+
+	for _, bad := range []string{
+		"PT0S",
+		"PT5X /DA0AAAAAAAA///wBQb+cr0AUAAeAhxDVUVJSAAAjn/PAAGlmbAICAAAAAAsoKGKNAIAmsnRfg==",
+		"yesterday /DA0AAAAAAAA///wBQb+cr0AUAAeAhxDVUVJSAAAjn/PAAGlmbAICAAAAAAsoKGKNAIAmsnRfg==",
+		"PT0S 0xFC00",
+		"PT0S <SpliceInfoSection",
+	} {
+		if _, err := ReadCues(strings.NewReader(bad), start); nil == err {
+			t.Logf("Expected an error for %q", bad)
+			t.Fail()
+		}
+	}
+	// an offset without a time part is a whole day
+	if cues, err := ReadCues(strings.NewReader("P1D /DA0AAAAAAAA///wBQb+cr0AUAAeAhxDVUVJSAAAjn/PAAGlmbAICAAAAAAsoKGKNAIAmsnRfg=="), start); nil != err || !cues[0].At.Equal(start.Add(24*time.Hour)) {
+		t.Log("Unexpected day offset", cues, err)
+		t.Fail()
+	}
+	if _, err := ReadCues(strings.NewReader(recording), time.Time{}); nil == err {
+		t.Log("Expected an error for offsets without a start")
+		t.Fail()
+	}
+}
+
+func TestRun(t *testing.T) {
+	cues, err := ReadCues(strings.NewReader(recording), start)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	events, err := Run(decode(t), cues, Clock{})
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	var described []string
+	for _, e := range events {
+		described = append(described, e.String())
+	}
+	expected := []string{
+		"2021-07-26T09:00:00Z cue line 2",
+		"2021-07-26T09:00:00Z match MediaPoint start by cue on line 2",
+		"2021-07-26T09:00:00Z apply Policy blackout by MediaPoint start",
+		"2021-07-26T09:00:00Z actions zip-a blackout/slate (content slate)",
+		"2021-07-26T09:00:05Z cue line 3",
+		"2021-07-26T09:00:30Z cue line 5",
+		"2021-07-26T09:00:30Z match MediaPoint start by cue on line 5",
+		"2021-07-26T09:00:30Z apply Policy blackout by MediaPoint start",
+		"2021-07-26T09:00:30Z match MediaPoint end by cue on line 5",
+		"2021-07-26T09:00:30Z remove Policy blackout by MediaPoint end",
+		"2021-07-26T09:00:30Z actions zip-a none",
+		"2021-07-26T09:05:00Z match MediaPoint scheduled",
+		"2021-07-26T09:05:00Z apply Policy reusable by MediaPoint scheduled",
+		"2021-07-26T09:20:00Z cue line 6",
+	}
+	if !reflect.DeepEqual(expected, described) {
+		t.Logf("Expected\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(described, "\n"))
+		t.Fail()
+	}
+}
+
+func TestRunClock(t *testing.T) {
+	cues, err := ReadCues(strings.NewReader(recording), start)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	m := decode(t)
+	// without the end of the program, the blackout applied again expires; the
+	// first cue is before the clock starts
+	m.MediaPoints = m.MediaPoints[:1]
+	events, err := Run(m, cues, Clock{Start: start.Add(time.Second), Stop: start.Add(2 * time.Minute)})
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	var kinds []Kind
+	for _, e := range events {
+		kinds = append(kinds, e.Kind)
+	}
+	expected := []Kind{CueKind, MatchKind, ApplyKind, ActionsKind, CueKind, MatchKind, ApplyKind, ActionsKind, ExpireKind, ActionsKind}
+	if !reflect.DeepEqual(expected, kinds) || !events[8].At.Equal(start.Add(90*time.Second)) {
+		t.Log("Unexpected events", events)
+		t.Fail()
+	}
+
+	m.MediaPoints[0].MatchSignal.Assertions[0].Declaration = "/SpliceInfoSection["
+	if _, err := Run(m, cues, Clock{}); nil == err {
+		t.Log("Expected an error for an invalid assertion")
+		t.Fail()
+	}
+}
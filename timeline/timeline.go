@@ -0,0 +1,410 @@
+// Package timeline computes, for each audience of a Media, the intervals during
+// which its policies are in force, without running a live decision engine:
+//
+//	report, err := timeline.Calculate(&media, from, to)
+//	for _, tl := range report.Timelines {
+//		for _, interval := range tl.Intervals {
+//			fmt.Println(tl.Key, interval.Start, interval.End, interval.Content())
+//		}
+//	}
+//
+// MediaPoints are taken to occur at their matchTime, shifted by their matchOffset,
+// unless another Scheduler is given; points that occur outside of their effective
+// and expires window do nothing. At each point the Removes are processed before
+// the Applys, and points occurring together are processed by order.
+//
+// An Apply with a duration is in force for that duration, unless its policy is
+// removed or applied again earlier. An Apply without a duration lasts until its
+// policy is removed or applied again; when neither happens, until the
+// expectedDuration of its MediaPoint has passed, or else indefinitely.
+package timeline
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"time"
+
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+// A Scheduler returns the time a MediaPoint occurs at, or false when it cannot
+// tell, as for points matched by signal only.
+type Scheduler func(mp *scte224.MediaPoint) (time.Time, bool)
+
+// MatchTime schedules MediaPoints at their matchTime, shifted by their matchOffset.
+func MatchTime(mp *scte224.MediaPoint) (time.Time, bool) {
+	if mp.MatchTime == nil || mp.MatchTime.IsZero() {
+		return time.Time{}, false
+	}
+	return mp.MatchTime.Time().Add(mp.MatchOffset.GoDuration()), true
+}
+
+// MatchTimeOrEffective schedules MediaPoints as MatchTime does, and the others at
+// their effective time, as if the signals they match came as early as allowed.
+func MatchTimeOrEffective(mp *scte224.MediaPoint) (time.Time, bool) {
+	if t, ok := MatchTime(mp); ok {
+		return t, true
+	}
+	if mp.Effective == nil || mp.Effective.IsZero() {
+		return time.Time{}, false
+	}
+	return mp.Effective.Time(), true
+}
+
+// A PolicyResolver returns the policy a reference points to, or nil if it is
+// unknown.
+type PolicyResolver func(href string) (*scte224.Policy, error)
+
+// Policies returns a PolicyResolver for references to the given policies by id.
+func Policies(policies ...*scte224.Policy) PolicyResolver {
+	byID := make(map[string]*scte224.Policy, len(policies))
+	for _, p := range policies {
+		if p != nil && p.Id != "" {
+			byID[p.Id] = p
+		}
+	}
+	return func(href string) (*scte224.Policy, error) {
+		return byID[href], nil
+	}
+}
+
+type options struct {
+	schedule Scheduler
+	resolve  PolicyResolver
+}
+
+// An Option changes how a timeline is calculated.
+type Option func(*options)
+
+// WithScheduler sets the Scheduler giving the time of MediaPoints, MatchTime by default.
+func WithScheduler(schedule Scheduler) Option {
+	return func(o *options) {
+		o.schedule = schedule
+	}
+}
+
+// WithPolicyResolver resolves the policies Applys refer to by xlink:href without
+// defining them.
+func WithPolicyResolver(resolve PolicyResolver) Option {
+	return func(o *options) {
+		o.resolve = resolve
+	}
+}
+
+// An Action is a ViewingPolicy in force.
+type Action struct {
+	// Policy is the id of the applied policy, or its href when it has no id.
+	Policy string `json:"policy"`
+	// MediaPoint is the id of the MediaPoint that applied the policy.
+	MediaPoint    string                 `json:"mediaPoint,omitempty"`
+	Priority      uint                   `json:"priority"`
+	Applied       time.Time              `json:"applied"`
+	ViewingPolicy *scte224.ViewingPolicy `json:"viewingPolicy"`
+}
+
+// An Interval is a period during which the same actions are in force.
+type Interval struct {
+	Start time.Time `json:"start"`
+	// End is nil when the interval lasts beyond the end of the range calculated.
+	End *time.Time `json:"end,omitempty"`
+	// Actions are sorted by priority, the lowest value first, then by the time
+	// they were applied, the latest first.
+	Actions []*Action `json:"actions"`
+}
+
+// Content returns the content action of the first action having one, or nil.
+func (interval *Interval) Content() *scte224.ContentAction {
+	for _, action := range interval.Actions {
+		if action.ViewingPolicy.Content != nil {
+			return action.ViewingPolicy.Content
+		}
+	}
+	return nil
+}
+
+// A Timeline is the intervals during which actions are in force for an audience.
+type Timeline struct {
+	// Key identifies the audience: its id, else its href, else its XML. It is
+	// empty for viewing policies without an audience, which apply to every viewer.
+	Key       string            `json:"key"`
+	Audience  *scte224.Audience `json:"audience,omitempty"`
+	Intervals []*Interval       `json:"intervals"`
+}
+
+// A Report is the result of Calculate.
+type Report struct {
+	// Timelines are in the order their audiences first appear in the Media.
+	Timelines []*Timeline `json:"timelines"`
+	// Unscheduled lists the MediaPoints the Scheduler could not give a time for.
+	Unscheduled []*scte224.MediaPoint `json:"unscheduled,omitempty"`
+	// Unresolved lists the references to policies that could not be resolved.
+	Unresolved []string `json:"unresolved,omitempty"`
+}
+
+// application is a policy applied by a MediaPoint.
+type application struct {
+	key        string
+	policy     *scte224.Policy
+	mediaPoint *scte224.MediaPoint
+	priority   uint
+	start, end time.Time
+	bounded    bool // whether end is set
+}
+
+// close ends a in force at t, unless it ended already.
+func (a *application) close(t time.Time) {
+	if !a.bounded || a.end.After(t) {
+		a.end, a.bounded = t, true
+	}
+}
+
+// Calculate returns the timelines of the audiences of media between from and to.
+// A zero from or to leaves the range unbounded on that side; the range is further
+// restricted to the effective and expires window of media.
+func Calculate(media *scte224.Media, from, to time.Time, opts ...Option) (*Report, error) {
+	o := options{schedule: MatchTime}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	report := &Report{}
+
+	type event struct {
+		at time.Time
+		mp *scte224.MediaPoint
+	}
+	var events []event
+	for _, mp := range media.MediaPoints {
+		if mp == nil {
+			continue
+		}
+		at, ok := o.schedule(mp)
+		if !ok {
+			report.Unscheduled = append(report.Unscheduled, mp)
+			continue
+		}
+		if (mp.Effective != nil && !mp.Effective.IsZero() && at.Before(mp.Effective.Time())) ||
+			(mp.Expires != nil && !mp.Expires.IsZero() && !at.Before(mp.Expires.Time())) {
+			continue
+		}
+		events = append(events, event{at, mp})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].at.Equal(events[j].at) {
+			return events[i].at.Before(events[j].at)
+		}
+		return events[i].mp.GetOrder() < events[j].mp.GetOrder()
+	})
+
+	var applications []*application
+	active := make(map[string]*application)
+	for _, e := range events {
+		for _, remove := range e.mp.Removes {
+			if remove == nil {
+				continue
+			}
+			key := PolicyKey(remove.Policy)
+			if a := active[key]; a != nil {
+				a.close(e.at)
+				delete(active, key)
+			}
+		}
+		for _, apply := range e.mp.Applys {
+			if apply == nil || apply.Policy == nil {
+				continue
+			}
+			key := PolicyKey(apply.Policy)
+			if a := active[key]; a != nil {
+				a.close(e.at)
+			}
+			a := &application{key: key, policy: apply.Policy, mediaPoint: e.mp, priority: apply.GetPriority(), start: e.at}
+			if duration := apply.Duration.GoDuration(); duration > 0 {
+				a.end, a.bounded = e.at.Add(duration), true
+			}
+			applications = append(applications, a)
+			active[key] = a
+		}
+	}
+	for _, a := range applications {
+		if expected := a.mediaPoint.ExpectedDuration.GoDuration(); !a.bounded && expected > 0 {
+			a.end, a.bounded = a.start.Add(expected), true
+		}
+	}
+
+	if media.Effective != nil && !media.Effective.IsZero() && (from.IsZero() || from.Before(media.Effective.Time())) {
+		from = media.Effective.Time()
+	}
+	if media.Expires != nil && !media.Expires.IsZero() && (to.IsZero() || to.After(media.Expires.Time())) {
+		to = media.Expires.Time()
+	}
+
+	// the actions of each application, by audience
+	timelines := make(map[string]*Timeline)
+	effects := make(map[string][]effect)
+	unresolved := make(map[string]bool)
+	for _, a := range applications {
+		policy, err := o.resolvePolicy(a.policy)
+		if err != nil {
+			return nil, err
+		}
+		if policy == nil {
+			if !unresolved[a.key] {
+				unresolved[a.key] = true
+				report.Unresolved = append(report.Unresolved, a.key)
+			}
+			continue
+		}
+		for _, vp := range policy.ViewingPolicys {
+			if vp == nil {
+				continue
+			}
+			key, err := AudienceKey(vp.Audience)
+			if err != nil {
+				return nil, err
+			}
+			if timelines[key] == nil {
+				timelines[key] = &Timeline{Key: key, Audience: vp.Audience}
+				report.Timelines = append(report.Timelines, timelines[key])
+			}
+			effects[key] = append(effects[key], effect{a, &Action{
+				Policy:        a.key,
+				MediaPoint:    a.mediaPoint.Id,
+				Priority:      a.priority,
+				Applied:       a.start,
+				ViewingPolicy: vp,
+			}})
+		}
+	}
+	for _, tl := range report.Timelines {
+		tl.Intervals = intervals(effects[tl.Key], from, to)
+	}
+	return report, nil
+}
+
+// resolvePolicy returns p, or the policy it refers to when it defines no viewing
+// policy; nil if there is none.
+func (o *options) resolvePolicy(p *scte224.Policy) (*scte224.Policy, error) {
+	if len(p.ViewingPolicys) > 0 || p.XLinkHRef == "" {
+		return p, nil
+	}
+	if o.resolve == nil {
+		return nil, nil
+	}
+	resolved, err := o.resolve(p.XLinkHRef)
+	if err != nil {
+		return nil, fmt.Errorf("timeline: resolving policy %s: %v", p.XLinkHRef, err)
+	}
+	return resolved, nil
+}
+
+type effect struct {
+	*application
+	action *Action
+}
+
+// intervals splits the range from, to at every start and end of effects, and
+// returns the parts during which some effect is in force, merging neighbours in
+// which the same are.
+func intervals(effects []effect, from, to time.Time) []*Interval {
+	inRange := func(t time.Time) bool {
+		return (from.IsZero() || t.After(from)) && (to.IsZero() || t.Before(to))
+	}
+	var bounds []time.Time
+	for _, e := range effects {
+		if (!to.IsZero() && !e.start.Before(to)) || (e.bounded && !from.IsZero() && !e.end.After(from)) {
+			continue
+		}
+		if inRange(e.start) {
+			bounds = append(bounds, e.start)
+		} else {
+			bounds = append(bounds, from)
+		}
+		if e.bounded && inRange(e.end) {
+			bounds = append(bounds, e.end)
+		}
+	}
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i].Before(bounds[j]) })
+
+	var result []*Interval
+	var previous []effect
+	for i, start := range bounds {
+		if i > 0 && start.Equal(bounds[i-1]) {
+			continue
+		}
+		var inForce []effect
+		for _, e := range effects {
+			if !e.start.After(start) && (!e.bounded || e.end.After(start)) {
+				inForce = append(inForce, e)
+			}
+		}
+		if len(result) > 0 && sameEffects(previous, inForce) {
+			continue
+		}
+		if len(result) > 0 && result[len(result)-1].End == nil {
+			end := start
+			result[len(result)-1].End = &end
+		}
+		previous = inForce
+		if len(inForce) == 0 {
+			continue
+		}
+		sort.SliceStable(inForce, func(i, j int) bool {
+			if inForce[i].priority != inForce[j].priority {
+				return inForce[i].priority < inForce[j].priority
+			}
+			return inForce[i].start.After(inForce[j].start)
+		})
+		interval := &Interval{Start: start}
+		for _, e := range inForce {
+			interval.Actions = append(interval.Actions, e.action)
+		}
+		result = append(result, interval)
+	}
+	if len(result) > 0 && result[len(result)-1].End == nil && !to.IsZero() {
+		end := to
+		result[len(result)-1].End = &end
+	}
+	return result
+}
+
+func sameEffects(a, b []effect) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	actions := make(map[*Action]bool, len(a))
+	for _, e := range a {
+		actions[e.action] = true
+	}
+	for _, e := range b {
+		if !actions[e.action] {
+			return false
+		}
+	}
+	return true
+}
+
+// PolicyKey identifies a policy by its id, or by the href of a reference to it.
+func PolicyKey(p *scte224.Policy) string {
+	if p == nil {
+		return ""
+	}
+	if p.Id != "" {
+		return p.Id
+	}
+	return p.XLinkHRef
+}
+
+// AudienceKey identifies an audience by its id, else its href, else its XML; it is
+// empty for a nil audience, which stands for every viewer.
+func AudienceKey(aud *scte224.Audience) (string, error) {
+	switch {
+	case aud == nil:
+		return "", nil
+	case aud.Id != "":
+		return aud.Id, nil
+	case aud.XLinkHRef != "":
+		return aud.XLinkHRef, nil
+	}
+	raw, err := xml.Marshal(aud)
+	return string(raw), err
+}
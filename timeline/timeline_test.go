@@ -0,0 +1,214 @@
+package timeline
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	scte224 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+const schedule = `<Media xmlns="http://www.scte.org/schemas/224" xmlns:action="urn:scte:224:action" xmlns:xlink="http://www.w3.org/1999/xlink" id="m">
+	<MediaPoint id="start" matchTime="2021-07-26T10:00:00Z">
+		<Apply>
+			<Policy id="blackout">
+				<ViewingPolicy id="slate">
+					<Audience id="zip-a"/>
+					<action:Content>slate</action:Content>
+				</ViewingPolicy>
+			</Policy>
+		</Apply>
+		<Apply duration="PT30M" priority="1">
+			<Policy id="alternate">
+				<ViewingPolicy id="alt">
+					<Audience id="zip-a"/>
+					<action:Content>alternate</action:Content>
+				</ViewingPolicy>
+				<ViewingPolicy id="everyone">
+					<action:SignalPointDeletion>true</action:SignalPointDeletion>
+				</ViewingPolicy>
+			</Policy>
+		</Apply>
+	</MediaPoint>
+	<MediaPoint id="end" matchTime="2021-07-26T11:00:00Z" matchOffset="PT1H">
+		<Remove><Policy xlink:href="blackout"/></Remove>
+	</MediaPoint>
+	<MediaPoint id="signal">
+		<MatchSignal match="ALL"><Assert>/SpliceInfoSection</Assert></MatchSignal>
+	</MediaPoint>
+	<MediaPoint id="expected" matchTime="2021-07-26T13:00:00Z" expectedDuration="PT1H">
+		<Apply><Policy xlink:href="reusable"/></Apply>
+	</MediaPoint>
+	<MediaPoint id="expired" matchTime="2021-07-26T15:00:00Z" expires="2021-07-26T14:00:00Z">
+		<Apply><Policy xlink:href="reusable"/></Apply>
+	</MediaPoint>
+</Media>`
+
+func at(clock string) time.Time {
+	t, err := time.Parse(time.RFC3339, "2021-07-26T"+clock+"Z")
+	if nil != err {
+		panic(err)
+	}
+	return t
+}
+
+func decodeSchedule(t *testing.T) *scte224.Media {
+	media := &scte224.Media{}
+	if err := xml.Unmarshal([]byte(schedule), media); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	return media
+}
+
+// describe returns the start and end of each interval with the ids of the viewing
+// policies in force.
+func describe(intervals []*Interval) []string {
+	var described []string
+	for _, interval := range intervals {
+		line := interval.Start.Format("15:04") + "-"
+		if interval.End != nil {
+			line += interval.End.Format("15:04")
+		}
+		for _, action := range interval.Actions {
+			line += " " + action.ViewingPolicy.Id
+		}
+		described = append(described, line)
+	}
+	return described
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCalculate(t *testing.T) {
+	reusable := &scte224.Policy{
+		ReusableType:   scte224.ReusableType{IdentifiableType: scte224.IdentifiableType{Id: "reusable"}},
+		ViewingPolicys: []*scte224.ViewingPolicy{{ReusableType: scte224.ReusableType{IdentifiableType: scte224.IdentifiableType{Id: "late"}}, Audience: &scte224.Audience{Match: "ANY"}}},
+	}
+	report, err := Calculate(decodeSchedule(t), time.Time{}, time.Time{}, WithPolicyResolver(Policies(reusable)))
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if len(report.Timelines) != 3 {
+		t.Log("Expected 3 timelines, got", len(report.Timelines))
+		t.FailNow()
+	}
+
+	zipA := report.Timelines[0]
+	if zipA.Key != "zip-a" || !equal(describe(zipA.Intervals), []string{"10:00-10:30 slate alt", "10:30-12:00 slate"}) {
+		t.Log("Unexpected timeline", zipA.Key, describe(zipA.Intervals))
+		t.Fail()
+	}
+	if content := zipA.Intervals[0].Content(); content == nil || content.Content != "slate" {
+		t.Log("Unexpected content in force", content)
+		t.Fail()
+	}
+	if action := zipA.Intervals[0].Actions[1]; action.Policy != "alternate" || action.MediaPoint != "start" || action.Priority != 1 || !action.Applied.Equal(at("10:00:00")) {
+		t.Log("Unexpected action", action)
+		t.Fail()
+	}
+
+	everyone := report.Timelines[1]
+	if everyone.Key != "" || everyone.Audience != nil || !equal(describe(everyone.Intervals), []string{"10:00-10:30 everyone"}) {
+		t.Log("Unexpected timeline", everyone.Key, describe(everyone.Intervals))
+		t.Fail()
+	}
+
+	anonymous := report.Timelines[2]
+	if anonymous.Key != `<Audience xmlns="http://www.scte.org/schemas/224" match="ANY"></Audience>` || !equal(describe(anonymous.Intervals), []string{"13:00-14:00 late"}) {
+		t.Log("Unexpected timeline", anonymous.Key, describe(anonymous.Intervals))
+		t.Fail()
+	}
+
+	if len(report.Unscheduled) != 1 || report.Unscheduled[0].Id != "signal" || len(report.Unresolved) != 0 {
+		t.Log("Unexpected unscheduled points or unresolved policies", report.Unscheduled, report.Unresolved)
+		t.Fail()
+	}
+}
+
+func TestCalculateRange(t *testing.T) {
+	report, err := Calculate(decodeSchedule(t), at("10:15:00"), at("11:00:00"))
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if len(report.Timelines) != 2 || !equal(describe(report.Timelines[0].Intervals), []string{"10:15-10:30 slate alt", "10:30-11:00 slate"}) {
+		t.Log("Unexpected timelines", report.Timelines)
+		t.Fail()
+	}
+	if len(report.Unresolved) != 1 || report.Unresolved[0] != "reusable" {
+		t.Log("Expected the reusable policy to be unresolved", report.Unresolved)
+		t.Fail()
+	}
+
+	// without the Remove, the blackout lasts beyond the range
+	media := decodeSchedule(t)
+	media.MediaPoints[1].Removes = nil
+	report, _ = Calculate(media, at("11:00:00"), time.Time{})
+	if !equal(describe(report.Timelines[0].Intervals), []string{"11:00- slate"}) {
+		t.Log("Unexpected timeline", describe(report.Timelines[0].Intervals))
+		t.Fail()
+	}
+}
+
+func TestCalculateOrderAndReapply(t *testing.T) {
+	first, second := uint(1), uint(2)
+	blackout := func() *scte224.Policy {
+		return &scte224.Policy{
+			ReusableType:   scte224.ReusableType{IdentifiableType: scte224.IdentifiableType{Id: "blackout"}},
+			ViewingPolicys: []*scte224.ViewingPolicy{{ReusableType: scte224.ReusableType{IdentifiableType: scte224.IdentifiableType{Id: "vp"}}}},
+		}
+	}
+	matchTime := func(clock string) *scte224.MediaPoint {
+		mp := &scte224.MediaPoint{}
+		if err := xml.Unmarshal([]byte(`<MediaPoint xmlns="http://www.scte.org/schemas/224" matchTime="2021-07-26T`+clock+`Z"/>`), mp); nil != err {
+			panic(err)
+		}
+		mp.Id = clock
+		return mp
+	}
+
+	// the Remove, ordered after the Apply at the same time, ends the blackout at once
+	apply, remove := matchTime("10:00:00"), matchTime("10:00:00")
+	apply.Order, remove.Order = &first, &second
+	apply.Applys = []*scte224.Apply{{Policy: blackout()}}
+	remove.Removes = []*scte224.Remove{{Policy: blackout()}}
+	report, _ := Calculate(&scte224.Media{MediaPoints: []*scte224.MediaPoint{remove, apply}}, time.Time{}, time.Time{})
+	if len(report.Timelines) != 1 || len(report.Timelines[0].Intervals) != 0 {
+		t.Log("Expected no interval", describe(report.Timelines[0].Intervals))
+		t.Fail()
+	}
+
+	// applying the policy again restarts it, with a new duration
+	again := matchTime("10:20:00")
+	apply.Applys[0].Duration = "PT30M"
+	again.Applys = []*scte224.Apply{{Duration: "PT15M", Policy: blackout()}}
+	report, _ = Calculate(&scte224.Media{MediaPoints: []*scte224.MediaPoint{apply, again}}, time.Time{}, time.Time{})
+	intervals := report.Timelines[0].Intervals
+	if !equal(describe(intervals), []string{"10:00-10:20 vp", "10:20-10:35 vp"}) || intervals[1].Actions[0].MediaPoint != "10:20:00" {
+		t.Log("Unexpected timeline", describe(intervals))
+		t.Fail()
+	}
+
+	// signal points can be placed at their effective time
+	signal := matchTime("10:00:00")
+	signal.MatchTime = nil
+	signal.Effective = apply.MatchTime
+	signal.Applys = []*scte224.Apply{{Duration: "PT5M", Policy: blackout()}}
+	report, _ = Calculate(&scte224.Media{MediaPoints: []*scte224.MediaPoint{signal}}, time.Time{}, time.Time{}, WithScheduler(MatchTimeOrEffective))
+	if len(report.Unscheduled) != 0 || !equal(describe(report.Timelines[0].Intervals), []string{"10:00-10:05 vp"}) {
+		t.Log("Unexpected timeline", describe(report.Timelines[0].Intervals))
+		t.Fail()
+	}
+}
@@ -119,3 +119,39 @@ func TestRoundtrip(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestRawNodeNamespaces(t *testing.T) {
+	const other = `<Other xmlns="urn:example" xmlns:x="urn:example:x" x:a="1"><x:b></x:b></Other>`
+	raw := `<ViewingPolicy xmlns="http://www.scte.org/schemas/224/2015" id="vp">` + other + `</ViewingPolicy>`
+	var vp ViewingPolicy
+	if err := xml.Unmarshal([]byte(raw), &vp); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	attrs := vp.ActionProperty[0].Attributes
+	if 2 != len(attrs) || "xmlns:x" != attrs[0].Name.Local || "x:a" != attrs[1].Name.Local {
+		t.Log("Expected the declaration and prefixed attribute as written, got", attrs)
+		t.Fail()
+	}
+
+	marshaled, err := xml.Marshal(vp)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if !strings.Contains(string(marshaled), other) {
+		t.Log(string(marshaled))
+		t.Fail()
+	}
+
+	var again ViewingPolicy
+	if err := xml.Unmarshal(marshaled, &again); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	remarshaled, _ := xml.Marshal(again)
+	if string(marshaled) != string(remarshaled) {
+		t.Log("Encoding is not stable", string(remarshaled))
+		t.Fail()
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/Comcast/scte224structs/types/xsd"
 )
 
 const schemaLocation = "http://www.scte.org/schemas/224/SCTE224-20151115.xsd"
@@ -14,13 +16,13 @@ const schemaLocation = "http://www.scte.org/schemas/224/SCTE224-20151115.xsd"
 // Structs for SCTE 224 2015 ESNI Objects.
 // Table 3
 type IdentifiableType struct {
-	Id          string     `xml:"id,attr,omitempty"`
-	Description string     `xml:"description,attr,omitempty"`
-	LastUpdated *time.Time `xml:"lastUpdated,attr,omitempty"`
-	XMLBase     string     `xml:"xml:base,attr,omitempty"`
-	AltIDs      []*AltID   `xml:"http://www.scte.org/schemas/224/2015 AltID,omitempty"`
-	Metadata    *Metadata  `xml:"http://www.scte.org/schemas/224/2015 Metadata,omitempty"`
-	Ext         *Ext       `xml:"http://www.scte.org/schemas/224/2015 Ext,omitempty"`
+	Id          string        `xml:"id,attr,omitempty"`
+	Description string        `xml:"description,attr,omitempty"`
+	LastUpdated *xsd.DateTime `xml:"lastUpdated,attr,omitempty"`
+	XMLBase     string        `xml:"xml:base,attr,omitempty"`
+	AltIDs      []*AltID      `xml:"http://www.scte.org/schemas/224/2015 AltID,omitempty"`
+	Metadata    *Metadata     `xml:"http://www.scte.org/schemas/224/2015 Metadata,omitempty"`
+	Ext         *Ext          `xml:"http://www.scte.org/schemas/224/2015 Ext,omitempty"`
 }
 
 //Table 5
@@ -34,8 +36,8 @@ type ReusableType struct {
 type Media struct {
 	ReusableType
 	XMLName     xml.Name      `xml:"http://www.scte.org/schemas/224/2015 Media"`
-	Effective   *time.Time    `xml:"effective,attr,omitempty"`
-	Expires     *time.Time    `xml:"expires,attr,omitempty"`
+	Effective   *xsd.DateTime `xml:"effective,attr,omitempty"`
+	Expires     *xsd.DateTime `xml:"expires,attr,omitempty"`
 	Source      string        `xml:"source,attr,omitempty"`
 	MediaPoints []*MediaPoint `xml:"http://www.scte.org/schemas/224/2015 MediaPoint"`
 }
@@ -44,19 +46,19 @@ type Media struct {
 //Table 7
 type MediaPoint struct {
 	IdentifiableType
-	XMLName          xml.Name     `xml:"http://www.scte.org/schemas/224/2015 MediaPoint"`
-	Effective        *time.Time   `xml:"effective,attr,omitempty"`
-	Expires          *time.Time   `xml:"expires,attr,omitempty"`
-	MatchTime        *time.Time   `xml:"matchTime,attr,omitempty"`
-	MatchOffset      Duration     `xml:"matchOffset,attr,omitempty"`
-	Source           string       `xml:"source,attr,omitempty"`
-	ExpectedDuration Duration     `xml:"-"` // not in the 2015 XSD
-	Order            *uint        `xml:"-"` // used internally for ordering but not in the 2015 XSD
-	Reusable         bool         `xml:"-"` // not in the 2015 XSD
-	Removes          []*Remove    `xml:"http://www.scte.org/schemas/224/2015 Remove"`
-	Applys           []*Apply     `xml:"http://www.scte.org/schemas/224/2015 Apply"`
-	MatchSignal      *MatchSignal `xml:"http://www.scte.org/schemas/224/2015 MatchSignal"`
-	MediaGuid        string       `xml:"-"` // used internally to track which media this point is part of
+	XMLName          xml.Name      `xml:"http://www.scte.org/schemas/224/2015 MediaPoint"`
+	Effective        *xsd.DateTime `xml:"effective,attr,omitempty"`
+	Expires          *xsd.DateTime `xml:"expires,attr,omitempty"`
+	MatchTime        *xsd.DateTime `xml:"matchTime,attr,omitempty"`
+	MatchOffset      Duration      `xml:"matchOffset,attr,omitempty"`
+	Source           string        `xml:"source,attr,omitempty"`
+	ExpectedDuration Duration      `xml:"-"` // not in the 2015 XSD
+	Order            *uint         `xml:"-"` // used internally for ordering but not in the 2015 XSD
+	Reusable         bool          `xml:"-"` // not in the 2015 XSD
+	Removes          []*Remove     `xml:"http://www.scte.org/schemas/224/2015 Remove"`
+	Applys           []*Apply      `xml:"http://www.scte.org/schemas/224/2015 Apply"`
+	MatchSignal      *MatchSignal  `xml:"http://www.scte.org/schemas/224/2015 MatchSignal"`
+	MediaGuid        string        `xml:"-"` // used internally to track which media this point is part of
 }
 
 type Metadata struct {
@@ -81,6 +83,9 @@ type Any struct {
 	Namespace  NamespaceCleaner `xml:"xmlns,attr"`
 	Attributes []xml.Attr       `xml:",any,attr"`
 	Value      string           `xml:",innerxml" json:"value"`
+	// Typed holds the decoded value of a node whose name is registered with the
+	// extension package, and replaces Value when the node is encoded
+	Typed interface{} `xml:"-" json:"typed,omitempty"`
 }
 
 type Duration string
@@ -0,0 +1,103 @@
+// Package adi11 provides structs for ADI 1.1 content metadata, as carried in the
+// Metadata element of SCTE 224 objects by partners that have not moved to
+// SCTE 236 (ADI 3.0). adi30.Upgrade and adi30.Downgrade convert it to and from
+// ADI 3.0 assets.
+package adi11
+
+// The types below model ADI 1.1 metadata, which predates SCTE 236.
+// The CableLabs® Asset Distribution Interface Specification Version 1.1
+// (MD-SP-ADI1.1-C01-120803) provides a DTD to describe the file format.
+//
+// Within an ADI 1.1 file, App_Data and Content elements both have an attribute
+// named "Value". In these cases, the field is named ValueAttr to prevent duplication
+// since the encoding/xml package uses "Value" within the Attr struct to represent
+// the "value" in the attribute.
+
+import "encoding/xml"
+
+// Asset_Class values of the AMS elements adi30.Upgrade and adi30.Downgrade convert.
+const (
+	ClassPackage  = "package"
+	ClassTitle    = "title"
+	ClassMovie    = "movie"
+	ClassPreview  = "preview"
+	ClassPoster   = "poster"
+	ClassBoxCover = "box cover"
+)
+
+// IdentifierSystem names the ADI 1.1 Asset_ID among the AlternateIds of an ADI 3.0 asset.
+const IdentifierSystem = "VOD1.1"
+
+// ADI11 Top level element of an ADI 1.1 package. Its Metadata describes the
+// package, and its Assets are the titles it delivers.
+type ADI11 struct {
+	XMLName  xml.Name  `xml:"ADI"`
+	Metadata *Metadata `xml:"Metadata"`
+	Assets   []*Asset  `xml:"Asset,omitempty"`
+}
+
+// Asset elements nest: a title asset holds the movie, preview and poster assets
+// of the title, each of which names its file in a Content element.
+type Asset struct {
+	XMLName  xml.Name  `xml:"Asset"`
+	Metadata *Metadata `xml:"Metadata"`
+	Assets   []*Asset  `xml:"Asset,omitempty"`
+	Content  *Content  `xml:"Content,omitempty"`
+}
+
+// Metadata elements are containers for a single AMS element and
+// zero or more App_Data elements.
+type Metadata struct {
+	XMLName xml.Name   `xml:"Metadata"`
+	Ams     *AMS       `xml:"AMS"`
+	AppData []*AppData `xml:"App_Data,omitempty"`
+}
+
+// Values returns the values of every App_Data with the given name, in document order.
+func (m *Metadata) Values(name string) []string {
+	var values []string
+	for _, appData := range m.AppData {
+		if appData != nil && appData.Name == name {
+			values = append(values, appData.ValueAttr)
+		}
+	}
+	return values
+}
+
+// An AMS element typically has a class of package, title, movie or poster (or box-cover).
+// The CableLabs specification indicates other @Asset_Class values including
+// preview, trickfile, encrypted and barker.
+type AMS struct {
+	XMLName      xml.Name `xml:"AMS"`
+	Product      string   `xml:"Product,attr"`
+	AssetID      string   `xml:"Asset_ID,attr"`
+	ProviderID   string   `xml:"Provider_ID,attr"`
+	Provider     string   `xml:"Provider,attr"`
+	AssetClass   string   `xml:"Asset_Class,attr"`
+	AssetName    string   `xml:"Asset_Name,attr"`
+	Description  string   `xml:"Description,attr"`
+	Verb         string   `xml:"Verb,attr,omitempty"`
+	VersionMinor string   `xml:"Version_Minor,attr"`
+	VersionMajor string   `xml:"Version_Major,attr"`
+	CreationDate string   `xml:"Creation_Date,attr"`
+	//Value        string   `xml:",chardata"`
+}
+
+// AppData (App_Data) specifies additional metadata not included in the AMS element.
+// Access to the contents of the "Value" attribute is provided via ValueAttr.
+type AppData struct {
+	XMLName   xml.Name `xml:"App_Data"`
+	App       string   `xml:"App,attr,omitempty"`
+	Name      string   `xml:"Name,attr,omitempty"`
+	ValueAttr string   `xml:"Value,attr,omitempty"`
+	//Value     string   `xml:",chardata"`
+}
+
+// Content elements typically specify a file.
+// For example <Content  Value="/pid-fxnetworks.com-aid-DDDE0000103928336978.jpg"/>
+// Access to the "Value" attribute is provided via ValueAttr
+type Content struct {
+	XMLName   xml.Name `xml:"Content"`
+	ValueAttr string   `xml:"Value,attr"`
+	//Value     string   `xml:",chardata"`
+}
@@ -0,0 +1,742 @@
+package adi30
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Comcast/scte224structs/types/scte224v20180501/adi11"
+)
+
+// Metadata is the ADI 1.1 Metadata element, which this package held before the
+// adi11 package did.
+//
+// Deprecated: use adi11.Metadata.
+type Metadata = adi11.Metadata
+
+// AMS is the ADI 1.1 AMS element.
+//
+// Deprecated: use adi11.AMS.
+type AMS = adi11.AMS
+
+// AppData is the ADI 1.1 App_Data element.
+//
+// Deprecated: use adi11.AppData.
+type AppData = adi11.AppData
+
+// Content is the ADI 1.1 Content element.
+//
+// Deprecated: use adi11.Content.
+type Content = adi11.Content
+
+// newAssets maps the Asset_Class of the assets held by a title to their ADI 3.0 type.
+var newAssets = map[string]func() Asset{
+	adi11.ClassMovie:    func() Asset { return &Movie{} },
+	adi11.ClassPreview:  func() Asset { return &Preview{} },
+	adi11.ClassPoster:   func() Asset { return &Poster{} },
+	adi11.ClassBoxCover: func() Asset { return &BoxCover{} },
+}
+
+// Upgrade converts an ADI 1.1 package into ADI 3.0 assets. The package becomes an
+// offer, and each title asset becomes a title plus a content group referencing the
+// title and the movies, previews and posters it holds. App_Data without an ADI 3.0
+// counterpart is kept in the Ext of the asset it was found on; the AMS Verb is
+// dropped.
+func Upgrade(adi *adi11.ADI11) (*ADI30, error) {
+	if adi.Metadata == nil || adi.Metadata.Ams == nil {
+		return nil, errors.New("adi30: package has no AMS")
+	}
+
+	result := &ADI30{}
+	offer := &Offer{}
+	upgradeMetadata(offer, adi.Metadata, packageFields)
+	result.Assets = append(result.Assets, offer)
+	var terms *Terms
+
+	for _, titleAsset := range adi.Assets {
+		if titleAsset.Metadata == nil || titleAsset.Metadata.Ams == nil {
+			return nil, errors.New("adi30: title asset has no AMS")
+		}
+		if class := titleAsset.Metadata.Ams.AssetClass; class != adi11.ClassTitle {
+			return nil, fmt.Errorf("adi30: expected a %s asset in the package, found %q", adi11.ClassTitle, class)
+		}
+
+		title := &Title{}
+		offerMetadata, titleMetadata := splitOfferAppData(titleAsset.Metadata)
+		upgradeMetadata(title, titleMetadata, titleFields)
+		for _, appData := range offerMetadata.AppData {
+			switch {
+			case appData.Name == "Billing_ID" && (offer.BillingId == "" || offer.BillingId == appData.ValueAttr):
+				offer.BillingId = appData.ValueAttr
+			case appData.Name == "Suggested_Price" && terms == nil:
+				terms = &Terms{SuggestedPrice: appData.ValueAttr}
+				terms.UriId = uriId(offer.UriId, terms)
+				terms.Provider = offer.Provider
+				offer.TermsRef = &UriId{UriId: terms.UriId}
+			case appData.Name == "Suggested_Price" && terms.SuggestedPrice == appData.ValueAttr:
+			default:
+				appendExt(title, appData)
+			}
+		}
+
+		group := &ContentGroup{TitleRef: &UriId{UriId: title.UriId}}
+		group.UriId = uriId(title.UriId, group)
+		group.Provider = title.Provider
+		result.Assets = append(result.Assets, group, title)
+		offer.ContentGroupRefs = append(offer.ContentGroupRefs, &UriId{UriId: group.UriId})
+
+		for _, contentAsset := range titleAsset.Assets {
+			if contentAsset.Metadata == nil || contentAsset.Metadata.Ams == nil {
+				return nil, errors.New("adi30: content asset has no AMS")
+			}
+			class := contentAsset.Metadata.Ams.AssetClass
+			newAsset, ok := newAssets[class]
+			if !ok {
+				return nil, fmt.Errorf("adi30: no ADI 3.0 asset type for Asset_Class %q", class)
+			}
+			asset := newAsset()
+			upgradeMetadata(asset, contentAsset.Metadata, contentFields[class])
+			if contentAsset.Content != nil {
+				content(asset).SourceUrl = contentAsset.Content.ValueAttr
+			}
+			ref := &UriId{UriId: base(asset).UriId}
+			switch class {
+			case adi11.ClassMovie:
+				group.MovieRefs = append(group.MovieRefs, ref)
+			case adi11.ClassPreview:
+				group.PreviewRefs = append(group.PreviewRefs, ref)
+			case adi11.ClassPoster:
+				group.PosterRefs = append(group.PosterRefs, ref)
+			case adi11.ClassBoxCover:
+				group.BoxCoverRefs = append(group.BoxCoverRefs, ref)
+			}
+			result.Assets = append(result.Assets, asset)
+		}
+	}
+
+	if terms != nil {
+		result.Assets = append(result.Assets, terms)
+	}
+	return result, nil
+}
+
+// Downgrade converts ADI 3.0 assets into an ADI 1.1 package. Each content group
+// of the offer becomes a title asset holding the content the group references.
+// Without an offer, the package is described by the first title; without content
+// groups, the first title holds all the content of the block. Categories and
+// assets of unknown types have no ADI 1.1 counterpart and are dropped.
+func Downgrade(adi *ADI30) (*adi11.ADI11, error) {
+	byUriId := make(map[string]Asset)
+	var offer *Offer
+	var terms *Terms
+	var groups []*ContentGroup
+	var titles []*Title
+	var contents []Asset
+	for _, asset := range adi.Assets {
+		if b := base(asset); b != nil && b.UriId != "" {
+			byUriId[b.UriId] = asset
+		}
+		switch a := asset.(type) {
+		case *Offer:
+			if offer == nil {
+				offer = a
+			}
+		case *Terms:
+			if terms == nil {
+				terms = a
+			}
+		case *ContentGroup:
+			groups = append(groups, a)
+		case *Title:
+			titles = append(titles, a)
+		case *Movie, *Preview, *Poster, *BoxCover:
+			contents = append(contents, a)
+		}
+	}
+	if len(titles) == 0 {
+		return nil, errors.New("adi30: no title asset to downgrade")
+	}
+
+	if offer != nil {
+		if offer.TermsRef != nil {
+			if t, ok := byUriId[offer.TermsRef.UriId].(*Terms); ok {
+				terms = t
+			}
+		}
+		if len(offer.ContentGroupRefs) > 0 {
+			groups = nil
+			for _, ref := range offer.ContentGroupRefs {
+				group, ok := byUriId[ref.UriId].(*ContentGroup)
+				if !ok {
+					return nil, fmt.Errorf("adi30: offer references unknown content group %q", ref.UriId)
+				}
+				groups = append(groups, group)
+			}
+		}
+	}
+
+	result := &adi11.ADI11{}
+	if offer != nil {
+		result.Metadata = downgradeMetadata(offer, adi11.ClassPackage, packageFields)
+	} else {
+		result.Metadata = downgradeMetadata(titles[0], adi11.ClassPackage, nil)
+	}
+	product := result.Metadata.Ams.Product
+
+	downgradeTitle := func(title *Title, held []Asset) {
+		titleAsset := &adi11.Asset{Metadata: downgradeMetadata(title, adi11.ClassTitle, titleFields)}
+		if offer != nil && offer.BillingId != "" {
+			titleAsset.Metadata.AppData = append(titleAsset.Metadata.AppData, &adi11.AppData{Name: "Billing_ID", ValueAttr: offer.BillingId})
+		}
+		if terms != nil && terms.SuggestedPrice != "" {
+			titleAsset.Metadata.AppData = append(titleAsset.Metadata.AppData, &adi11.AppData{Name: "Suggested_Price", ValueAttr: terms.SuggestedPrice})
+		}
+		for _, asset := range held {
+			class := classOf(asset)
+			contentAsset := &adi11.Asset{Metadata: downgradeMetadata(asset, class, contentFields[class])}
+			if sourceUrl := content(asset).SourceUrl; sourceUrl != "" {
+				contentAsset.Content = &adi11.Content{ValueAttr: sourceUrl}
+			}
+			titleAsset.Assets = append(titleAsset.Assets, contentAsset)
+		}
+		result.Assets = append(result.Assets, titleAsset)
+	}
+
+	if len(groups) == 0 {
+		downgradeTitle(titles[0], contents)
+	}
+	for _, group := range groups {
+		if group.TitleRef == nil {
+			return nil, fmt.Errorf("adi30: content group %q has no title", group.UriId)
+		}
+		title, ok := byUriId[group.TitleRef.UriId].(*Title)
+		if !ok {
+			return nil, fmt.Errorf("adi30: content group %q references unknown title %q", group.UriId, group.TitleRef.UriId)
+		}
+		var held []Asset
+		for _, refs := range [][]*UriId{group.MovieRefs, group.PreviewRefs, group.PosterRefs, group.BoxCoverRefs} {
+			for _, ref := range refs {
+				asset, ok := byUriId[ref.UriId]
+				if !ok || classOf(asset) == "" {
+					return nil, fmt.Errorf("adi30: content group %q references unknown content %q", group.UriId, ref.UriId)
+				}
+				held = append(held, asset)
+			}
+		}
+		downgradeTitle(title, held)
+	}
+
+	for _, titleAsset := range result.Assets {
+		setApp(titleAsset.Metadata, product)
+		for _, contentAsset := range titleAsset.Assets {
+			setApp(contentAsset.Metadata, product)
+		}
+	}
+	setApp(result.Metadata, product)
+	return result, nil
+}
+
+// splitOfferAppData separates the App_Data of a title that ADI 3.0 keeps on the
+// offer and its terms from the App_Data of the title itself.
+func splitOfferAppData(metadata *adi11.Metadata) (offer, title *adi11.Metadata) {
+	offer = &adi11.Metadata{}
+	title = &adi11.Metadata{Ams: metadata.Ams}
+	for _, appData := range metadata.AppData {
+		if appData != nil && (appData.Name == "Billing_ID" || appData.Name == "Suggested_Price") {
+			offer.AppData = append(offer.AppData, appData)
+		} else {
+			title.AppData = append(title.AppData, appData)
+		}
+	}
+	return offer, title
+}
+
+func upgradeMetadata(asset Asset, metadata *adi11.Metadata, fields []appDataField) {
+	ams := metadata.Ams
+	b := base(asset)
+	b.UriId = uriId(ams.ProviderID+"/"+ams.AssetID, asset)
+	b.ProviderVersionNum = ams.VersionMajor
+	b.InternalVersionNum = ams.VersionMinor
+	b.CreationDateTime = ams.CreationDate
+	b.Product = ams.Product
+	b.Provider = ams.Provider
+	if ams.AssetID != "" {
+		b.AlternateIds = append(b.AlternateIds, &AlternateId{IdentifierSystem: adi11.IdentifierSystem, Value: ams.AssetID})
+	}
+	if ams.AssetName != "" {
+		b.AssetName = &DeprecatableValue{Value: ams.AssetName}
+	}
+	if ams.Description != "" {
+		b.Description = &DeprecatableValue{Value: ams.Description}
+	}
+
+	for _, appData := range metadata.AppData {
+		if appData == nil {
+			continue
+		}
+		if field := findField(appData.Name, fields); field == nil || !field.set(asset, appData.ValueAttr) {
+			appendExt(asset, appData)
+		}
+	}
+}
+
+func downgradeMetadata(asset Asset, class string, fields []appDataField) *adi11.Metadata {
+	b := base(asset)
+	ams := &adi11.AMS{
+		Product:      b.Product,
+		Provider:     b.Provider,
+		AssetClass:   class,
+		VersionMajor: b.ProviderVersionNum,
+		VersionMinor: b.InternalVersionNum,
+		CreationDate: b.CreationDateTime,
+	}
+	if i := strings.IndexByte(b.UriId, '/'); i >= 0 {
+		ams.ProviderID = b.UriId[:i]
+		ams.AssetID = b.UriId[strings.LastIndexByte(b.UriId, '/')+1:]
+	}
+	for _, alternateId := range b.AlternateIds {
+		if alternateId != nil && alternateId.IdentifierSystem == adi11.IdentifierSystem {
+			ams.AssetID = alternateId.Value
+			break
+		}
+	}
+	if b.AssetName != nil {
+		ams.AssetName = b.AssetName.Value
+	}
+	if b.Description != nil {
+		ams.Description = b.Description.Value
+	}
+
+	metadata := &adi11.Metadata{Ams: ams}
+	for _, field := range append(commonFields, fields...) {
+		for _, value := range field.get(asset) {
+			metadata.AppData = append(metadata.AppData, &adi11.AppData{Name: field.name, ValueAttr: value})
+		}
+	}
+	if b.Ext != nil {
+		for _, appData := range b.Ext.App_Data {
+			if appData != nil {
+				metadata.AppData = append(metadata.AppData, &adi11.AppData{Name: appData.Name, ValueAttr: appData.Value})
+			}
+		}
+	}
+	return metadata
+}
+
+// setApp fills in the App attribute of App_Data, which ADI 3.0 does not carry; it
+// conventionally matches the Product of the package.
+func setApp(metadata *adi11.Metadata, app string) {
+	for _, appData := range metadata.AppData {
+		if appData.App == "" {
+			appData.App = app
+		}
+	}
+}
+
+func appendExt(asset Asset, appData *adi11.AppData) {
+	b := base(asset)
+	if b.Ext == nil {
+		b.Ext = &Ext{}
+	}
+	b.Ext.App_Data = append(b.Ext.App_Data, &ExtAppData{Name: appData.Name, Value: appData.ValueAttr})
+}
+
+// uriId builds the uriId of an asset from the provider and id of another, as
+// "provider/<asset type>/id".
+func uriId(from string, asset Asset) string {
+	segment := strings.ToLower(strings.TrimSuffix(asset.XSIType()[strings.IndexByte(asset.XSIType(), ':')+1:], "Type"))
+	provider, id := from, ""
+	if i := strings.IndexByte(from, '/'); i >= 0 {
+		provider, id = from[:i], from[strings.LastIndexByte(from, '/')+1:]
+	}
+	return provider + "/" + segment + "/" + id
+}
+
+func classOf(asset Asset) string {
+	switch asset.(type) {
+	case *Movie:
+		return adi11.ClassMovie
+	case *Preview:
+		return adi11.ClassPreview
+	case *Poster:
+		return adi11.ClassPoster
+	case *BoxCover:
+		return adi11.ClassBoxCover
+	}
+	return ""
+}
+
+func base(asset Asset) *AssetType {
+	switch a := asset.(type) {
+	case *Offer:
+		return &a.AssetType
+	case *ContentGroup:
+		return &a.AssetType
+	case *Category:
+		return &a.AssetType
+	case *Terms:
+		return &a.AssetType
+	case *Title:
+		return &a.AssetType
+	case *Movie, *Preview, *Poster, *BoxCover:
+		return &content(a).AssetType
+	}
+	return nil
+}
+
+func content(asset Asset) *ContentType {
+	switch a := asset.(type) {
+	case *Movie:
+		return &a.ContentType
+	case *Preview:
+		return &a.ContentType
+	case *Poster:
+		return &a.ContentType
+	case *BoxCover:
+		return &a.ContentType
+	}
+	return nil
+}
+
+func video(asset Asset) *VideoType {
+	switch a := asset.(type) {
+	case *Movie:
+		return &a.VideoType
+	case *Preview:
+		return &a.VideoType
+	}
+	return nil
+}
+
+func image(asset Asset) *ImageType {
+	switch a := asset.(type) {
+	case *Poster:
+		return &a.ImageType
+	case *BoxCover:
+		return &a.ImageType
+	}
+	return nil
+}
+
+// localizable returns the first LocalizableTitle of a title, adding one when create
+// is set, and nil otherwise when the title has none.
+func localizable(asset Asset, create bool) *LocalizableTitle {
+	title := asset.(*Title)
+	if len(title.LocalizableTitles) == 0 {
+		if !create {
+			return nil
+		}
+		title.LocalizableTitles = append(title.LocalizableTitles, &LocalizableTitle{})
+	}
+	return title.LocalizableTitles[0]
+}
+
+// appDataField maps App_Data of one name onto an ADI 3.0 asset and back.
+type appDataField struct {
+	name string
+	// set applies one value, and reports false if the asset cannot hold it
+	set func(asset Asset, value string) bool
+	// get returns the values the asset holds, in document order
+	get func(asset Asset) []string
+}
+
+func findField(name string, fields []appDataField) *appDataField {
+	for _, list := range [][]appDataField{commonFields, fields} {
+		for i := range list {
+			if list[i].name == name {
+				return &list[i]
+			}
+		}
+	}
+	return nil
+}
+
+// stringField maps a single valued App_Data; locate returns nil when create is
+// false and the element holding the field does not exist.
+func stringField(name string, locate func(asset Asset, create bool) *string) appDataField {
+	return appDataField{
+		name: name,
+		set: func(asset Asset, value string) bool {
+			field := locate(asset, true)
+			if *field != "" && *field != value {
+				return false
+			}
+			*field = value
+			return true
+		},
+		get: func(asset Asset) []string {
+			if field := locate(asset, false); field != nil && *field != "" {
+				return []string{*field}
+			}
+			return nil
+		},
+	}
+}
+
+func listField(name string, locate func(asset Asset) *[]string) appDataField {
+	return appDataField{
+		name: name,
+		set: func(asset Asset, value string) bool {
+			field := locate(asset)
+			*field = append(*field, value)
+			return true
+		},
+		get: func(asset Asset) []string {
+			return *locate(asset)
+		},
+	}
+}
+
+// flagField maps an App_Data of Y or N onto an xs:boolean.
+func flagField(name string, locate func(asset Asset) *string) appDataField {
+	return appDataField{
+		name: name,
+		set: func(asset Asset, value string) bool {
+			field := locate(asset)
+			if *field != "" {
+				return false
+			}
+			switch value {
+			case "Y":
+				*field = "true"
+			case "N":
+				*field = "false"
+			default:
+				return false
+			}
+			return true
+		},
+		get: func(asset Asset) []string {
+			switch *locate(asset) {
+			case "true", "1":
+				return []string{"Y"}
+			case "false", "0":
+				return []string{"N"}
+			}
+			return nil
+		},
+	}
+}
+
+// personField maps App_Data naming a member of the cast or crew as "Last,First".
+func personField(name string, locate func(localizable *LocalizableTitle) *[]*Person) appDataField {
+	return appDataField{
+		name: name,
+		set: func(asset Asset, value string) bool {
+			person := &Person{SortableName: value, FullName: value}
+			if i := strings.IndexByte(value, ','); i >= 0 {
+				person.LastName = strings.TrimSpace(value[:i])
+				person.FirstName = strings.TrimSpace(value[i+1:])
+				person.FullName = strings.TrimSpace(person.FirstName + " " + person.LastName)
+			}
+			field := locate(localizable(asset, true))
+			*field = append(*field, person)
+			return true
+		},
+		get: func(asset Asset) []string {
+			l := localizable(asset, false)
+			if l == nil {
+				return nil
+			}
+			var values []string
+			for _, person := range *locate(l) {
+				switch {
+				case person == nil:
+				case person.SortableName != "":
+					values = append(values, person.SortableName)
+				case person.LastName != "" || person.FirstName != "":
+					values = append(values, person.LastName+","+person.FirstName)
+				case person.FullName != "":
+					values = append(values, person.FullName)
+				}
+			}
+			return values
+		},
+	}
+}
+
+func baseField(locate func(b *AssetType) *string) func(Asset, bool) *string {
+	return func(asset Asset, create bool) *string {
+		return locate(base(asset))
+	}
+}
+
+func localizableField(locate func(l *LocalizableTitle) *string) func(Asset, bool) *string {
+	return func(asset Asset, create bool) *string {
+		if l := localizable(asset, create); l != nil {
+			return locate(l)
+		}
+		return nil
+	}
+}
+
+func titleField(locate func(t *Title) *string) func(Asset, bool) *string {
+	return func(asset Asset, create bool) *string {
+		return locate(asset.(*Title))
+	}
+}
+
+func videoField(locate func(v *VideoType) *string) func(Asset, bool) *string {
+	return func(asset Asset, create bool) *string {
+		return locate(video(asset))
+	}
+}
+
+func contentField(locate func(c *ContentType) *string) func(Asset, bool) *string {
+	return func(asset Asset, create bool) *string {
+		return locate(content(asset))
+	}
+}
+
+// commonFields apply to assets of every class.
+var commonFields = []appDataField{
+	stringField("Provider_QA_Contact", baseField(func(b *AssetType) *string { return &b.ProviderQAContact })),
+	stringField("Licensing_Window_Start", baseField(func(b *AssetType) *string { return &b.StartDateTime })),
+	stringField("Licensing_Window_End", baseField(func(b *AssetType) *string { return &b.EndDateTime })),
+}
+
+var packageFields = []appDataField{
+	stringField("Provider_Content_Tier", func(asset Asset, create bool) *string {
+		return &asset.(*Offer).ProviderContentTier
+	}),
+	{
+		name: "Metadata_Spec_Version",
+		set: func(asset Asset, value string) bool {
+			offer := asset.(*Offer)
+			if offer.SourceMetadataSpecVersion != nil {
+				return false
+			}
+			offer.SourceMetadataSpecVersion = &DeprecatableValue{Value: value}
+			return true
+		},
+		get: func(asset Asset) []string {
+			if version := asset.(*Offer).SourceMetadataSpecVersion; version != nil {
+				return []string{version.Value}
+			}
+			return nil
+		},
+	},
+}
+
+var titleFields = []appDataField{
+	stringField("Title_Sort_Name", localizableField(func(l *LocalizableTitle) *string { return &l.TitleSortName })),
+	stringField("Title_Brief", localizableField(func(l *LocalizableTitle) *string { return &l.TitleBrief })),
+	stringField("Title", localizableField(func(l *LocalizableTitle) *string { return &l.TitleMedium })),
+	stringField("Episode_Name", localizableField(func(l *LocalizableTitle) *string { return &l.EpisodeName })),
+	stringField("Episode_ID", localizableField(func(l *LocalizableTitle) *string { return &l.EpisodeID })),
+	stringField("Summary_Long", localizableField(func(l *LocalizableTitle) *string { return &l.SummaryLong })),
+	stringField("Summary_Medium", localizableField(func(l *LocalizableTitle) *string { return &l.SummaryMedium })),
+	stringField("Summary_Short", localizableField(func(l *LocalizableTitle) *string { return &l.SummaryShort })),
+	stringField("Actors_Display", localizableField(func(l *LocalizableTitle) *string { return &l.ActorDisplay })),
+	personField("Actors", func(l *LocalizableTitle) *[]*Person { return &l.Actors }),
+	personField("Director", func(l *LocalizableTitle) *[]*Person { return &l.Directors }),
+	personField("Producers", func(l *LocalizableTitle) *[]*Person { return &l.Producers }),
+	personField("Writer", func(l *LocalizableTitle) *[]*Person { return &l.Writers }),
+	{
+		name: "Rating",
+		set: func(asset Asset, value string) bool {
+			title := asset.(*Title)
+			title.Ratings = append(title.Ratings, &Rating{Value: value})
+			return true
+		},
+		get: func(asset Asset) []string {
+			var values []string
+			for _, rating := range asset.(*Title).Ratings {
+				if rating != nil {
+					values = append(values, rating.Value)
+				}
+			}
+			return values
+		},
+	},
+	listField("Advisories", func(asset Asset) *[]string { return &asset.(*Title).Advisories }),
+	flagField("Closed_Captioning", func(asset Asset) *string { return &asset.(*Title).IsClosedCaptioning }),
+	flagField("Season_Premiere", func(asset Asset) *string { return &asset.(*Title).IsSeasonPremiere }),
+	flagField("Season_Finale", func(asset Asset) *string { return &asset.(*Title).IsSeasonFinale }),
+	stringField("Display_Run_Time", titleField(func(t *Title) *string { return &t.DisplayRunTime })),
+	{
+		name: "Year",
+		set: func(asset Asset, value string) bool {
+			title := asset.(*Title)
+			year, err := strconv.Atoi(value)
+			if err != nil || title.Year != 0 {
+				return false
+			}
+			title.Year = year
+			return true
+		},
+		get: func(asset Asset) []string {
+			if year := asset.(*Title).Year; year != 0 {
+				return []string{strconv.Itoa(year)}
+			}
+			return nil
+		},
+	},
+	listField("Country_of_Origin", func(asset Asset) *[]string { return &asset.(*Title).Countries }),
+	listField("Genre", func(asset Asset) *[]string { return &asset.(*Title).Genres }),
+	stringField("Show_Type", titleField(func(t *Title) *string { return &t.ShowType })),
+}
+
+var fileFields = []appDataField{
+	stringField("Content_FileSize", contentField(func(c *ContentType) *string { return &c.ContentFileSize })),
+	stringField("Content_CheckSum", contentField(func(c *ContentType) *string { return &c.ContentCheckSum })),
+}
+
+var videoFields = append([]appDataField{
+	listField("Audio_Type", func(asset Asset) *[]string { return &video(asset).AudioTypes }),
+	stringField("Screen_Format", videoField(func(v *VideoType) *string { return &v.ScreenFormat })),
+	stringField("Resolution", videoField(func(v *VideoType) *string { return &v.Resolution })),
+	stringField("Frame_Rate", videoField(func(v *VideoType) *string { return &v.FrameRate })),
+	stringField("Codec", videoField(func(v *VideoType) *string { return &v.Codec })),
+	stringField("Bit_Rate", videoField(func(v *VideoType) *string { return &v.BitRate })),
+	{
+		name: "Languages",
+		set: func(asset Asset, value string) bool {
+			v := video(asset)
+			v.Languages = append(v.Languages, &Language{Value: value})
+			return true
+		},
+		get: func(asset Asset) []string {
+			var values []string
+			for _, language := range video(asset).Languages {
+				if language != nil {
+					values = append(values, language.Value)
+				}
+			}
+			return values
+		},
+	},
+	listField("Subtitle_Languages", func(asset Asset) *[]string { return &video(asset).SubtitleLanguages }),
+	listField("Dubbed_Languages", func(asset Asset) *[]string { return &video(asset).DubbedLanguages }),
+}, fileFields...)
+
+var imageFields = append([]appDataField{
+	{
+		// Image_Aspect_Ratio holds the resolution of an image, as "1920x1080"
+		name: "Image_Aspect_Ratio",
+		set: func(asset Asset, value string) bool {
+			i := image(asset)
+			x := strings.IndexByte(value, 'x')
+			if x < 0 || i.X_Resolution != "" || i.Y_Resolution != "" {
+				return false
+			}
+			i.X_Resolution, i.Y_Resolution = value[:x], value[x+1:]
+			return true
+		},
+		get: func(asset Asset) []string {
+			if i := image(asset); i.X_Resolution != "" || i.Y_Resolution != "" {
+				return []string{i.X_Resolution + "x" + i.Y_Resolution}
+			}
+			return nil
+		},
+	},
+}, fileFields...)
+
+// contentFields holds the App_Data mapped for each Asset_Class held by a title.
+var contentFields = map[string][]appDataField{
+	adi11.ClassMovie:    videoFields,
+	adi11.ClassPreview:  videoFields,
+	adi11.ClassPoster:   imageFields,
+	adi11.ClassBoxCover: imageFields,
+}
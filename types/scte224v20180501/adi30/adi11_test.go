@@ -0,0 +1,230 @@
+package adi30
+
+import (
+	"encoding/xml"
+	"sort"
+	"testing"
+
+	"github.com/Comcast/scte224structs/types/scte224v20180501/adi11"
+)
+
+const packageRaw = `<ADI>
+  <Metadata>
+    <AMS Provider="FX" Product="MOD" Asset_Name="Example_Package" Version_Major="1" Version_Minor="0" Description="Example package" Creation_Date="2020-03-30" Provider_ID="fxnetworks.com" Asset_ID="PKGE0000000000000001" Asset_Class="package"/>
+    <App_Data App="MOD" Name="Provider_Content_Tier" Value="FX_1"/>
+    <App_Data App="MOD" Name="Metadata_Spec_Version" Value="CableLabsVOD1.1"/>
+  </Metadata>
+  <Asset>
+    <Metadata>
+      <AMS Provider="FX" Product="MOD" Asset_Name="Example_Title" Version_Major="1" Version_Minor="0" Description="Example title" Creation_Date="2020-03-30" Provider_ID="fxnetworks.com" Asset_ID="TITL0000000000000001" Asset_Class="title"/>
+      <App_Data App="MOD" Name="Type" Value="title"/>
+      <App_Data App="MOD" Name="Title_Brief" Value="Example"/>
+      <App_Data App="MOD" Name="Title" Value="The Example"/>
+      <App_Data App="MOD" Name="Summary_Short" Value="An example."/>
+      <App_Data App="MOD" Name="Rating" Value="TV-MA"/>
+      <App_Data App="MOD" Name="Closed_Captioning" Value="Y"/>
+      <App_Data App="MOD" Name="Year" Value="2020"/>
+      <App_Data App="MOD" Name="Country_of_Origin" Value="US"/>
+      <App_Data App="MOD" Name="Actors" Value="Doe,Jane"/>
+      <App_Data App="MOD" Name="Actors" Value="Roe,John"/>
+      <App_Data App="MOD" Name="Director" Value="Smith,Alan"/>
+      <App_Data App="MOD" Name="Genre" Value="Drama"/>
+      <App_Data App="MOD" Name="Genre" Value="Family"/>
+      <App_Data App="MOD" Name="Billing_ID" Value="00000"/>
+      <App_Data App="MOD" Name="Suggested_Price" Value="3.99"/>
+      <App_Data App="MOD" Name="Licensing_Window_Start" Value="2020-03-30"/>
+      <App_Data App="MOD" Name="Licensing_Window_End" Value="2020-04-30"/>
+      <App_Data App="MOD" Name="Maximum_Viewing_Length" Value="00:24:00"/>
+    </Metadata>
+    <Asset>
+      <Metadata>
+        <AMS Provider="FX" Product="MOD" Asset_Name="Example_Movie" Version_Major="1" Version_Minor="0" Description="Example movie" Creation_Date="2020-03-30" Provider_ID="fxnetworks.com" Asset_ID="MOVI0000000000000001" Asset_Class="movie"/>
+        <App_Data App="MOD" Name="Audio_Type" Value="Dolby 5.1"/>
+        <App_Data App="MOD" Name="Languages" Value="en"/>
+        <App_Data App="MOD" Name="Content_FileSize" Value="1048576"/>
+        <App_Data App="MOD" Name="Content_CheckSum" Value="d41d8cd98f00b204e9800998ecf8427e"/>
+      </Metadata>
+      <Content Value="movie.mpg"/>
+    </Asset>
+    <Asset>
+      <Metadata>
+        <AMS Provider="FX" Product="MOD" Asset_Name="Example_Poster" Version_Major="1" Version_Minor="0" Description="Example poster" Creation_Date="2020-03-30" Provider_ID="fxnetworks.com" Asset_ID="POST0000000000000001" Asset_Class="poster"/>
+        <App_Data App="MOD" Name="Image_Aspect_Ratio" Value="640x480"/>
+      </Metadata>
+      <Content Value="poster.jpg"/>
+    </Asset>
+  </Asset>
+</ADI>`
+
+func decodePackage(t *testing.T) *adi11.ADI11 {
+	var adi adi11.ADI11
+	if err := xml.Unmarshal([]byte(packageRaw), &adi); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	return &adi
+}
+
+func TestUpgrade(t *testing.T) {
+	adi30Assets, err := Upgrade(decodePackage(t))
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+
+	if 6 != len(adi30Assets.Assets) {
+		t.Log("Expected an offer, content group, title, movie, poster and terms, found", len(adi30Assets.Assets), "assets")
+		t.FailNow()
+	}
+	offer, isOffer := adi30Assets.Assets[0].(*Offer)
+	group, isGroup := adi30Assets.Assets[1].(*ContentGroup)
+	title, isTitle := adi30Assets.Assets[2].(*Title)
+	movie, isMovie := adi30Assets.Assets[3].(*Movie)
+	poster, isPoster := adi30Assets.Assets[4].(*Poster)
+	terms, isTerms := adi30Assets.Assets[5].(*Terms)
+	if !isOffer || !isGroup || !isTitle || !isMovie || !isPoster || !isTerms {
+		t.Log("Assets were not converted to the expected types")
+		t.FailNow()
+	}
+
+	if "fxnetworks.com/offer/PKGE0000000000000001" != offer.UriId || "FX_1" != offer.ProviderContentTier || "00000" != offer.BillingId {
+		t.Log("Offer not converted", offer)
+		t.Fail()
+	}
+	if terms.UriId != offer.TermsRef.UriId || "3.99" != terms.SuggestedPrice {
+		t.Log("Terms not converted", terms)
+		t.Fail()
+	}
+	if group.UriId != offer.ContentGroupRefs[0].UriId || title.UriId != group.TitleRef.UriId || movie.UriId != group.MovieRefs[0].UriId || poster.UriId != group.PosterRefs[0].UriId {
+		t.Log("Content group does not reference the assets", group)
+		t.Fail()
+	}
+	if "TITL0000000000000001" != title.AlternateIds[0].Value || adi11.IdentifierSystem != title.AlternateIds[0].IdentifierSystem || "2020-03-30" != title.StartDateTime {
+		t.Log("Title AMS not converted", title.AssetType)
+		t.Fail()
+	}
+	localized := title.LocalizableTitles[0]
+	if "The Example" != localized.TitleMedium || "Jane" != localized.Actors[0].FirstName || "Roe" != localized.Actors[1].LastName || "Smith,Alan" != localized.Directors[0].SortableName {
+		t.Log("Title not converted", localized)
+		t.Fail()
+	}
+	if 2020 != title.Year || "true" != title.IsClosedCaptioning || 2 != len(title.Genres) || "US" != title.Countries[0] {
+		t.Log("Title not converted", title)
+		t.Fail()
+	}
+	// App_Data without a counterpart is kept
+	if 2 != len(title.Ext.App_Data) || "Type" != title.Ext.App_Data[0].Name || "Maximum_Viewing_Length" != title.Ext.App_Data[1].Name {
+		t.Log("Unmapped App_Data not kept", title.Ext)
+		t.Fail()
+	}
+	if "movie.mpg" != movie.SourceUrl || "en" != movie.Languages[0].Value || "1048576" != movie.ContentFileSize {
+		t.Log("Movie not converted", movie)
+		t.Fail()
+	}
+	if "640" != poster.X_Resolution || "480" != poster.Y_Resolution {
+		t.Log("Poster not converted", poster)
+		t.Fail()
+	}
+}
+
+// sortAppData puts App_Data in name order, since a round trip groups them by the
+// field they map to.
+func sortAppData(adi *adi11.ADI11) {
+	var sortAsset func(metadata *adi11.Metadata, assets []*adi11.Asset)
+	sortAsset = func(metadata *adi11.Metadata, assets []*adi11.Asset) {
+		sort.SliceStable(metadata.AppData, func(i, j int) bool {
+			return metadata.AppData[i].Name < metadata.AppData[j].Name
+		})
+		for _, asset := range assets {
+			sortAsset(asset.Metadata, asset.Assets)
+		}
+	}
+	sortAsset(adi.Metadata, adi.Assets)
+}
+
+func TestRoundtrip(t *testing.T) {
+	original := decodePackage(t)
+	upgraded, err := Upgrade(original)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	// the ADI 3.0 form must survive marshaling
+	marshaled, err := xml.Marshal(upgraded)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	var decoded ADI30
+	if err := xml.Unmarshal(marshaled, &decoded); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	downgraded, err := Downgrade(&decoded)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+
+	sortAppData(original)
+	sortAppData(downgraded)
+	expected, _ := xml.MarshalIndent(original, "", "  ")
+	actual, _ := xml.MarshalIndent(downgraded, "", "  ")
+	if string(expected) != string(actual) {
+		t.Log(string(actual))
+		t.Log("did not match")
+		t.Log(string(expected))
+		t.Fail()
+	}
+}
+
+func TestDowngradeWithoutOffer(t *testing.T) {
+	adi := &ADI30{Assets: []Asset{
+		&Title{AssetType: AssetType{UriId: "hbo.com/title/HBO", Provider: "HBO"}, Year: 2020},
+		&Movie{},
+	}}
+	adi.Assets[1].(*Movie).SourceUrl = "movie.ts"
+
+	downgraded, err := Downgrade(adi)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if adi11.ClassPackage != downgraded.Metadata.Ams.AssetClass || "hbo.com" != downgraded.Metadata.Ams.ProviderID || "HBO" != downgraded.Metadata.Ams.AssetID {
+		t.Log("Package not described by the title", downgraded.Metadata.Ams)
+		t.Fail()
+	}
+	if 1 != len(downgraded.Assets) || 1 != len(downgraded.Assets[0].Assets) || "movie.ts" != downgraded.Assets[0].Assets[0].Content.ValueAttr {
+		t.Log("Title does not hold the movie")
+		t.Fail()
+	}
+	if year := downgraded.Assets[0].Metadata.Values("Year"); 1 != len(year) || "2020" != year[0] {
+		t.Log("Year not converted", year)
+		t.Fail()
+	}
+}
+
+func TestConversionErrors(t *testing.T) {
+	if _, err := Upgrade(&adi11.ADI11{}); nil == err {
+		t.Log("A package without an AMS should not convert")
+		t.Fail()
+	}
+	trickfile := decodePackage(t)
+	trickfile.Assets[0].Assets[0].Metadata.Ams.AssetClass = "trickfile"
+	if _, err := Upgrade(trickfile); nil == err {
+		t.Log("A trickfile asset has no ADI 3.0 counterpart")
+		t.Fail()
+	}
+	if _, err := Downgrade(&ADI30{Assets: []Asset{&Offer{}}}); nil == err {
+		t.Log("ADI 3.0 without a title should not convert")
+		t.Fail()
+	}
+	dangling := &ADI30{Assets: []Asset{
+		&Title{AssetType: AssetType{UriId: "p/title/1"}},
+		&ContentGroup{TitleRef: &UriId{UriId: "p/title/1"}, MovieRefs: []*UriId{{UriId: "p/movie/1"}}},
+	}}
+	if _, err := Downgrade(dangling); nil == err {
+		t.Log("A reference to a missing movie should not convert")
+		t.Fail()
+	}
+}
@@ -0,0 +1,211 @@
+package adi30
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const fullADI = `<core:ADI3 xmlns:core="http://www.scte.org/schemas/236/2017/core" xmlns:offer="http://www.scte.org/schemas/236/2017/offer" xmlns:t="http://www.scte.org/schemas/236/2017/title" xmlns:content="http://www.scte.org/schemas/236/2017/content" xmlns:terms="http://www.scte.org/schemas/236/2017/terms" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+  <core:Asset xsi:type="offer:OfferType" uriId="example.com/offer/1" providerVersionNum="2">
+    <core:AlternateId identifierSystem="VOD1.1">PAID0000000000000001</core:AlternateId>
+    <core:Provider>EXAMPLE</core:Provider>
+    <offer:Presentation><offer:CategoryRef uriId="example.com/category/movies"/><offer:DisplayAsNew>P7D</offer:DisplayAsNew></offer:Presentation>
+    <offer:BillingId>B001</offer:BillingId>
+    <offer:TermsRef uriId="example.com/terms/1"/>
+    <offer:ContentGroupRef uriId="example.com/group/1"/>
+  </core:Asset>
+  <core:Asset xsi:type="offer:ContentGroupType" uriId="example.com/group/1">
+    <offer:TitleRef uriId="example.com/title/1"/>
+    <offer:MovieRef uriId="example.com/movie/1"/>
+    <offer:PosterRef uriId="example.com/poster/1"/>
+  </core:Asset>
+  <core:Asset xsi:type="offer:CategoryType" uriId="example.com/category/movies">
+    <offer:CategoryPath>Movies/Drama</offer:CategoryPath>
+  </core:Asset>
+  <core:Asset xsi:type="terms:TermsType" uriId="example.com/terms/1">
+    <terms:BillingGracePeriod>PT5M</terms:BillingGracePeriod>
+    <terms:SuggestedPrice>3.99</terms:SuggestedPrice>
+    <terms:SubscriberViewLimit startDateTime="2020-01-01T00:00:00Z" endDateTime="2020-01-02T00:00:00Z" maximumViews="3"/>
+  </core:Asset>
+  <core:Asset xsi:type="t:TitleType" uriId="example.com/title/1">
+    <core:Description deprecated="true">old</core:Description>
+    <t:LocalizableTitle xml:lang="en">
+      <t:TitleBrief>Example</t:TitleBrief>
+      <t:Actor fullName="Jane Doe" firstName="Jane" lastName="Doe" sortableName="Doe, Jane"/>
+      <t:Director fullName="John Roe"/>
+    </t:LocalizableTitle>
+    <t:Rating ratingSystem="MPAA">PG</t:Rating>
+    <t:Year>2017</t:Year>
+    <t:Country>US</t:Country>
+    <t:Genre>Drama</t:Genre>
+    <t:Genre>Family</t:Genre>
+    <t:CopyrightNotice>2017 Example</t:CopyrightNotice>
+  </core:Asset>
+  <core:Asset xsi:type="content:MovieType" uriId="example.com/movie/1">
+    <content:SourceUrl>movie.ts</content:SourceUrl>
+    <content:AudioType>Dolby 5.1</content:AudioType>
+    <content:Duration>PT1H30M</content:Duration>
+    <content:Language bitStreamMode="2">eng</content:Language>
+    <content:TrickModesRestricted><content:TrickModeExclusion type="FF"/></content:TrickModesRestricted>
+  </core:Asset>
+  <core:Asset xsi:type="content:PosterType" uriId="example.com/poster/1">
+    <content:SourceUrl>poster.jpg</content:SourceUrl>
+    <content:X_Resolution>1920</content:X_Resolution>
+  </core:Asset>
+  <core:Asset xsi:type="signaling:SignalingType" uriId="example.com/signal/1" xmlns:signaling="http://www.scte.org/schemas/236/2017/signaling">
+    <signaling:Anything>kept</signaling:Anything>
+  </core:Asset>
+  <core:Ext><core:App_Data Name="Season_Number" Value="1"/></core:Ext>
+</core:ADI3>`
+
+func TestAssetDispatch(t *testing.T) {
+	var adi ADI30
+	if err := xml.Unmarshal([]byte(fullADI), &adi); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+
+	expectedTypes := []string{"*adi30.Offer", "*adi30.ContentGroup", "*adi30.Category", "*adi30.Terms", "*adi30.Title", "*adi30.Movie", "*adi30.Poster", "*adi30.UnknownAsset"}
+	if len(expectedTypes) != len(adi.Assets) {
+		t.Log("Expected", len(expectedTypes), "assets, found", len(adi.Assets))
+		t.FailNow()
+	}
+	for i, asset := range adi.Assets {
+		if actual := reflect.TypeOf(asset).String(); expectedTypes[i] != actual {
+			t.Log("Asset", i, "should have been", expectedTypes[i], "but was", actual)
+			t.Fail()
+		}
+	}
+
+	offer := adi.Assets[0].(*Offer)
+	if "example.com/terms/1" != offer.TermsRef.UriId || "VOD1.1" != offer.AlternateIds[0].IdentifierSystem || "example.com/category/movies" != offer.Presentations[0].CategoryRefs[0].UriId {
+		t.Log("Offer not fully decoded", offer)
+		t.Fail()
+	}
+	terms := adi.Assets[3].(*Terms)
+	if 1 != len(terms.SubscriberViewLimits) || "3" != terms.SubscriberViewLimits[0].MaximumViews {
+		t.Log("SubscriberViewLimit not decoded", terms)
+		t.Fail()
+	}
+	title := adi.Assets[4].(*Title)
+	if "en" != title.LocalizableTitles[0].Lang || "Doe, Jane" != title.LocalizableTitles[0].Actors[0].SortableName || "John Roe" != title.LocalizableTitles[0].Directors[0].FullName {
+		t.Log("Cast and crew not decoded", title.LocalizableTitles[0])
+		t.Fail()
+	}
+	if 2017 != title.Year || "US" != title.Countries[0] || 2 != len(title.Genres) || "2017 Example" != title.CopyrightNotice || !title.Description.Deprecated {
+		t.Log("Title not fully decoded", title)
+		t.Fail()
+	}
+	movie := adi.Assets[5].(*Movie)
+	if "movie.ts" != movie.SourceUrl || "PT1H30M" != movie.Duration || "FF" != movie.TrickModesRestricted.TrickModeExclusions[0].Type {
+		t.Log("Movie not fully decoded", movie)
+		t.Fail()
+	}
+	unknown := adi.Assets[7].(*UnknownAsset)
+	if "signaling:SignalingType" != unknown.Type || 1 != len(unknown.Attrs) || !strings.Contains(unknown.InnerXML, "kept") {
+		t.Log("Unknown asset not kept", unknown)
+		t.Fail()
+	}
+}
+
+func TestADIRoundtrip(t *testing.T) {
+	var adi ADI30
+	if err := xml.Unmarshal([]byte(fullADI), &adi); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	marshaled, err := xml.MarshalIndent(&adi, "", "  ")
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	// every prefix used by an xsi:type is declared on ADI3, whatever the source used
+	if !strings.HasPrefix(string(marshaled), `<ADI3 xmlns="http://www.scte.org/schemas/236/2017/core" xmlns:content="http://www.scte.org/schemas/236/2017/content" xmlns:offer="http://www.scte.org/schemas/236/2017/offer" xmlns:terms="http://www.scte.org/schemas/236/2017/terms" xmlns:title="http://www.scte.org/schemas/236/2017/title">`) {
+		t.Log(string(marshaled))
+		t.Fail()
+	}
+
+	var again ADI30
+	if err := xml.Unmarshal(marshaled, &again); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if !reflect.DeepEqual(adi, again) {
+		t.Log(string(marshaled))
+		t.Log("did not decode to the original assets")
+		t.Fail()
+	}
+}
+
+func TestADIJSONRoundtrip(t *testing.T) {
+	var adi ADI30
+	if err := xml.Unmarshal([]byte(fullADI), &adi); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	marshaled, err := json.Marshal(&adi)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if !strings.Contains(string(marshaled), `"xsiType":"title:TitleType"`) {
+		t.Log("Expected the asset types in the JSON:", string(marshaled))
+		t.Fail()
+	}
+
+	var again ADI30
+	if err := json.Unmarshal(marshaled, &again); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	again.XMLName = adi.XMLName
+	if !reflect.DeepEqual(adi, again) {
+		t.Log(string(marshaled))
+		t.Log("did not decode to the original assets")
+		t.Fail()
+	}
+}
+
+func TestUnmodeledElements(t *testing.T) {
+	const raw = `<core:ADI3 xmlns:core="http://www.scte.org/schemas/236/2017/core" xmlns:title="http://www.scte.org/schemas/236/2017/title" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+  <core:Asset xsi:type="title:TitleType" uriId="example.com/title/1">
+    <core:Provider>Example</core:Provider>
+    <title:Year>2020</title:Year>
+    <title:EpisodeId scheme="ex">E7</title:EpisodeId>
+    <x:Code xmlns:x="urn:example:x" x:kind="a">1</x:Code>
+  </core:Asset>
+</core:ADI3>`
+	var adi ADI30
+	if err := xml.Unmarshal([]byte(raw), &adi); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	title := adi.Assets[0].(*Title)
+	if 2020 != title.Year || 2 != len(title.Other) || "EpisodeId" != title.Other[0].XMLName.Local || "E7" != title.Other[0].InnerXML {
+		t.Log("Expected the element without a field to be kept, got", title.Other)
+		t.FailNow()
+	}
+
+	marshaled, err := xml.Marshal(&adi)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if !strings.Contains(string(marshaled), `<EpisodeId xmlns="http://www.scte.org/schemas/236/2017/title" scheme="ex">E7</EpisodeId><Code xmlns="urn:example:x" xmlns:x="urn:example:x" x:kind="a">1</Code>`) {
+		t.Log(string(marshaled))
+		t.Fail()
+	}
+	var again ADI30
+	if err := xml.Unmarshal(marshaled, &again); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if !reflect.DeepEqual(adi, again) {
+		t.Log(string(marshaled))
+		t.Log("did not decode to the original assets")
+		t.Fail()
+	}
+}
@@ -0,0 +1,85 @@
+package adi30
+
+// Structs for the SCTE 236 content namespace, which describes the files that are
+// delivered: video for movies and previews, images for posters and box covers.
+
+// ContentType holds what every content asset has in common.
+type ContentType struct {
+	AssetType
+	SourceUrl       string `xml:"http://www.scte.org/schemas/236/2017/content SourceUrl,omitempty" json:"sourceUrl,omitempty"`
+	ContentFileSize string `xml:"http://www.scte.org/schemas/236/2017/content ContentFileSize,omitempty" json:"contentFileSize,omitempty"`
+	ContentCheckSum string `xml:"http://www.scte.org/schemas/236/2017/content ContentCheckSum,omitempty" json:"contentCheckSum,omitempty"`
+}
+
+// VideoType holds the properties of video content.
+type VideoType struct {
+	ContentType
+	AudioTypes           []string              `xml:"http://www.scte.org/schemas/236/2017/content AudioType,omitempty" json:"audioTypes,omitempty"`
+	ScreenFormat         string                `xml:"http://www.scte.org/schemas/236/2017/content ScreenFormat,omitempty" json:"screenFormat,omitempty"`
+	Resolution           string                `xml:"http://www.scte.org/schemas/236/2017/content Resolution,omitempty" json:"resolution,omitempty"`
+	FrameRate            string                `xml:"http://www.scte.org/schemas/236/2017/content FrameRate,omitempty" json:"frameRate,omitempty"`
+	Codec                string                `xml:"http://www.scte.org/schemas/236/2017/content Codec,omitempty" json:"codec,omitempty"`
+	BitRate              string                `xml:"http://www.scte.org/schemas/236/2017/content BitRate,omitempty" json:"bitRate,omitempty"`
+	Duration             string                `xml:"http://www.scte.org/schemas/236/2017/content Duration,omitempty" json:"duration,omitempty"`
+	Languages            []*Language           `xml:"http://www.scte.org/schemas/236/2017/content Language,omitempty" json:"languages,omitempty"`
+	SubtitleLanguages    []string              `xml:"http://www.scte.org/schemas/236/2017/content SubtitleLanguage,omitempty" json:"subtitleLanguages,omitempty"`
+	DubbedLanguages      []string              `xml:"http://www.scte.org/schemas/236/2017/content DubbedLanguage,omitempty" json:"dubbedLanguages,omitempty"`
+	TrickModesRestricted *TrickModesRestricted `xml:"http://www.scte.org/schemas/236/2017/content TrickModesRestricted,omitempty" json:"trickModesRestricted,omitempty"`
+	CopyrightNotice      string                `xml:"http://www.scte.org/schemas/236/2017/content CopyrightNotice,omitempty" json:"copyrightNotice,omitempty"`
+}
+
+// Movie (content:MovieType) is the feature video of a title.
+type Movie struct {
+	VideoType
+	Other []*Element `xml:",any" json:"other,omitempty"`
+}
+
+func (m *Movie) XSIType() string { return "content:MovieType" }
+
+// Preview (content:PreviewType) is a trailer or other promotional video.
+type Preview struct {
+	VideoType
+	Other []*Element `xml:",any" json:"other,omitempty"`
+}
+
+func (p *Preview) XSIType() string { return "content:PreviewType" }
+
+// ImageType holds the properties of image content.
+type ImageType struct {
+	ContentType
+	X_Resolution string      `xml:"http://www.scte.org/schemas/236/2017/content X_Resolution,omitempty" json:"xResolution,omitempty"`
+	Y_Resolution string      `xml:"http://www.scte.org/schemas/236/2017/content Y_Resolution,omitempty" json:"yResolution,omitempty"`
+	Languages    []*Language `xml:"http://www.scte.org/schemas/236/2017/content Language,omitempty" json:"languages,omitempty"`
+}
+
+// Poster (content:PosterType) is the key art of a title.
+type Poster struct {
+	ImageType
+	Other []*Element `xml:",any" json:"other,omitempty"`
+}
+
+func (p *Poster) XSIType() string { return "content:PosterType" }
+
+// BoxCover (content:BoxCoverType) is the packaging art of a title.
+type BoxCover struct {
+	ImageType
+	Other []*Element `xml:",any" json:"other,omitempty"`
+}
+
+func (bc *BoxCover) XSIType() string { return "content:BoxCoverType" }
+
+// Language is a spoken language of the content, with the AC-3 bit stream mode of its audio.
+type Language struct {
+	BitStreamMode string `xml:"bitStreamMode,attr,omitempty" json:"bitStreamMode,omitempty"`
+	Value         string `xml:",chardata" json:"value,omitempty"`
+}
+
+// TrickModesRestricted lists the trick modes a player must not allow.
+type TrickModesRestricted struct {
+	TrickModeExclusions []*TrickModeExclusion `xml:"http://www.scte.org/schemas/236/2017/content TrickModeExclusion,omitempty" json:"trickModeExclusions,omitempty"`
+}
+
+type TrickModeExclusion struct {
+	Type  string `xml:"type,attr,omitempty" json:"type,omitempty"`
+	Value string `xml:",chardata" json:"value,omitempty"`
+}
@@ -0,0 +1,251 @@
+// Package adi30 provides structs for SCTE 236 2017 (ADI 3.0) content metadata,
+// as carried in the Metadata element of SCTE 224 objects.
+//
+// The schema is split over the core, offer, title, content and terms
+// namespaces, each of which has a file of its own here. Every asset is written as
+// a core:Asset element whose xsi:type names the concrete type, for example
+// <core:Asset xsi:type="title:TitleType">, and is decoded into the matching
+// struct of this package. Assets of an unknown type are kept as UnknownAsset.
+package adi30
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"github.com/Comcast/scte224structs/internal/xmlns"
+)
+
+const (
+	CoreNamespace    = "http://www.scte.org/schemas/236/2017/core"
+	OfferNamespace   = "http://www.scte.org/schemas/236/2017/offer"
+	TitleNamespace   = "http://www.scte.org/schemas/236/2017/title"
+	ContentNamespace = "http://www.scte.org/schemas/236/2017/content"
+	TermsNamespace   = "http://www.scte.org/schemas/236/2017/terms"
+	XSINamespace     = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+// prefixes declared on ADI3 for the namespaces used by xsi:type values, in the
+// order they are declared
+var prefixes = []struct {
+	prefix    string
+	namespace string
+}{
+	{"content", ContentNamespace},
+	{"offer", OfferNamespace},
+	{"terms", TermsNamespace},
+	{"title", TitleNamespace},
+}
+
+// assetTypes maps the local part of an xsi:type to the struct it is decoded into.
+var assetTypes = map[string]func() Asset{
+	"OfferType":        func() Asset { return &Offer{} },
+	"ContentGroupType": func() Asset { return &ContentGroup{} },
+	"CategoryType":     func() Asset { return &Category{} },
+	"TitleType":        func() Asset { return &Title{} },
+	"MovieType":        func() Asset { return &Movie{} },
+	"PreviewType":      func() Asset { return &Preview{} },
+	"PosterType":       func() Asset { return &Poster{} },
+	"BoxCoverType":     func() Asset { return &BoxCover{} },
+	"TermsType":        func() Asset { return &Terms{} },
+}
+
+// ADI30 Top level element of an ADI 3.0 metadata block.
+type ADI30 struct {
+	XMLName xml.Name `xml:"http://www.scte.org/schemas/236/2017/core ADI3" json:"-"`
+	Assets  []Asset  `json:"assets,omitempty"`
+	Ext     *Ext     `json:"ext,omitempty"`
+}
+
+// Asset is implemented by every asset type of this package.
+type Asset interface {
+	// XSIType returns the qualified xsi:type of the asset, such as "title:TitleType".
+	XSIType() string
+}
+
+// UnmarshalXML decodes each core:Asset into the struct for its xsi:type.
+func (adi *ADI30) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	adi.XMLName = start.Name
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Local == "Asset":
+				asset, err := decodeAsset(d, t)
+				if err != nil {
+					return err
+				}
+				adi.Assets = append(adi.Assets, asset)
+			case t.Name.Local == "Ext":
+				adi.Ext = &Ext{}
+				if err := d.DecodeElement(adi.Ext, &t); err != nil {
+					return err
+				}
+			default:
+				if err := d.Skip(); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+func decodeAsset(d *xml.Decoder, start xml.StartElement) (Asset, error) {
+	var xsiType string
+	attrs := make([]xml.Attr, 0, len(start.Attr))
+	for _, attr := range start.Attr {
+		switch {
+		case attr.Name.Space == XSINamespace && attr.Name.Local == "type":
+			xsiType = attr.Value
+		case attr.Name.Space == "xmlns", attr.Name.Space == "" && attr.Name.Local == "xmlns":
+			// namespace declarations are rewritten by the encoder
+		default:
+			attrs = append(attrs, attr)
+		}
+	}
+	start.Attr = attrs
+
+	local := xsiType
+	if i := strings.IndexByte(local, ':'); i >= 0 {
+		local = local[i+1:]
+	}
+	if newAsset, ok := assetTypes[local]; ok {
+		asset := newAsset()
+		return asset, d.DecodeElement(asset, &start)
+	}
+
+	unknown := &UnknownAsset{Type: xsiType}
+	return unknown, d.DecodeElement(unknown, &start)
+}
+
+// MarshalXML writes each asset as a core:Asset with its xsi:type, and declares the
+// prefixes those types use on ADI3.
+func (adi *ADI30) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: xml.Name{Space: CoreNamespace, Local: "ADI3"}}
+	for _, p := range prefixes {
+		for _, asset := range adi.Assets {
+			if asset != nil && strings.HasPrefix(asset.XSIType(), p.prefix+":") {
+				start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:" + p.prefix}, Value: p.namespace})
+				break
+			}
+		}
+	}
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, asset := range adi.Assets {
+		if asset == nil {
+			continue
+		}
+		assetStart := xml.StartElement{
+			Name: xml.Name{Space: CoreNamespace, Local: "Asset"},
+			Attr: []xml.Attr{{Name: xml.Name{Space: XSINamespace, Local: "type"}, Value: asset.XSIType()}},
+		}
+		if err := e.EncodeElement(asset, assetStart); err != nil {
+			return err
+		}
+	}
+	if adi.Ext != nil {
+		if err := e.EncodeElement(adi.Ext, xml.StartElement{Name: xml.Name{Local: "Ext"}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// AssetType holds what every asset has in common. Each asset type keeps the
+// child elements it does not model in Other, so that they are written back.
+type AssetType struct {
+	UriId                string `xml:"uriId,attr" json:"uriId,omitempty"`
+	ProviderVersionNum   string `xml:"providerVersionNum,attr,omitempty" json:"providerVersionNum,omitempty"`
+	InternalVersionNum   string `xml:"internalVersionNum,attr,omitempty" json:"internalVersionNum,omitempty"`
+	CreationDateTime     string `xml:"creationDateTime,attr,omitempty" json:"creationDateTime,omitempty"`
+	StartDateTime        string `xml:"startDateTime,attr,omitempty" json:"startDateTime,omitempty"`
+	EndDateTime          string `xml:"endDateTime,attr,omitempty" json:"endDateTime,omitempty"`
+	LastModifiedDateTime string `xml:"lastModifiedDateTime,attr,omitempty" json:"lastModifiedDateTime,omitempty"`
+
+	AlternateIds      []*AlternateId     `xml:"AlternateId,omitempty" json:"alternateIds,omitempty"`
+	ProviderQAContact string             `xml:"ProviderQAContact,omitempty" json:"providerQAContact,omitempty"`
+	AssetName         *DeprecatableValue `xml:"AssetName,omitempty" json:"assetName,omitempty"`
+	Product           string             `xml:"Product,omitempty" json:"product,omitempty"`
+	Provider          string             `xml:"Provider,omitempty" json:"provider,omitempty"`
+	Description       *DeprecatableValue `xml:"Description,omitempty" json:"description,omitempty"`
+	Ext               *Ext               `xml:"Ext,omitempty" json:"ext,omitempty"`
+}
+
+// AlternateId identifies an asset within another identifier system.
+type AlternateId struct {
+	IdentifierSystem string `xml:"identifierSystem,attr,omitempty" json:"identifierSystem,omitempty"`
+	Value            string `xml:",chardata" json:"value,omitempty"`
+}
+
+// DeprecatableValue is a value that the provider may flag as deprecated.
+type DeprecatableValue struct {
+	Deprecated bool   `xml:"deprecated,attr,omitempty" json:"deprecated,omitempty"`
+	Value      string `xml:",chardata" json:"value,omitempty"`
+}
+
+// UriId references another asset of the same ADI3 block by its uriId.
+type UriId struct {
+	UriId string `xml:"uriId,attr,omitempty" json:"uriId,omitempty"`
+}
+
+// Ext carries provider specific name/value pairs.
+type Ext struct {
+	App_Data []*ExtAppData `xml:"App_Data,omitempty" json:"appData,omitempty"`
+}
+
+type ExtAppData struct {
+	Name  string `xml:"Name,attr" json:"name"`
+	Value string `xml:"Value,attr" json:"value"`
+}
+
+// UnknownAsset keeps an asset whose xsi:type is not modeled by this package, so
+// that it is written back unchanged.
+type UnknownAsset struct {
+	Type     string     `xml:"-" json:"type"`
+	Attrs    []xml.Attr `xml:",any,attr" json:"attrs,omitempty"`
+	InnerXML string     `xml:",innerxml" json:"innerXML,omitempty"`
+}
+
+func (u *UnknownAsset) XSIType() string { return u.Type }
+
+// Element keeps a child element of an asset that this package does not model,
+// so that it is written back unchanged.
+type Element struct {
+	XMLName  xml.Name   `json:"name"`
+	Attrs    []xml.Attr `xml:",any,attr" json:"attrs,omitempty"`
+	InnerXML string     `xml:",innerxml" json:"innerXML,omitempty"`
+}
+
+// UnmarshalXML decodes the element as encoding/xml would, but leaves out the
+// declaration of its default namespace, which is written from its name, and
+// keeps other namespace declarations as written; see xmlns.LiteralAttrs.
+func (el *Element) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	// a type without methods, as calling DecodeElement on el would recurse
+	type plainElement Element
+	var plain plainElement
+	if err := d.DecodeElement(&plain, &start); err != nil {
+		return err
+	}
+	attrs := plain.Attrs[:0]
+	for _, attr := range plain.Attrs {
+		if attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+			continue
+		}
+		attrs = append(attrs, attr)
+	}
+	plain.Attrs = xmlns.LiteralAttrs(attrs)
+	if len(plain.Attrs) == 0 {
+		plain.Attrs = nil
+	}
+	*el = Element(plain)
+	return nil
+}
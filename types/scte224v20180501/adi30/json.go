@@ -0,0 +1,68 @@
+package adi30
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// jsonAsset is the JSON form of an asset: its xsi:type, which tells the struct
+// it decodes into, and its fields.
+type jsonAsset struct {
+	XSIType string          `json:"xsiType"`
+	Asset   json.RawMessage `json:"asset"`
+}
+
+type jsonADI30 struct {
+	Assets []jsonAsset `json:"assets,omitempty"`
+	Ext    *Ext        `json:"ext,omitempty"`
+}
+
+// MarshalJSON writes each asset along with its xsi:type.
+func (adi *ADI30) MarshalJSON() ([]byte, error) {
+	var out jsonADI30
+	out.Ext = adi.Ext
+	for _, asset := range adi.Assets {
+		if asset == nil {
+			continue
+		}
+		raw, err := json.Marshal(asset)
+		if err != nil {
+			return nil, err
+		}
+		out.Assets = append(out.Assets, jsonAsset{XSIType: asset.XSIType(), Asset: raw})
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes each asset into the struct for its xsi:type, as
+// UnmarshalXML does; assets of an unknown type are kept as UnknownAsset.
+func (adi *ADI30) UnmarshalJSON(data []byte) error {
+	var in jsonADI30
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	adi.Ext = in.Ext
+	adi.Assets = nil
+	for _, entry := range in.Assets {
+		local := entry.XSIType
+		if i := strings.IndexByte(local, ':'); i >= 0 {
+			local = local[i+1:]
+		}
+		var asset Asset
+		if newAsset, ok := assetTypes[local]; ok {
+			asset = newAsset()
+		} else {
+			asset = &UnknownAsset{}
+		}
+		if len(entry.Asset) > 0 {
+			if err := json.Unmarshal(entry.Asset, asset); err != nil {
+				return err
+			}
+		}
+		if unknown, ok := asset.(*UnknownAsset); ok {
+			unknown.Type = entry.XSIType
+		}
+		adi.Assets = append(adi.Assets, asset)
+	}
+	return nil
+}
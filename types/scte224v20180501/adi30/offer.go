@@ -0,0 +1,50 @@
+package adi30
+
+// Structs for the SCTE 236 offer namespace, which packages titles and content
+// for sale.
+
+// Offer (offer:OfferType) is what a subscriber buys: it points at the terms it is
+// sold under and at the content group it delivers.
+type Offer struct {
+	AssetType
+	Presentations               []*Presentation    `xml:"http://www.scte.org/schemas/236/2017/offer Presentation,omitempty" json:"presentations,omitempty"`
+	BillingId                   string             `xml:"http://www.scte.org/schemas/236/2017/offer BillingId,omitempty" json:"billingId,omitempty"`
+	TermsRef                    *UriId             `xml:"http://www.scte.org/schemas/236/2017/offer TermsRef,omitempty" json:"termsRef,omitempty"`
+	ContentGroupRefs            []*UriId           `xml:"http://www.scte.org/schemas/236/2017/offer ContentGroupRef,omitempty" json:"contentGroupRefs,omitempty"`
+	PromotionalContentGroupRefs []*UriId           `xml:"http://www.scte.org/schemas/236/2017/offer PromotionalContentGroupRef,omitempty" json:"promotionalContentGroupRefs,omitempty"`
+	ProviderContentTier         string             `xml:"http://www.scte.org/schemas/236/2017/offer ProviderContentTier,omitempty" json:"providerContentTier,omitempty"`
+	SourceMetadataSpecVersion   *DeprecatableValue `xml:"http://www.scte.org/schemas/236/2017/offer SourceMetadataSpecVersion,omitempty" json:"sourceMetadataSpecVersion,omitempty"`
+	Other                       []*Element         `xml:",any" json:"other,omitempty"`
+}
+
+func (o *Offer) XSIType() string { return "offer:OfferType" }
+
+// Presentation places an offer in the navigation categories.
+type Presentation struct {
+	CategoryRefs        []*UriId `xml:"http://www.scte.org/schemas/236/2017/offer CategoryRef,omitempty" json:"categoryRefs,omitempty"`
+	DisplayAsNew        string   `xml:"http://www.scte.org/schemas/236/2017/offer DisplayAsNew,omitempty" json:"displayAsNew,omitempty"`
+	DisplayAsLastChance string   `xml:"http://www.scte.org/schemas/236/2017/offer DisplayAsLastChance,omitempty" json:"displayAsLastChance,omitempty"`
+}
+
+// ContentGroup (offer:ContentGroupType) ties a title to the content files that
+// make it up.
+type ContentGroup struct {
+	AssetType
+	TitleRef     *UriId     `xml:"http://www.scte.org/schemas/236/2017/offer TitleRef,omitempty" json:"titleRef,omitempty"`
+	MovieRefs    []*UriId   `xml:"http://www.scte.org/schemas/236/2017/offer MovieRef,omitempty" json:"movieRefs,omitempty"`
+	PreviewRefs  []*UriId   `xml:"http://www.scte.org/schemas/236/2017/offer PreviewRef,omitempty" json:"previewRefs,omitempty"`
+	PosterRefs   []*UriId   `xml:"http://www.scte.org/schemas/236/2017/offer PosterRef,omitempty" json:"posterRefs,omitempty"`
+	BoxCoverRefs []*UriId   `xml:"http://www.scte.org/schemas/236/2017/offer BoxCoverRef,omitempty" json:"boxCoverRefs,omitempty"`
+	Other        []*Element `xml:",any" json:"other,omitempty"`
+}
+
+func (cg *ContentGroup) XSIType() string { return "offer:ContentGroupType" }
+
+// Category (offer:CategoryType) is a node of the navigation tree.
+type Category struct {
+	AssetType
+	CategoryPath string     `xml:"http://www.scte.org/schemas/236/2017/offer CategoryPath,omitempty" json:"categoryPath,omitempty"`
+	Other        []*Element `xml:",any" json:"other,omitempty"`
+}
+
+func (c *Category) XSIType() string { return "offer:CategoryType" }
@@ -0,0 +1,21 @@
+package adi30
+
+// Structs for the SCTE 236 terms namespace, the business rules an offer is sold under.
+
+// Terms (terms:TermsType) describes pricing and viewing limits.
+type Terms struct {
+	AssetType
+	BillingGracePeriod   string                 `xml:"http://www.scte.org/schemas/236/2017/terms BillingGracePeriod,omitempty" json:"billingGracePeriod,omitempty"`
+	SuggestedPrice       string                 `xml:"http://www.scte.org/schemas/236/2017/terms SuggestedPrice,omitempty" json:"suggestedPrice,omitempty"`
+	SubscriberViewLimits []*SubscriberViewLimit `xml:"http://www.scte.org/schemas/236/2017/terms SubscriberViewLimit,omitempty" json:"subscriberViewLimits,omitempty"`
+	Other                []*Element             `xml:",any" json:"other,omitempty"`
+}
+
+func (t *Terms) XSIType() string { return "terms:TermsType" }
+
+// SubscriberViewLimit caps how often an offer may be viewed within a window.
+type SubscriberViewLimit struct {
+	StartDateTime string `xml:"startDateTime,attr,omitempty" json:"startDateTime,omitempty"`
+	EndDateTime   string `xml:"endDateTime,attr,omitempty" json:"endDateTime,omitempty"`
+	MaximumViews  string `xml:"maximumViews,attr,omitempty" json:"maximumViews,omitempty"`
+}
@@ -0,0 +1,57 @@
+package adi30
+
+// Structs for the SCTE 236 title namespace, the descriptive metadata of a program.
+
+// Title (title:TitleType) describes a program independent of how it is delivered.
+type Title struct {
+	AssetType
+	LocalizableTitles  []*LocalizableTitle `xml:"http://www.scte.org/schemas/236/2017/title LocalizableTitle,omitempty" json:"localizableTitles,omitempty"`
+	Ratings            []*Rating           `xml:"http://www.scte.org/schemas/236/2017/title Rating,omitempty" json:"ratings,omitempty"`
+	Advisories         []string            `xml:"http://www.scte.org/schemas/236/2017/title Advisory,omitempty" json:"advisories,omitempty"`
+	IsClosedCaptioning string              `xml:"http://www.scte.org/schemas/236/2017/title IsClosedCaptioning,omitempty" json:"isClosedCaptioning,omitempty"`
+	IsSeasonPremiere   string              `xml:"http://www.scte.org/schemas/236/2017/title IsSeasonPremiere,omitempty" json:"isSeasonPremiere,omitempty"`
+	IsSeasonFinale     string              `xml:"http://www.scte.org/schemas/236/2017/title IsSeasonFinale,omitempty" json:"isSeasonFinale,omitempty"`
+	DisplayRunTime     string              `xml:"http://www.scte.org/schemas/236/2017/title DisplayRunTime,omitempty" json:"displayRunTime,omitempty"`
+	Year               int                 `xml:"http://www.scte.org/schemas/236/2017/title Year,omitempty" json:"year,omitempty"`
+	Countries          []string            `xml:"http://www.scte.org/schemas/236/2017/title Country,omitempty" json:"countries,omitempty"`
+	Genres             []string            `xml:"http://www.scte.org/schemas/236/2017/title Genre,omitempty" json:"genres,omitempty"`
+	ShowType           string              `xml:"http://www.scte.org/schemas/236/2017/title ShowType,omitempty" json:"showType,omitempty"`
+	CopyrightNotice    string              `xml:"http://www.scte.org/schemas/236/2017/title CopyrightNotice,omitempty" json:"copyrightNotice,omitempty"`
+	Other              []*Element          `xml:",any" json:"other,omitempty"`
+}
+
+func (t *Title) XSIType() string { return "title:TitleType" }
+
+// LocalizableTitle holds the names, summaries and credits of a title in one language.
+type LocalizableTitle struct {
+	Lang          string    `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty" json:"lang,omitempty"`
+	TitleSortName string    `xml:"http://www.scte.org/schemas/236/2017/title TitleSortName,omitempty" json:"titleSortName,omitempty"`
+	TitleBrief    string    `xml:"http://www.scte.org/schemas/236/2017/title TitleBrief,omitempty" json:"titleBrief,omitempty"`
+	TitleMedium   string    `xml:"http://www.scte.org/schemas/236/2017/title TitleMedium,omitempty" json:"titleMedium,omitempty"`
+	TitleLong     string    `xml:"http://www.scte.org/schemas/236/2017/title TitleLong,omitempty" json:"titleLong,omitempty"`
+	SummaryShort  string    `xml:"http://www.scte.org/schemas/236/2017/title SummaryShort,omitempty" json:"summaryShort,omitempty"`
+	SummaryMedium string    `xml:"http://www.scte.org/schemas/236/2017/title SummaryMedium,omitempty" json:"summaryMedium,omitempty"`
+	SummaryLong   string    `xml:"http://www.scte.org/schemas/236/2017/title SummaryLong,omitempty" json:"summaryLong,omitempty"`
+	EpisodeName   string    `xml:"http://www.scte.org/schemas/236/2017/title EpisodeName,omitempty" json:"episodeName,omitempty"`
+	EpisodeID     string    `xml:"http://www.scte.org/schemas/236/2017/title EpisodeID,omitempty" json:"episodeID,omitempty"`
+	ActorDisplay  string    `xml:"http://www.scte.org/schemas/236/2017/title ActorDisplay,omitempty" json:"actorDisplay,omitempty"`
+	StudioDisplay string    `xml:"http://www.scte.org/schemas/236/2017/title StudioDisplay,omitempty" json:"studioDisplay,omitempty"`
+	Actors        []*Person `xml:"http://www.scte.org/schemas/236/2017/title Actor,omitempty" json:"actors,omitempty"`
+	Directors     []*Person `xml:"http://www.scte.org/schemas/236/2017/title Director,omitempty" json:"directors,omitempty"`
+	Producers     []*Person `xml:"http://www.scte.org/schemas/236/2017/title Producer,omitempty" json:"producers,omitempty"`
+	Writers       []*Person `xml:"http://www.scte.org/schemas/236/2017/title Writer,omitempty" json:"writers,omitempty"`
+}
+
+// Person names a member of the cast or crew.
+type Person struct {
+	FullName     string `xml:"fullName,attr,omitempty" json:"fullName,omitempty"`
+	FirstName    string `xml:"firstName,attr,omitempty" json:"firstName,omitempty"`
+	LastName     string `xml:"lastName,attr,omitempty" json:"lastName,omitempty"`
+	SortableName string `xml:"sortableName,attr,omitempty" json:"sortableName,omitempty"`
+}
+
+// Rating is a content rating within a rating system, such as urn:v-chip.
+type Rating struct {
+	RatingSystem string `xml:"ratingSystem,attr,omitempty" json:"ratingSystem,omitempty"`
+	Value        string `xml:",chardata" json:"value,omitempty"`
+}
@@ -0,0 +1,156 @@
+package scte224v20180501
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/Comcast/scte224structs/extension"
+)
+
+type teamCodes struct {
+	Home string `xml:"home,attr"`
+	Away string `xml:"away,attr"`
+}
+
+func TestTypedExtensionNodes(t *testing.T) {
+	name := xml.Name{Space: "urn:example:league", Local: "Teams"}
+	extension.Register(name, &teamCodes{})
+	defer extension.Unregister(name)
+
+	raw := `<Media xmlns="http://www.scte.org/schemas/224" id="m"><Metadata><Teams xmlns="urn:example:league" home="PHI" away="NYG"/><Other xmlns="urn:example:other">x</Other></Metadata></Media>`
+	var media Media
+	if err := xml.Unmarshal([]byte(raw), &media); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	teams, ok := media.Metadata.Nodes[0].Typed.(*teamCodes)
+	if !ok || "PHI" != teams.Home || "NYG" != teams.Away {
+		t.Log("Registered node was not decoded", media.Metadata.Nodes[0])
+		t.Fail()
+	}
+	if nil != media.Metadata.Nodes[1].Typed || "x" != media.Metadata.Nodes[1].Value {
+		t.Log("Unregistered node should be kept as is", media.Metadata.Nodes[1])
+		t.Fail()
+	}
+
+	marshaled, err := xml.Marshal(media)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if !strings.Contains(string(marshaled), `<Metadata xmlns="http://www.scte.org/schemas/224"><Teams xmlns="urn:example:league" home="PHI" away="NYG"></Teams><Other xmlns="urn:example:other">x</Other></Metadata>`) {
+		t.Log(string(marshaled))
+		t.Fail()
+	}
+
+	// typed values are decoded from JSON into their registered type as well
+	asJSON, err := json.Marshal(media)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	var fromJSON Media
+	if err := json.Unmarshal(asJSON, &fromJSON); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if _, ok := fromJSON.Metadata.Nodes[0].Typed.(*teamCodes); !ok {
+		t.Log("Registered node was not decoded from JSON", fromJSON.Metadata.Nodes[0])
+		t.Fail()
+	}
+	again, err := xml.Marshal(fromJSON)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if string(marshaled) != string(again) {
+		t.Log(string(again))
+		t.Fail()
+	}
+}
+
+func TestTypedNodeAttributes(t *testing.T) {
+	name := xml.Name{Space: "urn:example:league", Local: "Teams"}
+	extension.Register(name, &teamCodes{})
+	defer extension.Unregister(name)
+
+	const teams = `<Teams xmlns="urn:example:league" season="2021" home="PHI" away="NYG"></Teams>`
+	raw := `<Media xmlns="http://www.scte.org/schemas/224" id="m"><Metadata>` + teams + `</Metadata></Media>`
+	var media Media
+	if err := xml.Unmarshal([]byte(raw), &media); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if attrs := media.Metadata.Nodes[0].Attributes; 1 != len(attrs) || "season" != attrs[0].Name.Local {
+		t.Log("Expected the attribute without a field to be kept, got", attrs)
+		t.Fail()
+	}
+	marshaled, err := xml.Marshal(media)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if !strings.Contains(string(marshaled), teams) {
+		t.Log(string(marshaled))
+		t.Fail()
+	}
+
+	// without the type registered, a typed node decodes from JSON as written
+	asJSON, err := json.Marshal(media)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	extension.Unregister(name)
+	var fromJSON Media
+	if err := json.Unmarshal(asJSON, &fromJSON); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if _, ok := fromJSON.Metadata.Nodes[0].Typed.(json.RawMessage); !ok {
+		t.Log("Expected the typed value to be kept as JSON", fromJSON.Metadata.Nodes[0])
+		t.Fail()
+	}
+	if again, _ := json.Marshal(fromJSON); string(asJSON) != string(again) {
+		t.Log(string(again))
+		t.Fail()
+	}
+}
+
+func TestRawNodeNamespaces(t *testing.T) {
+	const detail = `<Detail xmlns="urn:example" xmlns:x="urn:example:x" x:type="string"><x:value>1</x:value></Detail>`
+	raw := `<Media xmlns="http://www.scte.org/schemas/224" id="m"><Metadata>` + detail + `</Metadata></Media>`
+	var media Media
+	if err := xml.Unmarshal([]byte(raw), &media); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	attrs := media.Metadata.Nodes[0].Attributes
+	if 2 != len(attrs) || "xmlns:x" != attrs[0].Name.Local || "x:type" != attrs[1].Name.Local {
+		t.Log("Expected the declaration and prefixed attribute as written, got", attrs)
+		t.Fail()
+	}
+
+	marshaled, err := xml.Marshal(media)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if !strings.Contains(string(marshaled), detail) {
+		t.Log(string(marshaled))
+		t.Fail()
+	}
+
+	var again Media
+	if err := xml.Unmarshal(marshaled, &again); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	remarshaled, _ := xml.Marshal(again)
+	if string(marshaled) != string(remarshaled) {
+		t.Log("Encoding is not stable", string(remarshaled))
+		t.Fail()
+	}
+}
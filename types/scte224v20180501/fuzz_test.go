@@ -0,0 +1,61 @@
+//go:build go1.18
+// +build go1.18
+
+package scte224v20180501
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Comcast/scte224structs/internal/roundtrip"
+)
+
+// fuzzRoots returns a new value of every type a document can have as its root.
+func fuzzRoots() []interface{} {
+	return []interface{}{&Media{}, &MediaPoint{}, &Policy{}, &ViewingPolicy{}, &Audience{}, &Results{}, &Audit{}}
+}
+
+func addFuzzSeeds(f *testing.F) {
+	for _, raw := range []string{CALI_XML, topLevelNamspaces, inlinedNamespaces, originalADIMetadata, adi11Metadata, viewingpolicy, spi, vpPPOStart, spd, content, random, streamAudience} {
+		f.Add([]byte(raw))
+	}
+	f.Add([]byte(`<Results xmlns="http://www.scte.org/schemas/224" size="1">` + streamAudience + `</Results>`))
+	// a node kept as raw XML whose prefix declaration is used by an attribute
+	// and a child
+	f.Add([]byte(`<ViewingPolicy xmlns="http://www.scte.org/schemas/224" id="vp"><Other xmlns="urn:example" xmlns:x="urn:example:x" x:a="1"><x:b/></Other></ViewingPolicy>`))
+}
+
+// FuzzUnmarshal decodes arbitrary bytes into every root type, and checks that
+// what decodes encodes to XML that decodes and encodes to the same bytes. The
+// seeds are whole documents, which are slow to minimize; run it with a short
+// -fuzzminimizetime, such as 1s.
+func FuzzUnmarshal(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		roundtrip.Check(t, raw, fuzzRoots, nil)
+	})
+}
+
+// FuzzResultsDecoder reads arbitrary bytes as a stream of Results entries,
+// which must end in an error or io.EOF without panicking.
+func FuzzResultsDecoder(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		rd := NewResultsDecoder(bytes.NewReader(raw))
+		for {
+			if _, err := rd.Next(); nil != err {
+				return
+			}
+		}
+	})
+}
+
+// FuzzConvertDuration converts arbitrary strings, which must not panic.
+func FuzzConvertDuration(f *testing.F) {
+	for _, seed := range []string{"", "PT30S", "PT1H30M", "P1DT2H", "PT1.5S", "-PT5M", "P-1DT-1H", "PT"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, raw string) {
+		ConvertDuration(raw)
+	})
+}
@@ -0,0 +1,77 @@
+package scte224v20180501
+
+import (
+	"encoding/xml"
+
+	"github.com/Comcast/scte224structs/internal/xmlns"
+)
+
+//********************* Marshal Options *************************//
+
+// A MarshalOption changes how the Marshal methods of the root objects write them.
+type MarshalOption func(*marshalOptions)
+
+type marshalOptions struct {
+	schemaLocation bool
+	schemas        []xmlns.Schema
+	prefix, indent string
+}
+
+// WithSchemaLocation adds an xsi:schemaLocation attribute pointing to the 2018
+// schema to the root element, as some validators require.
+func WithSchemaLocation() MarshalOption {
+	return func(o *marshalOptions) { o.schemaLocation = true }
+}
+
+// WithSchema lists location as the schema of namespace in the xsi:schemaLocation
+// attribute WithSchemaLocation adds, when the document uses namespace. SCTE does
+// not publish locations for its Action and Audience schemas, so those are only
+// listed when given this way:
+//
+//	vp.Marshal(WithSchemaLocation(), WithSchema("urn:scte:224:action", "SCTE224_Action_20200130.xsd"))
+func WithSchema(namespace, location string) MarshalOption {
+	return func(o *marshalOptions) {
+		o.schemas = append(o.schemas, xmlns.Schema{Namespace: namespace, Location: location})
+	}
+}
+
+// WithIndent indents the output as xml.MarshalIndent does.
+func WithIndent(prefix, indent string) MarshalOption {
+	return func(o *marshalOptions) { o.prefix, o.indent = prefix, indent }
+}
+
+// Marshal returns the XML encoding of m with the given options.
+func (m *Media) Marshal(opts ...MarshalOption) ([]byte, error) { return marshal(m, opts) }
+
+// Marshal returns the XML encoding of mp with the given options.
+func (mp *MediaPoint) Marshal(opts ...MarshalOption) ([]byte, error) { return marshal(mp, opts) }
+
+// Marshal returns the XML encoding of p with the given options.
+func (p *Policy) Marshal(opts ...MarshalOption) ([]byte, error) { return marshal(p, opts) }
+
+// Marshal returns the XML encoding of vp with the given options.
+func (vp *ViewingPolicy) Marshal(opts ...MarshalOption) ([]byte, error) { return marshal(vp, opts) }
+
+// Marshal returns the XML encoding of aud with the given options.
+func (aud *Audience) Marshal(opts ...MarshalOption) ([]byte, error) { return marshal(aud, opts) }
+
+// Marshal returns the XML encoding of results with the given options.
+func (results *Results) Marshal(opts ...MarshalOption) ([]byte, error) {
+	return marshal(results, opts)
+}
+
+// Marshal returns the XML encoding of audit with the given options.
+func (audit *Audit) Marshal(opts ...MarshalOption) ([]byte, error) { return marshal(audit, opts) }
+
+func marshal(v interface{}, opts []MarshalOption) ([]byte, error) {
+	var o marshalOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	raw, err := xml.MarshalIndent(v, o.prefix, o.indent)
+	if err != nil || !o.schemaLocation {
+		return raw, err
+	}
+	schemas := append([]xmlns.Schema{{Namespace: esniNamespace, Location: schemaLocation}}, o.schemas...)
+	return xmlns.AddSchemaLocation(raw, schemas...)
+}
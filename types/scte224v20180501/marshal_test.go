@@ -0,0 +1,26 @@
+package scte224v20180501
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarshalSchemaLocation(t *testing.T) {
+	vp := &ViewingPolicy{Content: &ContentAction{Content: "replace"}}
+	located, err := vp.Marshal(WithSchemaLocation(), WithSchema("urn:scte:224:action", "SCTE224_Action_20200130.xsd"))
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	expected := `xsi:schemaLocation="http://www.scte.org/schemas/224 http://www.scte.org/schemas/224/SCTE224-20180501.xsd urn:scte:224:action SCTE224_Action_20200130.xsd"`
+	if !strings.HasPrefix(string(located), `<ViewingPolicy xmlns="http://www.scte.org/schemas/224" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" `+expected+`>`) {
+		t.Log("Schema locations not added", string(located))
+		t.Fail()
+	}
+
+	plain, err := vp.Marshal()
+	if nil != err || strings.Contains(string(plain), "schemaLocation") {
+		t.Log("Schema locations added without the option", string(plain), err)
+		t.Fail()
+	}
+}
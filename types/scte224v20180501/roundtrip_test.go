@@ -181,11 +181,11 @@ const expectedInlinedADIMetadata = `<Media xmlns="http://www.scte.org/schemas/22
     <AltID xmlns="http://www.scte.org/schemas/224">ow7qaht5qULfWFcl5sbvzJXOVJEHsP6a</AltID>
     <Metadata xmlns="http://www.scte.org/schemas/224">
       <ADI3 xmlns="http://www.scte.org/schemas/236/2017/core" xmlns:content="http://www.scte.org/schemas/236/2017/content" xmlns:title="http://www.scte.org/schemas/236/2017/title">
-        <Asset xmlns:XMLSchema-instance="http://www.w3.org/2001/XMLSchema-instance" XMLSchema-instance:type="content:MovieType" uriId="" providerVersionNum="20" internalVersionNum="20" creationDateTime="2020-03-30T00:00:00" startDateTime="2020-03-30T18:00:00.000-07:00" endDateTime="2020-03-30T19:01:00.000-07:00" lastModifiedDateTime="2020-03-24T23:21:02.000-07:00">
+        <Asset xmlns="http://www.scte.org/schemas/236/2017/core" xmlns:_XMLSchema-instance="http://www.w3.org/2001/XMLSchema-instance" _XMLSchema-instance:type="content:MovieType" uriId="" providerVersionNum="20" internalVersionNum="20" creationDateTime="2020-03-30T00:00:00" startDateTime="2020-03-30T18:00:00.000-07:00" endDateTime="2020-03-30T19:01:00.000-07:00" lastModifiedDateTime="2020-03-24T23:21:02.000-07:00">
           <Provider>HBO</Provider>
           <Language xmlns="http://www.scte.org/schemas/236/2017/content" bitStreamMode="2">eng</Language>
         </Asset>
-        <Asset xmlns:XMLSchema-instance="http://www.w3.org/2001/XMLSchema-instance" XMLSchema-instance:type="title:TitleType" uriId="hbo.com/title/HBO" providerVersionNum="20" internalVersionNum="20" creationDateTime="2020-03-30T00:00:00" startDateTime="2020-03-30T18:00:00.000-07:00" endDateTime="2020-03-30T19:01:00.000-07:00" lastModifiedDateTime="2020-03-24T23:21:02.000-07:00">
+        <Asset xmlns="http://www.scte.org/schemas/236/2017/core" xmlns:_XMLSchema-instance="http://www.w3.org/2001/XMLSchema-instance" _XMLSchema-instance:type="title:TitleType" uriId="hbo.com/title/HBO" providerVersionNum="20" internalVersionNum="20" creationDateTime="2020-03-30T00:00:00" startDateTime="2020-03-30T18:00:00.000-07:00" endDateTime="2020-03-30T19:01:00.000-07:00" lastModifiedDateTime="2020-03-24T23:21:02.000-07:00">
           <Provider>HBO</Provider>
           <Ext>
             <App_Data Name="Season_Number" Value="1"></App_Data>
@@ -245,3 +245,63 @@ func TestADIMetadata(t *testing.T) {
 		t.Fail()
 	}
 }
+
+const adi11Metadata = `<MediaPoint xmlns="http://www.scte.org/schemas/224" id="fxnetworks.com/media/FX/program/1/start">
+  <Metadata>
+    <ADI xmlns="">
+      <Metadata>
+        <AMS Product="MOD" Asset_ID="PKGE0000000000000001" Provider_ID="fxnetworks.com" Provider="FX" Asset_Class="package" Asset_Name="Example_Package" Description="Example package" Version_Minor="0" Version_Major="1" Creation_Date="2020-03-30"></AMS>
+      </Metadata>
+    </ADI>
+  </Metadata>
+</MediaPoint>`
+
+func TestADI11Metadata(t *testing.T) {
+	var mediaPoint MediaPoint
+	if err := xml.Unmarshal([]byte(adi11Metadata), &mediaPoint); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	adi := mediaPoint.Metadata.ADI11
+	if nil == adi || "PKGE0000000000000001" != adi.Metadata.Ams.AssetID {
+		t.Log("ADI 1.1 metadata was not decoded")
+		t.FailNow()
+	}
+	if 0 != len(mediaPoint.Metadata.Nodes) {
+		t.Log("ADI 1.1 metadata should not also be kept as a node")
+		t.Fail()
+	}
+
+	marshaled, err := xml.Marshal(mediaPoint)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if !strings.Contains(string(marshaled), `<Metadata xmlns="http://www.scte.org/schemas/224"><ADI xmlns=""><Metadata><AMS Product="MOD" Asset_ID="PKGE0000000000000001"`) {
+		t.Log(string(marshaled))
+		t.Fail()
+	}
+
+	// ADI 1.1 has no namespace, so it decodes as such again
+	var again MediaPoint
+	if err := xml.Unmarshal(marshaled, &again); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if nil == again.Metadata.ADI11 || 0 != len(again.Metadata.Nodes) {
+		t.Log("ADI 1.1 metadata was not decoded from the marshaled XML")
+		t.Fail()
+	}
+
+	// an ADI element in another namespace is not ADI 1.1
+	inherited := strings.Replace(adi11Metadata, `<ADI xmlns="">`, `<ADI>`, 1)
+	var other MediaPoint
+	if err := xml.Unmarshal([]byte(inherited), &other); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if nil != other.Metadata.ADI11 || 1 != len(other.Metadata.Nodes) {
+		t.Log("Expected an ADI element in the SCTE 224 namespace to be kept as a node")
+		t.Fail()
+	}
+}
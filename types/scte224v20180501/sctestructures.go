@@ -8,7 +8,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Comcast/scte224structs/types/scte224v20180501/adi11"
 	"github.com/Comcast/scte224structs/types/scte224v20180501/adi30"
+	"github.com/Comcast/scte224structs/types/xsd"
 )
 
 const schemaLocation = "http://www.scte.org/schemas/224/SCTE224-20180501.xsd"
@@ -16,13 +18,13 @@ const schemaLocation = "http://www.scte.org/schemas/224/SCTE224-20180501.xsd"
 // Structs for SCTE 224 2018 ESNI Objects.
 // Table 3
 type IdentifiableType struct {
-	Id          string     `xml:"id,attr,omitempty" json:"id,omitempty"`
-	Description string     `xml:"description,attr,omitempty" json:"description,omitempty"`
-	LastUpdated *time.Time `xml:"lastUpdated,attr,omitempty" json:"lastUpdated,omitempty"`
-	XMLBase     string     `xml:"xml:base,attr,omitempty" json:"-"`
-	AltIDs      []*AltID   `xml:"http://www.scte.org/schemas/224 AltID,omitempty" json:"altIDs,omitempty"`
-	Metadata    *Metadata  `xml:"http://www.scte.org/schemas/224 Metadata,omitempty" json:"metadata,omitempty"`
-	Ext         *Ext       `xml:"http://www.scte.org/schemas/224 Ext,omitempty" json:"ext,omitempty"`
+	Id          string        `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Description string        `xml:"description,attr,omitempty" json:"description,omitempty"`
+	LastUpdated *xsd.DateTime `xml:"lastUpdated,attr,omitempty" json:"lastUpdated,omitempty"`
+	XMLBase     string        `xml:"xml:base,attr,omitempty" json:"-"`
+	AltIDs      []*AltID      `xml:"http://www.scte.org/schemas/224 AltID,omitempty" json:"altIDs,omitempty"`
+	Metadata    *Metadata     `xml:"http://www.scte.org/schemas/224 Metadata,omitempty" json:"metadata,omitempty"`
+	Ext         *Ext          `xml:"http://www.scte.org/schemas/224 Ext,omitempty" json:"ext,omitempty"`
 }
 
 //Table 5
@@ -36,8 +38,8 @@ type ReusableType struct {
 type Media struct {
 	ReusableType
 	XMLName     xml.Name      `xml:"http://www.scte.org/schemas/224 Media" json:"-"`
-	Effective   *time.Time    `xml:"effective,attr,omitempty" json:"effective,omitempty"`
-	Expires     *time.Time    `xml:"expires,attr,omitempty" json:"expires,omitempty"`
+	Effective   *xsd.DateTime `xml:"effective,attr,omitempty" json:"effective,omitempty"`
+	Expires     *xsd.DateTime `xml:"expires,attr,omitempty" json:"expires,omitempty"`
 	Source      string        `xml:"source,attr,omitempty" json:"source,omitempty"`
 	MediaPoints []*MediaPoint `xml:"http://www.scte.org/schemas/224 MediaPoint" json:"mediaPoints,omitempty"`
 }
@@ -46,19 +48,19 @@ type Media struct {
 //Table 7
 type MediaPoint struct {
 	IdentifiableType
-	XMLName          xml.Name     `xml:"http://www.scte.org/schemas/224 MediaPoint" json:"-"`
-	Effective        *time.Time   `xml:"effective,attr,omitempty" json:"effective,omitempty"`
-	Expires          *time.Time   `xml:"expires,attr,omitempty" json:"expires,omitempty"`
-	MatchTime        *time.Time   `xml:"matchTime,attr,omitempty" json:"matchTime,omitempty"`
-	MatchOffset      Duration     `xml:"matchOffset,attr,omitempty" json:"matchOffset,omitempty"`
-	Source           string       `xml:"source,attr,omitempty" json:"source,omitempty"`
-	ExpectedDuration Duration     `xml:"expectedDuration,attr,omitempty" json:"expectedDuration,omitempty"`
-	Order            *uint        `xml:"order,attr,omitempty" json:"order,omitempty"`
-	Reusable         bool         `xml:"reusable,attr,omitempty" json:"reusable,omitempty"`
-	Removes          []*Remove    `xml:"http://www.scte.org/schemas/224 Remove" json:"removes,omitempty"`
-	Applys           []*Apply     `xml:"http://www.scte.org/schemas/224 Apply" json:"applys,omitempty"`
-	MatchSignal      *MatchSignal `xml:"http://www.scte.org/schemas/224 MatchSignal" json:"matchSignal,omitempty"`
-	MediaGuid        string       `xml:"-"` // used internally to track which media this point is part of
+	XMLName          xml.Name      `xml:"http://www.scte.org/schemas/224 MediaPoint" json:"-"`
+	Effective        *xsd.DateTime `xml:"effective,attr,omitempty" json:"effective,omitempty"`
+	Expires          *xsd.DateTime `xml:"expires,attr,omitempty" json:"expires,omitempty"`
+	MatchTime        *xsd.DateTime `xml:"matchTime,attr,omitempty" json:"matchTime,omitempty"`
+	MatchOffset      Duration      `xml:"matchOffset,attr,omitempty" json:"matchOffset,omitempty"`
+	Source           string        `xml:"source,attr,omitempty" json:"source,omitempty"`
+	ExpectedDuration Duration      `xml:"expectedDuration,attr,omitempty" json:"expectedDuration,omitempty"`
+	Order            *uint         `xml:"order,attr,omitempty" json:"order,omitempty"`
+	Reusable         bool          `xml:"reusable,attr,omitempty" json:"reusable,omitempty"`
+	Removes          []*Remove     `xml:"http://www.scte.org/schemas/224 Remove" json:"removes,omitempty"`
+	Applys           []*Apply      `xml:"http://www.scte.org/schemas/224 Apply" json:"applys,omitempty"`
+	MatchSignal      *MatchSignal  `xml:"http://www.scte.org/schemas/224 MatchSignal" json:"matchSignal,omitempty"`
+	MediaGuid        string        `xml:"-"` // used internally to track which media this point is part of
 }
 
 func (mp *MediaPoint) HasExplicitOrder() bool {
@@ -75,6 +77,7 @@ func (mp *MediaPoint) GetOrder() uint {
 type Metadata struct {
 	XMLName xml.Name     `xml:"http://www.scte.org/schemas/224 Metadata" json:"-"`
 	ADI30   *adi30.ADI30 `xml:"http://www.scte.org/schemas/236/2017/core ADI3" json:"-"`
+	ADI11   *adi11.ADI11 `xml:"ADI" json:"-"`
 	Nodes   []Any        `xml:",any" json:"values,omitempty"`
 }
 
@@ -95,6 +98,9 @@ type Any struct {
 	Namespace  NamespaceCleaner `xml:"xmlns,attr"`
 	Attributes []xml.Attr       `xml:",any,attr"`
 	Value      string           `xml:",innerxml" json:"value"`
+	// Typed holds the decoded value of a node whose name is registered with the
+	// extension package, and replaces Value when the node is encoded
+	Typed interface{} `xml:"-" json:"typed,omitempty"`
 }
 
 type Duration string
@@ -140,6 +146,9 @@ type AltID struct {
 	XMLName     xml.Name `xml:"http://www.scte.org/schemas/224 AltID" json:"-"`
 	Description string   `xml:"description,attr,omitempty" json:"description,omitempty"`
 	Value       string   `xml:",chardata" json:"value,omitempty"`
+	// Type is the type of a 2020 AltID, which the 2018 schema does not have. It
+	// is kept for the upgrade of a downgraded value, and never encoded.
+	Type string `xml:"-" json:"-"`
 }
 
 //Table 10
@@ -173,6 +182,9 @@ type MatchSignal struct {
 	Match           Match     `xml:"match,attr,omitempty" json:"match,omitempty"`
 	SignalTolerance Duration  `xml:"signalTolerance,attr,omitempty" json:"signalTolerance,omitempty"`
 	Assertions      []*Assert `xml:"http://www.scte.org/schemas/224 Assert,omitempty" json:"assertions,omitempty"`
+	// Schema is the schema of a 2020 MatchSignal, which the 2018 schema does not
+	// have. It is kept for the upgrade of a downgraded value, and never encoded.
+	Schema string `xml:"-" json:"-"`
 }
 
 type Match string
@@ -227,15 +239,15 @@ type SignalPointInsertionAction struct {
 }
 
 type SignalPoint struct {
-	Offset               Duration   `xml:"offset,attr,omitempty" json:"offset,omitempty"`
-	SegmentationEventId  string     `xml:"segmentationEventId,attr,omitempty" json:"segmentationEventId,omitempty"`
-	SegmentationDuration int64      `xml:"segmentationDuration,attr,omitempty" json:"segmentationDuration,omitempty"`
-	SegmentationTypeId   *uint      `xml:"segmentationTypeId,attr,omitempty" json:"segmentationTypeId,omitempty"`
-	SegmentationUpidType *uint      `xml:"segmentationUpidType,attr,omitempty" json:"segmentationUpidType,omitempty"`
-	SegmentationUpid     string     `xml:"segmentationUpid,attr,omitempty" json:"segmentationUpid,omitempty"`
-	RepeatInterval       Duration   `xml:"repeatInterval,attr,omitempty" json:"repeatInterval,omitempty"`
-	RepeatStart          *time.Time `xml:"repeatStart,attr,omitempty" json:"repeatStart,omitempty"`
-	RepeatStop           *time.Time `xml:"repeatStop,attr,omitempty" json:"repeatStop,omitempty"`
+	Offset               Duration      `xml:"offset,attr,omitempty" json:"offset,omitempty"`
+	SegmentationEventId  string        `xml:"segmentationEventId,attr,omitempty" json:"segmentationEventId,omitempty"`
+	SegmentationDuration int64         `xml:"segmentationDuration,attr,omitempty" json:"segmentationDuration,omitempty"`
+	SegmentationTypeId   *uint         `xml:"segmentationTypeId,attr,omitempty" json:"segmentationTypeId,omitempty"`
+	SegmentationUpidType *uint         `xml:"segmentationUpidType,attr,omitempty" json:"segmentationUpidType,omitempty"`
+	SegmentationUpid     string        `xml:"segmentationUpid,attr,omitempty" json:"segmentationUpid,omitempty"`
+	RepeatInterval       Duration      `xml:"repeatInterval,attr,omitempty" json:"repeatInterval,omitempty"`
+	RepeatStart          *xsd.DateTime `xml:"repeatStart,attr,omitempty" json:"repeatStart,omitempty"`
+	RepeatStop           *xsd.DateTime `xml:"repeatStop,attr,omitempty" json:"repeatStop,omitempty"`
 }
 
 //Table 13
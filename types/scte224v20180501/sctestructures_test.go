@@ -256,7 +256,7 @@ func TestSignalPointInsertion(t *testing.T) {
 				if time.Second*75 != repeatInterval {
 					t.Error("expected a 75 second interval rather than", repeatInterval)
 				}
-				totalRepeatTime := sp.RepeatStop.Sub(*sp.RepeatStart)
+				totalRepeatTime := sp.RepeatStop.Time().Sub(sp.RepeatStart.Time())
 				if time.Minute*15 != totalRepeatTime {
 					t.Error("expected repeats to last 15 minutes rather than", totalRepeatTime)
 				}
@@ -0,0 +1,65 @@
+package scte224v20180501
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+const signalPointInsertion = `<SignalPointInsertion xmlns="urn:scte:224:action" offset="PT10S">
+	<SignalPoint segmentationTypeId="52" segmentationUpidType="9" segmentationUpid="SIGNAL:abc" segmentationDuration="2700000"/>
+	<SignalPoint offset="PT1M" segmentationTypeId="53" repeatInterval="PT5M" repeatStop="2021-07-26T09:15:00Z"/>
+	<SignalPoint offset="PT5S" segmentationTypeId="16" repeatInterval="PT15M" repeatStart="2021-07-26T08:00:00Z"/>
+</SignalPointInsertion>`
+
+func TestExpandSignalPoints(t *testing.T) {
+	spi := &SignalPointInsertionAction{}
+	if err := xml.Unmarshal([]byte(signalPointInsertion), spi); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	trigger := time.Date(2021, 7, 26, 9, 0, 0, 0, time.UTC)
+	signals, err := spi.Expand(trigger, trigger.Add(30*time.Minute))
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	expected := []struct {
+		offset     time.Duration
+		typeID     uint
+		repetition int
+	}{
+		{10 * time.Second, 52, 0},
+		{70 * time.Second, 53, 0},
+		{370 * time.Second, 53, 1},
+		{670 * time.Second, 53, 2},
+		// the first time of the repeatStart series after 09:00:15
+		{15 * time.Minute, 16, 0},
+		{30 * time.Minute, 16, 1},
+	}
+	if len(signals) != len(expected) {
+		t.Log("Expected", len(expected), "signals, got", len(signals))
+		t.FailNow()
+	}
+	for i, e := range expected {
+		s := signals[i]
+		if s.Offset != e.offset || !s.At.Equal(trigger.Add(e.offset)) || *s.SignalPoint.SegmentationTypeId != e.typeID || s.Repetition != e.repetition {
+			t.Logf("Unexpected signal %d: %+v", i, s)
+			t.Fail()
+		}
+	}
+
+	if _, err := spi.Expand(trigger, time.Time{}); err != ErrUnboundedRepeat {
+		t.Log("Expected ErrUnboundedRepeat, got", err)
+		t.Fail()
+	}
+	spi.SignalPoints = spi.SignalPoints[:2]
+	if signals, err := spi.Expand(trigger, time.Time{}); nil != err || len(signals) != 4 {
+		t.Log("Expected repeatStop to bound the repetitions", signals, err)
+		t.Fail()
+	}
+	if signals, err := spi.Expand(trigger, trigger.Add(time.Second)); nil != err || len(signals) != 0 {
+		t.Log("Expected no signal before the horizon", signals, err)
+		t.Fail()
+	}
+}
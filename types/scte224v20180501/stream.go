@@ -0,0 +1,95 @@
+package scte224v20180501
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Comcast/scte224structs/internal/results"
+)
+
+const esniNamespace = "http://www.scte.org/schemas/224"
+
+//********************* Results Streaming *************************//
+
+// ResultsDecoder reads a Results document one top level entry at a time, so that
+// full-market responses can be processed without holding every Media in memory.
+type ResultsDecoder struct {
+	decoder *results.Decoder
+}
+
+// NewResultsDecoder returns a ResultsDecoder that reads a Results document of
+// this version of the schema from r.
+func NewResultsDecoder(r io.Reader) *ResultsDecoder {
+	return &ResultsDecoder{decoder: results.NewDecoder(r, esniNamespace, newResultsEntry)}
+}
+
+// Size returns the size attribute of the Results element, which is only known
+// once the first call to Next has read the start of the document.
+func (rd *ResultsDecoder) Size() int { return rd.decoder.Size() }
+
+// Next returns the next top level entry of the Results, one of *Media,
+// *MediaPoint, *Policy, *ViewingPolicy, *Audience or *Audit, and io.EOF once the
+// Results element has been closed. Unknown children are skipped.
+func (rd *ResultsDecoder) Next() (interface{}, error) { return rd.decoder.Next() }
+
+// Stream decodes the remaining entries on a separate goroutine and sends them on
+// the returned channel, which is closed at the end of the document, on the first
+// error, or when ctx is cancelled. The error channel then receives the reason, if
+// any, and is closed.
+func (rd *ResultsDecoder) Stream(ctx context.Context) (<-chan interface{}, <-chan error) {
+	return rd.decoder.Stream(ctx)
+}
+
+func newResultsEntry(local string) interface{} {
+	switch local {
+	case "Media":
+		return &Media{}
+	case "MediaPoint":
+		return &MediaPoint{}
+	case "Policy":
+		return &Policy{}
+	case "ViewingPolicy":
+		return &ViewingPolicy{}
+	case "Audience":
+		return &Audience{}
+	case "Audit":
+		return &Audit{}
+	}
+	return nil
+}
+
+// ResultsEncoder writes a Results document one entry at a time. The Results
+// element is opened by the first Encode and closed by Close.
+type ResultsEncoder struct {
+	encoder *results.Encoder
+}
+
+// NewResultsEncoder returns a ResultsEncoder that writes a Results document of
+// this version of the schema to w. Nothing is written until the first Encode or
+// Close.
+func NewResultsEncoder(w io.Writer) *ResultsEncoder {
+	return &ResultsEncoder{encoder: results.NewEncoder(w, esniNamespace)}
+}
+
+// Indent behaves like xml.Encoder.Indent.
+func (re *ResultsEncoder) Indent(prefix, indent string) { re.encoder.Indent(prefix, indent) }
+
+// SetSize sets the size attribute of the Results element. It has no effect once
+// the first entry has been encoded.
+func (re *ResultsEncoder) SetSize(size int) { re.encoder.SetSize(size) }
+
+// Encode writes v, which must be a *Media, *MediaPoint, *Policy, *ViewingPolicy,
+// *Audience or *Audit, as the next entry of the Results.
+func (re *ResultsEncoder) Encode(v interface{}) error {
+	switch v.(type) {
+	case *Media, *MediaPoint, *Policy, *ViewingPolicy, *Audience, *Audit:
+	default:
+		return fmt.Errorf("%T cannot be encoded as a Results entry", v)
+	}
+	return re.encoder.Encode(v)
+}
+
+// Close ends the Results element and flushes the output. A Results with no entries
+// is still written.
+func (re *ResultsEncoder) Close() error { return re.encoder.Close() }
@@ -0,0 +1,91 @@
+package scte224v20180501
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+const streamAudience = `<Audience xmlns="http://www.scte.org/schemas/224" id="nbcuni.com/audience/CH61" description="CH61" lastUpdated="2020-02-27T11:15:08.770408-08:00" match="ANY">
+  <Zip xmlns="urn:scte:224:audience">19103</Zip>
+</Audience>`
+
+func TestResultsStream(t *testing.T) {
+	var media Media
+	var viewingPolicy ViewingPolicy
+	var audience Audience
+	for raw, value := range map[string]interface{}{CALI_XML: &media, spi: &viewingPolicy, streamAudience: &audience} {
+		if err := xml.Unmarshal([]byte(raw), value); nil != err {
+			t.Log(err)
+			t.FailNow()
+		}
+	}
+	results := Results{
+		Size:           3,
+		Medias:         []*Media{&media},
+		ViewingPolicys: []*ViewingPolicy{&viewingPolicy},
+		Audiences:      []*Audience{&audience},
+	}
+	expected, marshalErr := xml.MarshalIndent(results, "", "  ")
+	if nil != marshalErr {
+		t.Log(marshalErr)
+		t.FailNow()
+	}
+
+	var out bytes.Buffer
+	encoder := NewResultsEncoder(&out)
+	encoder.Indent("", "  ")
+	entries, errs := NewResultsDecoder(bytes.NewReader(expected)).Stream(context.Background())
+	count := 0
+	for entry := range entries {
+		count++
+		if err := encoder.Encode(entry); nil != err {
+			t.Log(err)
+			t.Fail()
+		}
+	}
+	if err := <-errs; nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	encoder.SetSize(count)
+	if err := encoder.Close(); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+
+	if 3 != count {
+		t.Log("Expected 3 entries, found", count)
+		t.Fail()
+	}
+	// Size is only applied before the first entry
+	streamed := strings.Replace(out.String(), `<Results xmlns="http://www.scte.org/schemas/224">`, `<Results xmlns="http://www.scte.org/schemas/224" size="3">`, 1)
+	if string(expected) != streamed {
+		t.Log(streamed)
+		t.Log("did not match")
+		t.Log(string(expected))
+		t.Fail()
+	}
+}
+
+func TestResultsDecoderNamespace(t *testing.T) {
+	decoder := NewResultsDecoder(strings.NewReader(`<Results xmlns="http://www.scte.org/schemas/224/2015"><Audience id="a"/></Results>`))
+	if _, err := decoder.Next(); nil == err {
+		t.Log("2015 Results should not be read as 2018")
+		t.Fail()
+	}
+
+	decoder = NewResultsDecoder(strings.NewReader(`<Results xmlns="http://www.scte.org/schemas/224" size="1"><Audience id="a"/></Results>`))
+	entry, err := decoder.Next()
+	if audience, ok := entry.(*Audience); nil != err || !ok || "a" != audience.Id || 1 != decoder.Size() {
+		t.Log("Expected Audience a, found", entry, err)
+		t.Fail()
+	}
+	if _, err := decoder.Next(); io.EOF != err {
+		t.Log("Expected io.EOF, found", err)
+		t.Fail()
+	}
+}
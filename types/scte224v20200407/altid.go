@@ -0,0 +1,52 @@
+package scte224v20200407
+
+import "strings"
+
+// The AltID types the 2020 schema defines; other types are private, named with
+// the AltIDPrivatePrefix.
+const (
+	AltIDCallSign      = "CallSign"
+	AltIDEIDR          = "EIDR"
+	AltIDAdID          = "Ad-ID"
+	AltIDPrivatePrefix = "private:"
+)
+
+// IsPrivate reports whether the type of a is a private one.
+func (a *AltID) IsPrivate() bool {
+	return strings.HasPrefix(a.Type, AltIDPrivatePrefix)
+}
+
+// AltID returns the first AltID of the given type, or nil. The type "" finds
+// AltIDs without a type.
+func (idType *IdentifiableType) AltID(altIDType string) *AltID {
+	for _, altID := range idType.AltIDs {
+		if altID != nil && altID.Type == altIDType {
+			return altID
+		}
+	}
+	return nil
+}
+
+// AltIDValues returns the values of the AltIDs of the given type, in document
+// order, with surrounding whitespace removed.
+func (idType *IdentifiableType) AltIDValues(altIDType string) []string {
+	var values []string
+	for _, altID := range idType.AltIDs {
+		if altID != nil && altID.Type == altIDType {
+			values = append(values, strings.TrimSpace(altID.Value))
+		}
+	}
+	return values
+}
+
+// HasAltID reports whether there is an AltID of the given type and value,
+// ignoring the whitespace surrounding values.
+func (idType *IdentifiableType) HasAltID(altIDType, value string) bool {
+	value = strings.TrimSpace(value)
+	for _, v := range idType.AltIDValues(altIDType) {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package scte224v20200407
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAltIDAccessors(t *testing.T) {
+	var mp *MediaPoint
+	err := xml.Unmarshal([]byte(`<MediaPoint xmlns="http://www.scte.org/schemas/224" id="mp">
+	<AltID type="EIDR"> 10.5240/7791-8534-2C23-9030-8610-5 </AltID>
+	<AltID type="private:TMS">EP012345670003</AltID>
+	<AltID type="private:TMS">SH012345670000</AltID>
+	<AltID>untyped</AltID>
+</MediaPoint>`), &mp)
+	if !assert.Nil(t, err, "Error unmarshalling mediapoint") {
+		return
+	}
+
+	assert.Equal(t, " 10.5240/7791-8534-2C23-9030-8610-5 ", mp.AltID(AltIDEIDR).Value)
+	assert.True(t, mp.HasAltID(AltIDEIDR, "10.5240/7791-8534-2C23-9030-8610-5"))
+	assert.Equal(t, []string{"EP012345670003", "SH012345670000"}, mp.AltIDValues("private:TMS"))
+	assert.True(t, mp.AltID("private:TMS").IsPrivate())
+	assert.False(t, mp.AltID(AltIDEIDR).IsPrivate())
+	assert.Equal(t, "untyped", mp.AltID("").Value)
+	assert.Nil(t, mp.AltID(AltIDAdID))
+	assert.False(t, mp.HasAltID(AltIDCallSign, "untyped"))
+}
@@ -2,11 +2,12 @@ package scte224v20200407
 
 import (
 	"encoding/xml"
-	"time"
+	"log"
 
 	"github.com/Comcast/scte224structs/convert"
 	scte224_2015 "github.com/Comcast/scte224structs/types/scte224v20151115"
 	scte224_2018 "github.com/Comcast/scte224structs/types/scte224v20180501"
+	"github.com/Comcast/scte224structs/types/xsd"
 )
 
 //Table 11
@@ -76,7 +77,7 @@ func (vp *ViewingPolicy) Get2018() scte224_2018.ViewingPolicy {
 	}
 
 	if vp.SignalPointInsertion != nil {
-		signalPoints2018 := make([]*scte224_2018.SignalPoint, len(vp.SignalPointInsertion.SignalPoints))
+		signalPoints2018 := make([]*scte224_2018.SignalPoint, 0, len(vp.SignalPointInsertion.SignalPoints))
 		for _, signalPoint := range vp.SignalPointInsertion.SignalPoints {
 			if signalPoint == nil {
 				continue
@@ -102,12 +103,7 @@ func (vp *ViewingPolicy) Get2018() scte224_2018.ViewingPolicy {
 
 		vpActionProps2018 := make([]scte224_2018.Any, 0, len(vp.SignalPointInsertion.ActionProperty))
 		for _, vpActionProp := range vp.SignalPointInsertion.ActionProperty {
-			vpActionProps2018 = append(vpActionProps2018, scte224_2018.Any{
-				XMLName:    vpActionProp.XMLName,
-				Namespace:  scte224_2018.NamespaceCleaner(vpActionProp.Namespace),
-				Attributes: vpActionProp.Attributes,
-				Value:      vpActionProp.Value,
-			})
+			vpActionProps2018 = append(vpActionProps2018, vpActionProp.Get2018())
 		}
 
 		destination.SignalPointInsertion.ActionProperty = vpActionProps2018
@@ -120,6 +116,17 @@ func (vp *ViewingPolicy) Get2018() scte224_2018.ViewingPolicy {
 		}
 	}
 
+	// the 2018 schema has no Allocation, which is carried verbatim as an
+	// ActionProperty instead; UpgradeViewingPolicy restores it
+	if vp.Allocation != nil {
+		allocation2018, err := anyOf(vp.Allocation)
+		if err != nil {
+			log.Println(err)
+		} else {
+			destination.ActionProperty = append(destination.ActionProperty, allocation2018)
+		}
+	}
+
 	for _, action := range vp.ActionProperty {
 		destination.ActionProperty = append(destination.ActionProperty, action.Get2018())
 	}
@@ -195,15 +202,15 @@ type SignalPointInsertionAction struct {
 }
 
 type SignalPoint struct {
-	Offset               Duration   `xml:"offset,attr,omitempty" json:"offset,omitempty"`
-	SegmentationEventId  string     `xml:"segmentationEventId,attr,omitempty" json:"segmentationEventId,omitempty"`
-	SegmentationDuration int64      `xml:"segmentationDuration,attr,omitempty" json:"segmentationDuration,omitempty"`
-	SegmentationTypeId   *uint      `xml:"segmentationTypeId,attr,omitempty" json:"segmentationTypeId,omitempty"`
-	SegmentationUpidType *uint      `xml:"segmentationUpidType,attr,omitempty" json:"segmentationUpidType,omitempty"`
-	SegmentationUpid     string     `xml:"segmentationUpid,attr,omitempty" json:"segmentationUpid,omitempty"`
-	RepeatInterval       Duration   `xml:"repeatInterval,attr,omitempty" json:"repeatInterval,omitempty"`
-	RepeatStart          *time.Time `xml:"repeatStart,attr,omitempty" json:"repeatStart,omitempty"`
-	RepeatStop           *time.Time `xml:"repeatStop,attr,omitempty" json:"repeatStop,omitempty"`
+	Offset               Duration      `xml:"offset,attr,omitempty" json:"offset,omitempty"`
+	SegmentationEventId  string        `xml:"segmentationEventId,attr,omitempty" json:"segmentationEventId,omitempty"`
+	SegmentationDuration int64         `xml:"segmentationDuration,attr,omitempty" json:"segmentationDuration,omitempty"`
+	SegmentationTypeId   *uint         `xml:"segmentationTypeId,attr,omitempty" json:"segmentationTypeId,omitempty"`
+	SegmentationUpidType *uint         `xml:"segmentationUpidType,attr,omitempty" json:"segmentationUpidType,omitempty"`
+	SegmentationUpid     string        `xml:"segmentationUpid,attr,omitempty" json:"segmentationUpid,omitempty"`
+	RepeatInterval       Duration      `xml:"repeatInterval,attr,omitempty" json:"repeatInterval,omitempty"`
+	RepeatStart          *xsd.DateTime `xml:"repeatStart,attr,omitempty" json:"repeatStart,omitempty"`
+	RepeatStop           *xsd.DateTime `xml:"repeatStop,attr,omitempty" json:"repeatStop,omitempty"`
 }
 
 //Table 13
@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 	"testing"
 
+	scte224_2018 "github.com/Comcast/scte224structs/types/scte224v20180501"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -53,6 +54,19 @@ func TestViewingPolicyDowngrade_PPOStart(t *testing.T) {
 	assert.EqualValues(t, vpPPOStart, string(vp2018Marshaled), "Downgrade failed")
 }
 
+func TestViewingPolicyDowngradeSignalPoints(t *testing.T) {
+	var vp *ViewingPolicy
+	err := xml.Unmarshal([]byte(vpPPOStart), &vp)
+	assert.Nil(t, err, "Error unmarshalling viewingpolicy")
+	expected := len(vp.SignalPointInsertion.SignalPoints)
+	vp.SignalPointInsertion.SignalPoints = append(vp.SignalPointInsertion.SignalPoints, nil)
+
+	// the downgrade has one SignalPoint per SignalPoint, and none that are nil
+	signalPoints := vp.Get2018().SignalPointInsertion.SignalPoints
+	assert.Equal(t, expected, len(signalPoints))
+	assert.NotContains(t, signalPoints, (*scte224_2018.SignalPoint)(nil))
+}
+
 func TestAudienceDowngrade(t *testing.T) {
 	var aud Audience
 	err := xml.Unmarshal([]byte(aud2020Raw), &aud)
@@ -2,24 +2,25 @@ package scte224v20200407
 
 import (
 	"encoding/xml"
-	"time"
 
 	"github.com/Comcast/scte224structs/convert"
 	scte224_2015 "github.com/Comcast/scte224structs/types/scte224v20151115"
 	scte224_2018 "github.com/Comcast/scte224structs/types/scte224v20180501"
+	"github.com/Comcast/scte224structs/types/scte224v20180501/adi11"
 	"github.com/Comcast/scte224structs/types/scte224v20180501/adi30"
+	"github.com/Comcast/scte224structs/types/xsd"
 )
 
 // Structs for SCTE 224 2020 ESNI Objects.
 // Table 3
 type IdentifiableType struct {
-	Id          string     `xml:"id,attr,omitempty" json:"id,omitempty"`
-	Description string     `xml:"description,attr,omitempty" json:"description,omitempty"`
-	LastUpdated *time.Time `xml:"lastUpdated,attr,omitempty" json:"lastUpdated,omitempty"`
-	XMLBase     string     `xml:"xml:base,attr,omitempty" json:"-"`
-	AltIDs      []*AltID   `xml:"http://www.scte.org/schemas/224 AltID,omitempty" json:"altIDs,omitempty"`
-	Metadata    *Metadata  `xml:"http://www.scte.org/schemas/224 Metadata,omitempty" json:"metadata,omitempty"`
-	Ext         *Ext       `xml:"http://www.scte.org/schemas/224 Ext,omitempty" json:"ext,omitempty"`
+	Id          string        `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Description string        `xml:"description,attr,omitempty" json:"description,omitempty"`
+	LastUpdated *xsd.DateTime `xml:"lastUpdated,attr,omitempty" json:"lastUpdated,omitempty"`
+	XMLBase     string        `xml:"xml:base,attr,omitempty" json:"-"`
+	AltIDs      []*AltID      `xml:"http://www.scte.org/schemas/224 AltID,omitempty" json:"altIDs,omitempty"`
+	Metadata    *Metadata     `xml:"http://www.scte.org/schemas/224 Metadata,omitempty" json:"metadata,omitempty"`
+	Ext         *Ext          `xml:"http://www.scte.org/schemas/224 Ext,omitempty" json:"ext,omitempty"`
 }
 
 func (idType *IdentifiableType) Get2018() scte224_2018.IdentifiableType {
@@ -41,6 +42,7 @@ func (idType *IdentifiableType) Get2018() scte224_2018.IdentifiableType {
 			XMLName:     altID.XMLName,
 			Description: altID.Description,
 			Value:       altID.Value,
+			Type:        altID.Type,
 		}
 		destination.AltIDs = append(destination.AltIDs, altID2018)
 	}
@@ -49,6 +51,7 @@ func (idType *IdentifiableType) Get2018() scte224_2018.IdentifiableType {
 		metadata2018 := &scte224_2018.Metadata{
 			XMLName: idType.Metadata.XMLName,
 			ADI30:   idType.Metadata.ADI30, // pointer copy; this could lead to incoming value being mutated, but caller should expect it since this method has a pointer receiver
+			ADI11:   idType.Metadata.ADI11, // pointer copy, as above
 		}
 
 		for _, node := range idType.Metadata.Nodes {
@@ -63,7 +66,7 @@ func (idType *IdentifiableType) Get2018() scte224_2018.IdentifiableType {
 			XMLName: idType.Ext.XMLName,
 		}
 
-		for _, node := range idType.Metadata.Nodes {
+		for _, node := range idType.Ext.Nodes {
 			ext2018.Nodes = append(ext2018.Nodes, node.Get2018())
 		}
 
@@ -99,6 +102,7 @@ func (rt ReusableType) Get2015() scte224_2015.ReusableType {
 type Metadata struct {
 	XMLName xml.Name     `xml:"http://www.scte.org/schemas/224 Metadata" json:"-"`
 	ADI30   *adi30.ADI30 `xml:"http://www.scte.org/schemas/236/2017/core ADI3" json:"-"`
+	ADI11   *adi11.ADI11 `xml:"ADI" json:"-"`
 	Nodes   []Any        `xml:",any" json:"values,omitempty"`
 }
 
@@ -126,6 +130,9 @@ type Any struct {
 	Namespace  NamespaceCleaner `xml:"xmlns,attr"`
 	Attributes []xml.Attr       `xml:",any,attr"`
 	Value      string           `xml:",innerxml" json:"value"`
+	// Typed holds the decoded value of a node whose name is registered with the
+	// extension package, and replaces Value when the node is encoded
+	Typed interface{} `xml:"-" json:"typed,omitempty"`
 }
 
 func (any Any) Get2018() scte224_2018.Any {
@@ -134,6 +141,7 @@ func (any Any) Get2018() scte224_2018.Any {
 		Namespace:  scte224_2018.NamespaceCleaner(any.Namespace),
 		Attributes: any.Attributes,
 		Value:      any.Value,
+		Typed:      any.Typed,
 	}
 
 	return node2018
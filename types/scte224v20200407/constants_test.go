@@ -55,9 +55,11 @@ const vpPPOStart string = `<ViewingPolicy xmlns="http://www.scte.org/schemas/224
   </SignalPointInsertion>
 </ViewingPolicy>`
 
-// Same as "vp2020Raw" but without the additional "Allocation" action
+// Same as "vp2020Raw", with the additional "Allocation" action carried as an
+// ActionProperty
 const vp2018Raw string = `<ViewingPolicy xmlns="http://www.scte.org/schemas/224" id="test/program" description="test program" lastUpdated="2021-01-19T18:49:26.298986528Z">
     <Content xmlns="urn:scte:224:action">CONTENT</Content>
+    <Allocation xmlns="urn:scte:224:action" ownerType="PROVIDER" ownerName="Stu" duration="PT30S" ads="FreeWheel/MRM"><Slots xmlns="urn:scte:224:action"><Slot xmlns="urn:scte:224:action" duration="PT15S" offset="PT0S"><AdsReferenceId xmlns="urn:scte:224:action">98765</AdsReferenceId><AdsReferenceId xmlns="urn:scte:224:action">87654</AdsReferenceId><AdsReferenceId xmlns="urn:scte:224:action">54321</AdsReferenceId><AdsReferenceId xmlns="urn:scte:224:action">56343</AdsReferenceId><SlotRules xmlns="urn:scte:224:action"><SlotRule xmlns="urn:scte:224:action" rule="exclusionWithInference"><Parameter xmlns="urn:scte:224:action" parameterName="advertiser">advertiser_external_id</Parameter></SlotRule></SlotRules></Slot><Slot xmlns="urn:scte:224:action" duration="PT30S" offset="PT15S"><AdsReferenceId xmlns="urn:scte:224:action">123</AdsReferenceId></Slot><Slot xmlns="urn:scte:224:action" duration="PT15S" offset="PT45S"></Slot></Slots></Allocation>
 </ViewingPolicy>`
 
 const aud2020Raw string = `<Audience xmlns="http://www.scte.org/schemas/224" id="foo/audience/any.all" description="Users anywhere on any device" lastUpdated="2021-03-24T03:27:05Z" match="ALL">
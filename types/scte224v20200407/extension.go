@@ -0,0 +1,192 @@
+package scte224v20200407
+
+import (
+	"encoding/json"
+	"encoding/xml"
+
+	"github.com/Comcast/scte224structs/extension"
+	"github.com/Comcast/scte224structs/internal/xmlns"
+	"github.com/Comcast/scte224structs/types/scte224v20180501/adi11"
+	"github.com/Comcast/scte224structs/types/scte224v20180501/adi30"
+)
+
+//********************* Extension Nodes *************************//
+//
+// Metadata and Ext decode the nodes whose name is registered with the extension
+// package into typed values, which are kept in the Typed field of an Any and
+// encoded back from it. Other nodes are kept as raw XML, as before.
+
+var (
+	adi30Start = xml.StartElement{Name: xml.Name{Space: adi30.CoreNamespace, Local: "ADI3"}}
+	// adi11Start starts ADI 1.1 metadata, which has no namespace, within the
+	// namespaced Metadata element.
+	adi11Start = xml.StartElement{
+		Name: xml.Name{Local: "ADI"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: ""}},
+	}
+)
+
+// UnmarshalXML decodes ADI metadata into ADI30 and ADI11, and the other children
+// into Nodes.
+func (m *Metadata) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	m.XMLName = start.Name
+	return decodeChildren(d, func(child xml.StartElement) error {
+		switch {
+		case child.Name.Space == adi30.CoreNamespace && child.Name.Local == "ADI3":
+			if m.ADI30 == nil {
+				m.ADI30 = &adi30.ADI30{}
+			}
+			return d.DecodeElement(m.ADI30, &child)
+		case child.Name.Space == "" && child.Name.Local == "ADI":
+			if m.ADI11 == nil {
+				m.ADI11 = &adi11.ADI11{}
+			}
+			return d.DecodeElement(m.ADI11, &child)
+		}
+		node, err := decodeNode(d, child)
+		m.Nodes = append(m.Nodes, node)
+		return err
+	})
+}
+
+// MarshalXML encodes Metadata as encoding/xml would, except for typed nodes,
+// which are encoded from their value.
+func (m *Metadata) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = xml.Name{Space: esniNamespace, Local: "Metadata"}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	if m.ADI30 != nil {
+		if err := e.EncodeElement(m.ADI30, adi30Start); err != nil {
+			return err
+		}
+	}
+	if m.ADI11 != nil {
+		if err := e.EncodeElement(m.ADI11, adi11Start); err != nil {
+			return err
+		}
+	}
+	if err := encodeNodes(e, m.Nodes); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+// UnmarshalXML decodes the children of Ext into Nodes.
+func (ext *Ext) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	ext.XMLName = start.Name
+	return decodeChildren(d, func(child xml.StartElement) error {
+		node, err := decodeNode(d, child)
+		ext.Nodes = append(ext.Nodes, node)
+		return err
+	})
+}
+
+// MarshalXML encodes Ext as encoding/xml would, except for typed nodes, which are
+// encoded from their value.
+func (ext *Ext) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = xml.Name{Space: esniNamespace, Local: "Ext"}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	if err := encodeNodes(e, ext.Nodes); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+// decodeChildren calls child for each element below the current one, which must
+// consume it, and returns at the end of the current element.
+func decodeChildren(d *xml.Decoder, child func(start xml.StartElement) error) error {
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if err := child(t); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// UnmarshalXML decodes an element as encoding/xml would, except that namespace
+// declarations are kept as they were written; see xmlns.LiteralAttrs.
+func (node *Any) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	// a type without methods, as calling DecodeElement on node would recurse
+	type plainAny Any
+	var plain plainAny
+	if err := d.DecodeElement(&plain, &start); err != nil {
+		return err
+	}
+	plain.Attributes = xmlns.LiteralAttrs(plain.Attributes)
+	*node = Any(plain)
+	return nil
+}
+
+// UnmarshalJSON decodes a node as encoding/json would, except that a typed value
+// is decoded into the type registered for the name of the node, as UnmarshalXML
+// does, rather than into a map. Without a registered type, the typed value is
+// kept as the json.RawMessage it was written as, which encodes back unchanged.
+func (node *Any) UnmarshalJSON(data []byte) error {
+	type plainAny Any
+	var plain struct {
+		plainAny
+		Typed json.RawMessage `json:"typed,omitempty"`
+	}
+	if err := json.Unmarshal(data, &plain); err != nil {
+		return err
+	}
+	*node = Any(plain.plainAny)
+	node.Typed = nil
+	if len(plain.Typed) == 0 || string(plain.Typed) == "null" {
+		return nil
+	}
+	typed, ok := extension.New(node.XMLName)
+	if !ok {
+		node.Typed = plain.Typed
+		return nil
+	}
+	if err := json.Unmarshal(plain.Typed, typed); err != nil {
+		return err
+	}
+	node.Typed = typed
+	return nil
+}
+
+// decodeNode decodes an element into the type registered for its name, if any.
+func decodeNode(d *xml.Decoder, start xml.StartElement) (Any, error) {
+	if typed, ok := extension.New(start.Name); ok {
+		err := d.DecodeElement(typed, &start)
+		// the attributes the type has no field for are written back with it
+		attrs := xmlns.LiteralAttrs(xmlns.Unmodeled(start.Attr, typed))
+		return Any{XMLName: start.Name, Attributes: attrs, Typed: typed}, err
+	}
+	var node Any
+	err := d.DecodeElement(&node, &start)
+	return node, err
+}
+
+// encodeNodes writes nodes the way encoding/xml writes an ",any" field named Nodes.
+func encodeNodes(e *xml.Encoder, nodes []Any) error {
+	for i := range nodes {
+		node := &nodes[i]
+		name := node.XMLName
+		if name.Local == "" {
+			name = xml.Name{Local: "Nodes"}
+		}
+		var v interface{} = node
+		start := xml.StartElement{Name: name}
+		if node.Typed != nil {
+			v, start.Attr = node.Typed, node.Attributes
+		}
+		if err := e.EncodeElement(v, start); err != nil {
+			return err
+		}
+	}
+	return nil
+}
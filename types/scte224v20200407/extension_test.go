@@ -0,0 +1,133 @@
+package scte224v20200407
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/Comcast/scte224structs/extension"
+	"github.com/stretchr/testify/assert"
+)
+
+type rightsWindow struct {
+	Start   string   `xml:"start,attr"`
+	End     string   `xml:"end,attr"`
+	Regions []string `xml:"urn:example:rights Region"`
+}
+
+var rightsWindowName = xml.Name{Space: "urn:example:rights", Local: "RightsWindow"}
+
+const extensionMediaPointRaw = `<MediaPoint xmlns="http://www.scte.org/schemas/224" xmlns:r="urn:example:rights" id="mp">
+  <Ext><r:RightsWindow start="2021-01-01T00:00:00Z" end="2021-01-02T00:00:00Z"><r:Region>US</r:Region></r:RightsWindow></Ext>
+  <Metadata><r:RightsWindow start="2021-02-01T00:00:00Z" end="2021-02-02T00:00:00Z"><r:Region>CA</r:Region><r:Region>MX</r:Region></r:RightsWindow><League xmlns="urn:example:league">NFL</League></Metadata>
+</MediaPoint>`
+
+func TestTypedExtensionNodes(t *testing.T) {
+	extension.Register(rightsWindowName, rightsWindow{})
+	defer extension.Unregister(rightsWindowName)
+
+	var mp MediaPoint
+	assert.Nil(t, xml.Unmarshal([]byte(extensionMediaPointRaw), &mp))
+	assert.Equal(t, 1, len(mp.Ext.Nodes))
+	assert.Equal(t, &rightsWindow{Start: "2021-01-01T00:00:00Z", End: "2021-01-02T00:00:00Z", Regions: []string{"US"}}, mp.Ext.Nodes[0].Typed)
+	assert.Equal(t, 2, len(mp.Metadata.Nodes))
+	assert.Equal(t, []string{"CA", "MX"}, mp.Metadata.Nodes[0].Typed.(*rightsWindow).Regions)
+	// unregistered nodes are kept as raw XML
+	assert.Nil(t, mp.Metadata.Nodes[1].Typed)
+	assert.Equal(t, "NFL", mp.Metadata.Nodes[1].Value)
+
+	// typed values are encoded from the value, so changes are kept
+	mp.Metadata.Nodes[0].Typed.(*rightsWindow).Regions = []string{"CA"}
+	marshaled, err := xml.Marshal(&mp)
+	assert.Nil(t, err)
+	assert.Equal(t, `<MediaPoint xmlns="http://www.scte.org/schemas/224" id="mp">`+
+		`<Metadata xmlns="http://www.scte.org/schemas/224"><RightsWindow xmlns="urn:example:rights" start="2021-02-01T00:00:00Z" end="2021-02-02T00:00:00Z"><Region xmlns="urn:example:rights">CA</Region></RightsWindow><League xmlns="urn:example:league">NFL</League></Metadata>`+
+		`<Ext xmlns="http://www.scte.org/schemas/224"><RightsWindow xmlns="urn:example:rights" start="2021-01-01T00:00:00Z" end="2021-01-02T00:00:00Z"><Region xmlns="urn:example:rights">US</Region></RightsWindow></Ext>`+
+		`</MediaPoint>`, string(marshaled))
+
+	// the fast encoder hands typed nodes to encoding/xml
+	assertFastMarshal(t, &mp)
+
+	// typed values are decoded from JSON into their registered type as well
+	asJSON, err := json.Marshal(&mp)
+	assert.Nil(t, err)
+	var fromJSON MediaPoint
+	assert.Nil(t, json.Unmarshal(asJSON, &fromJSON))
+	assert.Equal(t, mp.Ext.Nodes[0].Typed, fromJSON.Ext.Nodes[0].Typed)
+	again, err := xml.Marshal(&fromJSON)
+	assert.Nil(t, err)
+	assert.Equal(t, string(marshaled), string(again))
+
+	// typed values survive downgrades
+	mp2018 := mp.Get2018()
+	assert.Equal(t, mp.Metadata.Nodes[0].Typed, mp2018.Metadata.Nodes[0].Typed)
+	mp2015 := mp.Get2015()
+	assert.Equal(t, mp.Metadata.Nodes[0].Typed, mp2015.Metadata.Nodes[0].Typed)
+}
+
+func TestTypedNodeAttributes(t *testing.T) {
+	extension.Register(rightsWindowName, rightsWindow{})
+	defer extension.Unregister(rightsWindowName)
+
+	const window = `<RightsWindow xmlns="urn:example:rights" id="w1" start="2021-01-01T00:00:00Z" xmlns:x="urn:example:x" x:note="a" end="2021-01-02T00:00:00Z">` +
+		`<Region xmlns="urn:example:rights">US</Region></RightsWindow>`
+	raw := `<MediaPoint xmlns="http://www.scte.org/schemas/224" id="mp"><Ext>` + window + `</Ext></MediaPoint>`
+	var mp MediaPoint
+	assert.Nil(t, xml.Unmarshal([]byte(raw), &mp))
+	node := mp.Ext.Nodes[0]
+	assert.Equal(t, "US", node.Typed.(*rightsWindow).Regions[0])
+	// the attributes the type has no field for are kept, with their prefixes
+	assert.Equal(t, []xml.Attr{
+		{Name: xml.Name{Local: "id"}, Value: "w1"},
+		{Name: xml.Name{Local: "xmlns:x"}, Value: "urn:example:x"},
+		{Name: xml.Name{Local: "x:note"}, Value: "a"},
+	}, node.Attributes)
+
+	// and are written back before those of the type
+	marshaled, err := xml.Marshal(&mp)
+	assert.Nil(t, err)
+	assert.Equal(t, `<MediaPoint xmlns="http://www.scte.org/schemas/224" id="mp"><Ext xmlns="http://www.scte.org/schemas/224">`+
+		`<RightsWindow xmlns="urn:example:rights" id="w1" xmlns:x="urn:example:x" x:note="a" start="2021-01-01T00:00:00Z" end="2021-01-02T00:00:00Z">`+
+		`<Region xmlns="urn:example:rights">US</Region></RightsWindow></Ext></MediaPoint>`, string(marshaled))
+	assertFastMarshal(t, &mp)
+
+	var again MediaPoint
+	assert.Nil(t, xml.Unmarshal(marshaled, &again))
+	assert.Equal(t, mp, again)
+
+	// a typed node decoded from JSON without its type registered is kept as
+	// written rather than failing the document
+	asJSON, err := json.Marshal(&mp)
+	assert.Nil(t, err)
+	extension.Unregister(rightsWindowName)
+	var fromJSON MediaPoint
+	assert.Nil(t, json.Unmarshal(asJSON, &fromJSON))
+	assert.IsType(t, json.RawMessage{}, fromJSON.Ext.Nodes[0].Typed)
+	assert.Equal(t, node.Attributes, fromJSON.Ext.Nodes[0].Attributes)
+	reencoded, err := json.Marshal(&fromJSON)
+	assert.Nil(t, err)
+	assert.Equal(t, string(asJSON), string(reencoded))
+}
+
+func TestRawNodeNamespaces(t *testing.T) {
+	const note = `<Note xmlns="urn:example" xmlns:a="urn:a/ns" a:kind="x">keep <a:b>raw</a:b></Note>`
+	raw := `<Policy xmlns="http://www.scte.org/schemas/224" id="p"><Ext>` + note + `</Ext></Policy>`
+	var policy Policy
+	assert.Nil(t, xml.Unmarshal([]byte(raw), &policy))
+	// the declaration and the attribute using its prefix are kept as written
+	assert.Equal(t, []xml.Attr{
+		{Name: xml.Name{Local: "xmlns:a"}, Value: "urn:a/ns"},
+		{Name: xml.Name{Local: "a:kind"}, Value: "x"},
+	}, policy.Ext.Nodes[0].Attributes)
+
+	marshaled, err := xml.Marshal(&policy)
+	assert.Nil(t, err)
+	assert.Contains(t, string(marshaled), note)
+	assertFastMarshal(t, &policy)
+
+	var again Policy
+	assert.Nil(t, xml.Unmarshal(marshaled, &again))
+	remarshaled, err := xml.Marshal(&again)
+	assert.Nil(t, err)
+	assert.Equal(t, string(marshaled), string(remarshaled))
+}
@@ -0,0 +1,827 @@
+package scte224v20200407
+
+import (
+	"encoding/xml"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/Comcast/scte224structs/types/xsd"
+)
+
+//********************* Fast Marshaling *************************//
+//
+// The AppendXML methods below write the same bytes as xml.Marshal, field for
+// field and namespace declaration for namespace declaration, without walking the
+// structs through reflection. They are meant for services that re-marshal the
+// same objects at a high rate; decoding still goes through encoding/xml.
+//
+// Anything added to the structs above has to be added here as well, in field
+// order, or the two encodings will drift apart. fastxml_test.go compares them.
+
+const (
+	xlinkNamespace  = "http://www.w3.org/1999/xlink"
+	actionNamespace = "urn:scte:224:action"
+	xmlNamespace    = "http://www.w3.org/XML/1998/namespace"
+)
+
+// errSlowPath is returned internally when the output of encoding/xml cannot be
+// reproduced exactly, in which case the whole value is marshaled by encoding/xml.
+var errSlowPath = errors.New("scte224: fast marshaling not possible")
+
+// AppendXML appends the XML encoding of m to b. The result is identical to xml.Marshal(m).
+func (m *Media) AppendXML(b []byte) ([]byte, error) {
+	return appendXML(b, m, func(w *xmlWriter) error { return w.media(m) })
+}
+
+// AppendXML appends the XML encoding of mp to b. The result is identical to xml.Marshal(mp).
+func (mp *MediaPoint) AppendXML(b []byte) ([]byte, error) {
+	return appendXML(b, mp, func(w *xmlWriter) error { return w.mediaPoint(mp) })
+}
+
+// AppendXML appends the XML encoding of p to b. The result is identical to xml.Marshal(p).
+func (p *Policy) AppendXML(b []byte) ([]byte, error) {
+	return appendXML(b, p, func(w *xmlWriter) error { return w.policy(p) })
+}
+
+// AppendXML appends the XML encoding of vp to b. The result is identical to xml.Marshal(vp).
+func (vp *ViewingPolicy) AppendXML(b []byte) ([]byte, error) {
+	return appendXML(b, vp, func(w *xmlWriter) error { return w.viewingPolicy(vp) })
+}
+
+// AppendXML appends the XML encoding of aud to b. The result is identical to xml.Marshal(aud).
+func (aud *Audience) AppendXML(b []byte) ([]byte, error) {
+	return appendXML(b, aud, func(w *xmlWriter) error { return w.audience(aud) })
+}
+
+// AppendXML appends the XML encoding of audit to b. The result is identical to xml.Marshal(audit).
+func (audit *Audit) AppendXML(b []byte) ([]byte, error) {
+	return appendXML(b, audit, func(w *xmlWriter) error { return w.audit(audit) })
+}
+
+// AppendXML appends the XML encoding of results to b. The result is identical to xml.Marshal(results).
+func (results *Results) AppendXML(b []byte) ([]byte, error) {
+	return appendXML(b, results, func(w *xmlWriter) error { return w.results(results) })
+}
+
+func appendXML(b []byte, v interface{}, write func(w *xmlWriter) error) ([]byte, error) {
+	w := xmlWriter{buf: b}
+	err := write(&w)
+	if err == errSlowPath {
+		var raw []byte
+		if raw, err = xml.Marshal(v); err == nil {
+			return append(b, raw...), nil
+		}
+	}
+	if err != nil {
+		return b, err
+	}
+	return w.buf, nil
+}
+
+// quirks are the behaviors of encoding/xml that changed between Go releases.
+type quirks struct {
+	// undeclaresNamespace is set when an element without a namespace, named by an
+	// untagged XMLName field, gets xmlns="" below a namespaced parent
+	undeclaresNamespace bool
+	// escapesXMLPrefix is set when attribute prefixes starting with "xml" get a
+	// leading "_"
+	escapesXMLPrefix bool
+}
+
+var (
+	probeOnce sync.Once
+	probed    quirks
+)
+
+// encoderQuirks asks the encoding/xml in use how it handles the cases above.
+func encoderQuirks() quirks {
+	probeOnce.Do(func() {
+		probe := &Ext{Nodes: []Any{{
+			XMLName:    xml.Name{Local: "p"},
+			Attributes: []xml.Attr{{Name: xml.Name{Space: "urn:probe/xmlns", Local: "a"}}},
+		}}}
+		raw, err := xml.Marshal(probe)
+		if err != nil {
+			return
+		}
+		probed.undeclaresNamespace = strings.Contains(string(raw), ` xmlns=""`)
+		probed.escapesXMLPrefix = strings.Contains(string(raw), "xmlns:_xmlns=")
+	})
+	return probed
+}
+
+// nsBinding is an attribute namespace prefix declared on an open element.
+type nsBinding struct {
+	prefix string
+	url    string
+}
+
+// xmlWriter mirrors the printer of encoding/xml: every element with a namespace
+// declares it as the default namespace, and attribute namespaces get a prefix
+// derived from the namespace URL that stays in scope until the declaring element
+// is closed.
+type xmlWriter struct {
+	buf      []byte
+	bindings []nsBinding
+	marks    []int
+	seq      int
+	// delegated is set once a subtree has been written by encoding/xml, whose
+	// prefix counter is no longer known
+	delegated bool
+}
+
+func (w *xmlWriter) start(space, local string) {
+	w.marks = append(w.marks, len(w.bindings))
+	w.buf = append(w.buf, '<')
+	w.buf = append(w.buf, local...)
+	if space != "" {
+		w.buf = append(w.buf, ` xmlns="`...)
+		w.buf = appendEscaped(w.buf, space)
+		w.buf = append(w.buf, '"')
+	}
+}
+
+func (w *xmlWriter) closeStart() {
+	w.buf = append(w.buf, '>')
+}
+
+func (w *xmlWriter) end(local string) {
+	w.buf = append(w.buf, "</"...)
+	w.buf = append(w.buf, local...)
+	w.buf = append(w.buf, '>')
+	mark := w.marks[len(w.marks)-1]
+	w.marks = w.marks[:len(w.marks)-1]
+	w.bindings = w.bindings[:mark]
+}
+
+// text writes an element holding nothing but character data.
+func (w *xmlWriter) text(space, local, text string) {
+	w.start(space, local)
+	w.closeStart()
+	w.buf = appendEscaped(w.buf, text)
+	w.end(local)
+}
+
+func (w *xmlWriter) attr(space, local, value string) error {
+	w.buf = append(w.buf, ' ')
+	if space != "" {
+		prefix, err := w.attrPrefix(space)
+		if err != nil {
+			return err
+		}
+		w.buf = append(w.buf, prefix...)
+		w.buf = append(w.buf, ':')
+	}
+	w.buf = append(w.buf, local...)
+	w.buf = append(w.buf, `="`...)
+	w.buf = appendEscaped(w.buf, value)
+	w.buf = append(w.buf, '"')
+	return nil
+}
+
+func (w *xmlWriter) stringAttr(local, value string) {
+	if value != "" {
+		w.attr("", local, value)
+	}
+}
+
+// timeAttr writes a dateTime in its lexical form, even when it is empty, as
+// encoding/xml does for text marshalers.
+func (w *xmlWriter) timeAttr(local string, value *xsd.DateTime) {
+	if value != nil {
+		w.attr("", local, value.String())
+	}
+}
+
+func (w *xmlWriter) uintAttr(local string, value *uint) {
+	if value != nil {
+		w.attr("", local, strconv.FormatUint(uint64(*value), 10))
+	}
+}
+
+func (w *xmlWriter) boolAttr(local string, value bool) {
+	if value {
+		w.attr("", local, "true")
+	}
+}
+
+// attrPrefix follows the prefix selection of encoding/xml.
+func (w *xmlWriter) attrPrefix(url string) (string, error) {
+	for i := len(w.bindings) - 1; i >= 0; i-- {
+		if w.bindings[i].url == url {
+			return w.bindings[i].prefix, nil
+		}
+	}
+	if url == xmlNamespace {
+		return "xml", nil
+	}
+
+	prefix := defaultPrefix(url)
+	if w.prefixBound(prefix) {
+		if w.delegated {
+			return "", errSlowPath
+		}
+		for w.seq++; ; w.seq++ {
+			if id := prefix + "_" + strconv.Itoa(w.seq); !w.prefixBound(id) {
+				prefix = id
+				break
+			}
+		}
+	}
+
+	w.bindings = append(w.bindings, nsBinding{prefix: prefix, url: url})
+	w.buf = append(w.buf, "xmlns:"...)
+	w.buf = append(w.buf, prefix...)
+	w.buf = append(w.buf, `="`...)
+	w.buf = appendEscaped(w.buf, url)
+	w.buf = append(w.buf, `" `...)
+	return prefix, nil
+}
+
+func (w *xmlWriter) prefixBound(prefix string) bool {
+	for _, binding := range w.bindings {
+		if binding.prefix == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultPrefix returns the prefix encoding/xml picks for url when it is free:
+// the last path segment, or "_" when that is not an XML name, with names
+// starting with "xml" escaped by a leading "_" where the toolchain does so.
+func defaultPrefix(url string) string {
+	prefix := strings.TrimRight(url, "/")
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		prefix = prefix[i+1:]
+	}
+	if name, ascii := isASCIIName(prefix); !ascii {
+		// leave the unicode name tables to encoding/xml
+		prefix = encoderPrefix(url)
+	} else {
+		if !name {
+			prefix = "_"
+		}
+		if len(prefix) >= 3 && strings.EqualFold(prefix[:3], "xml") && encoderQuirks().escapesXMLPrefix {
+			prefix = "_" + prefix
+		}
+	}
+	return prefix
+}
+
+// isASCIIName reports whether s is a colon-free XML name, as long as s is ASCII.
+func isASCIIName(s string) (name, ascii bool) {
+	name = s != ""
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= utf8.RuneSelf:
+			return false, false
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', c == '_':
+		case i > 0 && ('0' <= c && c <= '9' || c == '-' || c == '.'):
+		default:
+			name = false
+		}
+	}
+	return name, true
+}
+
+// encoderPrefix asks encoding/xml which prefix it picks for an attribute in url.
+func encoderPrefix(url string) string {
+	var sb strings.Builder
+	encoder := xml.NewEncoder(&sb)
+	start := xml.StartElement{Name: xml.Name{Local: "a"}, Attr: []xml.Attr{{Name: xml.Name{Space: url, Local: "a"}}}}
+	if encoder.EncodeToken(start) != nil || encoder.Flush() != nil {
+		return "_"
+	}
+	// <a xmlns:PREFIX="url" PREFIX:a="">
+	prefix := strings.TrimPrefix(sb.String(), "<a xmlns:")
+	return prefix[:strings.IndexByte(prefix, '=')]
+}
+
+// appendEscaped escapes s the way encoding/xml escapes attribute values and character data.
+func appendEscaped(b []byte, s string) []byte {
+	last := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= 0x20 && c < utf8.RuneSelf && c != '"' && c != '\'' && c != '&' && c != '<' && c != '>' {
+			i++
+			continue
+		}
+		r, width := utf8.DecodeRuneInString(s[i:])
+		i += width
+		var esc string
+		switch r {
+		case '"':
+			esc = "&#34;"
+		case '\'':
+			esc = "&#39;"
+		case '&':
+			esc = "&amp;"
+		case '<':
+			esc = "&lt;"
+		case '>':
+			esc = "&gt;"
+		case '\t':
+			esc = "&#x9;"
+		case '\n':
+			esc = "&#xA;"
+		case '\r':
+			esc = "&#xD;"
+		default:
+			if !isInCharacterRange(r) || (r == utf8.RuneError && width == 1) {
+				esc = "\uFFFD"
+				break
+			}
+			continue
+		}
+		b = append(b, s[last:i-width]...)
+		b = append(b, esc...)
+		last = i
+	}
+	return append(b, s[last:]...)
+}
+
+func isInCharacterRange(r rune) bool {
+	return r == 0x09 ||
+		r == 0x0A ||
+		r == 0x0D ||
+		r >= 0x20 && r <= 0xD7FF ||
+		r >= 0xE000 && r <= 0xFFFD ||
+		r >= 0x10000 && r <= 0x10FFFF
+}
+
+func (w *xmlWriter) identifiableAttrs(idType *IdentifiableType) error {
+	w.stringAttr("id", idType.Id)
+	w.stringAttr("description", idType.Description)
+	w.timeAttr("lastUpdated", idType.LastUpdated)
+	w.stringAttr("xml:base", idType.XMLBase)
+	return nil
+}
+
+func (w *xmlWriter) reusableAttrs(rt *ReusableType) error {
+	if err := w.identifiableAttrs(&rt.IdentifiableType); err != nil {
+		return err
+	}
+	if rt.XLinkHRef != "" {
+		return w.attr(xlinkNamespace, "href", rt.XLinkHRef)
+	}
+	return nil
+}
+
+func (w *xmlWriter) identifiableChildren(idType *IdentifiableType) error {
+	for _, altID := range idType.AltIDs {
+		if altID == nil {
+			continue
+		}
+		w.start(esniNamespace, "AltID")
+		w.stringAttr("description", altID.Description)
+		w.stringAttr("type", altID.Type)
+		w.closeStart()
+		w.buf = appendEscaped(w.buf, altID.Value)
+		w.end("AltID")
+	}
+	if idType.Metadata != nil {
+		if err := w.metadata(idType.Metadata); err != nil {
+			return err
+		}
+	}
+	if idType.Ext != nil {
+		w.start(esniNamespace, "Ext")
+		w.closeStart()
+		if err := w.anys("Nodes", idType.Ext.Nodes); err != nil {
+			return err
+		}
+		w.end("Ext")
+	}
+	return nil
+}
+
+func (w *xmlWriter) metadata(metadata *Metadata) error {
+	w.start(esniNamespace, "Metadata")
+	w.closeStart()
+	if metadata.ADI30 != nil {
+		if err := w.delegate(metadata.ADI30, adi30Start); err != nil {
+			return err
+		}
+	}
+	if metadata.ADI11 != nil {
+		if err := w.delegate(metadata.ADI11, adi11Start); err != nil {
+			return err
+		}
+	}
+	if err := w.anys("Nodes", metadata.Nodes); err != nil {
+		return err
+	}
+	w.end("Metadata")
+	return nil
+}
+
+// delegate hands ADI metadata to encoding/xml, to be written as Metadata.MarshalXML
+// writes it, starting with start. Its output only matches what the
+// enclosing encoder would have written when no attribute prefixes are in scope
+// and none have been renamed so far.
+func (w *xmlWriter) delegate(adi interface{}, start xml.StartElement) error {
+	if len(w.bindings) > 0 || w.seq > 0 {
+		return errSlowPath
+	}
+	w.delegated = true
+	buf := appendBuffer{buf: w.buf}
+	if err := xml.NewEncoder(&buf).EncodeElement(adi, start); err != nil {
+		return err
+	}
+	w.buf = buf.buf
+	return nil
+}
+
+// appendBuffer lets encoding/xml write straight into the output slice.
+type appendBuffer struct {
+	buf []byte
+}
+
+func (ab *appendBuffer) Write(p []byte) (int, error) {
+	ab.buf = append(ab.buf, p...)
+	return len(p), nil
+}
+
+// anys writes the nodes of an ",any" field; nodes without a name are named after the field.
+func (w *xmlWriter) anys(field string, nodes []Any) error {
+	for i := range nodes {
+		node := &nodes[i]
+		if node.Typed != nil {
+			// typed extension nodes are encoded by encoding/xml
+			return errSlowPath
+		}
+		name := node.XMLName
+		if name.Local == "" {
+			name = xml.Name{Local: field}
+		}
+		w.start(name.Space, name.Local)
+		for _, attr := range node.Attributes {
+			if attr.Name.Local == "" {
+				continue
+			}
+			if err := w.attr(attr.Name.Space, attr.Name.Local, attr.Value); err != nil {
+				return err
+			}
+		}
+		if name.Space == "" && encoderQuirks().undeclaresNamespace {
+			// every element enclosing an Any is namespaced
+			w.buf = append(w.buf, ` xmlns=""`...)
+		}
+		w.closeStart()
+		w.buf = append(w.buf, node.Value...)
+		w.end(name.Local)
+	}
+	return nil
+}
+
+func (w *xmlWriter) media(m *Media) error {
+	if m == nil {
+		return nil
+	}
+	w.start(esniNamespace, "Media")
+	if err := w.reusableAttrs(&m.ReusableType); err != nil {
+		return err
+	}
+	w.timeAttr("effective", m.Effective)
+	w.timeAttr("expires", m.Expires)
+	w.stringAttr("source", m.Source)
+	w.closeStart()
+
+	if err := w.identifiableChildren(&m.IdentifiableType); err != nil {
+		return err
+	}
+	for _, mp := range m.MediaPoints {
+		if err := w.mediaPoint(mp); err != nil {
+			return err
+		}
+	}
+	w.end("Media")
+	return nil
+}
+
+func (w *xmlWriter) mediaPoint(mp *MediaPoint) error {
+	if mp == nil {
+		return nil
+	}
+	w.start(esniNamespace, "MediaPoint")
+	if err := w.identifiableAttrs(&mp.IdentifiableType); err != nil {
+		return err
+	}
+	w.timeAttr("effective", mp.Effective)
+	w.timeAttr("expires", mp.Expires)
+	w.timeAttr("matchTime", mp.MatchTime)
+	w.stringAttr("matchOffset", string(mp.MatchOffset))
+	w.stringAttr("source", mp.Source)
+	w.stringAttr("expectedDuration", string(mp.ExpectedDuration))
+	w.uintAttr("order", mp.Order)
+	w.boolAttr("reusable", mp.Reusable)
+	w.closeStart()
+
+	if err := w.identifiableChildren(&mp.IdentifiableType); err != nil {
+		return err
+	}
+	for _, remove := range mp.Removes {
+		if remove == nil {
+			continue
+		}
+		w.start(esniNamespace, "Remove")
+		w.closeStart()
+		if err := w.policy(remove.Policy); err != nil {
+			return err
+		}
+		w.end("Remove")
+	}
+	for _, apply := range mp.Applys {
+		if apply == nil {
+			continue
+		}
+		w.start(esniNamespace, "Apply")
+		w.stringAttr("duration", string(apply.Duration))
+		w.uintAttr("priority", apply.Priority)
+		w.closeStart()
+		if err := w.policy(apply.Policy); err != nil {
+			return err
+		}
+		w.end("Apply")
+	}
+	if ms := mp.MatchSignal; ms != nil {
+		w.start(esniNamespace, "MatchSignal")
+		w.stringAttr("match", string(ms.Match))
+		w.stringAttr("signalTolerance", string(ms.SignalTolerance))
+		w.stringAttr("schema", ms.Schema)
+		w.closeStart()
+		for _, assertion := range ms.Assertions {
+			if assertion != nil {
+				w.text(esniNamespace, "Assert", assertion.Declaration)
+			}
+		}
+		w.end("MatchSignal")
+	}
+	w.end("MediaPoint")
+	return nil
+}
+
+func (w *xmlWriter) policy(p *Policy) error {
+	if p == nil {
+		return nil
+	}
+	w.start(esniNamespace, "Policy")
+	if err := w.reusableAttrs(&p.ReusableType); err != nil {
+		return err
+	}
+	w.closeStart()
+
+	if err := w.identifiableChildren(&p.IdentifiableType); err != nil {
+		return err
+	}
+	for _, vp := range p.ViewingPolicys {
+		if err := w.viewingPolicy(vp); err != nil {
+			return err
+		}
+	}
+	w.end("Policy")
+	return nil
+}
+
+func (w *xmlWriter) viewingPolicy(vp *ViewingPolicy) error {
+	if vp == nil {
+		return nil
+	}
+	w.start(esniNamespace, "ViewingPolicy")
+	if err := w.reusableAttrs(&vp.ReusableType); err != nil {
+		return err
+	}
+	w.closeStart()
+
+	if err := w.identifiableChildren(&vp.IdentifiableType); err != nil {
+		return err
+	}
+	if err := w.audience(vp.Audience); err != nil {
+		return err
+	}
+	if vp.SignalPointDeletion != nil {
+		w.text(actionNamespace, "SignalPointDeletion", vp.SignalPointDeletion.SignalPointDeletion)
+	}
+	if spi := vp.SignalPointInsertion; spi != nil {
+		if err := w.signalPointInsertion(spi); err != nil {
+			return err
+		}
+	}
+	if vp.Content != nil {
+		w.text(actionNamespace, "Content", vp.Content.Content)
+	}
+	if vp.Allocation != nil {
+		w.allocation(vp.Allocation)
+	}
+	if err := w.anys("ActionProperty", vp.ActionProperty); err != nil {
+		return err
+	}
+	w.end("ViewingPolicy")
+	return nil
+}
+
+func (w *xmlWriter) signalPointInsertion(spi *SignalPointInsertionAction) error {
+	w.start(actionNamespace, "SignalPointInsertion")
+	w.stringAttr("offset", string(spi.Offset))
+	w.closeStart()
+	for _, sp := range spi.SignalPoints {
+		if sp == nil {
+			continue
+		}
+		w.start(actionNamespace, "SignalPoint")
+		w.stringAttr("offset", string(sp.Offset))
+		w.stringAttr("segmentationEventId", sp.SegmentationEventId)
+		if sp.SegmentationDuration != 0 {
+			w.attr("", "segmentationDuration", strconv.FormatInt(sp.SegmentationDuration, 10))
+		}
+		w.uintAttr("segmentationTypeId", sp.SegmentationTypeId)
+		w.uintAttr("segmentationUpidType", sp.SegmentationUpidType)
+		w.stringAttr("segmentationUpid", sp.SegmentationUpid)
+		w.stringAttr("repeatInterval", string(sp.RepeatInterval))
+		w.timeAttr("repeatStart", sp.RepeatStart)
+		w.timeAttr("repeatStop", sp.RepeatStop)
+		w.closeStart()
+		w.end("SignalPoint")
+	}
+	if err := w.anys("ActionProperty", spi.ActionProperty); err != nil {
+		return err
+	}
+	w.end("SignalPointInsertion")
+	return nil
+}
+
+func (w *xmlWriter) allocation(alloc *Allocation) {
+	w.start(actionNamespace, "Allocation")
+	w.stringAttr("ownerType", alloc.OwnerType)
+	w.stringAttr("ownerName", alloc.OwnerName)
+	w.stringAttr("duration", string(alloc.Duration))
+	w.stringAttr("ads", alloc.Ads)
+	w.closeStart()
+	for _, slots := range alloc.Slots {
+		if slots == nil {
+			continue
+		}
+		w.start(actionNamespace, "Slots")
+		w.closeStart()
+		for _, slot := range slots.AdSlots {
+			if slot != nil {
+				w.slot(slot)
+			}
+		}
+		w.end("Slots")
+	}
+	w.end("Allocation")
+}
+
+func (w *xmlWriter) slot(slot *Slot) {
+	w.start(actionNamespace, "Slot")
+	w.stringAttr("duration", string(slot.Duration))
+	w.stringAttr("offset", string(slot.Offset))
+	w.closeStart()
+	for _, ref := range slot.AdsReferenceId {
+		if ref == nil {
+			continue
+		}
+		w.start(actionNamespace, "AdsReferenceId")
+		w.stringAttr("referenceType", ref.ReferenceType)
+		w.boolAttr("exclude", ref.Exclude)
+		w.closeStart()
+		w.buf = appendEscaped(w.buf, ref.ID)
+		w.end("AdsReferenceId")
+	}
+	if rules := slot.SlotRules; rules != nil {
+		w.start(actionNamespace, "SlotRules")
+		w.closeStart()
+		for _, rule := range rules.SlotRule {
+			if rule == nil {
+				continue
+			}
+			w.start(actionNamespace, "SlotRule")
+			w.stringAttr("rule", rule.Rule)
+			w.closeStart()
+			for _, param := range rule.Parameters {
+				if param == nil {
+					continue
+				}
+				w.start(actionNamespace, "Parameter")
+				w.stringAttr("parameterName", param.ParameterName)
+				w.closeStart()
+				w.buf = appendEscaped(w.buf, param.Value)
+				w.end("Parameter")
+			}
+			w.end("SlotRule")
+		}
+		w.end("SlotRules")
+	}
+	w.end("Slot")
+}
+
+func (w *xmlWriter) audience(aud *Audience) error {
+	if aud == nil {
+		return nil
+	}
+	w.start(esniNamespace, "Audience")
+	if err := w.reusableAttrs(&aud.ReusableType); err != nil {
+		return err
+	}
+	w.stringAttr("match", string(aud.Match))
+	w.closeStart()
+
+	if err := w.identifiableChildren(&aud.IdentifiableType); err != nil {
+		return err
+	}
+	for _, nested := range aud.Audiences {
+		if err := w.audience(nested); err != nil {
+			return err
+		}
+	}
+	if err := w.anys("AudienceProperty", aud.AudienceProperty); err != nil {
+		return err
+	}
+	w.end("Audience")
+	return nil
+}
+
+func (w *xmlWriter) audit(audit *Audit) error {
+	if audit == nil {
+		return nil
+	}
+	w.start(esniNamespace, "Audit")
+	if err := w.identifiableAttrs(&audit.IdentifiableType); err != nil {
+		return err
+	}
+	if audit.XLinkHRef != "" {
+		if err := w.attr(xlinkNamespace, "href", audit.XLinkHRef); err != nil {
+			return err
+		}
+	}
+	if audit.XLinkRole != "" {
+		if err := w.attr(xlinkNamespace, "role", audit.XLinkRole); err != nil {
+			return err
+		}
+	}
+	w.stringAttr("authorization", audit.Authorization)
+	w.stringAttr("policyMode", audit.PolicyMode)
+	w.stringAttr("trigger", audit.Trigger)
+	w.stringAttr("result", audit.Result)
+	w.closeStart()
+
+	if err := w.identifiableChildren(&audit.IdentifiableType); err != nil {
+		return err
+	}
+	for _, nested := range audit.Audits {
+		if err := w.audit(nested); err != nil {
+			return err
+		}
+	}
+	w.end("Audit")
+	return nil
+}
+
+func (w *xmlWriter) results(results *Results) error {
+	if results == nil {
+		return nil
+	}
+	w.start(esniNamespace, "Results")
+	if results.Size != 0 {
+		w.attr("", "size", strconv.Itoa(results.Size))
+	}
+	w.closeStart()
+	for _, m := range results.Medias {
+		if err := w.media(m); err != nil {
+			return err
+		}
+	}
+	for _, mp := range results.MediaPoints {
+		if err := w.mediaPoint(mp); err != nil {
+			return err
+		}
+	}
+	for _, p := range results.Policys {
+		if err := w.policy(p); err != nil {
+			return err
+		}
+	}
+	for _, vp := range results.ViewingPolicys {
+		if err := w.viewingPolicy(vp); err != nil {
+			return err
+		}
+	}
+	for _, aud := range results.Audiences {
+		if err := w.audience(aud); err != nil {
+			return err
+		}
+	}
+	for _, audit := range results.Audits {
+		if err := w.audit(audit); err != nil {
+			return err
+		}
+	}
+	w.end("Results")
+	return nil
+}
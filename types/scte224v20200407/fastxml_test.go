@@ -0,0 +1,202 @@
+package scte224v20200407
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/Comcast/scte224structs/types/xsd"
+	"github.com/stretchr/testify/assert"
+)
+
+// exercises prefix reuse under an xlink:href, colliding attribute namespaces,
+// escaping and every optional attribute of the action elements
+const fastPolicyRaw = `<Policy xmlns="http://www.scte.org/schemas/224" xmlns:xlink="http://www.w3.org/1999/xlink" id="test/policy" xlink:href="test/policy" xml:base="http://example.com/">
+  <AltID type="private:test" description="a &amp; b">alt&#x9;id</AltID>
+  <Ext><Note xmlns="urn:example" xmlns:a="urn:a/ns" xmlns:b="urn:b/ns" a:kind="x" b:kind="y">keep <b>raw</b></Note></Ext>
+  <ViewingPolicy id="test/vp" xlink:href="test/vp">
+    <Audience xlink:href="test/audience" match="ANY">
+      <Audience id="nested"><Zip xmlns="urn:scte:224:audience">19103</Zip></Audience>
+    </Audience>
+    <SignalPointDeletion xmlns="urn:scte:224:action">true</SignalPointDeletion>
+    <SignalPointInsertion xmlns="urn:scte:224:action" offset="PT1M">
+      <SignalPoint offset="PT0S" segmentationEventId="1" segmentationDuration="-5" segmentationTypeId="0" segmentationUpidType="9" segmentationUpid="SIGNAL:&lt;x&gt;" repeatInterval="PT5M" repeatStart="2021-01-01T00:00:00Z" repeatStop="2021-01-01T01:00:00.5-05:00"/>
+      <Other xmlns="urn:example">data</Other>
+    </SignalPointInsertion>
+    <Content xmlns="urn:scte:224:action">CONTENT</Content>
+    <Allocation xmlns="urn:scte:224:action" ownerType="DISTRIBUTOR" ownerName="test" duration="PT2M" ads="2">
+      <Slots>
+        <Slot duration="PT30S" offset="PT0S">
+          <AdsReferenceId referenceType="Ad-ID" exclude="true">ABCD0001000H</AdsReferenceId>
+          <SlotRules><SlotRule rule="position"><Parameter parameterName="index">1</Parameter></SlotRule></SlotRules>
+        </Slot>
+      </Slots>
+    </Allocation>
+    <Capture xmlns="urn:scte:224:action" xmlns:xml2="http://example.com/xml/" xml2:mode="on" xml:lang="en">x</Capture>
+  </ViewingPolicy>
+</Policy>`
+
+const fastAuditRaw = `<Results xmlns="http://www.scte.org/schemas/224" xmlns:xlink="http://www.w3.org/1999/xlink" size="2">
+  <Audit id="a1" xlink:href="test/audit" xlink:role="role" authorization="token" policyMode="APPLY" trigger="SIGNAL" result="SUCCESS">
+    <Audit id="a2" lastUpdated="2021-01-19T18:49:26.298986528Z"/>
+  </Audit>
+  <MediaPoint id="mp" order="0" reusable="true" matchOffset="PT1S" expectedDuration="PT1H">
+    <Apply priority="3"><Policy xlink:href="p"/></Apply>
+    <Remove><Policy xlink:href="q"/></Remove>
+  </MediaPoint>
+</Results>`
+
+const fastADIRaw = `<Media xmlns="http://www.scte.org/schemas/224" id="test/media">
+  <MediaPoint id="mp">
+    <Metadata>
+      <ADI3 xmlns="http://www.scte.org/schemas/236/2017/core" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"/>
+      <ADI xmlns=""><Metadata><AMS Asset_Class="package" Asset_ID="PAID0000000000000001" Provider_ID="example.com"/><App_Data App="MOD" Name="Provider_Content_Tier" Value="1"/></Metadata></ADI>
+      <Detail xmlns="urn:example" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="string">x</Detail>
+    </Metadata>
+  </MediaPoint>
+</Media>`
+
+func assertFastMarshal(t *testing.T, v interface {
+	AppendXML([]byte) ([]byte, error)
+}) {
+	t.Helper()
+	expected, err := xml.Marshal(v)
+	assert.Nil(t, err, "Error marshaling with encoding/xml")
+
+	prefix := []byte("prefix")
+	fast, err := v.AppendXML(prefix)
+	assert.Nil(t, err, "Error marshaling with AppendXML")
+	assert.Equal(t, "prefix"+string(expected), string(fast))
+}
+
+func TestAppendXML(t *testing.T) {
+	for _, raw := range []string{vp2020Raw, vpSignalPointInsertion_w_SpliceInfoSection, vpPPOStart, fastPolicyRaw} {
+		var vp *ViewingPolicy
+		if strings.HasPrefix(raw, "<Policy") {
+			var policy *Policy
+			assert.Nil(t, xml.Unmarshal([]byte(raw), &policy))
+			assertFastMarshal(t, policy)
+			vp = policy.ViewingPolicys[0]
+		} else {
+			assert.Nil(t, xml.Unmarshal([]byte(raw), &vp))
+		}
+		assertFastMarshal(t, vp)
+	}
+
+	for _, raw := range []string{media2020Raw, anotherMedia2020Raw, fastADIRaw} {
+		var media *Media
+		assert.Nil(t, xml.Unmarshal([]byte(raw), &media))
+		assertFastMarshal(t, media)
+		assertFastMarshal(t, media.MediaPoints[0])
+	}
+
+	var audience *Audience
+	assert.Nil(t, xml.Unmarshal([]byte(aud2020Raw), &audience))
+	assertFastMarshal(t, audience)
+
+	var results *Results
+	assert.Nil(t, xml.Unmarshal([]byte(fastAuditRaw), &results))
+	assertFastMarshal(t, results)
+	assertFastMarshal(t, results.Audits[0])
+}
+
+func TestAppendXMLEdgeCases(t *testing.T) {
+	// nil values marshal to nothing
+	var media *Media
+	out, err := media.AppendXML(nil)
+	assert.Nil(t, err)
+	assert.Empty(t, out)
+
+	// invalid UTF-8 and characters outside the XML range
+	assertFastMarshal(t, &Audience{ReusableType: ReusableType{IdentifiableType: IdentifiableType{Description: "bad \xff\x01 \"quoted\" 'text'\r\n"}}})
+
+	// an Any without a name, and attributes without a local name
+	assertFastMarshal(t, &ViewingPolicy{ActionProperty: []Any{{Value: "x", Attributes: []xml.Attr{{Value: "ignored"}}}}})
+
+	// non-ASCII namespace path segments and names reserved for XML
+	assertFastMarshal(t, &Audience{AudienceProperty: []Any{{
+		XMLName: xml.Name{Space: "urn:example", Local: "Prop"},
+		Attributes: []xml.Attr{
+			{Name: xml.Name{Space: "http://example.com/ünï", Local: "a"}},
+			{Name: xml.Name{Space: "http://example.com/9lives", Local: "b"}},
+			{Name: xml.Name{Space: "http://example.com/XmLish", Local: "c"}},
+			{Name: xml.Name{Space: "http://example.com/ns/", Local: "d"}},
+			{Name: xml.Name{Space: "http://example.org/ns", Local: "e"}},
+		},
+	}}})
+
+	// ADI 3.0 metadata below a declared prefix falls back to encoding/xml
+	var policy *Policy
+	assert.Nil(t, xml.Unmarshal([]byte(fastPolicyRaw), &policy))
+	var adiMedia *Media
+	assert.Nil(t, xml.Unmarshal([]byte(fastADIRaw), &adiMedia))
+	assert.NotNil(t, adiMedia.MediaPoints[0].Metadata.ADI30)
+	assert.NotNil(t, adiMedia.MediaPoints[0].Metadata.ADI11)
+	policy.ViewingPolicys[0].Metadata = adiMedia.MediaPoints[0].Metadata
+	assertFastMarshal(t, policy)
+
+	// times are written as they were read, beyond the range of time.Time formatting too
+	farFuture, err := xsd.ParseDateTime("10000-01-01T00:00:00.500")
+	assert.Nil(t, err)
+	assertFastMarshal(t, &MediaPoint{Effective: &farFuture, Expires: &xsd.DateTime{}})
+}
+
+func benchmarkViewingPolicy(b *testing.B) *ViewingPolicy {
+	var policy *Policy
+	if err := xml.Unmarshal([]byte(fastPolicyRaw), &policy); err != nil {
+		b.Fatal(err)
+	}
+	return policy.ViewingPolicys[0]
+}
+
+func benchmarkMedia(b *testing.B) *Media {
+	var media *Media
+	if err := xml.Unmarshal([]byte(anotherMedia2020Raw), &media); err != nil {
+		b.Fatal(err)
+	}
+	return media
+}
+
+func BenchmarkViewingPolicyMarshal(b *testing.B) {
+	vp := benchmarkViewingPolicy(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := xml.Marshal(vp); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkViewingPolicyAppendXML(b *testing.B) {
+	vp := benchmarkViewingPolicy(b)
+	b.ReportAllocs()
+	var buf []byte
+	for i := 0; i < b.N; i++ {
+		var err error
+		if buf, err = vp.AppendXML(buf[:0]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMediaMarshal(b *testing.B) {
+	media := benchmarkMedia(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := xml.Marshal(media); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMediaAppendXML(b *testing.B) {
+	media := benchmarkMedia(b)
+	b.ReportAllocs()
+	var buf []byte
+	for i := 0; i < b.N; i++ {
+		var err error
+		if buf, err = media.AppendXML(buf[:0]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+//go:build go1.18
+// +build go1.18
+
+package scte224v20200407
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/Comcast/scte224structs/internal/roundtrip"
+)
+
+// fuzzRoots returns a new value of every type a document can have as its root.
+func fuzzRoots() []interface{} {
+	return []interface{}{&Media{}, &MediaPoint{}, &Policy{}, &ViewingPolicy{}, &Audience{}, &Results{}, &Audit{}}
+}
+
+func addFuzzSeeds(f *testing.F) {
+	for _, raw := range []string{
+		vp2020Raw, vpSignalPointInsertion_w_SpliceInfoSection, vpPPOStart, vp2018Raw, aud2020Raw,
+		media2020Raw, media2018Raw, anotherMedia2020Raw, extensionMediaPointRaw,
+		fastPolicyRaw, fastAuditRaw, fastADIRaw, matchSignalXML,
+	} {
+		f.Add([]byte(raw))
+	}
+	f.Add([]byte(`<Results xmlns="http://www.scte.org/schemas/224" size="1">` + aud2020Raw + `</Results>`))
+	// a MatchSignal without a schema, which decoding defaults
+	f.Add([]byte(`<MediaPoint xmlns="http://www.scte.org/schemas/224" id="mp"><MatchSignal match="ANY" schema=" "><Assert>/SpliceInfoSection</Assert></MatchSignal></MediaPoint>`))
+}
+
+// FuzzUnmarshal decodes arbitrary bytes into every root type, and checks that
+// what decodes encodes to XML that decodes and encodes to the same bytes, and
+// that AppendXML writes the same bytes as encoding/xml. The seeds are whole
+// documents, which are slow to minimize; run it with a short -fuzzminimizetime,
+// such as 1s.
+func FuzzUnmarshal(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		roundtrip.Check(t, raw, fuzzRoots, checkAppendXML)
+	})
+}
+
+// checkAppendXML fails t unless AppendXML writes v as encoding/xml does.
+func checkAppendXML(t *testing.T, v interface{}, encoded []byte) {
+	fast, err := v.(interface {
+		AppendXML([]byte) ([]byte, error)
+	}).AppendXML(nil)
+	if nil != err {
+		t.Logf("%T: AppendXML fails where encoding/xml does not: %v", v, err)
+		t.FailNow()
+	}
+	if !bytes.Equal(encoded, fast) {
+		t.Logf("%T: AppendXML differs from encoding/xml\n%s\n%s", v, encoded, fast)
+		t.FailNow()
+	}
+}
+
+// FuzzResultsDecoder reads arbitrary bytes as a stream of Results entries,
+// which must end in an error or io.EOF without panicking.
+func FuzzResultsDecoder(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		rd := NewResultsDecoder(bytes.NewReader(raw))
+		for {
+			if _, err := rd.Next(); nil != err {
+				return
+			}
+		}
+	})
+}
+
+// FuzzMatchSignal checks that decoding a MatchSignal always gives it a schema.
+func FuzzMatchSignal(f *testing.F) {
+	f.Add([]byte(matchSignalXML))
+	f.Add([]byte(`<MatchSignal xmlns="http://www.scte.org/schemas/224" schema="urn:example"/>`))
+	f.Add([]byte("<MatchSignal xmlns=\"http://www.scte.org/schemas/224\" schema=\"\t\"/>"))
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		var matchSignal MatchSignal
+		if err := xml.Unmarshal(raw, &matchSignal); nil != err {
+			return
+		}
+		if "" == strings.TrimSpace(matchSignal.Schema) {
+			t.Logf("Expected a schema after decoding %q", raw)
+			t.Fail()
+		}
+	})
+}
+
+// FuzzConvertDuration converts arbitrary strings, which must not panic.
+func FuzzConvertDuration(f *testing.F) {
+	for _, seed := range []string{"", "PT30S", "PT1H30M", "P1DT2H", "PT1.5S", "-PT5M", "P-1DT-1H", "PT"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, raw string) {
+		ConvertDuration(raw)
+	})
+}
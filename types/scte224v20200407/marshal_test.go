@@ -0,0 +1,52 @@
+package scte224v20200407
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalSchemaLocation(t *testing.T) {
+	media := &Media{ReusableType: ReusableType{IdentifiableType: IdentifiableType{Id: "hbo.com/media/HBOHD"}}}
+	plain, err := media.Marshal()
+	assert.Nil(t, err)
+	expected, _ := xml.Marshal(media)
+	assert.Equal(t, string(expected), string(plain))
+
+	located, err := media.Marshal(WithSchemaLocation())
+	assert.Nil(t, err)
+	assert.Equal(t, `<Media xmlns="http://www.scte.org/schemas/224" id="hbo.com/media/HBOHD" `+
+		`xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://www.scte.org/schemas/224 https://www.scte.org/standards-development/library/standards-catalog/ansiscte-224-2018r1/"></Media>`,
+		string(located))
+
+	// the Action and Audience schemas given are listed when their namespaces are used
+	const viewingPolicyRaw = `<ViewingPolicy xmlns="http://www.scte.org/schemas/224" id="vp">` +
+		`<Audience match="ANY"><Zip xmlns="urn:scte:224:audience">80202</Zip></Audience>` +
+		`<Content xmlns="urn:scte:224:action">replace</Content></ViewingPolicy>`
+	var vp ViewingPolicy
+	assert.Nil(t, xml.Unmarshal([]byte(viewingPolicyRaw), &vp))
+	located, err = vp.Marshal(WithSchemaLocation(), WithIndent("", "  "),
+		WithSchema(actionNamespace, "SCTE224_Action_20210803.xsd"),
+		WithSchema("urn:scte:224:audience", "SCTE224_Audience_20210420.xsd"),
+		WithSchema("urn:example", "example.xsd"))
+	assert.Nil(t, err)
+	var decoded struct {
+		SchemaLocation string `xml:"http://www.w3.org/2001/XMLSchema-instance schemaLocation,attr"`
+	}
+	assert.Nil(t, xml.Unmarshal(located, &decoded))
+	assert.Equal(t, "http://www.scte.org/schemas/224 "+schemaLocation+" "+
+		"urn:scte:224:action SCTE224_Action_20210803.xsd "+
+		"urn:scte:224:audience SCTE224_Audience_20210420.xsd", decoded.SchemaLocation)
+
+	// other schemas are only listed when given
+	located, err = vp.Marshal(WithSchemaLocation())
+	assert.Nil(t, err)
+	assert.Nil(t, xml.Unmarshal(located, &decoded))
+	assert.Equal(t, "http://www.scte.org/schemas/224 "+schemaLocation, decoded.SchemaLocation)
+
+	results := &Results{Size: 1, Medias: []*Media{media}}
+	located, err = results.Marshal(WithSchemaLocation(), WithIndent("", "  "))
+	assert.Nil(t, err)
+	assert.Contains(t, string(located), `<Results xmlns="http://www.scte.org/schemas/224" size="1" xmlns:xsi=`)
+}
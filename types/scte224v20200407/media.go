@@ -11,6 +11,7 @@ import (
 	"github.com/Comcast/scte224structs/convert"
 	scte224_2015 "github.com/Comcast/scte224structs/types/scte224v20151115"
 	scte224_2018 "github.com/Comcast/scte224structs/types/scte224v20180501"
+	"github.com/Comcast/scte224structs/types/xsd"
 )
 
 const schemaLocation = "https://www.scte.org/standards-development/library/standards-catalog/ansiscte-224-2018r1/"
@@ -21,8 +22,8 @@ const schemaLocation = "https://www.scte.org/standards-development/library/stand
 type Media struct {
 	ReusableType
 	XMLName     xml.Name      `xml:"http://www.scte.org/schemas/224 Media" json:"-"`
-	Effective   *time.Time    `xml:"effective,attr,omitempty" json:"effective,omitempty"`
-	Expires     *time.Time    `xml:"expires,attr,omitempty" json:"expires,omitempty"`
+	Effective   *xsd.DateTime `xml:"effective,attr,omitempty" json:"effective,omitempty"`
+	Expires     *xsd.DateTime `xml:"expires,attr,omitempty" json:"expires,omitempty"`
 	Source      string        `xml:"source,attr,omitempty" json:"source,omitempty"`
 	MediaPoints []*MediaPoint `xml:"http://www.scte.org/schemas/224 MediaPoint" json:"mediaPoints,omitempty"`
 }
@@ -60,19 +61,19 @@ func (m *Media) Get2015() scte224_2015.Media {
 //Table 7
 type MediaPoint struct {
 	IdentifiableType
-	XMLName          xml.Name     `xml:"http://www.scte.org/schemas/224 MediaPoint" json:"-"`
-	Effective        *time.Time   `xml:"effective,attr,omitempty" json:"effective,omitempty"`
-	Expires          *time.Time   `xml:"expires,attr,omitempty" json:"expires,omitempty"`
-	MatchTime        *time.Time   `xml:"matchTime,attr,omitempty" json:"matchTime,omitempty"`
-	MatchOffset      Duration     `xml:"matchOffset,attr,omitempty" json:"matchOffset,omitempty"`
-	Source           string       `xml:"source,attr,omitempty" json:"source,omitempty"`
-	ExpectedDuration Duration     `xml:"expectedDuration,attr,omitempty" json:"expectedDuration,omitempty"`
-	Order            *uint        `xml:"order,attr,omitempty" json:"order,omitempty"`
-	Reusable         bool         `xml:"reusable,attr,omitempty" json:"reusable,omitempty"`
-	Removes          []*Remove    `xml:"http://www.scte.org/schemas/224 Remove" json:"removes,omitempty"`
-	Applys           []*Apply     `xml:"http://www.scte.org/schemas/224 Apply" json:"applys,omitempty"`
-	MatchSignal      *MatchSignal `xml:"http://www.scte.org/schemas/224 MatchSignal" json:"matchSignal,omitempty"`
-	MediaGuid        string       `xml:"-"` // used internally to track which media this point is part of
+	XMLName          xml.Name      `xml:"http://www.scte.org/schemas/224 MediaPoint" json:"-"`
+	Effective        *xsd.DateTime `xml:"effective,attr,omitempty" json:"effective,omitempty"`
+	Expires          *xsd.DateTime `xml:"expires,attr,omitempty" json:"expires,omitempty"`
+	MatchTime        *xsd.DateTime `xml:"matchTime,attr,omitempty" json:"matchTime,omitempty"`
+	MatchOffset      Duration      `xml:"matchOffset,attr,omitempty" json:"matchOffset,omitempty"`
+	Source           string        `xml:"source,attr,omitempty" json:"source,omitempty"`
+	ExpectedDuration Duration      `xml:"expectedDuration,attr,omitempty" json:"expectedDuration,omitempty"`
+	Order            *uint         `xml:"order,attr,omitempty" json:"order,omitempty"`
+	Reusable         bool          `xml:"reusable,attr,omitempty" json:"reusable,omitempty"`
+	Removes          []*Remove     `xml:"http://www.scte.org/schemas/224 Remove" json:"removes,omitempty"`
+	Applys           []*Apply      `xml:"http://www.scte.org/schemas/224 Apply" json:"applys,omitempty"`
+	MatchSignal      *MatchSignal  `xml:"http://www.scte.org/schemas/224 MatchSignal" json:"matchSignal,omitempty"`
+	MediaGuid        string        `xml:"-"` // used internally to track which media this point is part of
 }
 
 func (mp *MediaPoint) Get2018() scte224_2018.MediaPoint {
@@ -98,6 +99,7 @@ func (mp *MediaPoint) Get2018() scte224_2018.MediaPoint {
 		matchSignal2018.XMLName = mp.MatchSignal.XMLName
 		matchSignal2018.Match = scte224_2018.Match(mp.MatchSignal.Match)
 		matchSignal2018.SignalTolerance = scte224_2018.Duration(mp.MatchSignal.SignalTolerance)
+		matchSignal2018.Schema = mp.MatchSignal.Schema
 
 		for _, assertion := range mp.MatchSignal.Assertions {
 			if assertion == nil {
@@ -253,7 +255,7 @@ func (ms *MatchSignal) UnmarshalXML(d *xml.Decoder, start xml.StartElement) erro
 		return err
 	}
 
-	if strings.Trim(matchSignal.Schema, " ") == "" {
+	if strings.TrimSpace(matchSignal.Schema) == "" {
 		// set the default value
 		matchSignal.Schema = matchSignalSchemaDefault
 	}
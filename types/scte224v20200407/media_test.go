@@ -50,3 +50,27 @@ func TestMatchSignal2020(t *testing.T) {
 
 	assert.Equalf(t, matchSignalSchemaDefault, matchSignal.Schema, "Expected default schema %s but got %s \n", matchSignalSchemaDefault, matchSignal.Schema)
 }
+
+func TestDateTimeRoundtrip(t *testing.T) {
+	raw := `<Media xmlns="http://www.scte.org/schemas/224" id="m" lastUpdated="2021-07-04T12:00:00.000-04:00" effective="2021-07-04T00:00:00" expires="2021-07-05T00:00:00.5Z">` +
+		`<MediaPoint xmlns="http://www.scte.org/schemas/224" id="mp" matchTime="2021-07-04T20:00:00+05:30"></MediaPoint></Media>`
+	var media Media
+	assert.Nil(t, xml.Unmarshal([]byte(raw), &media))
+	assert.False(t, media.Effective.HasZone())
+
+	// offsets, precision and missing zones survive both encoders and downgrades
+	marshaled, err := xml.Marshal(&media)
+	assert.Nil(t, err)
+	assert.Equal(t, raw, string(marshaled))
+	assertFastMarshal(t, &media)
+	media2018 := media.Get2018()
+	assert.Equal(t, "2021-07-04T12:00:00.000-04:00", media2018.LastUpdated.String())
+	assert.Equal(t, "2021-07-04T20:00:00+05:30", media2018.MediaPoints[0].MatchTime.String())
+}
+
+func TestMatchSignalBlankSchema(t *testing.T) {
+	var matchSignal MatchSignal
+	raw := "<MatchSignal xmlns=\"http://www.scte.org/schemas/224\" schema=\"\t\n\"/>"
+	assert.Nil(t, xml.Unmarshal([]byte(raw), &matchSignal))
+	assert.Equal(t, matchSignalSchemaDefault, matchSignal.Schema)
+}
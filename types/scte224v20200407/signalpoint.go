@@ -0,0 +1,77 @@
+package scte224v20200407
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Comcast/scte224structs/internal/repeat"
+)
+
+// MaxRepetitions is the most signals a single SignalPoint expands to.
+const MaxRepetitions = repeat.MaxRepetitions
+
+var (
+	// ErrUnboundedRepeat is returned when expanding a SignalPoint that repeats
+	// without a repeatStop, and no horizon bounds the repetition.
+	ErrUnboundedRepeat = repeat.ErrUnbounded
+	// ErrTooManyRepeats is returned when expanding a SignalPoint that repeats
+	// more than MaxRepetitions times before its repeatStop or the horizon.
+	ErrTooManyRepeats = repeat.ErrTooMany
+)
+
+// A ScheduledSignal is a signal to insert, expanded from a SignalPoint.
+type ScheduledSignal struct {
+	At time.Time `json:"at"`
+	// Offset is the time from the trigger to At.
+	Offset time.Duration `json:"offset"`
+	// Repetition is 0 for the first signal of a SignalPoint, 1 for its first
+	// repetition and so on.
+	Repetition int `json:"repetition"`
+	// SignalPoint holds the segmentation parameters of the signal.
+	SignalPoint *SignalPoint `json:"signalPoint"`
+}
+
+// Expand returns the signals to insert for a trigger, such as the match of a
+// MediaPoint, in the order of their times.
+//
+// Each SignalPoint is inserted at the trigger shifted by the offset of the action
+// and its own offset. A SignalPoint with a repeatInterval is then inserted again
+// every interval, until its repeatStop, or else the horizon. A repeatStart anchors
+// the repetitions: the signals are then at repeatStart plus a multiple of the
+// interval, from the first of these times not before the shifted trigger.
+// Signals past the horizon are dropped even when repeatStop is later; a zero
+// horizon leaves repeats bounded by their repeatStop only. A SignalPoint that
+// repeats more than MaxRepetitions times is an error.
+func (spi *SignalPointInsertionAction) Expand(trigger, horizon time.Time) ([]*ScheduledSignal, error) {
+	if spi == nil {
+		return nil, nil
+	}
+	var signals []*ScheduledSignal
+	base := trigger.Add(spi.Offset.GoDuration())
+	for _, sp := range spi.SignalPoints {
+		if sp == nil {
+			continue
+		}
+		expanded, err := sp.expand(base.Add(sp.Offset.GoDuration()), trigger, horizon)
+		if err != nil {
+			return nil, err
+		}
+		signals = append(signals, expanded...)
+	}
+	sort.SliceStable(signals, func(i, j int) bool {
+		return signals[i].At.Before(signals[j].At)
+	})
+	return signals, nil
+}
+
+func (sp *SignalPoint) expand(first, trigger, horizon time.Time) ([]*ScheduledSignal, error) {
+	times, err := repeat.Times(first, sp.RepeatInterval.GoDuration(), sp.RepeatStart, sp.RepeatStop, horizon)
+	if err != nil {
+		return nil, err
+	}
+	signals := make([]*ScheduledSignal, 0, len(times))
+	for repetition, at := range times {
+		signals = append(signals, &ScheduledSignal{At: at, Offset: at.Sub(trigger), Repetition: repetition, SignalPoint: sp})
+	}
+	return signals, nil
+}
@@ -0,0 +1,44 @@
+package scte224v20200407
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandSignalPoints(t *testing.T) {
+	var vp *ViewingPolicy
+	err := xml.Unmarshal([]byte(`<ViewingPolicy xmlns="http://www.scte.org/schemas/224" xmlns:action="urn:scte:224:action" id="vp">
+	<action:SignalPointInsertion offset="PT30S">
+		<action:SignalPoint offset="PT30S" segmentationTypeId="52" repeatInterval="PT1M" repeatStart="2021-07-26T09:00:10Z" repeatStop="2021-07-26T09:03:10Z"/>
+		<action:SignalPoint segmentationTypeId="53" segmentationDuration="2700000"/>
+	</action:SignalPointInsertion>
+</ViewingPolicy>`), &vp)
+	if !assert.Nil(t, err, "Error unmarshalling viewingpolicy") {
+		return
+	}
+
+	trigger := time.Date(2021, 7, 26, 9, 0, 0, 0, time.UTC)
+	signals, err := vp.SignalPointInsertion.Expand(trigger, time.Time{})
+	assert.Nil(t, err)
+	var offsets []time.Duration
+	for _, s := range signals {
+		offsets = append(offsets, s.Offset)
+	}
+	assert.Equal(t, []time.Duration{30 * time.Second, 70 * time.Second, 130 * time.Second, 190 * time.Second}, offsets)
+	assert.Equal(t, uint(53), *signals[0].SignalPoint.SegmentationTypeId)
+	assert.Equal(t, 2, signals[3].Repetition)
+
+	vp.SignalPointInsertion.SignalPoints[0].RepeatStop = nil
+	_, err = vp.SignalPointInsertion.Expand(trigger, time.Time{})
+	assert.Equal(t, ErrUnboundedRepeat, err)
+	signals, err = vp.SignalPointInsertion.Expand(trigger, trigger.Add(10*time.Minute))
+	assert.Nil(t, err)
+	assert.Len(t, signals, 10)
+	// a short interval up to a distant horizon is refused rather than expanded
+	vp.SignalPointInsertion.SignalPoints[0].RepeatInterval = "PT0.001S"
+	_, err = vp.SignalPointInsertion.Expand(trigger, trigger.Add(time.Hour))
+	assert.Equal(t, ErrTooManyRepeats, err)
+}
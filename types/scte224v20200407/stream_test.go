@@ -0,0 +1,126 @@
+package scte224v20200407
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func streamTestResults(t *testing.T) *Results {
+	results := &Results{Size: 4}
+	for _, entry := range []struct {
+		raw   string
+		value interface{}
+	}{
+		{media2020Raw, &Media{}},
+		{anotherMedia2020Raw, &Media{}},
+		{vp2020Raw, &ViewingPolicy{}},
+		{aud2020Raw, &Audience{}},
+	} {
+		assert.Nil(t, xml.Unmarshal([]byte(entry.raw), entry.value), "Error unmarshalling fixture")
+		switch v := entry.value.(type) {
+		case *Media:
+			results.Medias = append(results.Medias, v)
+		case *ViewingPolicy:
+			results.ViewingPolicys = append(results.ViewingPolicys, v)
+		case *Audience:
+			results.Audiences = append(results.Audiences, v)
+		}
+	}
+	return results
+}
+
+func TestResultsStreamRoundtrip(t *testing.T) {
+	results := streamTestResults(t)
+	raw, err := xml.Marshal(results)
+	assert.Nil(t, err, "Error marshaling results")
+
+	decoder := NewResultsDecoder(bytes.NewReader(raw))
+	var entries []interface{}
+	for {
+		entry, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err, "Error decoding results entry")
+		if err != nil {
+			return
+		}
+		entries = append(entries, entry)
+	}
+	assert.Equal(t, 4, decoder.Size())
+	assert.Len(t, entries, 4)
+	assert.Equal(t, results.Medias[0], entries[0])
+	assert.Equal(t, results.Audiences[0], entries[3])
+
+	var out bytes.Buffer
+	encoder := NewResultsEncoder(&out)
+	encoder.SetSize(decoder.Size())
+	for _, entry := range entries {
+		assert.Nil(t, encoder.Encode(entry), "Error encoding results entry")
+	}
+	assert.Nil(t, encoder.Close())
+	assert.Equal(t, string(raw), out.String(), "Streamed Results should match xml.Marshal")
+
+	_, err = decoder.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestResultsDecoderSkipsUnknown(t *testing.T) {
+	raw := `<?xml version="1.0"?>
+<Results xmlns="http://www.scte.org/schemas/224" xmlns:x="urn:example">
+	<x:Extra><Media/></x:Extra>
+	<Policy id="p1"/>
+</Results>`
+	decoder := NewResultsDecoder(strings.NewReader(raw))
+	entry, err := decoder.Next()
+	assert.Nil(t, err)
+	if policy, ok := entry.(*Policy); assert.True(t, ok, "Expected a Policy") {
+		assert.Equal(t, "p1", policy.Id)
+	}
+	_, err = decoder.Next()
+	assert.Equal(t, io.EOF, err)
+
+	_, err = NewResultsDecoder(strings.NewReader(media2020Raw)).Next()
+	assert.NotNil(t, err, "Only Results documents can be streamed")
+
+	_, err = NewResultsDecoder(strings.NewReader(`<Results xmlns="http://www.scte.org/schemas/224"><Policy id="p1"/>`)).Next()
+	assert.Nil(t, err)
+}
+
+func TestResultsStreamCancel(t *testing.T) {
+	raw, err := xml.Marshal(streamTestResults(t))
+	assert.Nil(t, err, "Error marshaling results")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	entries, errs := NewResultsDecoder(bytes.NewReader(raw)).Stream(ctx)
+	first := <-entries
+	assert.IsType(t, &Media{}, first)
+	cancel()
+	for range entries {
+		// at most one entry may already be in flight
+	}
+	assert.Equal(t, context.Canceled, <-errs)
+
+	entries, errs = NewResultsDecoder(bytes.NewReader(raw)).Stream(context.Background())
+	count := 0
+	for range entries {
+		count++
+	}
+	assert.Equal(t, 4, count)
+	assert.Nil(t, <-errs)
+}
+
+func TestResultsEncoder(t *testing.T) {
+	var out bytes.Buffer
+	encoder := NewResultsEncoder(&out)
+	assert.NotNil(t, encoder.Encode(&Results{}), "Results cannot be nested")
+	assert.Nil(t, encoder.Close())
+	assert.Equal(t, `<Results xmlns="http://www.scte.org/schemas/224"></Results>`, out.String())
+	assert.NotNil(t, encoder.Encode(&Policy{}), "Encode after Close should fail")
+}
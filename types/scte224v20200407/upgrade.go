@@ -0,0 +1,301 @@
+package scte224v20200407
+
+import (
+	"encoding/xml"
+	"log"
+
+	scte224_2018 "github.com/Comcast/scte224structs/types/scte224v20180501"
+)
+
+// The upgrade of 2018 values restores what Get2018 keeps of the 2020 values the
+// 2018 schema cannot express: the Allocation of a ViewingPolicy, carried as an
+// ActionProperty, the type of an AltID and the schema of a MatchSignal, so that
+// the upgrade of the downgrade of a 2020 value is equal to it.
+
+var allocationName = xml.Name{Space: actionNamespace, Local: "Allocation"}
+
+// anyOf returns the XML of v as a 2018 Any.
+func anyOf(v interface{}) (scte224_2018.Any, error) {
+	node := scte224_2018.Any{}
+	raw, err := xml.Marshal(v)
+	if err != nil {
+		return node, err
+	}
+	err = xml.Unmarshal(raw, &node)
+	return node, err
+}
+
+// allocationOf decodes the Allocation carried by a 2018 ActionProperty.
+func allocationOf(node scte224_2018.Any) (*Allocation, error) {
+	raw, err := xml.Marshal(node)
+	if err != nil {
+		return nil, err
+	}
+	alloc := &Allocation{}
+	if err := xml.Unmarshal(raw, alloc); err != nil {
+		return nil, err
+	}
+	return alloc, nil
+}
+
+func upgradeAny(node scte224_2018.Any) Any {
+	return Any{
+		XMLName:    node.XMLName,
+		Namespace:  NamespaceCleaner(node.Namespace),
+		Attributes: node.Attributes,
+		Value:      node.Value,
+		Typed:      node.Typed,
+	}
+}
+
+func upgradeAnys(nodes []scte224_2018.Any) []Any {
+	if nodes == nil {
+		return nil
+	}
+	upgraded := make([]Any, 0, len(nodes))
+	for _, node := range nodes {
+		upgraded = append(upgraded, upgradeAny(node))
+	}
+	return upgraded
+}
+
+func UpgradeIdentifiableType(idType scte224_2018.IdentifiableType) IdentifiableType {
+	destination := IdentifiableType{
+		Id:          idType.Id,
+		Description: idType.Description,
+		LastUpdated: idType.LastUpdated,
+		XMLBase:     idType.XMLBase,
+	}
+
+	for _, altID := range idType.AltIDs {
+		if altID == nil {
+			continue
+		}
+		destination.AltIDs = append(destination.AltIDs, &AltID{
+			XMLName:     altID.XMLName,
+			Description: altID.Description,
+			Value:       altID.Value,
+			Type:        altID.Type,
+		})
+	}
+
+	if idType.Metadata != nil {
+		destination.Metadata = &Metadata{
+			XMLName: idType.Metadata.XMLName,
+			ADI30:   idType.Metadata.ADI30,
+			ADI11:   idType.Metadata.ADI11,
+			Nodes:   upgradeAnys(idType.Metadata.Nodes),
+		}
+	}
+
+	if idType.Ext != nil {
+		destination.Ext = &Ext{
+			XMLName: idType.Ext.XMLName,
+			Nodes:   upgradeAnys(idType.Ext.Nodes),
+		}
+	}
+
+	return destination
+}
+
+func UpgradeReusableType(rt scte224_2018.ReusableType) ReusableType {
+	return ReusableType{
+		IdentifiableType: UpgradeIdentifiableType(rt.IdentifiableType),
+		XLinkHRef:        rt.XLinkHRef,
+	}
+}
+
+func UpgradeAudience(aud scte224_2018.Audience) Audience {
+	destination := Audience{
+		ReusableType:     UpgradeReusableType(aud.ReusableType),
+		XMLName:          aud.XMLName,
+		Match:            Match(aud.Match),
+		AudienceProperty: upgradeAnys(aud.AudienceProperty),
+	}
+
+	for _, nested := range aud.Audiences {
+		if nested == nil {
+			continue
+		}
+		nested2020 := UpgradeAudience(*nested)
+		destination.Audiences = append(destination.Audiences, &nested2020)
+	}
+
+	return destination
+}
+
+// UpgradeViewingPolicy upgrades vp, and decodes an Allocation carried as an
+// ActionProperty into the Allocation of the result; one that cannot be decoded
+// is left an ActionProperty.
+func UpgradeViewingPolicy(vp scte224_2018.ViewingPolicy) ViewingPolicy {
+	destination := ViewingPolicy{
+		ReusableType: UpgradeReusableType(vp.ReusableType),
+		XMLName:      vp.XMLName,
+	}
+
+	if vp.Audience != nil {
+		aud2020 := UpgradeAudience(*vp.Audience)
+		destination.Audience = &aud2020
+	}
+
+	if vp.SignalPointDeletion != nil {
+		destination.SignalPointDeletion = &SignalPointDeletionAction{
+			XMLName:             vp.SignalPointDeletion.XMLName,
+			SignalPointDeletion: vp.SignalPointDeletion.SignalPointDeletion,
+		}
+	}
+
+	if vp.SignalPointInsertion != nil {
+		insertion := &SignalPointInsertionAction{
+			Offset:         Duration(vp.SignalPointInsertion.Offset),
+			ActionProperty: upgradeAnys(vp.SignalPointInsertion.ActionProperty),
+		}
+		for _, signalPoint := range vp.SignalPointInsertion.SignalPoints {
+			if signalPoint == nil {
+				continue
+			}
+			insertion.SignalPoints = append(insertion.SignalPoints, &SignalPoint{
+				Offset:               Duration(signalPoint.Offset),
+				SegmentationEventId:  signalPoint.SegmentationEventId,
+				SegmentationDuration: signalPoint.SegmentationDuration,
+				SegmentationTypeId:   signalPoint.SegmentationTypeId,
+				SegmentationUpidType: signalPoint.SegmentationUpidType,
+				SegmentationUpid:     signalPoint.SegmentationUpid,
+				RepeatInterval:       Duration(signalPoint.RepeatInterval),
+				RepeatStart:          signalPoint.RepeatStart,
+				RepeatStop:           signalPoint.RepeatStop,
+			})
+		}
+		destination.SignalPointInsertion = insertion
+	}
+
+	if vp.Content != nil {
+		destination.Content = &ContentAction{
+			XMLName: vp.Content.XMLName,
+			Content: vp.Content.Content,
+		}
+	}
+
+	for _, action := range vp.ActionProperty {
+		if action.XMLName == allocationName && destination.Allocation == nil {
+			alloc, err := allocationOf(action)
+			if err == nil {
+				destination.Allocation = alloc
+				continue
+			}
+			log.Println(err)
+		}
+		destination.ActionProperty = append(destination.ActionProperty, upgradeAny(action))
+	}
+
+	return destination
+}
+
+func UpgradePolicy(p scte224_2018.Policy) Policy {
+	destination := Policy{
+		ReusableType: UpgradeReusableType(p.ReusableType),
+		XMLName:      p.XMLName,
+	}
+
+	for _, vp := range p.ViewingPolicys {
+		if vp == nil {
+			continue
+		}
+		vp2020 := UpgradeViewingPolicy(*vp)
+		destination.ViewingPolicys = append(destination.ViewingPolicys, &vp2020)
+	}
+
+	return destination
+}
+
+func upgradePolicyPointer(p *scte224_2018.Policy) *Policy {
+	if p == nil {
+		return nil
+	}
+	p2020 := UpgradePolicy(*p)
+	return &p2020
+}
+
+// UpgradeMediaPoint upgrades mp; a MatchSignal without a schema gets the
+// default one, as when it is decoded.
+func UpgradeMediaPoint(mp scte224_2018.MediaPoint) MediaPoint {
+	destination := MediaPoint{
+		IdentifiableType: UpgradeIdentifiableType(mp.IdentifiableType),
+		XMLName:          mp.XMLName,
+		Effective:        mp.Effective,
+		Expires:          mp.Expires,
+		MatchTime:        mp.MatchTime,
+		MatchOffset:      Duration(mp.MatchOffset),
+		Source:           mp.Source,
+		ExpectedDuration: Duration(mp.ExpectedDuration),
+		Order:            mp.Order,
+		Reusable:         mp.Reusable,
+		MediaGuid:        mp.MediaGuid,
+	}
+
+	for _, remove := range mp.Removes {
+		if remove == nil {
+			continue
+		}
+		destination.Removes = append(destination.Removes, &Remove{
+			XMLName: remove.XMLName,
+			Policy:  upgradePolicyPointer(remove.Policy),
+		})
+	}
+
+	for _, apply := range mp.Applys {
+		if apply == nil {
+			continue
+		}
+		destination.Applys = append(destination.Applys, &Apply{
+			XMLName:  apply.XMLName,
+			Duration: Duration(apply.Duration),
+			Priority: apply.Priority,
+			Policy:   upgradePolicyPointer(apply.Policy),
+		})
+	}
+
+	if mp.MatchSignal != nil {
+		matchSignal := &MatchSignal{
+			XMLName:         mp.MatchSignal.XMLName,
+			Match:           Match(mp.MatchSignal.Match),
+			SignalTolerance: Duration(mp.MatchSignal.SignalTolerance),
+			Schema:          mp.MatchSignal.Schema,
+		}
+		if matchSignal.Schema == "" {
+			matchSignal.Schema = matchSignalSchemaDefault
+		}
+		for _, assertion := range mp.MatchSignal.Assertions {
+			if assertion == nil {
+				continue
+			}
+			matchSignal.Assertions = append(matchSignal.Assertions, &Assert{
+				XMLName:     assertion.XMLName,
+				Declaration: assertion.Declaration,
+			})
+		}
+		destination.MatchSignal = matchSignal
+	}
+
+	return destination
+}
+
+func UpgradeMedia(m scte224_2018.Media) Media {
+	destination := Media{
+		ReusableType: UpgradeReusableType(m.ReusableType),
+		XMLName:      m.XMLName,
+		Effective:    m.Effective,
+		Expires:      m.Expires,
+		Source:       m.Source,
+	}
+
+	for _, mp := range m.MediaPoints {
+		if mp == nil {
+			continue
+		}
+		mp2020 := UpgradeMediaPoint(*mp)
+		destination.MediaPoints = append(destination.MediaPoints, &mp2020)
+	}
+
+	return destination
+}
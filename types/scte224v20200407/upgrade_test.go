@@ -0,0 +1,44 @@
+package scte224v20200407
+
+import (
+	"encoding/xml"
+	"testing"
+
+	scte224_2018 "github.com/Comcast/scte224structs/types/scte224v20180501"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewingPolicyRoundTrip(t *testing.T) {
+	var vp ViewingPolicy
+	err := xml.Unmarshal([]byte(vp2020Raw), &vp)
+	assert.Nil(t, err, "Error unmarshalling viewingpolicy")
+	assert.NotNil(t, vp.Allocation)
+
+	vp2018 := vp.Get2018()
+	upgraded := UpgradeViewingPolicy(vp2018)
+	assert.Equal(t, vp, upgraded, "Round trip through 2018 lost data")
+
+	// the Allocation also survives the 2018 XML
+	var decoded scte224_2018.ViewingPolicy
+	err = xml.Unmarshal([]byte(vp2018Raw), &decoded)
+	assert.Nil(t, err, "Error unmarshalling 2018 viewingpolicy")
+	upgraded = UpgradeViewingPolicy(decoded)
+	assert.Equal(t, vp.Allocation, upgraded.Allocation)
+	assert.Empty(t, upgraded.ActionProperty)
+}
+
+func TestMediaRoundTrip(t *testing.T) {
+	var media Media
+	err := xml.Unmarshal([]byte(media2020Raw), &media)
+	assert.Nil(t, err, "Error unmarshalling media")
+
+	media2018 := media.Get2018()
+	upgraded := UpgradeMedia(media2018)
+	assert.Equal(t, media, upgraded, "Round trip through 2018 lost data")
+	assert.Equal(t, "CallSign", upgraded.MediaPoints[1].AltIDs[0].Type)
+}
+
+func TestUpgradeMatchSignalSchema(t *testing.T) {
+	mp := UpgradeMediaPoint(scte224_2018.MediaPoint{MatchSignal: &scte224_2018.MatchSignal{Match: "ANY"}})
+	assert.Equal(t, matchSignalSchemaDefault, mp.MatchSignal.Schema)
+}
@@ -0,0 +1,257 @@
+// Package xsd provides XML Schema datatypes whose lexical form matters to SCTE 224
+// partners and is lost by the closest Go types.
+package xsd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateTime is an xs:dateTime value, such as the effective, expires and matchTime
+// attributes. It accepts every lexical form of the XML Schema type, with or
+// without fractional seconds, and with a time zone of Z, an offset, or none at
+// all, and keeps the form it was read in: a value is written back exactly as it
+// was read.
+//
+// A DateTime written without a time zone is not an instant until a zone is
+// chosen for it; Time reads it as UTC, Resolve lets the caller decide.
+type DateTime struct {
+	lexical string
+}
+
+// ErrNoZone is returned by RequireZone for values written without a time zone.
+var ErrNoZone = errors.New("xsd: dateTime has no time zone")
+
+// ParseDateTime reads an xs:dateTime. Leading and trailing whitespace is
+// ignored, as the XML Schema type collapses it.
+func ParseDateTime(s string) (DateTime, error) {
+	lexical := strings.Trim(s, " \t\r\n")
+	if _, _, err := parseFields(lexical); err != nil {
+		return DateTime{}, fmt.Errorf("xsd: invalid dateTime %q: %v", s, err)
+	}
+	return DateTime{lexical: lexical}, nil
+}
+
+// NewDateTime returns the DateTime of t, in RFC 3339 form with the zone of t.
+func NewDateTime(t time.Time) DateTime {
+	return DateTime{lexical: t.Format(time.RFC3339Nano)}
+}
+
+// String returns the lexical form of dt.
+func (dt DateTime) String() string {
+	return dt.lexical
+}
+
+// IsZero reports whether dt holds no value.
+func (dt DateTime) IsZero() bool {
+	return dt.lexical == ""
+}
+
+// HasZone reports whether dt was written with a time zone.
+func (dt DateTime) HasZone() bool {
+	_, zoned, _ := parseFields(dt.lexical)
+	return zoned
+}
+
+// Time returns the instant of dt, reading a value without a time zone as UTC.
+// The zero DateTime gives the zero time.
+func (dt DateTime) Time() time.Time {
+	t, _ := dt.Resolve(AssumeUTC)
+	return t
+}
+
+// Resolve returns the instant of dt. Values without a time zone are handed to
+// resolution; values with one are returned in their own offset.
+func (dt DateTime) Resolve(resolution ZoneResolution) (time.Time, error) {
+	if dt.lexical == "" {
+		return time.Time{}, nil
+	}
+	t, zoned, err := parseFields(dt.lexical)
+	if err != nil || zoned {
+		return t, err
+	}
+	return resolution(t)
+}
+
+// MarshalText returns the lexical form of dt.
+func (dt DateTime) MarshalText() ([]byte, error) {
+	return []byte(dt.lexical), nil
+}
+
+// UnmarshalText reads an xs:dateTime, as ParseDateTime does.
+func (dt *DateTime) UnmarshalText(text []byte) error {
+	parsed, err := ParseDateTime(string(text))
+	if err != nil {
+		return err
+	}
+	*dt = parsed
+	return nil
+}
+
+//********************* Zone Resolution *************************//
+
+// A ZoneResolution gives the instant of a DateTime written without a time zone.
+// It is passed the date and time fields of the value as a time in UTC.
+type ZoneResolution func(fields time.Time) (time.Time, error)
+
+// AssumeUTC reads values without a time zone as UTC, as Time does.
+func AssumeUTC(fields time.Time) (time.Time, error) {
+	return fields, nil
+}
+
+// AssumeLocation returns a ZoneResolution reading values without a time zone as
+// wall clock time in loc, such as the local time of the programmer.
+func AssumeLocation(loc *time.Location) ZoneResolution {
+	return func(f time.Time) (time.Time, error) {
+		return time.Date(f.Year(), f.Month(), f.Day(), f.Hour(), f.Minute(), f.Second(), f.Nanosecond(), loc), nil
+	}
+}
+
+// RequireZone rejects values without a time zone with ErrNoZone.
+func RequireZone(time.Time) (time.Time, error) {
+	return time.Time{}, ErrNoZone
+}
+
+//********************* Parsing *************************//
+
+// parseFields reads the lexical form
+//
+//	'-'? yyyy '-' mm '-' dd 'T' hh ':' mm ':' ss ('.' s+)? (('+' | '-') hh ':' mm | 'Z')?
+//
+// and returns its instant; values without a time zone are returned in UTC.
+func parseFields(s string) (time.Time, bool, error) {
+	p := fieldParser{s: s}
+	negative := p.accept('-')
+	yearDigits := p.digits()
+	if len(yearDigits) < 4 || (len(yearDigits) > 4 && yearDigits[0] == '0') {
+		return time.Time{}, false, errors.New("year must have four digits, or more without leading zeros")
+	}
+	if len(yearDigits) > 9 {
+		return time.Time{}, false, errors.New("year out of range")
+	}
+	year := atoi(yearDigits)
+	if negative {
+		year = -year
+	}
+	month := p.field('-', 2)
+	day := p.field('-', 2)
+	hour := p.field('T', 2)
+	minute := p.field(':', 2)
+	second := p.field(':', 2)
+	nanosecond, fractional := 0, false
+	if p.accept('.') {
+		fraction := p.digits()
+		if fraction == "" {
+			p.err = errors.New("missing fractional seconds")
+		}
+		for i := 0; i < 9; i++ {
+			nanosecond *= 10
+			if i < len(fraction) {
+				nanosecond += int(fraction[i] - '0')
+			}
+		}
+		fractional = strings.Trim(fraction, "0") != ""
+	}
+	var loc *time.Location
+	switch {
+	case p.accept('Z'):
+		loc = time.UTC
+	case p.peek('+') || p.peek('-'):
+		sign := 1
+		if p.s[p.i] == '-' {
+			sign = -1
+		}
+		p.i++
+		hours := p.field(0, 2)
+		minutes := p.field(':', 2)
+		if hours > 14 || minutes > 59 || (hours == 14 && minutes != 0) {
+			return time.Time{}, false, errors.New("time zone out of range")
+		}
+		offset := sign * (hours*3600 + minutes*60)
+		if offset == 0 {
+			loc = time.UTC
+		} else {
+			loc = time.FixedZone("", offset)
+		}
+	}
+	if p.err != nil {
+		return time.Time{}, false, p.err
+	}
+	if p.i != len(p.s) {
+		return time.Time{}, false, fmt.Errorf("unexpected %q", p.s[p.i:])
+	}
+
+	if month < 1 || month > 12 {
+		return time.Time{}, false, errors.New("month out of range")
+	}
+	if day < 1 || day > daysIn(time.Month(month), year) {
+		return time.Time{}, false, errors.New("day out of range")
+	}
+	// 24:00:00 is the first instant of the next day
+	if (hour > 23 && !(hour == 24 && minute == 0 && second == 0 && !fractional)) || minute > 59 || second > 59 {
+		return time.Time{}, false, errors.New("time out of range")
+	}
+	zoned := loc != nil
+	if !zoned {
+		loc = time.UTC
+	}
+	return time.Date(year, time.Month(month), day, hour, minute, second, nanosecond, loc), zoned, nil
+}
+
+func daysIn(month time.Month, year int) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// fieldParser reads the fields of a lexical form, remembering the first error.
+type fieldParser struct {
+	s   string
+	i   int
+	err error
+}
+
+func (p *fieldParser) peek(c byte) bool {
+	return p.i < len(p.s) && p.s[p.i] == c
+}
+
+func (p *fieldParser) accept(c byte) bool {
+	if p.peek(c) {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *fieldParser) digits() string {
+	start := p.i
+	for p.i < len(p.s) && '0' <= p.s[p.i] && p.s[p.i] <= '9' {
+		p.i++
+	}
+	return p.s[start:p.i]
+}
+
+// field reads a separator, unless it is 0, followed by a number of n digits.
+func (p *fieldParser) field(separator byte, n int) int {
+	if p.err != nil {
+		return 0
+	}
+	if separator != 0 && !p.accept(separator) {
+		p.err = fmt.Errorf("expected %q at offset %d", separator, p.i)
+		return 0
+	}
+	digits := p.digits()
+	if len(digits) != n {
+		p.err = fmt.Errorf("expected %d digits at offset %d", n, p.i-len(digits))
+		return 0
+	}
+	return atoi(digits)
+}
+
+func atoi(digits string) int {
+	n := 0
+	for i := 0; i < len(digits); i++ {
+		n = n*10 + int(digits[i]-'0')
+	}
+	return n
+}
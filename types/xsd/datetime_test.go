@@ -0,0 +1,106 @@
+package xsd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+func TestParseDateTime(t *testing.T) {
+	tests := []struct {
+		lexical string
+		instant time.Time
+		zoned   bool
+	}{
+		{"2021-03-14T02:30:00", time.Date(2021, 3, 14, 2, 30, 0, 0, time.UTC), false},
+		{"2021-03-14T02:30:00Z", time.Date(2021, 3, 14, 2, 30, 0, 0, time.UTC), true},
+		{"2021-03-14T02:30:00.120-05:00", time.Date(2021, 3, 14, 7, 30, 0, 120000000, time.UTC), true},
+		{"2021-03-14T02:30:00+00:00", time.Date(2021, 3, 14, 2, 30, 0, 0, time.UTC), true},
+		{"2021-03-14T02:30:00.1234567891+14:00", time.Date(2021, 3, 13, 12, 30, 0, 123456789, time.UTC), true},
+		{"2020-02-29T24:00:00Z", time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), true},
+		{"12021-01-01T00:00:00Z", time.Date(12021, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{"-0044-03-15T12:00:00", time.Date(-44, 3, 15, 12, 0, 0, 0, time.UTC), false},
+	}
+	for _, test := range tests {
+		dt, err := ParseDateTime(" " + test.lexical + "\n")
+		if nil != err {
+			t.Log(err)
+			t.Fail()
+			continue
+		}
+		if test.lexical != dt.String() || test.zoned != dt.HasZone() || !test.instant.Equal(dt.Time()) {
+			t.Log("Unexpected value for", test.lexical, dt.String(), dt.HasZone(), dt.Time())
+			t.Fail()
+		}
+	}
+
+	for _, invalid := range []string{"", "2021-03-14", "2021-3-14T02:30:00", "21-03-14T02:30:00", "02021-03-14T02:30:00",
+		"2021-02-29T00:00:00", "2021-13-01T00:00:00", "2021-03-14T24:00:01", "2021-03-14T24:00:00.5", "2021-03-14T02:60:00",
+		"2021-03-14T02:30:00.", "2021-03-14T02:30:00+15:00", "2021-03-14T02:30:00+0500", "2021-03-14T02:30:00z", "2021-03-14 02:30:00"} {
+		if _, err := ParseDateTime(invalid); nil == err {
+			t.Log("Expected an error for", invalid)
+			t.Fail()
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	floating, _ := ParseDateTime("2021-07-04T20:00:00")
+	zoned, _ := ParseDateTime("2021-07-04T20:00:00-04:00")
+	newYork := time.FixedZone("EDT", -4*3600)
+
+	if instant, err := floating.Resolve(AssumeLocation(newYork)); nil != err || !instant.Equal(zoned.Time()) {
+		t.Log("Value without a zone not read in the location", instant, err)
+		t.Fail()
+	}
+	if _, err := floating.Resolve(RequireZone); ErrNoZone != err {
+		t.Log("Value without a zone accepted", err)
+		t.Fail()
+	}
+	// values with a zone ignore the resolution
+	if instant, err := zoned.Resolve(RequireZone); nil != err || !instant.Equal(time.Date(2021, 7, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Log("Value with a zone not resolved", instant, err)
+		t.Fail()
+	}
+	var zero DateTime
+	if !zero.IsZero() || !zero.Time().IsZero() {
+		t.Log("Zero value should give the zero time")
+		t.Fail()
+	}
+	if "2021-07-04T20:00:00.5-04:00" != NewDateTime(time.Date(2021, 7, 4, 20, 0, 0, 500000000, newYork)).String() {
+		t.Log("Unexpected lexical form", NewDateTime(time.Date(2021, 7, 4, 20, 0, 0, 500000000, newYork)))
+		t.Fail()
+	}
+}
+
+func TestDateTimeEncoding(t *testing.T) {
+	type window struct {
+		XMLName xml.Name  `xml:"Window" json:"-"`
+		Start   *DateTime `xml:"start,attr,omitempty" json:"start,omitempty"`
+		End     *DateTime `xml:"end,attr,omitempty" json:"end,omitempty"`
+	}
+	raw := `<Window start="2021-07-04T20:00:00.000" end="2021-07-04T23:59:59.999+05:30"></Window>`
+	var w window
+	if err := xml.Unmarshal([]byte(raw), &w); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	roundtrip, _ := xml.Marshal(&w)
+	if raw != string(roundtrip) {
+		t.Log(string(roundtrip))
+		t.Log("did not match")
+		t.Log(raw)
+		t.Fail()
+	}
+	encoded, _ := json.Marshal(&w)
+	if `{"start":"2021-07-04T20:00:00.000","end":"2021-07-04T23:59:59.999+05:30"}` != string(encoded) {
+		t.Log("Unexpected JSON", string(encoded))
+		t.Fail()
+	}
+
+	if err := xml.Unmarshal([]byte(`<Window start="July 4th"/>`), &w); nil == err {
+		t.Log("Invalid dateTime accepted")
+		t.Fail()
+	}
+}
@@ -0,0 +1,338 @@
+// Package xmldsig signs SCTE 224 documents with enveloped XML Signatures, so that
+// the blackout instructions they carry cannot be altered in transit unnoticed.
+//
+// Documents are canonicalized with Exclusive XML Canonicalization 1.0, without
+// comments, and signed with RSA or ECDSA keys that are usually read from PEM files:
+//
+//	signer, err := xmldsig.LoadSigner("esni.key", "esni.crt")
+//	signed, err := signer.SignValue(&media)
+//
+//	key, err := xmldsig.LoadPublicKey("esni.crt")
+//	err = xmldsig.Verify(signed, key)
+//
+// The Signature element is added as the last child of the root element and covers
+// the whole document (URI=""). The structs of the version packages ignore it when
+// decoding, so a verified document is unmarshaled as usual.
+package xmldsig
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+//********************* Document Tree *************************//
+
+// element keeps the lexical form of an element: prefixes are preserved by
+// canonicalization, so names are not resolved up front.
+type element struct {
+	parent   *element
+	prefix   string
+	local    string
+	decls    []xml.Attr // namespace declarations, as written
+	attrs    []xml.Attr // other attributes, with their prefix in Name.Space
+	children []interface{}
+}
+
+type text string
+
+// document is a parsed XML document: the root element and the processing
+// instructions around it. Comments and the document type are dropped, since
+// canonicalization without comments does not output them.
+type document struct {
+	before, after []xml.ProcInst
+	root          *element
+}
+
+func parse(raw []byte) (*document, error) {
+	d := xml.NewDecoder(bytes.NewReader(raw))
+	doc := &document{}
+	var current *element
+	for {
+		token, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if current == nil && doc.root != nil {
+				return nil, errors.New("xmldsig: more than one root element")
+			}
+			e := &element{parent: current, prefix: t.Name.Space, local: t.Name.Local}
+			for _, a := range t.Attr {
+				if a.Name.Space == "xmlns" || (a.Name.Space == "" && a.Name.Local == "xmlns") {
+					e.decls = append(e.decls, a)
+				} else {
+					e.attrs = append(e.attrs, a)
+				}
+			}
+			if current == nil {
+				doc.root = e
+			} else {
+				current.children = append(current.children, e)
+			}
+			current = e
+		case xml.EndElement:
+			// RawToken does not check that end elements match their start
+			if current == nil || current.prefix != t.Name.Space || current.local != t.Name.Local {
+				return nil, fmt.Errorf("xmldsig: unexpected end element </%s>", t.Name.Local)
+			}
+			current = current.parent
+		case xml.CharData:
+			if current != nil {
+				current.children = append(current.children, text(t))
+			}
+		case xml.ProcInst:
+			if t.Target == "xml" {
+				continue
+			}
+			pi := t.Copy()
+			switch {
+			case current != nil:
+				current.children = append(current.children, pi)
+			case doc.root == nil:
+				doc.before = append(doc.before, pi)
+			default:
+				doc.after = append(doc.after, pi)
+			}
+		}
+	}
+	if doc.root == nil {
+		return nil, errors.New("xmldsig: no root element")
+	}
+	if current != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return doc, nil
+}
+
+// lookup returns the namespace bound to prefix where e is, with "" standing for
+// the default namespace.
+func (e *element) lookup(prefix string) (string, bool) {
+	if prefix == "xml" {
+		return xmlNamespace, true
+	}
+	for ; e != nil; e = e.parent {
+		for _, decl := range e.decls {
+			if (prefix == "" && decl.Name.Space == "") || (decl.Name.Space == "xmlns" && decl.Name.Local == prefix) {
+				return decl.Value, true
+			}
+		}
+	}
+	return "", prefix == ""
+}
+
+// namespace returns the namespace of e.
+func (e *element) namespace() string {
+	namespace, _ := e.lookup(e.prefix)
+	return namespace
+}
+
+// childElements returns the child elements of e with the given namespace and local name.
+func (e *element) childElements(namespace, local string) []*element {
+	var found []*element
+	for _, child := range e.children {
+		if c, ok := child.(*element); ok && c.local == local && c.namespace() == namespace {
+			found = append(found, c)
+		}
+	}
+	return found
+}
+
+// attr returns the value of the unqualified attribute with the given name.
+func (e *element) attr(local string) (string, bool) {
+	for _, a := range e.attrs {
+		if a.Name.Space == "" && a.Name.Local == local {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// textContent returns the character data of e and its descendants.
+func (e *element) textContent() string {
+	var b strings.Builder
+	for _, child := range e.children {
+		switch c := child.(type) {
+		case text:
+			b.WriteString(string(c))
+		case *element:
+			b.WriteString(c.textContent())
+		}
+	}
+	return b.String()
+}
+
+//********************* Exclusive Canonicalization *************************//
+
+// Canonicalize returns the Exclusive XML Canonicalization 1.0 (without comments)
+// of an XML document.
+func Canonicalize(raw []byte) ([]byte, error) {
+	doc, err := parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	var c canonicalizer
+	if err := c.document(doc); err != nil {
+		return nil, err
+	}
+	return c.buf.Bytes(), nil
+}
+
+// CanonicalMarshal returns the Exclusive XML Canonicalization of the XML encoding
+// of v, such as a Media or Results of any version package.
+func CanonicalMarshal(v interface{}) ([]byte, error) {
+	raw, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return Canonicalize(raw)
+}
+
+type canonicalizer struct {
+	buf bytes.Buffer
+	// skip is left out of the output along with its descendants, as the
+	// enveloped signature transform requires for the Signature element.
+	skip *element
+}
+
+func (c *canonicalizer) document(doc *document) error {
+	for _, pi := range doc.before {
+		c.procInst(pi)
+		c.buf.WriteByte('\n')
+	}
+	if err := c.element(doc.root, map[string]string{"": ""}); err != nil {
+		return err
+	}
+	for _, pi := range doc.after {
+		c.buf.WriteByte('\n')
+		c.procInst(pi)
+	}
+	return nil
+}
+
+// element writes e, declaring the namespaces it visibly utilizes that were not
+// rendered, with the same value, by an output ancestor.
+func (c *canonicalizer) element(e *element, rendered map[string]string) error {
+	if e == c.skip {
+		return nil
+	}
+
+	utilized := []string{e.prefix}
+	for _, a := range e.attrs {
+		if a.Name.Space != "" && a.Name.Space != "xml" {
+			utilized = append(utilized, a.Name.Space)
+		}
+	}
+	var decls []xml.Attr
+	scope := rendered
+	for _, prefix := range utilized {
+		namespace, ok := e.lookup(prefix)
+		if !ok {
+			return fmt.Errorf("xmldsig: undeclared namespace prefix %s", prefix)
+		}
+		if value, ok := scope[prefix]; ok && value == namespace {
+			continue
+		}
+		if len(decls) == 0 {
+			scope = make(map[string]string, len(rendered)+1)
+			for p, n := range rendered {
+				scope[p] = n
+			}
+		}
+		scope[prefix] = namespace
+		decls = append(decls, xml.Attr{Name: xml.Name{Local: prefix}, Value: namespace})
+	}
+	sort.Slice(decls, func(i, j int) bool { return decls[i].Name.Local < decls[j].Name.Local })
+
+	// attributes are sorted by namespace, then local name; unqualified ones first
+	type sortable struct {
+		attr      xml.Attr
+		namespace string
+	}
+	attrs := make([]sortable, len(e.attrs))
+	for i, a := range e.attrs {
+		attrs[i].attr = a
+		if a.Name.Space != "" {
+			attrs[i].namespace, _ = e.lookup(a.Name.Space)
+		}
+	}
+	sort.SliceStable(attrs, func(i, j int) bool {
+		if attrs[i].namespace != attrs[j].namespace {
+			return attrs[i].namespace < attrs[j].namespace
+		}
+		return attrs[i].attr.Name.Local < attrs[j].attr.Name.Local
+	})
+
+	c.buf.WriteByte('<')
+	c.name(e.prefix, e.local)
+	for _, decl := range decls {
+		if decl.Name.Local == "" {
+			c.attr("", "xmlns", decl.Value)
+		} else {
+			c.attr("xmlns", decl.Name.Local, decl.Value)
+		}
+	}
+	for _, a := range attrs {
+		c.attr(a.attr.Name.Space, a.attr.Name.Local, a.attr.Value)
+	}
+	c.buf.WriteByte('>')
+
+	for _, child := range e.children {
+		switch t := child.(type) {
+		case *element:
+			if err := c.element(t, scope); err != nil {
+				return err
+			}
+		case text:
+			textEscaper.WriteString(&c.buf, string(t))
+		case xml.ProcInst:
+			c.procInst(t)
+		}
+	}
+
+	c.buf.WriteString("</")
+	c.name(e.prefix, e.local)
+	c.buf.WriteByte('>')
+	return nil
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+)
+
+func (c *canonicalizer) name(prefix, local string) {
+	if prefix != "" {
+		c.buf.WriteString(prefix)
+		c.buf.WriteByte(':')
+	}
+	c.buf.WriteString(local)
+}
+
+func (c *canonicalizer) attr(prefix, local, value string) {
+	c.buf.WriteByte(' ')
+	c.name(prefix, local)
+	c.buf.WriteString(`="`)
+	attrEscaper.WriteString(&c.buf, value)
+	c.buf.WriteByte('"')
+}
+
+func (c *canonicalizer) procInst(pi xml.ProcInst) {
+	c.buf.WriteString("<?")
+	c.buf.WriteString(pi.Target)
+	if len(pi.Inst) > 0 {
+		c.buf.WriteByte(' ')
+		c.buf.Write(pi.Inst)
+	}
+	c.buf.WriteString("?>")
+}
@@ -0,0 +1,65 @@
+package xmldsig
+
+import (
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		raw, expected string
+	}{
+		// declarations, comments and unused namespaces are dropped; attributes are sorted
+		{"<?xml version=\"1.0\"?>\n<?pi a?>\n<!-- c --><doc xmlns=\"urn:a\" xmlns:unused=\"urn:u\" b=\"2\" a=\"1\" xmlns:p=\"urn:p\" p:z=\"&quot;\t\"><e/><!-- x --><p:f>&lt;&#xD;&gt;</p:f><g xmlns=\"\">t</g></doc>\n",
+			"<?pi a?>\n<doc xmlns=\"urn:a\" xmlns:p=\"urn:p\" a=\"1\" b=\"2\" p:z=\"&quot;&#x9;\"><e></e><p:f>&lt;&#xD;&gt;</p:f><g xmlns=\"\">t</g></doc>"},
+		// namespaces are declared where they are used, and not again below
+		{`<a:Media xmlns:a="urn:a" xmlns:b="urn:b"><b:One><b:Two xmlns:b="urn:b"/></b:One><b:Three xmlns:b="urn:c"/></a:Media>`,
+			`<a:Media xmlns:a="urn:a"><b:One xmlns:b="urn:b"><b:Two></b:Two></b:One><b:Three xmlns:b="urn:c"></b:Three></a:Media>`},
+		// CDATA is written as text and attribute order depends on namespaces, not prefixes
+		{`<r xmlns:z="urn:1" xmlns:a="urn:2" a:x="2" z:x="1" y="0"><![CDATA[<&>]]></r>`,
+			`<r xmlns:a="urn:2" xmlns:z="urn:1" y="0" z:x="1" a:x="2">&lt;&amp;&gt;</r>`},
+	}
+	for _, test := range tests {
+		actual, err := Canonicalize([]byte(test.raw))
+		if nil != err {
+			t.Log(err)
+			t.Fail()
+			continue
+		}
+		if test.expected != string(actual) {
+			t.Log(string(actual))
+			t.Log("did not match")
+			t.Log(test.expected)
+			t.Fail()
+		}
+	}
+
+	for _, malformed := range []string{`<a><b></a>`, `<a>`, `<p:a/>`, `<a/><b/>`, ``} {
+		if _, err := Canonicalize([]byte(malformed)); nil == err {
+			t.Log("Malformed XML should not be canonicalized", malformed)
+			t.Fail()
+		}
+	}
+}
+
+// TestCanonicalizeSubset checks the example of section 2.2 of the Exclusive XML
+// Canonicalization recommendation, where a subtree is canonicalized in the
+// context of its document.
+func TestCanonicalizeSubset(t *testing.T) {
+	doc, err := parse([]byte(`<n0:local xmlns:n0="foo:bar" xmlns:n3="ftp://example.org"><n1:elem2 xmlns:n1="http://example.net" xml:lang="en"><n3:stuff xmlns:n3="ftp://example.org"/></n1:elem2></n0:local>`))
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	var c canonicalizer
+	if err := c.element(doc.root.children[0].(*element), map[string]string{"": ""}); nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	expected := `<n1:elem2 xmlns:n1="http://example.net" xml:lang="en"><n3:stuff xmlns:n3="ftp://example.org"></n3:stuff></n1:elem2>`
+	if expected != c.buf.String() {
+		t.Log(c.buf.String())
+		t.Log("did not match")
+		t.Log(expected)
+		t.Fail()
+	}
+}
@@ -0,0 +1,498 @@
+package xmldsig
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+
+	// registers the hash functions the signature methods refer to
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+// Namespace and algorithm identifiers of XML Signature.
+const (
+	Namespace = "http://www.w3.org/2000/09/xmldsig#"
+
+	ExclusiveC14N      = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	EnvelopedSignature = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+
+	SHA256 = "http://www.w3.org/2001/04/xmlenc#sha256"
+	SHA384 = "http://www.w3.org/2001/04/xmldsig-more#sha384"
+	SHA512 = "http://www.w3.org/2001/04/xmlenc#sha512"
+
+	RSASHA256   = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	RSASHA384   = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha384"
+	RSASHA512   = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha512"
+	ECDSASHA256 = "http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha256"
+	ECDSASHA384 = "http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha384"
+	ECDSASHA512 = "http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha512"
+)
+
+var digestMethods = map[string]crypto.Hash{
+	SHA256: crypto.SHA256,
+	SHA384: crypto.SHA384,
+	SHA512: crypto.SHA512,
+}
+
+type signatureMethod struct {
+	hash  crypto.Hash
+	ecdsa bool
+}
+
+var signatureMethods = map[string]signatureMethod{
+	RSASHA256:   {crypto.SHA256, false},
+	RSASHA384:   {crypto.SHA384, false},
+	RSASHA512:   {crypto.SHA512, false},
+	ECDSASHA256: {crypto.SHA256, true},
+	ECDSASHA384: {crypto.SHA384, true},
+	ECDSASHA512: {crypto.SHA512, true},
+}
+
+// ErrInvalidSignature is returned by Verify when the signature does not match
+// the document or the key.
+var ErrInvalidSignature = errors.New("xmldsig: invalid signature")
+
+//********************* Signing *************************//
+
+// A Signer adds enveloped signatures to documents.
+type Signer struct {
+	key    crypto.Signer
+	cert   *x509.Certificate
+	method string
+}
+
+// NewSigner returns a Signer using an RSA or ECDSA private key. The certificate,
+// which may be nil, is included in the KeyInfo of the signatures. ECDSA keys sign
+// with the hash matching their curve, and RSA keys with SHA-256.
+func NewSigner(key crypto.Signer, cert *x509.Certificate) (*Signer, error) {
+	s := &Signer{key: key, cert: cert}
+	switch public := key.Public().(type) {
+	case *rsa.PublicKey:
+		s.method = RSASHA256
+	case *ecdsa.PublicKey:
+		switch public.Curve {
+		case elliptic.P384():
+			s.method = ECDSASHA384
+		case elliptic.P521():
+			s.method = ECDSASHA512
+		default:
+			s.method = ECDSASHA256
+		}
+	default:
+		return nil, fmt.Errorf("xmldsig: unsupported key type %T", public)
+	}
+	if cert != nil && !publicKeyEqual(cert.PublicKey, key.Public()) {
+		return nil, errors.New("xmldsig: certificate does not match the private key")
+	}
+	return s, nil
+}
+
+// LoadSigner reads a PEM encoded private key (PKCS #1, SEC 1 or PKCS #8) and,
+// unless certFile is empty, the PEM encoded certificate of its public key.
+func LoadSigner(keyFile, certFile string) (*Signer, error) {
+	block, err := readPEM(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	var key interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("xmldsig: %s: %v", keyFile, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("xmldsig: %s: unsupported key type %T", keyFile, key)
+	}
+
+	var cert *x509.Certificate
+	if certFile != "" {
+		block, err := readPEM(certFile)
+		if err != nil {
+			return nil, err
+		}
+		if cert, err = x509.ParseCertificate(block.Bytes); err != nil {
+			return nil, fmt.Errorf("xmldsig: %s: %v", certFile, err)
+		}
+	}
+	return NewSigner(signer, cert)
+}
+
+// SignValue marshals v, such as a Media or Results of any version package, and
+// signs the result.
+func (s *Signer) SignValue(v interface{}) ([]byte, error) {
+	raw, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return s.Sign(raw)
+}
+
+// Sign returns the document with an enveloped Signature added as the last child of
+// its root element. The rest of the document is left as it was.
+func (s *Signer) Sign(raw []byte) ([]byte, error) {
+	doc, err := parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	var c canonicalizer
+	if err := c.document(doc); err != nil {
+		return nil, err
+	}
+	digest := crypto.SHA256.New()
+	digest.Write(c.buf.Bytes())
+
+	// SignedInfo is canonicalized where it ends up, within the signed document
+	signedInfo := s.signedInfo(digest.Sum(nil))
+	unsigned, err := insertSignature(raw, signature(signedInfo, "", ""))
+	if err != nil {
+		return nil, err
+	}
+	canonical, err := canonicalSignedInfo(unsigned)
+	if err != nil {
+		return nil, err
+	}
+	value, err := s.sign(canonical)
+	if err != nil {
+		return nil, err
+	}
+
+	var keyInfo string
+	if s.cert != nil {
+		keyInfo = `<ds:KeyInfo><ds:X509Data><ds:X509Certificate>` +
+			base64.StdEncoding.EncodeToString(s.cert.Raw) +
+			`</ds:X509Certificate></ds:X509Data></ds:KeyInfo>`
+	}
+	return insertSignature(raw, signature(signedInfo, base64.StdEncoding.EncodeToString(value), keyInfo))
+}
+
+func (s *Signer) signedInfo(digest []byte) string {
+	return `<ds:SignedInfo>` +
+		`<ds:CanonicalizationMethod Algorithm="` + ExclusiveC14N + `"/>` +
+		`<ds:SignatureMethod Algorithm="` + s.method + `"/>` +
+		`<ds:Reference URI="">` +
+		`<ds:Transforms>` +
+		`<ds:Transform Algorithm="` + EnvelopedSignature + `"/>` +
+		`<ds:Transform Algorithm="` + ExclusiveC14N + `"/>` +
+		`</ds:Transforms>` +
+		`<ds:DigestMethod Algorithm="` + SHA256 + `"/>` +
+		`<ds:DigestValue>` + base64.StdEncoding.EncodeToString(digest) + `</ds:DigestValue>` +
+		`</ds:Reference>` +
+		`</ds:SignedInfo>`
+}
+
+func signature(signedInfo, value, keyInfo string) string {
+	return `<ds:Signature xmlns:ds="` + Namespace + `">` + signedInfo +
+		`<ds:SignatureValue>` + value + `</ds:SignatureValue>` + keyInfo + `</ds:Signature>`
+}
+
+func (s *Signer) sign(canonicalSignedInfo []byte) ([]byte, error) {
+	method := signatureMethods[s.method]
+	h := method.hash.New()
+	h.Write(canonicalSignedInfo)
+	value, err := s.key.Sign(rand.Reader, h.Sum(nil), method.hash)
+	if err != nil || !method.ecdsa {
+		return value, err
+	}
+
+	// crypto.Signer returns ECDSA signatures in ASN.1, XML Signature wants r || s
+	var rs struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(value, &rs); err != nil {
+		return nil, err
+	}
+	size := (s.key.Public().(*ecdsa.PublicKey).Curve.Params().BitSize + 7) / 8
+	value = make([]byte, 2*size)
+	r, sv := rs.R.Bytes(), rs.S.Bytes()
+	copy(value[size-len(r):size], r)
+	copy(value[2*size-len(sv):], sv)
+	return value, nil
+}
+
+// insertSignature adds signature before the end tag of the root element.
+func insertSignature(raw []byte, signature string) ([]byte, error) {
+	d := xml.NewDecoder(bytes.NewReader(raw))
+	depth := 0
+	var rootName xml.Name
+	for {
+		offset := d.InputOffset()
+		token, err := d.RawToken()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				rootName = t.Name
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+			if depth > 0 {
+				continue
+			}
+			var b bytes.Buffer
+			b.Grow(len(raw) + len(signature))
+			if end := d.InputOffset(); end == offset {
+				// a self-closed root, <Media/>, is opened to hold the signature
+				b.Write(raw[:offset-2])
+				b.WriteByte('>')
+				b.WriteString(signature)
+				b.WriteString("</")
+				if rootName.Space != "" {
+					b.WriteString(rootName.Space)
+					b.WriteByte(':')
+				}
+				b.WriteString(rootName.Local)
+				b.WriteByte('>')
+				b.Write(raw[end:])
+			} else {
+				b.Write(raw[:offset])
+				b.WriteString(signature)
+				b.Write(raw[offset:])
+			}
+			return b.Bytes(), nil
+		}
+	}
+}
+
+//********************* Verification *************************//
+
+// Verify checks the enveloped signature of a document against the public key of
+// the signer. Only signatures made the way Sign makes them are accepted: a single
+// Signature on the root, referencing the whole document with the enveloped
+// signature and Exclusive C14N transforms. The KeyInfo of the signature is not
+// trusted; the key has to come from the caller.
+func Verify(raw []byte, key crypto.PublicKey) error {
+	doc, err := parse(raw)
+	if err != nil {
+		return err
+	}
+	signatures := doc.root.childElements(Namespace, "Signature")
+	if len(signatures) != 1 {
+		return fmt.Errorf("xmldsig: found %d Signature elements on the root, expected 1", len(signatures))
+	}
+	sig := signatures[0]
+	signedInfo, err := single(sig, "SignedInfo")
+	if err != nil {
+		return err
+	}
+	canonicalization, err := single(signedInfo, "CanonicalizationMethod")
+	if err != nil {
+		return err
+	}
+	if algorithm, _ := canonicalization.attr("Algorithm"); algorithm != ExclusiveC14N {
+		return fmt.Errorf("xmldsig: unsupported canonicalization method %s", algorithm)
+	}
+	signatureMethod, err := single(signedInfo, "SignatureMethod")
+	if err != nil {
+		return err
+	}
+	algorithm, _ := signatureMethod.attr("Algorithm")
+	method, ok := signatureMethods[algorithm]
+	if !ok {
+		return fmt.Errorf("xmldsig: unsupported signature method %s", algorithm)
+	}
+
+	if err := verifyReference(doc, sig, signedInfo); err != nil {
+		return err
+	}
+
+	value, err := base64Content(sig, "SignatureValue")
+	if err != nil {
+		return err
+	}
+	c := canonicalizer{}
+	if err := c.element(signedInfo, map[string]string{"": ""}); err != nil {
+		return err
+	}
+	h := method.hash.New()
+	h.Write(c.buf.Bytes())
+	hashed := h.Sum(nil)
+
+	switch public := key.(type) {
+	case *rsa.PublicKey:
+		if method.ecdsa || rsa.VerifyPKCS1v15(public, method.hash, hashed, value) != nil {
+			return ErrInvalidSignature
+		}
+	case *ecdsa.PublicKey:
+		size := (public.Curve.Params().BitSize + 7) / 8
+		if !method.ecdsa || len(value) != 2*size {
+			return ErrInvalidSignature
+		}
+		r := new(big.Int).SetBytes(value[:size])
+		s := new(big.Int).SetBytes(value[size:])
+		if !ecdsa.Verify(public, hashed, r, s) {
+			return ErrInvalidSignature
+		}
+	default:
+		return fmt.Errorf("xmldsig: unsupported key type %T", key)
+	}
+	return nil
+}
+
+func verifyReference(doc *document, sig, signedInfo *element) error {
+	reference, err := single(signedInfo, "Reference")
+	if err != nil {
+		return err
+	}
+	if uri, ok := reference.attr("URI"); !ok || uri != "" {
+		return fmt.Errorf("xmldsig: unsupported reference URI %q, expected the whole document", uri)
+	}
+	transforms, err := single(reference, "Transforms")
+	if err != nil {
+		return err
+	}
+	var algorithms []string
+	for _, transform := range transforms.childElements(Namespace, "Transform") {
+		algorithm, _ := transform.attr("Algorithm")
+		algorithms = append(algorithms, algorithm)
+	}
+	if len(algorithms) != 2 || algorithms[0] != EnvelopedSignature || algorithms[1] != ExclusiveC14N {
+		return fmt.Errorf("xmldsig: unsupported transforms %v", algorithms)
+	}
+	digestMethod, err := single(reference, "DigestMethod")
+	if err != nil {
+		return err
+	}
+	algorithm, _ := digestMethod.attr("Algorithm")
+	hash, ok := digestMethods[algorithm]
+	if !ok {
+		return fmt.Errorf("xmldsig: unsupported digest method %s", algorithm)
+	}
+	expected, err := base64Content(reference, "DigestValue")
+	if err != nil {
+		return err
+	}
+
+	c := canonicalizer{skip: sig}
+	if err := c.document(doc); err != nil {
+		return err
+	}
+	h := hash.New()
+	h.Write(c.buf.Bytes())
+	if !bytes.Equal(h.Sum(nil), expected) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// canonicalSignedInfo returns the canonical SignedInfo of a signed document.
+func canonicalSignedInfo(raw []byte) ([]byte, error) {
+	doc, err := parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	signatures := doc.root.childElements(Namespace, "Signature")
+	signedInfo, err := single(signatures[len(signatures)-1], "SignedInfo")
+	if err != nil {
+		return nil, err
+	}
+	var c canonicalizer
+	if err := c.element(signedInfo, map[string]string{"": ""}); err != nil {
+		return nil, err
+	}
+	return c.buf.Bytes(), nil
+}
+
+// single returns the only child of e with the given name in the XML Signature namespace.
+func single(e *element, local string) (*element, error) {
+	found := e.childElements(Namespace, local)
+	if len(found) != 1 {
+		return nil, fmt.Errorf("xmldsig: found %d %s elements, expected 1", len(found), local)
+	}
+	return found[0], nil
+}
+
+func base64Content(e *element, local string) ([]byte, error) {
+	child, err := single(e, local)
+	if err != nil {
+		return nil, err
+	}
+	// base64 content may be wrapped over several lines
+	content := bytes.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\r', '\n':
+			return -1
+		}
+		return r
+	}, []byte(child.textContent()))
+	value, err := base64.StdEncoding.DecodeString(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("xmldsig: %s: %v", local, err)
+	}
+	return value, nil
+}
+
+//********************* Keys *************************//
+
+// LoadPublicKey reads the key signatures are verified with from a PEM encoded
+// public key (PKIX or PKCS #1) or certificate.
+func LoadPublicKey(file string) (crypto.PublicKey, error) {
+	block, err := readPEM(file)
+	if err != nil {
+		return nil, err
+	}
+	var key interface{}
+	switch block.Type {
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = cert.PublicKey
+		}
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("xmldsig: %s: %v", file, err)
+	}
+	return key, nil
+}
+
+func readPEM(file string) (*pem.Block, error) {
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("xmldsig: %s: no PEM data", file)
+	}
+	return block, nil
+}
+
+func publicKeyEqual(a, b crypto.PublicKey) bool {
+	switch a := a.(type) {
+	case *rsa.PublicKey:
+		b, ok := b.(*rsa.PublicKey)
+		return ok && a.N.Cmp(b.N) == 0 && a.E == b.E
+	case *ecdsa.PublicKey:
+		b, ok := b.(*ecdsa.PublicKey)
+		return ok && a.Curve == b.Curve && a.X.Cmp(b.X) == 0 && a.Y.Cmp(b.Y) == 0
+	}
+	return false
+}
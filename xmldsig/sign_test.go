@@ -0,0 +1,223 @@
+package xmldsig
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"encoding/xml"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	scte224_2015 "github.com/Comcast/scte224structs/types/scte224v20151115"
+	scte224_2018 "github.com/Comcast/scte224structs/types/scte224v20180501"
+	scte224_2020 "github.com/Comcast/scte224structs/types/scte224v20200407"
+)
+
+// keyFiles writes an RSA key with its certificate and an ECDSA key with its
+// public key to a temporary directory, and returns their paths.
+func keyFiles(t *testing.T) (dir, rsaKey, rsaCert, ecKey, ecPublic string) {
+	dir, err := ioutil.TempDir("", "xmldsig")
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	write := func(name, blockType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); nil != err {
+			t.Log(err)
+			t.FailNow()
+		}
+		return path
+	}
+
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "esni.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &rsaPrivate.PublicKey, rsaPrivate)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	rsaKey = write("rsa.key", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaPrivate))
+	rsaCert = write("rsa.crt", "CERTIFICATE", certDER)
+
+	ecPrivate, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	pkcs8, _ := x509.MarshalPKCS8PrivateKey(ecPrivate)
+	pkix, _ := x509.MarshalPKIXPublicKey(&ecPrivate.PublicKey)
+	ecKey = write("ec.key", "PRIVATE KEY", pkcs8)
+	ecPublic = write("ec.pub", "PUBLIC KEY", pkix)
+	return
+}
+
+const (
+	media2015Raw   = `<Media xmlns="http://www.scte.org/schemas/224/2015" id="hbo.com/media/HBOHD" description="HBO HD"><MediaPoint id="hbo.com/media/HBOHD/start" matchTime="2021-01-01T00:00:00Z"></MediaPoint></Media>`
+	results2018Raw = `<Results xmlns="http://www.scte.org/schemas/224" size="1"><Media id="hbo.com/media/HBOHD"><MediaPoint id="hbo.com/media/HBOHD/start"><Apply><Policy xmlns:xlink="http://www.w3.org/1999/xlink" xlink:href="hbo.com/policy/blackout"></Policy></Apply></MediaPoint></Media></Results>`
+	media2020Raw   = `<Media xmlns="http://www.scte.org/schemas/224" id="hbo.com/media/HBOHD"><MediaPoint id="hbo.com/media/HBOHD/start"><Metadata><Rating xmlns="urn:example:rating">TV-MA</Rating></Metadata></MediaPoint></Media>`
+)
+
+func TestSignAndVerify(t *testing.T) {
+	dir, rsaKey, rsaCert, ecKey, ecPublic := keyFiles(t)
+	defer os.RemoveAll(dir)
+
+	rsaSigner, err := LoadSigner(rsaKey, rsaCert)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	ecSigner, err := LoadSigner(ecKey, "")
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	rsaPublic, err := LoadPublicKey(rsaCert)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	ecPublicKey, err := LoadPublicKey(ecPublic)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+
+	var media2015 scte224_2015.Media
+	var results2018 scte224_2018.Results
+	var media2020 scte224_2020.Media
+	values := []struct {
+		raw string
+		v   interface{}
+	}{
+		{media2015Raw, &media2015},
+		{results2018Raw, &results2018},
+		{media2020Raw, &media2020},
+	}
+	for _, value := range values {
+		if err := xml.Unmarshal([]byte(value.raw), value.v); nil != err {
+			t.Log(err)
+			t.FailNow()
+		}
+		for _, signer := range []struct {
+			signer         *Signer
+			key, wrong     crypto.PublicKey
+			method         string
+			hasCertificate bool
+		}{
+			{rsaSigner, rsaPublic, ecPublicKey, RSASHA256, true},
+			{ecSigner, ecPublicKey, rsaPublic, ECDSASHA384, false},
+		} {
+			signed, err := signer.signer.SignValue(value.v)
+			if nil != err {
+				t.Log(err)
+				t.FailNow()
+			}
+			if !strings.Contains(string(signed), signer.method) || signer.hasCertificate != strings.Contains(string(signed), "X509Certificate") {
+				t.Log("Unexpected signature", string(signed))
+				t.Fail()
+			}
+			if err := Verify(signed, signer.key); nil != err {
+				t.Log("Signature not verified", err, string(signed))
+				t.Fail()
+			}
+			if err := Verify(signed, signer.wrong); nil == err {
+				t.Log("Signature verified with the wrong key")
+				t.Fail()
+			}
+			tampered := strings.Replace(string(signed), "hbo.com/media/HBOHD", "hbo.com/media/HBO2", 1)
+			if err := Verify([]byte(tampered), signer.key); ErrInvalidSignature != err {
+				t.Log("Tampered document verified", err)
+				t.Fail()
+			}
+
+			// the signature does not get in the way of decoding
+			if err := xml.Unmarshal(signed, value.v); nil != err {
+				t.Log(err)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestSignDocument(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	signer, err := NewSigner(key, nil)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+
+	// self-closed roots are opened, and the formatting of the document is kept
+	signed, err := signer.Sign([]byte("<?xml version=\"1.0\"?>\n<esni:Media xmlns:esni=\"http://www.scte.org/schemas/224\" id=\"m\"/>\n"))
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if !strings.HasPrefix(string(signed), "<?xml version=\"1.0\"?>\n<esni:Media xmlns:esni=\"http://www.scte.org/schemas/224\" id=\"m\"><ds:Signature ") ||
+		!strings.HasSuffix(string(signed), "</ds:Signature></esni:Media>\n") {
+		t.Log("Signature not added to the root", string(signed))
+		t.Fail()
+	}
+	if err := Verify(signed, &key.PublicKey); nil != err {
+		t.Log(err)
+		t.Fail()
+	}
+
+	// whitespace within the root is signed
+	indented := strings.Replace(string(signed), `id="m">`, "id=\"m\">\n  ", 1)
+	if err := Verify([]byte(indented), &key.PublicKey); ErrInvalidSignature != err {
+		t.Log("Whitespace changes not detected", err)
+		t.Fail()
+	}
+
+	// only the signatures Sign makes are accepted
+	if err := Verify([]byte(`<Media xmlns="http://www.scte.org/schemas/224"/>`), &key.PublicKey); nil == err {
+		t.Log("Unsigned document verified")
+		t.Fail()
+	}
+	otherReference := strings.Replace(string(signed), `URI=""`, `URI="#m"`, 1)
+	if err := Verify([]byte(otherReference), &key.PublicKey); nil == err || ErrInvalidSignature == err {
+		t.Log("Unsupported reference not reported", err)
+		t.Fail()
+	}
+	twice, err := signer.Sign(signed)
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	if err := Verify(twice, &key.PublicKey); nil == err {
+		t.Log("Document with two signatures verified")
+		t.Fail()
+	}
+
+	other, _ := rsa.GenerateKey(rand.Reader, 1024)
+	cert := &x509.Certificate{PublicKey: &other.PublicKey}
+	if _, err := NewSigner(key, cert); nil == err {
+		t.Log("A certificate for another key should be rejected")
+		t.Fail()
+	}
+}
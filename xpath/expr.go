@@ -0,0 +1,773 @@
+package xpath
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// An Expr is a compiled XPath expression. It is safe for concurrent use.
+type Expr struct {
+	source string
+	root   expr
+}
+
+// Compile parses an XPath expression.
+func Compile(source string) (*Expr, error) {
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root := p.or()
+	if p.err == nil && p.peek().kind != tokenEOF {
+		p.fail("unexpected %s", p.peek())
+	}
+	if p.err != nil {
+		return nil, fmt.Errorf("xpath: %s: %v", source, p.err)
+	}
+	return &Expr{source: source, root: root}, nil
+}
+
+// MustCompile is like Compile but panics if the expression cannot be parsed.
+func MustCompile(source string) *Expr {
+	e, err := Compile(source)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// String returns the source of the expression.
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Evaluate returns the value of the expression with n as context node: a []*Node
+// in document order, a string, a float64 or a bool.
+func (e *Expr) Evaluate(n *Node) interface{} {
+	return e.root.eval(context{node: n, position: 1, size: 1})
+}
+
+// Matches reports whether the value of the expression with n as context node is
+// true: a non-empty node-set, a non-empty string, a number other than 0 and NaN,
+// or true.
+func (e *Expr) Matches(n *Node) bool {
+	return toBool(e.Evaluate(n))
+}
+
+// Select returns the nodes the expression selects with n as context node, or nil
+// if its value is not a node-set.
+func (e *Expr) Select(n *Node) []*Node {
+	nodes, _ := e.Evaluate(n).([]*Node)
+	return nodes
+}
+
+//********************* Lexer *************************//
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenName
+	tokenString
+	tokenNumber
+	tokenOperator // one of / // [ ] ( ) @ , | . .. * = != < <= > >= -
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return strconv.Quote(t.value)
+	}
+	return "'" + t.value + "'"
+}
+
+func lex(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("xpath: %s: unterminated string", s)
+			}
+			tokens = append(tokens, token{tokenString, s[i+1 : i+1+end]})
+			i += end + 2
+		case isDigit(c) || (c == '.' && i+1 < len(s) && isDigit(s[i+1])):
+			start := i
+			for i < len(s) && (isDigit(s[i]) || s[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokenNumber, s[start:i]})
+		case isNameStart(c):
+			start := i
+			for i < len(s) && (isNameStart(s[i]) || isDigit(s[i]) || s[i] == '-' || s[i] == '.' ||
+				// a prefix, but not an axis separator
+				(s[i] == ':' && i+1 < len(s) && s[i+1] != ':')) {
+				i++
+			}
+			tokens = append(tokens, token{tokenName, s[start:i]})
+		default:
+			operator := ""
+			for _, candidate := range []string{"//", "..", "!=", "<=", ">=", "/", "[", "]", "(", ")", "@", ",", "|", ".", "*", "=", "<", ">", "-"} {
+				if strings.HasPrefix(s[i:], candidate) {
+					operator = candidate
+					break
+				}
+			}
+			if operator == "" {
+				return nil, fmt.Errorf("xpath: %s: unexpected %q", s, c)
+			}
+			tokens = append(tokens, token{tokenOperator, operator})
+			i += len(operator)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF}), nil
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || c >= 0x80
+}
+
+//********************* Parser *************************//
+
+type parser struct {
+	tokens []token
+	pos    int
+	err    error
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) accept(operator string) bool {
+	if t := p.peek(); t.kind == tokenOperator && t.value == operator {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) acceptName(name string) bool {
+	if t := p.peek(); t.kind == tokenName && t.value == name {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(operator string) {
+	if !p.accept(operator) {
+		p.fail("expected '%s', found %s", operator, p.peek())
+	}
+}
+
+func (p *parser) fail(format string, args ...interface{}) {
+	if p.err == nil {
+		p.err = fmt.Errorf(format, args...)
+	}
+	p.pos = len(p.tokens) - 1
+}
+
+func (p *parser) or() expr {
+	left := p.and()
+	for p.acceptName("or") {
+		left = logical{or: true, left: left, right: p.and()}
+	}
+	return left
+}
+
+func (p *parser) and() expr {
+	left := p.equality()
+	for p.acceptName("and") {
+		left = logical{left: left, right: p.equality()}
+	}
+	return left
+}
+
+func (p *parser) equality() expr {
+	left := p.relational()
+	for {
+		switch {
+		case p.accept("="):
+			left = comparison{op: "=", left: left, right: p.relational()}
+		case p.accept("!="):
+			left = comparison{op: "!=", left: left, right: p.relational()}
+		default:
+			return left
+		}
+	}
+}
+
+func (p *parser) relational() expr {
+	left := p.union()
+	for {
+		op := ""
+		for _, candidate := range []string{"<", "<=", ">", ">="} {
+			if p.accept(candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return left
+		}
+		left = comparison{op: op, left: left, right: p.union()}
+	}
+}
+
+func (p *parser) union() expr {
+	left := p.unary()
+	for p.accept("|") {
+		left = union{left, p.unary()}
+	}
+	return left
+}
+
+func (p *parser) unary() expr {
+	if p.accept("-") {
+		return negation{p.unary()}
+	}
+	return p.path()
+}
+
+// nodeTypes are the node tests written like function calls.
+var nodeTypes = map[string]bool{"text": true, "node": true}
+
+func (p *parser) path() expr {
+	t := p.peek()
+	var filter expr
+	switch {
+	case t.kind == tokenString:
+		p.next()
+		filter = literal(t.value)
+	case t.kind == tokenNumber:
+		p.next()
+		n, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			p.fail("invalid number %s", t.value)
+		}
+		filter = number(n)
+	case t.kind == tokenOperator && t.value == "(":
+		p.next()
+		filter = p.or()
+		p.expect(")")
+	case t.kind == tokenName && !nodeTypes[t.value] && p.tokens[p.pos+1].kind == tokenOperator && p.tokens[p.pos+1].value == "(":
+		filter = p.call()
+	}
+	if filter == nil {
+		return p.locationPath()
+	}
+
+	for p.peek().kind == tokenOperator && p.peek().value == "[" {
+		filter = filtered{filter, p.predicate()}
+	}
+	path := &locationPath{filter: filter}
+	switch {
+	case p.accept("/"):
+		p.relativePath(path)
+	case p.accept("//"):
+		path.steps = append(path.steps, step{axis: axisDescendantOrSelf, test: nodeTest{kind: testNode}})
+		p.relativePath(path)
+	default:
+		return filter
+	}
+	return path
+}
+
+func (p *parser) call() expr {
+	name := p.next().value
+	p.expect("(")
+	c := call{name: localName(name)}
+	if !p.accept(")") {
+		c.args = append(c.args, p.or())
+		for p.accept(",") {
+			c.args = append(c.args, p.or())
+		}
+		p.expect(")")
+	}
+	if _, ok := functions[c.name]; !ok {
+		p.fail("unknown function %s()", name)
+	}
+	return c
+}
+
+func (p *parser) predicate() expr {
+	p.expect("[")
+	e := p.or()
+	p.expect("]")
+	return e
+}
+
+func (p *parser) locationPath() expr {
+	path := &locationPath{}
+	switch {
+	case p.accept("/"):
+		path.absolute = true
+		// a lone / selects the document node
+		if t := p.peek(); !(t.kind == tokenName || (t.kind == tokenOperator && (t.value == "@" || t.value == "*" || t.value == "." || t.value == ".."))) {
+			return path
+		}
+	case p.accept("//"):
+		path.absolute = true
+		path.steps = append(path.steps, step{axis: axisDescendantOrSelf, test: nodeTest{kind: testNode}})
+	}
+	p.relativePath(path)
+	return path
+}
+
+func (p *parser) relativePath(path *locationPath) {
+	path.steps = append(path.steps, p.step())
+	for {
+		switch {
+		case p.accept("/"):
+		case p.accept("//"):
+			path.steps = append(path.steps, step{axis: axisDescendantOrSelf, test: nodeTest{kind: testNode}})
+		default:
+			return
+		}
+		path.steps = append(path.steps, p.step())
+	}
+}
+
+func (p *parser) step() step {
+	switch {
+	case p.accept("."):
+		return step{axis: axisSelf, test: nodeTest{kind: testNode}}
+	case p.accept(".."):
+		return step{axis: axisParent, test: nodeTest{kind: testNode}}
+	}
+	s := step{axis: axisChild}
+	if p.accept("@") {
+		s.axis = axisAttribute
+	}
+	switch t := p.next(); {
+	case t.kind == tokenOperator && t.value == "*":
+		s.test = nodeTest{kind: testName, name: "*"}
+	case t.kind == tokenName && nodeTypes[t.value] && p.accept("("):
+		p.expect(")")
+		s.test = nodeTest{kind: testText}
+		if t.value == "node" {
+			s.test.kind = testNode
+		}
+	case t.kind == tokenName:
+		name := localName(t.value)
+		if strings.HasSuffix(t.value, ":*") {
+			name = "*"
+		}
+		s.test = nodeTest{kind: testName, name: name}
+	default:
+		p.fail("expected a step, found %s", t)
+	}
+	for p.peek().kind == tokenOperator && p.peek().value == "[" {
+		s.predicates = append(s.predicates, p.predicate())
+	}
+	return s
+}
+
+// localName drops the prefix of a qualified name.
+func localName(name string) string {
+	if i := strings.LastIndexByte(name, ':'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+//********************* Evaluation *************************//
+
+type context struct {
+	node           *Node
+	position, size int
+}
+
+type expr interface {
+	eval(ctx context) interface{}
+}
+
+type literal string
+
+func (l literal) eval(context) interface{} { return string(l) }
+
+type number float64
+
+func (n number) eval(context) interface{} { return float64(n) }
+
+type negation struct{ operand expr }
+
+func (n negation) eval(ctx context) interface{} { return -toNumber(n.operand.eval(ctx)) }
+
+type logical struct {
+	or          bool
+	left, right expr
+}
+
+func (l logical) eval(ctx context) interface{} {
+	if toBool(l.left.eval(ctx)) == l.or {
+		return l.or
+	}
+	return toBool(l.right.eval(ctx))
+}
+
+type union struct{ left, right expr }
+
+func (u union) eval(ctx context) interface{} {
+	left, _ := u.left.eval(ctx).([]*Node)
+	right, _ := u.right.eval(ctx).([]*Node)
+	return documentOrder(append(append([]*Node(nil), left...), right...))
+}
+
+type filtered struct{ filter, predicate expr }
+
+func (f filtered) eval(ctx context) interface{} {
+	nodes, ok := f.filter.eval(ctx).([]*Node)
+	if !ok {
+		return []*Node(nil)
+	}
+	return applyPredicate(nodes, f.predicate)
+}
+
+const (
+	axisChild = iota
+	axisAttribute
+	axisDescendantOrSelf
+	axisSelf
+	axisParent
+)
+
+const (
+	testName = iota
+	testText
+	testNode
+)
+
+type nodeTest struct {
+	kind int
+	name string // a local name, or *
+}
+
+func (t nodeTest) matches(n *Node, axis int) bool {
+	switch t.kind {
+	case testText:
+		return n.Kind == TextNode
+	case testName:
+		// the principal node type of the attribute axis is attribute, of the others element
+		if (axis == axisAttribute) != (n.Kind == AttributeNode) || (axis != axisAttribute && n.Kind != ElementNode) {
+			return false
+		}
+		return t.name == "*" || t.name == n.Name.Local
+	}
+	return true
+}
+
+type step struct {
+	axis       int
+	test       nodeTest
+	predicates []expr
+}
+
+func (s step) apply(n *Node) []*Node {
+	var candidates []*Node
+	switch s.axis {
+	case axisChild:
+		candidates = n.Children
+	case axisAttribute:
+		candidates = n.Attrs
+	case axisSelf:
+		candidates = []*Node{n}
+	case axisParent:
+		if n.Parent != nil {
+			candidates = []*Node{n.Parent}
+		}
+	case axisDescendantOrSelf:
+		var walk func(n *Node)
+		walk = func(n *Node) {
+			candidates = append(candidates, n)
+			for _, child := range n.Children {
+				walk(child)
+			}
+		}
+		walk(n)
+	}
+	var selected []*Node
+	for _, candidate := range candidates {
+		if s.test.matches(candidate, s.axis) {
+			selected = append(selected, candidate)
+		}
+	}
+	for _, predicate := range s.predicates {
+		selected = applyPredicate(selected, predicate)
+	}
+	return selected
+}
+
+// applyPredicate keeps the nodes for which predicate is true; a number is true at
+// that position.
+func applyPredicate(nodes []*Node, predicate expr) []*Node {
+	var kept []*Node
+	for i, n := range nodes {
+		value := predicate.eval(context{node: n, position: i + 1, size: len(nodes)})
+		if position, ok := value.(float64); ok {
+			if position == float64(i+1) {
+				kept = append(kept, n)
+			}
+		} else if toBool(value) {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
+
+type locationPath struct {
+	absolute bool
+	filter   expr // the expression the steps start from, if any
+	steps    []step
+}
+
+func (path *locationPath) eval(ctx context) interface{} {
+	var nodes []*Node
+	switch {
+	case path.filter != nil:
+		nodes, _ = path.filter.eval(ctx).([]*Node)
+	case path.absolute:
+		root := ctx.node
+		for root.Parent != nil {
+			root = root.Parent
+		}
+		nodes = []*Node{root}
+	default:
+		nodes = []*Node{ctx.node}
+	}
+	for _, s := range path.steps {
+		var next []*Node
+		for _, n := range nodes {
+			next = append(next, s.apply(n)...)
+		}
+		nodes = documentOrder(next)
+	}
+	return nodes
+}
+
+// documentOrder sorts nodes in document order and removes duplicates.
+func documentOrder(nodes []*Node) []*Node {
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].order < nodes[j].order })
+	unique := nodes[:0]
+	for i, n := range nodes {
+		if i == 0 || n != nodes[i-1] {
+			unique = append(unique, n)
+		}
+	}
+	return unique
+}
+
+type comparison struct {
+	op          string
+	left, right expr
+}
+
+func (c comparison) eval(ctx context) interface{} {
+	return compare(c.op, c.left.eval(ctx), c.right.eval(ctx))
+}
+
+// compare applies the comparison rules of XPath 1.0: a node-set compares true when
+// one of its nodes does.
+func compare(op string, left, right interface{}) bool {
+	if nodes, ok := left.([]*Node); ok {
+		if _, ok := right.(bool); ok {
+			return compareValues(op, toBool(left), right)
+		}
+		for _, n := range nodes {
+			if compare(op, n.String(), right) {
+				return true
+			}
+		}
+		return false
+	}
+	if nodes, ok := right.([]*Node); ok {
+		if _, ok := left.(bool); ok {
+			return compareValues(op, left, toBool(right))
+		}
+		for _, n := range nodes {
+			if compare(op, left, n.String()) {
+				return true
+			}
+		}
+		return false
+	}
+	return compareValues(op, left, right)
+}
+
+func compareValues(op string, left, right interface{}) bool {
+	if op == "=" || op == "!=" {
+		var equal bool
+		_, leftBool := left.(bool)
+		_, rightBool := right.(bool)
+		_, leftNumber := left.(float64)
+		_, rightNumber := right.(float64)
+		switch {
+		case leftBool || rightBool:
+			equal = toBool(left) == toBool(right)
+		case leftNumber || rightNumber:
+			equal = toNumber(left) == toNumber(right)
+		default:
+			equal = toString(left) == toString(right)
+		}
+		return equal == (op == "=")
+	}
+	l, r := toNumber(left), toNumber(right)
+	switch op {
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	}
+	return l >= r
+}
+
+type call struct {
+	name string
+	args []expr
+}
+
+func (c call) eval(ctx context) interface{} {
+	args := make([]interface{}, len(c.args))
+	for i, arg := range c.args {
+		args[i] = arg.eval(ctx)
+	}
+	return functions[c.name](ctx, args)
+}
+
+// argument returns the single argument of a function, or the context node when
+// there is none.
+func argument(ctx context, args []interface{}) interface{} {
+	if len(args) == 0 {
+		return []*Node{ctx.node}
+	}
+	return args[0]
+}
+
+var functions map[string]func(ctx context, args []interface{}) interface{}
+
+func init() {
+	two := func(f func(a, b string) bool) func(context, []interface{}) interface{} {
+		return func(_ context, args []interface{}) interface{} {
+			if len(args) != 2 {
+				return false
+			}
+			return f(toString(args[0]), toString(args[1]))
+		}
+	}
+	functions = map[string]func(ctx context, args []interface{}) interface{}{
+		"last":     func(ctx context, _ []interface{}) interface{} { return float64(ctx.size) },
+		"position": func(ctx context, _ []interface{}) interface{} { return float64(ctx.position) },
+		"count": func(_ context, args []interface{}) interface{} {
+			if len(args) == 0 {
+				return float64(0)
+			}
+			nodes, _ := args[0].([]*Node)
+			return float64(len(nodes))
+		},
+		"not":     func(ctx context, args []interface{}) interface{} { return !toBool(argument(ctx, args)) },
+		"true":    func(context, []interface{}) interface{} { return true },
+		"false":   func(context, []interface{}) interface{} { return false },
+		"boolean": func(ctx context, args []interface{}) interface{} { return toBool(argument(ctx, args)) },
+		"string":  func(ctx context, args []interface{}) interface{} { return toString(argument(ctx, args)) },
+		"number":  func(ctx context, args []interface{}) interface{} { return toNumber(argument(ctx, args)) },
+		"string-length": func(ctx context, args []interface{}) interface{} {
+			return float64(len([]rune(toString(argument(ctx, args)))))
+		},
+		"normalize-space": func(ctx context, args []interface{}) interface{} {
+			return strings.Join(strings.Fields(toString(argument(ctx, args))), " ")
+		},
+		"concat": func(_ context, args []interface{}) interface{} {
+			var b strings.Builder
+			for _, arg := range args {
+				b.WriteString(toString(arg))
+			}
+			return b.String()
+		},
+		"contains":    two(strings.Contains),
+		"starts-with": two(strings.HasPrefix),
+		"ends-with":   two(strings.HasSuffix),
+	}
+}
+
+//********************* Conversions *************************//
+
+func toBool(v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0 && !math.IsNaN(v)
+	case string:
+		return v != ""
+	case []*Node:
+		return len(v) > 0
+	}
+	return false
+}
+
+func toNumber(v interface{}) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case bool:
+		if v {
+			return 1
+		}
+		return 0
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(toString(v)), 64)
+	if err != nil {
+		return math.NaN()
+	}
+	return n
+}
+
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []*Node:
+		if len(v) == 0 {
+			return ""
+		}
+		return v[0].String()
+	}
+	return ""
+}
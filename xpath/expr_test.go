@@ -0,0 +1,131 @@
+package xpath
+
+import (
+	"math"
+	"testing"
+)
+
+const cue = `<SpliceInfoSection xmlns="http://www.scte.org/schemas/35" tier="4095">
+	<TimeSignal><SpliceTime ptsTime="1924989008"/></TimeSignal>
+	<SegmentationDescriptor segmentationEventId="1" segmentationTypeId="16">
+		<SegmentationUpid segmentationUpidType="1" segmentationUpidFormat="text">00044MA000000037610T0318201400</SegmentationUpid>
+	</SegmentationDescriptor>
+	<SegmentationDescriptor segmentationEventId="2" segmentationTypeId="52">
+		<SegmentationUpid segmentationUpidType="9">SIGNAL:<![CDATA[abc]]>&amp;def</SegmentationUpid>
+		<SegmentationUpid segmentationUpidType="8">000000002CA0A18A</SegmentationUpid>
+	</SegmentationDescriptor>
+</SpliceInfoSection>`
+
+func parseCue(t *testing.T) *Node {
+	doc, err := Parse([]byte(cue))
+	if nil != err {
+		t.Log(err)
+		t.FailNow()
+	}
+	return doc
+}
+
+func TestMatches(t *testing.T) {
+	doc := parseCue(t)
+	for _, test := range []struct {
+		expression string
+		expected   bool
+	}{
+		{"/SpliceInfoSection", true},
+		{"/SpliceInfoSection/SpliceInsert", false},
+		{"/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=16]", true},
+		{"/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=17]", false},
+		{"/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId='52']", true},
+		{"/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=16]/SegmentationUpid[@segmentationUpidType=1 and text()='00044MA000000037610T0318201400']", true},
+		{"/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=16]/SegmentationUpid[@segmentationUpidType=1 and contains(text(),'37610T')]", true},
+		{"/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=16]/SegmentationUpid[@segmentationUpidType=1 and text()='AIRING_ID']", false},
+		{"/scte35:SpliceInfoSection/scte35:SegmentationDescriptor[@segmentationTypeId >= 52]", true},
+		{"//SegmentationUpid[.='SIGNAL:abc&def']", true},
+		{"//SegmentationUpid[starts-with(., 'SIGNAL:') and ends-with(., 'def')]", true},
+		{"//SegmentationDescriptor[2]/@segmentationEventId = 2", true},
+		{"//SegmentationDescriptor[last()]/SegmentationUpid[position()=2]", true},
+		{"count(//SegmentationUpid) = 3", true},
+		{"//SegmentationDescriptor[count(SegmentationUpid) > 1]/@segmentationEventId = '2'", true},
+		{"not(//SpliceInsert)", true},
+		{"//SpliceTime/@ptsTime > 1924989007 and //SpliceTime/@ptsTime < 1924989009", true},
+		{"//SegmentationDescriptor[@segmentationTypeId=16 or @segmentationTypeId=17]/..//SpliceTime", true},
+		{"//@segmentationEventId != 1", true},
+		{"//@tier = -(-4095)", true},
+		{"string-length(normalize-space(concat(' a ', ' b '))) = 3", true},
+		{"//*[@segmentationUpidType=8] | //SpliceInsert", true},
+		{"boolean(//SpliceTime[@ptsTime=1]) = false()", true},
+	} {
+		e, err := Compile(test.expression)
+		if nil != err {
+			t.Log(err)
+			t.Fail()
+			continue
+		}
+		if e.Matches(doc) != test.expected {
+			t.Logf("Expected %s to be %v", test.expression, test.expected)
+			t.Fail()
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	doc := parseCue(t)
+	upids := MustCompile("//SegmentationUpid").Select(doc)
+	if len(upids) != 3 || upids[0].String() != "00044MA000000037610T0318201400" {
+		t.Log("Unexpected nodes", upids)
+		t.Fail()
+	}
+	// a union is in document order, without duplicates
+	both := MustCompile("//SegmentationDescriptor[2] | //SegmentationDescriptor | //SegmentationDescriptor[1]").Select(doc)
+	if len(both) != 2 || both[0].Attrs[0].Value != "1" {
+		t.Log("Unexpected union", both)
+		t.Fail()
+	}
+	// relative paths start from the context node
+	if ids := MustCompile("@segmentationEventId").Select(both[1]); len(ids) != 1 || ids[0].Value != "2" {
+		t.Log("Unexpected attribute", ids)
+		t.Fail()
+	}
+	if root := MustCompile("/").Select(both[1]); len(root) != 1 || root[0].Kind != DocumentNode {
+		t.Log("Expected the document node", root)
+		t.Fail()
+	}
+
+	if v := MustCompile("number('x')").Evaluate(doc); !math.IsNaN(v.(float64)) {
+		t.Log("Expected NaN, got", v)
+		t.Fail()
+	}
+	if v := MustCompile("string(//@tier)").Evaluate(doc); v != "4095" {
+		t.Log("Unexpected string", v)
+		t.Fail()
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"/SpliceInfoSection[",
+		"/SpliceInfoSection[@tier='1]",
+		"unknown(1)",
+		"/SpliceInfoSection/",
+		"1 2",
+		"/a/#",
+		"1 + 1",
+	} {
+		if _, err := Compile(expression); nil == err {
+			t.Logf("Expected %q not to compile", expression)
+			t.Fail()
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := Parse([]byte("<a><b></a>")); nil == err {
+		t.Log("Expected an error for malformed XML")
+		t.Fail()
+	}
+	if _, err := Parse([]byte("  ")); nil == err {
+		t.Log("Expected an error for an empty document")
+		t.Fail()
+	}
+}
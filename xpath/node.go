@@ -0,0 +1,132 @@
+// Package xpath evaluates the subset of XPath 1.0 that MatchSignal assertions and
+// SignalPointDeletion expressions are written in, such as
+//
+//	/SpliceInfoSection/SegmentationDescriptor[@segmentationTypeId=16]/SegmentationUpid[@segmentationUpidType=1 and contains(text(),'12345')]
+//
+// Supported are absolute and relative location paths with the child, attribute,
+// descendant-or-self (//), self (.) and parent (..) abbreviations, name tests,
+// * and the text() and node() tests, predicates, the operators or, and, =, !=,
+// <, <=, >, >=, | and unary minus, and the functions last, position, count, not,
+// true, false, boolean, string, number, string-length, normalize-space, concat,
+// contains, starts-with and ends-with.
+//
+// Names are matched on their local part: prefixes are accepted and ignored, so
+// that an assertion matches whether or not it qualifies the SCTE 35 elements.
+package xpath
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// NodeKind is the kind of a Node.
+type NodeKind int
+
+const (
+	DocumentNode NodeKind = iota
+	ElementNode
+	AttributeNode
+	TextNode
+)
+
+// A Node is a node of a parsed document. Comments and processing instructions are
+// not kept.
+type Node struct {
+	Kind NodeKind
+	// Name is the name of an element or attribute.
+	Name   xml.Name
+	Value  string // of an attribute or text node
+	Parent *Node
+	// Attrs are the attributes of an element, namespace declarations excepted.
+	Attrs    []*Node
+	Children []*Node
+	order    int // position in document order
+}
+
+// String returns the string-value of n: the concatenated text of the element or
+// document, or the value of the attribute or text node.
+func (n *Node) String() string {
+	if n.Kind == AttributeNode || n.Kind == TextNode {
+		return n.Value
+	}
+	var b strings.Builder
+	n.writeText(&b)
+	return b.String()
+}
+
+func (n *Node) writeText(b *strings.Builder) {
+	for _, child := range n.Children {
+		if child.Kind == TextNode {
+			b.WriteString(child.Value)
+		} else {
+			child.writeText(b)
+		}
+	}
+}
+
+// Parse parses an XML document and returns its document node.
+func Parse(raw []byte) (*Node, error) {
+	d := xml.NewDecoder(bytes.NewReader(raw))
+	doc := &Node{Kind: DocumentNode}
+	current := doc
+	order := 1
+	add := func(n *Node) {
+		n.order = order
+		order++
+	}
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			e := &Node{Kind: ElementNode, Name: t.Name, Parent: current}
+			add(e)
+			for _, a := range t.Attr {
+				if a.Name.Space == "xmlns" || (a.Name.Space == "" && a.Name.Local == "xmlns") {
+					continue
+				}
+				attr := &Node{Kind: AttributeNode, Name: a.Name, Value: a.Value, Parent: e}
+				add(attr)
+				e.Attrs = append(e.Attrs, attr)
+			}
+			current.Children = append(current.Children, e)
+			current = e
+		case xml.EndElement:
+			current = current.Parent
+		case xml.CharData:
+			if current == doc {
+				continue
+			}
+			// adjacent character data, split around entities or CDATA, is one text node
+			if last := len(current.Children) - 1; last >= 0 && current.Children[last].Kind == TextNode {
+				current.Children[last].Value += string(t)
+				continue
+			}
+			text := &Node{Kind: TextNode, Value: string(t), Parent: current}
+			add(text)
+			current.Children = append(current.Children, text)
+		}
+	}
+	if len(doc.Children) == 0 {
+		return nil, errors.New("xpath: no root element")
+	}
+	return doc, nil
+}
+
+// FromValue returns the document node of the XML encoding of v, such as a
+// decoded SCTE 35 cue.
+func FromValue(v interface{}) (*Node, error) {
+	raw, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(raw)
+}